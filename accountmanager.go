@@ -0,0 +1,221 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrAccountNotManaged is returned by the AccountManager if no client is
+// registered for the requested account.
+var ErrAccountNotManaged = errors.New("account not managed")
+
+// AccountManager holds multiple signed-in OpCLI instances for different
+// accounts and service accounts and routes operations to them by account
+// UUID or URL. It is safe for concurrent use.
+type AccountManager struct {
+	mu      sync.RWMutex
+	clients map[string]*OpCLI // key is the account UUID, or the user UUID for service accounts
+	opts    []Option
+}
+
+// NewAccountManager creates an empty AccountManager.
+//
+// Parameters:
+//   - opts: Options applied to every OpCLI instance created by the manager.
+//
+// Returns:
+//   - *AccountManager: A pointer to the new AccountManager.
+func NewAccountManager(opts ...Option) *AccountManager {
+	return &AccountManager{clients: make(map[string]*OpCLI), opts: opts}
+}
+
+// SignIn creates a new OpCLI instance, signs in to the given account and
+// registers the client with the manager.
+//
+// Parameters:
+//   - ctx: The context for managing the sign-in.
+//   - account: A pointer to the Account to sign in to.
+//
+// Returns:
+//   - *OpCLI: The signed-in client.
+//   - error: An error if the sign-in fails.
+func (m *AccountManager) SignIn(ctx context.Context, account *Account) (*OpCLI, error) {
+	cli, err := NewOpCLI(m.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cli.SignIn(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := m.Add(cli); err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
+
+// SignInWithServiceAccount creates a new OpCLI instance authenticated with the
+// given service account token and registers it with the manager.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - accesstoken: The service account access token.
+//
+// Returns:
+//   - *OpCLI: The authenticated client.
+//   - error: An error if the authentication fails.
+func (m *AccountManager) SignInWithServiceAccount(ctx context.Context, accesstoken string) (*OpCLI, error) {
+	cli, err := NewOpCLI(m.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cli.SignInWithServiceAccount(ctx, accesstoken); err != nil {
+		return nil, err
+	}
+
+	if err := m.Add(cli); err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
+
+// Add registers an already signed-in client with the manager. A client that
+// was registered for the same account before is replaced.
+//
+// Parameters:
+//   - cli: The signed-in OpCLI instance.
+//
+// Returns:
+//   - error: An error if the client has no account information.
+func (m *AccountManager) Add(cli *OpCLI) error {
+	key := managedAccountKey(cli.Account)
+	if key == "" {
+		return ErrMissingAccount
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clients[key] = cli
+	return nil
+}
+
+// Remove unregisters the client of the given account UUID or user UUID.
+//
+// Parameters:
+//   - uuid: The account UUID, or the user UUID for service accounts.
+func (m *AccountManager) Remove(uuid string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, cli := range m.clients {
+		if key == uuid || cli.Account.UserUUID == uuid {
+			delete(m.clients, key)
+		}
+	}
+}
+
+// Get returns the client registered for the given account UUID or user UUID.
+//
+// Parameters:
+//   - uuid: The account UUID, or the user UUID of the signed-in user.
+//
+// Returns:
+//   - *OpCLI: The registered client.
+//   - error: ErrAccountNotManaged if no client is registered for the UUID.
+func (m *AccountManager) Get(uuid string) (*OpCLI, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if cli, ok := m.clients[uuid]; ok {
+		return cli, nil
+	}
+
+	for _, cli := range m.clients {
+		if cli.Account.UserUUID == uuid {
+			return cli, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotManaged, uuid)
+}
+
+// GetByURL returns the client registered for the account with the given URL.
+//
+// Parameters:
+//   - url: The URL of the account, e.g. my.1password.com.
+//
+// Returns:
+//   - *OpCLI: The registered client.
+//   - error: ErrAccountNotManaged if no client matches, or ErrMultipleAccounts
+//     if more than one registered client matches the URL.
+func (m *AccountManager) GetByURL(url string) (*OpCLI, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var match *OpCLI
+	for _, cli := range m.clients {
+		if cli.Account.URL == "" || normalizeURL(cli.Account.URL) != normalizeURL(url) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("%w: URL %s", ErrMultipleAccounts, url)
+		}
+		match = cli
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotManaged, url)
+	}
+	return match, nil
+}
+
+// Clients returns all registered clients.
+//
+// Returns:
+//   - []*OpCLI: The registered clients in no particular order.
+func (m *AccountManager) Clients() []*OpCLI {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clients := make([]*OpCLI, 0, len(m.clients))
+	for _, cli := range m.clients {
+		clients = append(clients, cli)
+	}
+	return clients
+}
+
+// RefreshExpiredSessions signs in again to every registered account whose
+// session has expired. Service accounts are skipped, as their tokens do not
+// expire with inactivity.
+//
+// Returns:
+//   - error: The joined errors of all failed refreshes, or nil.
+func (m *AccountManager) RefreshExpiredSessions(ctx context.Context) error {
+	var errs []error
+	for _, cli := range m.Clients() {
+		if !cli.canRefreshSession() || !cli.Account.IsSessionExpired() {
+			continue
+		}
+
+		if err := cli.refreshSession(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", cli.Account.UserUUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// managedAccountKey returns the key under which a client is registered.
+func managedAccountKey(account *Account) string {
+	if account == nil {
+		return ""
+	}
+	if account.AccountUUID != "" {
+		return account.AccountUUID
+	}
+	return account.UserUUID
+}
@@ -1,12 +1,13 @@
 package onepassword
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"log/slog"
-	"os/exec"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,6 +28,7 @@ type Account struct {
 	signInTime           time.Time
 	signInExpireDuration time.Duration
 	sessionToken         string
+	lastUsed             int64 // Unix nanoseconds of the last successful command, accessed atomically
 }
 
 // setSignInTime updates the Account's signInTime field to the current time.
@@ -71,10 +73,32 @@ func (a *Account) SetSignInInfo(token string) {
 }
 
 // IsSessionExpired checks if the session associated with the account has expired.
-// It compares the time elapsed since the account's sign-in time with the session's
-// expiration duration. Returns true if the session has expired, otherwise false.
+// It compares the time elapsed since the account's last activity (the sign-in or
+// the last successful command) with the session's expiration duration.
+// Returns true if the session has expired, otherwise false.
 func (a *Account) IsSessionExpired() bool {
-	return time.Since(a.signInTime) > a.signInExpireDuration
+	return time.Since(a.lastActivity()) > a.signInExpireDuration
+}
+
+// lastActivity returns the time of the sign-in or the last successful
+// command, whichever is later.
+func (a *Account) lastActivity() time.Time {
+	lastActivity := a.signInTime
+	if lastUsed := time.Unix(0, atomic.LoadInt64(&a.lastUsed)); lastUsed.After(lastActivity) {
+		lastActivity = lastUsed
+	}
+	return lastActivity
+}
+
+// touchSession records a successful command, which resets the inactivity
+// timeout of the session.
+func (a *Account) touchSession() {
+	atomic.StoreInt64(&a.lastUsed, time.Now().UnixNano())
+}
+
+// hasSession reports whether the account holds a session token from SignIn.
+func (a *Account) hasSession() bool {
+	return a.sessionToken != ""
 }
 
 // IsSessionValid checks if the session associated with the account is valid.
@@ -83,10 +107,56 @@ func (a *Account) IsSessionValid() bool {
 	return !a.IsSessionExpired()
 }
 
+// defaultAccountCacheTTL is the time the account list is cached by default.
+const defaultAccountCacheTTL = time.Minute
+
+// accountCache caches the result of "op account list".
+type accountCache struct {
+	mu        sync.Mutex
+	accounts  []Account
+	fetchedAt time.Time
+	ttl       time.Duration
+}
+
+// SetAccountCacheTTL sets how long the account list is cached. A negative
+// duration disables the cache, zero restores the default of one minute.
+//
+// Parameters:
+//   - ttl: The time the account list is cached.
+func (cli *OpCLI) SetAccountCacheTTL(ttl time.Duration) {
+	cli.accountCache.mu.Lock()
+	defer cli.accountCache.mu.Unlock()
+
+	cli.accountCache.ttl = ttl
+}
+
+// RefreshAccountDetails discards the cached account list and retrieves it
+// again from the CLI.
+//
+// Returns:
+//   - ([]Account): A slice of Account objects representing the 1Password accounts.
+//   - (error): An error if the accounts cannot be retrieved.
+func (cli *OpCLI) RefreshAccountDetails(ctx context.Context) ([]Account, error) {
+	cli.invalidateAccountCache()
+	return cli.GetAccountDetails(ctx)
+}
+
+// invalidateAccountCache discards the cached account list.
+func (cli *OpCLI) invalidateAccountCache() {
+	cli.accountCache.mu.Lock()
+	defer cli.accountCache.mu.Unlock()
+
+	cli.accountCache.accounts = nil
+}
+
 // GetAccountDetails retrieves the details of all 1Password accounts configured
 // in the CLI. It executes the "op account list" command, parses the result,
 // and returns a slice of Account objects.
 //
+// The account list is cached for a short time (see SetAccountCacheTTL), since
+// the account configuration rarely changes during the lifetime of a process.
+// Use RefreshAccountDetails to bypass the cache.
+//
 // Returns:
 //   - ([]Account): A slice of Account objects representing the 1Password accounts.
 //   - (error): An error if the command execution or JSON parsing fails, or if no
@@ -96,22 +166,43 @@ func (a *Account) IsSessionValid() bool {
 //   - Returns an error if the "op account list" command fails to execute.
 //   - Returns an error if the JSON output cannot be parsed into Account objects.
 //   - Returns an error if no accounts are found.
-func (cli *OpCLI) GetAccountDetails() ([]Account, error) {
-	slog.Debug("retrieving 1Password account details")
+func (cli *OpCLI) GetAccountDetails(ctx context.Context) ([]Account, error) {
+	cli.accountCache.mu.Lock()
+	defer cli.accountCache.mu.Unlock()
 
-	output, err := exec.Command(cli.Path, "account", "list", "--format=json").Output()
+	ttl := cli.accountCache.ttl
+	if ttl == 0 {
+		ttl = defaultAccountCacheTTL
+	}
+	if cli.accountCache.accounts != nil && time.Since(cli.accountCache.fetchedAt) < ttl {
+		cli.log().Debug("using cached 1Password account details")
+		cli.recordAccountCacheLookup(true)
+		return slices.Clone(cli.accountCache.accounts), nil
+	}
+	if ttl > 0 {
+		cli.recordAccountCacheLookup(false)
+	}
+
+	cli.log().Debug("retrieving 1Password account details")
+
+	output, stderr, err := cli.run(ctx, cli.command("account", "list", "--format=json"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to list accounts: %v", err)
+		return nil, fmt.Errorf("failed to list accounts: %w", &OpCliError{Err: err, StderrOutput: string(stderr)})
 	}
 
 	var accounts []Account
-	if err := json.Unmarshal(output, &accounts); err != nil {
-		return nil, fmt.Errorf("failed to parse account list: %v", err)
+	if err := cli.unmarshal(output, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse account list: %w", err)
 	}
 
 	if len(accounts) == 0 {
-		slog.Error("no 1Password accounts found")
-		return nil, fmt.Errorf("no accounts found")
+		cli.log().Error("no 1Password accounts found")
+		return nil, fmt.Errorf("%w: no accounts configured", ErrAccountNotFound)
+	}
+
+	if ttl > 0 {
+		cli.accountCache.accounts = slices.Clone(accounts)
+		cli.accountCache.fetchedAt = time.Now()
 	}
 
 	return accounts, nil
@@ -123,15 +214,16 @@ func (cli *OpCLI) GetAccountDetails() ([]Account, error) {
 // Otherwise, it returns an error indicating that the account was not found.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - accountUUID: The UUID of the account to retrieve.
 //
 // Returns:
 //   - *Account: A pointer to the Account struct containing the account details.
 //   - error: An error if the account is not found or if there is an issue retrieving the account details.
-func (cli *OpCLI) GetAccountDetailsByUUID(accountUUID string) (*Account, error) {
-	slog.Debug("retrieving 1Password account details by UUID", "accountUUID", accountUUID)
+func (cli *OpCLI) GetAccountDetailsByUUID(ctx context.Context, accountUUID string) (*Account, error) {
+	cli.log().Debug("retrieving 1Password account details by UUID", "accountUUID", accountUUID)
 
-	accounts, err := cli.GetAccountDetails()
+	accounts, err := cli.GetAccountDetails(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +234,7 @@ func (cli *OpCLI) GetAccountDetailsByUUID(accountUUID string) (*Account, error)
 		}
 	}
 
-	return nil, fmt.Errorf("account with UUID %s not found", accountUUID)
+	return nil, fmt.Errorf("%w: UUID %s", ErrAccountNotFound, accountUUID)
 }
 
 // GetAccountDetailsByEmail retrieves the details of a 1Password account
@@ -152,16 +244,17 @@ func (cli *OpCLI) GetAccountDetailsByUUID(accountUUID string) (*Account, error)
 // method and searches for an account that matches the provided email.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - email: The email address of the account to retrieve.
 //
 // Returns:
 //   - A pointer to the Account struct if an account with the specified email is found.
 //   - An error if no account with the specified email is found or if there is an issue
 //     retrieving account details.
-func (cli *OpCLI) GetAccountDetailsByEmail(email string) (*Account, error) {
-	slog.Debug("retrieving 1Password account details by email", "email", email)
+func (cli *OpCLI) GetAccountDetailsByEmail(ctx context.Context, email string) (*Account, error) {
+	cli.log().Debug("retrieving 1Password account details by email", "email", email)
 
-	accounts, err := cli.GetAccountDetails()
+	accounts, err := cli.GetAccountDetails(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +265,14 @@ func (cli *OpCLI) GetAccountDetailsByEmail(email string) (*Account, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("account with email %s not found", email)
+	return nil, fmt.Errorf("%w: email %s", ErrAccountNotFound, email)
 }
 
 // GetAccountDetailsByURL retrieves the details of a 1Password account that matches the specified URL.
 // It searches through all available accounts and returns the account details if a match is found.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - url: The URL of the 1Password account to retrieve.
 //
 // Returns:
@@ -189,10 +283,10 @@ func (cli *OpCLI) GetAccountDetailsByEmail(email string) (*Account, error) {
 //   - Returns an error if no account matches the specified URL.
 //   - Returns an error if multiple accounts match the specified URL.
 //   - Returns an error if there is an issue retrieving the account details.
-func (cli *OpCLI) GetAccountDetailsByURL(url string) (*Account, error) {
-	slog.Debug("retrieving 1Password account details by URL", "url", url)
+func (cli *OpCLI) GetAccountDetailsByURL(ctx context.Context, url string) (*Account, error) {
+	cli.log().Debug("retrieving 1Password account details by URL", "url", url)
 
-	accounts, err := cli.GetAccountDetails()
+	accounts, err := cli.GetAccountDetails(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -212,7 +306,7 @@ func (cli *OpCLI) GetAccountDetailsByURL(url string) (*Account, error) {
 		return &matchingAccounts[0], nil
 	}
 
-	return nil, fmt.Errorf("account with URL %s not found", url)
+	return nil, fmt.Errorf("%w: URL %s", ErrAccountNotFound, url)
 }
 
 // GetAccountDetailsByAccountUUID retrieves the details of a 1Password account
@@ -223,16 +317,17 @@ func (cli *OpCLI) GetAccountDetailsByURL(url string) (*Account, error) {
 // is found, it returns the account details. If no match is found, an error is returned.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - accountUUID: A string representing the unique identifier of the account.
 //
 // Returns:
 //   - *Account: A pointer to the Account struct containing the account details, if found.
 //   - error: An error if the account with the specified UUID is not found or if there
 //     is an issue retrieving the account details.
-func (cli *OpCLI) GetAccountDetailsByAccountUUID(accountUUID string) (*Account, error) {
-	slog.Debug("retrieving 1Password account details by account UUID", "accountUUID", accountUUID)
+func (cli *OpCLI) GetAccountDetailsByAccountUUID(ctx context.Context, accountUUID string) (*Account, error) {
+	cli.log().Debug("retrieving 1Password account details by account UUID", "accountUUID", accountUUID)
 
-	accounts, err := cli.GetAccountDetails()
+	accounts, err := cli.GetAccountDetails(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -243,7 +338,201 @@ func (cli *OpCLI) GetAccountDetailsByAccountUUID(accountUUID string) (*Account,
 		}
 	}
 
-	return nil, fmt.Errorf("account with UUID %s not found", accountUUID)
+	return nil, fmt.Errorf("%w: UUID %s", ErrAccountNotFound, accountUUID)
+}
+
+// AddAccountOptions holds the details required to add a new account to the
+// 1Password CLI with "op account add".
+//
+// Fields:
+//   - Address: The sign-in address of the account, e.g. my.1password.com.
+//   - Email: The email address of the user.
+//   - SecretKey: The Secret Key of the user.
+//   - Shorthand: An optional shorthand for the account.
+//   - Password: The account password. If empty, it is requested from the CredentialProvider.
+//   - SignIn: Sign in to the account immediately after adding it.
+type AddAccountOptions struct {
+	Address   string
+	Email     string
+	SecretKey string
+	Shorthand string
+	Password  string
+	SignIn    bool
+}
+
+// AddAccount adds a new account to the 1Password CLI, which allows the
+// package to bootstrap a machine that has never signed in before.
+// The password is piped to the command's stdin. If no password is given in
+// the options, it is requested from the configured CredentialProvider.
+//
+// If SignIn is set, the returned account is signed in and becomes the active
+// account of the OpCLI instance.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: The AddAccountOptions describing the account to add.
+//
+// Returns:
+//   - *Account: A pointer to the added account.
+//   - error: An error if required options are missing, the command fails or
+//     the added account cannot be found afterwards.
+func (cli *OpCLI) AddAccount(ctx context.Context, opts AddAccountOptions) (*Account, error) {
+	if opts.Address == "" || opts.Email == "" || opts.SecretKey == "" {
+		return nil, fmt.Errorf("address, email and secret key are required")
+	}
+
+	cli.log().Debug("adding 1Password account", "address", opts.Address, "email", opts.Email)
+
+	args := []string{"account", "add",
+		"--address", opts.Address,
+		"--email", opts.Email,
+		"--secret-key", opts.SecretKey,
+	}
+	if opts.Shorthand != "" {
+		args = append(args, "--shorthand", opts.Shorthand)
+	}
+	if opts.SignIn {
+		args = append(args, "--signin", "--raw")
+	}
+
+	password := opts.Password
+	if password == "" {
+		var err error
+		password, err = cli.credentials().Password(ctx, &Account{URL: opts.Address, Email: opts.Email})
+		if err != nil {
+			return nil, fmt.Errorf("error reading password: %v", err)
+		}
+	}
+
+	cmd := cli.command(args...)
+	cmd.Stdin = strings.NewReader(password + "\n")
+	output, stderr, err := cli.run(ctx, cmd)
+	if err != nil {
+		return nil, &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		}
+	}
+
+	accounts, err := cli.RefreshAccountDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("account was added but could not be listed: %w", err)
+	}
+
+	var account *Account
+	for i := range accounts {
+		if accounts[i].Email == opts.Email && normalizeURL(accounts[i].URL) == normalizeURL(opts.Address) {
+			account = &accounts[i]
+			break
+		}
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account was added but could not be found: %s", opts.Address)
+	}
+
+	if opts.SignIn {
+		sessionToken := strings.TrimSpace(string(output))
+		if sessionToken == "" {
+			return nil, fmt.Errorf("no session token received from signin")
+		}
+
+		if err := cli.completeSignIn(account, sessionToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return account, nil
+}
+
+// ForgetAccount removes an account from the 1Password CLI configuration on
+// this machine using "op account forget". The session token of the account
+// is cleared, and if it is the active account of the OpCLI instance, the
+// account and the item cache are reset as well.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - account: A pointer to the Account to forget.
+//
+// Returns:
+//   - error: An error if the account is invalid or the command fails.
+func (cli *OpCLI) ForgetAccount(ctx context.Context, account *Account) error {
+	if account == nil || account.UserUUID == "" {
+		return ErrMissingAccount
+	}
+
+	cli.log().Debug("forgetting 1Password account", "account", account.UserUUID, "url", account.URL)
+
+	_, stderr, err := cli.run(ctx, cli.command("account", "forget", account.UserUUID))
+	if err != nil {
+		return &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		}
+	}
+
+	cli.invalidateAccountCache()
+
+	if cli.sessionStore != nil {
+		if err := cli.sessionStore.Delete(account); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return fmt.Errorf("failed to delete stored session token: %w", err)
+		}
+	}
+	account.SetSignInInfo("")
+	account.setSignInExpireDuration(0)
+
+	if cli.Account != nil && cli.Account.UserUUID == account.UserUUID {
+		cli.Account = nil
+		cli.cache = itemCache{items: make(map[string]*Item)}
+	}
+
+	return nil
+}
+
+// UseAccount switches the active account of the OpCLI instance, so that
+// subsequent commands run against it. A session held by the account, from
+// the environment or from the SessionStore is reused if the CLI still
+// accepts it; otherwise the account is signed in as by SignIn. If the
+// account is already active with a valid session, nothing is run.
+//
+// When the account changes, the cached items, vault, user and group lookups
+// and the detected account plan are discarded. The previous account stays
+// active if switching fails. UseAccount must not be called while other
+// commands of the instance are running.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - account: A pointer to the Account to use.
+//
+// Returns:
+//   - error: ErrMissingAccount if the account is invalid, or an error if the sign-in fails.
+//
+// Example usage:
+//
+//	for _, account := range accounts {
+//	    if err := cli.UseAccount(ctx, &account); err != nil {
+//	        log.Printf("Skipping %s: %v", account.URL, err)
+//	        continue
+//	    }
+//	    vaults, err := cli.GetVaultDetails(ctx)
+//	    // ...
+//	}
+func (cli *OpCLI) UseAccount(ctx context.Context, account *Account) error {
+	if account == nil || account.UserUUID == "" {
+		return ErrMissingAccount
+	}
+
+	previous := cli.Account
+	if previous == account && account.hasSession() && account.IsSessionValid() {
+		return nil
+	}
+
+	cli.log().Debug("switching account", "account", account.UserUUID)
+
+	if err := cli.SignIn(ctx, account); err != nil {
+		return fmt.Errorf("failed to switch to account %s: %w", account.UserUUID, err)
+	}
+
+	return nil
 }
 
 // normalizeURL standardizes URLs by removing protocols and trailing paths.
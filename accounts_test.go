@@ -0,0 +1,76 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUseAccount(t *testing.T) {
+	var commands, envs [][]string
+	cli := &OpCLI{Path: "op"}
+	cli.SetLogger(nil)
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		commands = append(commands, cmd.Args)
+		envs = append(envs, cmd.Env)
+		if slices.Contains(cmd.Env, "OP_SESSION_broken-uuid=invalid-token") {
+			return nil, []byte("[ERROR] invalid session token"), errors.New("exit status 1")
+		}
+		if cmd.Args[0] == "signin" {
+			return nil, []byte("[ERROR] no account found"), errors.New("exit status 1")
+		}
+		return []byte(`{"id":"abcdefghijklmnopqrstuvwxyz","name":"Private"}`), nil, nil
+	}))
+	cli.SetCacheTTL(CacheVaults, time.Minute)
+
+	first := &Account{UserUUID: "first-uuid"}
+	first.SetSignInInfo("first-token")
+	second := &Account{UserUUID: "second-uuid"}
+	second.SetSignInInfo("second-token")
+	broken := &Account{UserUUID: "broken-uuid"}
+	broken.SetSignInInfo("invalid-token")
+
+	ctx := context.Background()
+	if err := cli.UseAccount(ctx, first); err != nil {
+		t.Fatalf("UseAccount() error = %v", err)
+	}
+	if _, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz"); err != nil {
+		t.Fatalf("GetVaultDetailsByID() error = %v", err)
+	}
+
+	commands, envs = nil, nil
+	if err := cli.UseAccount(ctx, first); err != nil {
+		t.Fatalf("UseAccount() error = %v", err)
+	}
+	if len(commands) != 0 {
+		t.Errorf("UseAccount() with the active account ran %q; want no commands", commands)
+	}
+
+	if err := cli.UseAccount(ctx, second); err != nil {
+		t.Fatalf("UseAccount() error = %v", err)
+	}
+	if cli.Account != second || !slices.Contains(envs[0], "OP_SESSION_second-uuid=second-token") {
+		t.Errorf("UseAccount() = account %v after %q; want the second account with its session validated", cli.Account, commands)
+	}
+
+	commands = nil
+	if _, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz"); err != nil {
+		t.Fatalf("GetVaultDetailsByID() error = %v", err)
+	}
+	if len(commands) != 1 || !slices.Contains(commands[0], "second-uuid") {
+		t.Errorf("GetVaultDetailsByID() ran %q; want an uncached lookup in the second account", commands)
+	}
+
+	if err := cli.UseAccount(ctx, broken); err == nil {
+		t.Error("UseAccount() error = nil; want the sign-in error")
+	}
+	if cli.Account != second {
+		t.Errorf("Account = %v; want the previous account after a failed switch", cli.Account)
+	}
+
+	if err := cli.UseAccount(ctx, nil); !errors.Is(err, ErrMissingAccount) {
+		t.Errorf("UseAccount(nil) error = %v; want %v", err, ErrMissingAccount)
+	}
+}
@@ -0,0 +1,590 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// State describes the desired vaults and groups of an account. It is passed
+// to Plan or Apply, which compare it with the live account and derive the
+// changes necessary to reach it.
+//
+// Fields:
+//   - Vaults: The desired vaults, identified by name.
+//   - Groups: The desired groups, identified by name.
+type State struct {
+	Vaults []VaultState
+	Groups []GroupState
+}
+
+// VaultState describes a desired vault and its access.
+//
+// Fields:
+//   - Name: The name of the vault. The vault is created if no vault with this name exists.
+//   - Description: The description of the vault. An empty description leaves the existing one unchanged.
+//   - Icon: The icon of a created vault. Existing vaults keep their icon.
+//   - Users: The permissions of users, keyed by email or ID. If nil, the user access is not managed.
+//     Otherwise, users that are not listed lose their access to the vault, including the signed-in user.
+//   - Groups: The permissions of groups, keyed by name or ID. If nil, the group access is not managed.
+//     Otherwise, groups that are not listed lose their access, except for built-in groups.
+type VaultState struct {
+	Name        string
+	Description string
+	Icon        VaultIcon
+	Users       map[string][]Permission
+	Groups      map[string][]Permission
+}
+
+// GroupState describes a desired group and its members.
+//
+// Fields:
+//   - Name: The name of the group. The group is created if no group with this name exists.
+//   - Description: The description of the group. An empty description leaves the existing one unchanged.
+//   - Members: The emails or IDs of users with the member role.
+//   - Managers: The emails or IDs of users with the manager role.
+//     If both Members and Managers are nil, the membership is not managed. Otherwise, users that
+//     are not listed are removed from the group.
+type GroupState struct {
+	Name        string
+	Description string
+	Members     []string
+	Managers    []string
+}
+
+// ChangeAction describes the kind of a planned Change.
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeGrant  ChangeAction = "grant"
+	ChangeRevoke ChangeAction = "revoke"
+)
+
+// Change is a single step of a Plan.
+//
+// Fields:
+//   - Action: The kind of the change.
+//   - Resource: The type of the changed resource, e.g. "vault", "group", "group member" or "vault user".
+//   - Target: The changed resource, e.g. the vault name or "Engineering/jane@example.com".
+//   - Details: A description of the change, e.g. the granted permissions or the new role.
+type Change struct {
+	Action   ChangeAction
+	Resource string
+	Target   string
+	Details  string
+
+	apply func(ctx context.Context) error
+}
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	if c.Details == "" {
+		return fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Target)
+	}
+	return fmt.Sprintf("%s %s %s: %s", c.Action, c.Resource, c.Target, c.Details)
+}
+
+// Plan is the ordered list of changes that reconcile an account with a
+// desired State. It is created by OpCLI.Plan and executed by Plan.Apply.
+//
+// Fields:
+//   - Changes: The changes in the order they are applied.
+type Plan struct {
+	Changes []Change
+}
+
+// IsEmpty reports whether the account already matches the desired state.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns the changes of the plan, one per line.
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "no changes"
+	}
+
+	var lines []string
+	for _, change := range p.Changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Apply executes the changes of the plan in order. It stops at the first
+// failing change, as later changes may depend on it, e.g. grants on a created
+// vault.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - error: An error naming the failed change, or nil if all changes were applied.
+func (p *Plan) Apply(ctx context.Context) error {
+	for i, change := range p.Changes {
+		if err := change.apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s (%d of %d changes applied): %w", change, i, len(p.Changes), err)
+		}
+	}
+
+	return nil
+}
+
+// Plan compares the desired state with the live account and returns the
+// changes necessary to reach it, without changing anything. Groups and
+// their memberships are planned before vaults, so vault grants can refer to
+// created groups. Access that the CLI grants implicitly when a vault is
+// created, e.g. to its creator, is only revoked by a later plan.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - desired: The desired state of the account.
+//
+// Returns:
+//   - *Plan: The changes to apply. The plan is empty if the account matches the desired state.
+//   - error: An error if the state is invalid or the account cannot be read.
+//
+// Example usage:
+//
+//	plan, err := cli.Plan(ctx, onepassword.State{
+//	    Vaults: []onepassword.VaultState{{
+//	        Name:   "Engineering",
+//	        Groups: map[string][]onepassword.Permission{"Developers": onepassword.RoleEditor.Permissions()},
+//	    }},
+//	})
+//	if err != nil {
+//	    log.Fatalf("Failed to plan changes: %v", err)
+//	}
+//	fmt.Println(plan)
+func (cli *OpCLI) Plan(ctx context.Context, desired State) (*Plan, error) {
+	planner := &planner{cli: cli, plan: &Plan{}}
+
+	if err := planner.load(ctx, desired); err != nil {
+		return nil, err
+	}
+
+	for _, group := range desired.Groups {
+		if err := planner.planGroup(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, vault := range desired.Vaults {
+		if err := planner.planVault(ctx, vault); err != nil {
+			return nil, err
+		}
+	}
+
+	return planner.plan, nil
+}
+
+// Apply reconciles the account with the desired state. It is equivalent to
+// calling Plan and applying the returned plan.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - desired: The desired state of the account.
+//
+// Returns:
+//   - *Plan: The planned changes, also if applying them failed.
+//   - error: An error if planning or applying a change fails.
+func (cli *OpCLI) Apply(ctx context.Context, desired State) (*Plan, error) {
+	plan, err := cli.Plan(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, plan.Apply(ctx)
+}
+
+// planner holds the live state of the account while a Plan is created.
+type planner struct {
+	cli  *OpCLI
+	plan *Plan
+
+	users  []User
+	groups map[string]*Group
+	vaults map[string]*Vault
+}
+
+// load validates the desired state and reads the users, groups and vaults of the account.
+func (p *planner) load(ctx context.Context, desired State) error {
+	if err := validateStateNames("vault", desired.Vaults, func(v VaultState) string { return v.Name }); err != nil {
+		return err
+	}
+	if err := validateStateNames("group", desired.Groups, func(g GroupState) string { return g.Name }); err != nil {
+		return err
+	}
+
+	users, err := p.cli.ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	p.users = users
+
+	groups, err := p.cli.ListGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+	p.groups = make(map[string]*Group)
+	for i := range groups {
+		addByName(p.groups, groups[i].Name, &groups[i])
+	}
+
+	vaults, err := p.cli.GetVaultDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vaults: %w", err)
+	}
+	p.vaults = make(map[string]*Vault)
+	for i := range *vaults {
+		addByName(p.vaults, (*vaults)[i].Name, &(*vaults)[i])
+	}
+
+	return nil
+}
+
+// validateStateNames checks that every desired resource has a unique name.
+func validateStateNames[T any](kind string, resources []T, name func(T) string) error {
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		n := name(resource)
+		if n == "" {
+			return fmt.Errorf("invalid state: %s name cannot be empty", kind)
+		}
+		if seen[n] {
+			return fmt.Errorf("invalid state: %s %q is declared more than once", kind, n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// addByName indexes a live resource by name. Ambiguous names are stored as
+// nil, so they are only reported if the desired state refers to them.
+func addByName[T any](index map[string]*T, name string, resource *T) {
+	if _, exists := index[name]; exists {
+		index[name] = nil
+		return
+	}
+	index[name] = resource
+}
+
+// lookup returns the live resource with the given name, or nil if it does not exist.
+func lookup[T any](index map[string]*T, kind, name string) (*T, error) {
+	resource, exists := index[name]
+	if exists && resource == nil {
+		return nil, fmt.Errorf("%s %q: %w", kind, name, ErrMoreThanOneMatch)
+	}
+	return resource, nil
+}
+
+// findUser returns the user with the given email or ID.
+func (p *planner) findUser(identifier string) (*User, error) {
+	for i := range p.users {
+		if p.users[i].ID == identifier || strings.EqualFold(p.users[i].Email, identifier) {
+			return &p.users[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, identifier)
+}
+
+// findGroup returns the live or planned group with the given name or ID.
+func (p *planner) findGroup(identifier string) (*Group, error) {
+	group, err := lookup(p.groups, "group", identifier)
+	if err != nil || group != nil {
+		return group, err
+	}
+	for _, group := range p.groups {
+		if group != nil && group.ID != "" && group.ID == identifier {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, identifier)
+}
+
+// add appends a change to the plan.
+func (p *planner) add(action ChangeAction, resource, target, details string, apply func(ctx context.Context) error) {
+	p.plan.Changes = append(p.plan.Changes, Change{
+		Action:   action,
+		Resource: resource,
+		Target:   target,
+		Details:  details,
+		apply:    apply,
+	})
+}
+
+// planGroup plans the creation, update and membership changes of a group.
+func (p *planner) planGroup(ctx context.Context, desired GroupState) error {
+	group, err := lookup(p.groups, "group", desired.Name)
+	if err != nil {
+		return err
+	}
+
+	var members []GroupMember
+	if group == nil {
+		// The ID of the group is set once it is created
+		group = &Group{cli: p.cli, Name: desired.Name, Description: desired.Description}
+		p.groups[desired.Name] = group
+		p.add(ChangeCreate, "group", desired.Name, "", func(ctx context.Context) error {
+			created, err := p.cli.CreateGroup(ctx, desired.Name, desired.Description)
+			if err != nil {
+				return err
+			}
+			*group = *created
+			return nil
+		})
+	} else {
+		if desired.Description != "" && desired.Description != group.Description {
+			p.add(ChangeUpdate, "group", desired.Name, "description", func(ctx context.Context) error {
+				return group.SetDescription(ctx, desired.Description)
+			})
+		}
+
+		if desired.Members != nil || desired.Managers != nil {
+			members, err = group.ListMembers(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list members of group %s: %w", desired.Name, err)
+			}
+		}
+	}
+
+	if desired.Members == nil && desired.Managers == nil {
+		return nil
+	}
+
+	current := make(map[string]GroupRole)
+	for _, member := range members {
+		current[member.ID] = member.Role
+	}
+
+	wanted := make(map[string]GroupRole)
+	var order []*User
+	for _, roles := range []struct {
+		identifiers []string
+		role        GroupRole
+	}{
+		{desired.Members, GroupRoleMember},
+		{desired.Managers, GroupRoleManager},
+	} {
+		for _, identifier := range roles.identifiers {
+			user, err := p.findUser(identifier)
+			if err != nil {
+				return fmt.Errorf("invalid state for group %s: %w", desired.Name, err)
+			}
+			if _, exists := wanted[user.ID]; !exists {
+				order = append(order, user)
+			}
+			// Managers take precedence over members
+			if wanted[user.ID] != GroupRoleManager {
+				wanted[user.ID] = roles.role
+			}
+		}
+	}
+
+	for _, user := range order {
+		role := wanted[user.ID]
+		currentRole, isMember := current[user.ID]
+		if isMember && currentRole == role {
+			continue
+		}
+
+		action := ChangeGrant
+		if isMember {
+			action = ChangeUpdate
+		}
+
+		user := *user
+		p.add(action, "group member", desired.Name+"/"+user.Email, strings.ToLower(string(role)), func(ctx context.Context) error {
+			if role == GroupRoleManager {
+				return group.AddManager(ctx, user)
+			}
+			return group.AddMember(ctx, user)
+		})
+	}
+
+	for _, member := range members {
+		if _, exists := wanted[member.ID]; exists {
+			continue
+		}
+
+		user := member.User
+		p.add(ChangeRevoke, "group member", desired.Name+"/"+user.Email, "", func(ctx context.Context) error {
+			return group.RemoveMember(ctx, user)
+		})
+	}
+
+	return nil
+}
+
+// planVault plans the creation, update and access changes of a vault.
+func (p *planner) planVault(ctx context.Context, desired VaultState) error {
+	vault, err := lookup(p.vaults, "vault", desired.Name)
+	if err != nil {
+		return err
+	}
+
+	var users []User
+	var groups []Group
+	if vault == nil {
+		// The ID of the vault is set once it is created
+		vault = &Vault{cli: p.cli, Name: desired.Name}
+		p.vaults[desired.Name] = vault
+
+		icon := desired.Icon
+		if icon == "" {
+			icon = IconVaultDoor
+		}
+		p.add(ChangeCreate, "vault", desired.Name, "", func(ctx context.Context) error {
+			created, err := p.cli.CreateVault(ctx, desired.Name, desired.Description, icon, true)
+			if err != nil {
+				return err
+			}
+			*vault = *created
+			return nil
+		})
+	} else {
+		if desired.Description != "" {
+			details, err := p.cli.GetVaultDetailsByID(ctx, vault.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get vault %s: %w", desired.Name, err)
+			}
+			if details.Description != desired.Description {
+				p.add(ChangeUpdate, "vault", desired.Name, "description", func(ctx context.Context) error {
+					return vault.SetDescription(ctx, desired.Description)
+				})
+			}
+		}
+
+		if desired.Users != nil {
+			if users, err = vault.ListUsers(ctx); err != nil {
+				return err
+			}
+		}
+		if desired.Groups != nil {
+			if groups, err = vault.ListGroups(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if desired.Users != nil {
+		current := make(map[string][]Permission)
+		for _, user := range users {
+			current[user.ID] = user.Permissions
+		}
+
+		for _, identifier := range sortedKeys(desired.Users) {
+			user, err := p.findUser(identifier)
+			if err != nil {
+				return fmt.Errorf("invalid state for vault %s: %w", desired.Name, err)
+			}
+			p.planGrant(vault, "user", user.ID, user.Email, current[user.ID], desired.Users[identifier])
+			delete(current, user.ID)
+		}
+
+		for _, user := range users {
+			if permissions, exists := current[user.ID]; exists {
+				p.planGrant(vault, "user", user.ID, user.Email, permissions, nil)
+			}
+		}
+	}
+
+	if desired.Groups != nil {
+		current := make(map[string][]Permission)
+		for _, group := range groups {
+			current[group.ID] = group.Permissions
+		}
+
+		for _, identifier := range sortedKeys(desired.Groups) {
+			group, err := p.findGroup(identifier)
+			if err != nil {
+				return fmt.Errorf("invalid state for vault %s: %w", desired.Name, err)
+			}
+			p.planGroupGrant(vault, group, current[group.ID], desired.Groups[identifier])
+			if group.ID != "" {
+				delete(current, group.ID)
+			}
+		}
+
+		for i := range groups {
+			permissions, exists := current[groups[i].ID]
+			if !exists || groups[i].IsBuiltIn() {
+				continue
+			}
+			p.planGrant(vault, "group", groups[i].ID, groups[i].Name, permissions, nil)
+		}
+	}
+
+	return nil
+}
+
+// planGroupGrant plans the access of a group, which may be created by the plan.
+func (p *planner) planGroupGrant(vault *Vault, group *Group, current, desired []Permission) {
+	if group.ID != "" {
+		p.planGrant(vault, "group", group.ID, group.Name, current, desired)
+		return
+	}
+
+	// The group is created by the plan, so its ID is resolved when the change is applied
+	grants, _ := diffPermissions(current, p.cli.ResolvePermissions(desired...))
+	if len(grants) == 0 {
+		return
+	}
+	p.add(ChangeGrant, "vault group", vault.Name+"/"+group.Name, FormatPermissions(grants), func(ctx context.Context) error {
+		return vault.GrantPermissions(ctx, []PermissionGrant{{Group: group, Permissions: grants}})[0].Err
+	})
+}
+
+// planGrant plans the grants and revokes that change the current permissions
+// of a user or group to the desired ones.
+func (p *planner) planGrant(vault *Vault, kind, id, name string, current, desired []Permission) {
+	grants, revokes := diffPermissions(current, p.cli.ResolvePermissions(desired...))
+	target := vault.Name + "/" + name
+
+	if len(grants) > 0 {
+		grant := PermissionGrant{Permissions: grants}
+		if kind == "user" {
+			grant.User = &User{ID: id}
+		} else {
+			grant.Group = &Group{ID: id}
+		}
+		p.add(ChangeGrant, "vault "+kind, target, FormatPermissions(grants), func(ctx context.Context) error {
+			return vault.GrantPermissions(ctx, []PermissionGrant{grant})[0].Err
+		})
+	}
+
+	if len(revokes) > 0 {
+		p.add(ChangeRevoke, "vault "+kind, target, FormatPermissions(revokes), func(ctx context.Context) error {
+			return vault.revokePermissions(ctx, kind, id, revokes)
+		})
+	}
+}
+
+// diffPermissions returns the permissions that are missing from current and
+// the permissions of current that are not desired.
+func diffPermissions(current, desired []Permission) (grants, revokes []Permission) {
+	for _, permission := range desired {
+		if !slices.Contains(current, permission) {
+			grants = append(grants, permission)
+		}
+	}
+	for _, permission := range current {
+		if !slices.Contains(desired, permission) {
+			revokes = append(revokes, permission)
+		}
+	}
+	return grants, revokes
+}
+
+// sortedKeys returns the keys of a map in sorted order, so plans are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         []Permission
+		desired         []Permission
+		expectedGrants  []Permission
+		expectedRevokes []Permission
+	}{
+		{
+			name:           "No access",
+			desired:        []Permission{PermissionViewItems},
+			expectedGrants: []Permission{PermissionViewItems},
+		},
+		{
+			name:    "Unchanged",
+			current: []Permission{PermissionViewItems, PermissionEditItems},
+			desired: []Permission{PermissionEditItems, PermissionViewItems},
+		},
+		{
+			name:            "Changed",
+			current:         []Permission{PermissionViewItems, PermissionEditItems},
+			desired:         []Permission{PermissionViewItems, PermissionCreateItems},
+			expectedGrants:  []Permission{PermissionCreateItems},
+			expectedRevokes: []Permission{PermissionEditItems},
+		},
+		{
+			name:            "Access removed",
+			current:         []Permission{PermissionViewItems},
+			expectedRevokes: []Permission{PermissionViewItems},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants, revokes := diffPermissions(tt.current, tt.desired)
+			if !slices.Equal(grants, tt.expectedGrants) || !slices.Equal(revokes, tt.expectedRevokes) {
+				t.Errorf("diffPermissions() = %v, %v; want %v, %v", grants, revokes, tt.expectedGrants, tt.expectedRevokes)
+			}
+		})
+	}
+}
+
+func TestPlanInvalidState(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return []byte(`[]`), nil, nil
+	}))
+
+	tests := []struct {
+		name     string
+		state    State
+		expected string
+	}{
+		{
+			name:     "Duplicate vault",
+			state:    State{Vaults: []VaultState{{Name: "Engineering"}, {Name: "Engineering"}}},
+			expected: "declared more than once",
+		},
+		{
+			name:     "Unnamed group",
+			state:    State{Groups: []GroupState{{}}},
+			expected: "group name cannot be empty",
+		},
+		{
+			name: "Unknown user",
+			state: State{Vaults: []VaultState{{
+				Name:  "Engineering",
+				Users: map[string][]Permission{"jane@example.com": {PermissionViewItems}},
+			}}},
+			expected: "user not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cli.Plan(context.Background(), tt.state)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Plan() error = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
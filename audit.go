@@ -0,0 +1,238 @@
+package onepassword
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// AccessPath describes how a user obtained access to a vault.
+type AccessPath string
+
+const (
+	AccessDirect AccessPath = "direct" // Granted to the user.
+	AccessGroup  AccessPath = "group"  // Granted to a group the user is a member of.
+)
+
+// AuditGroup is a group with its members.
+//
+// Fields:
+//   - Group: The group.
+//   - Members: The members of the group with their roles.
+type AuditGroup struct {
+	Group   Group         `json:"group"`
+	Members []GroupMember `json:"members"`
+}
+
+// AuditAccess is the access of a user to a vault through one path. A user
+// who is granted access directly and through groups has one entry per path.
+//
+// Fields:
+//   - User: The user.
+//   - Vault: The vault, without permissions.
+//   - Permissions: The permissions granted through the path.
+//   - Path: Whether the permissions were granted directly or through a group.
+//   - Group: The group the permissions were granted to. Only set for AccessGroup.
+type AuditAccess struct {
+	User        User         `json:"user"`
+	Vault       Vault        `json:"vault"`
+	Permissions []Permission `json:"permissions"`
+	Path        AccessPath   `json:"path"`
+	Group       *Group       `json:"group,omitempty"`
+}
+
+// AuditReport is a snapshot of who can access which vault, with which
+// permissions, and through which path.
+//
+// Fields:
+//   - GeneratedAt: The time the report was generated.
+//   - Users: All users of the account, including service accounts.
+//   - Groups: All groups of the account with their members.
+//   - Access: The access of users to user-created vaults, sorted by user, vault and path.
+//   - ServiceAccountLimits: The rate limits of the service account the report was generated with.
+//     Only set if the OpCLI instance is signed in as a service account.
+type AuditReport struct {
+	GeneratedAt          time.Time                 `json:"generated_at"`
+	Users                []User                    `json:"users"`
+	Groups               []AuditGroup              `json:"groups"`
+	Access               []AuditAccess             `json:"access"`
+	ServiceAccountLimits []ServiceAccountRateLimit `json:"service_account_limits,omitempty"`
+}
+
+// GenerateAuditReport composes the users, groups, group memberships and
+// vault grants of the account into an AuditReport. Grants to groups are
+// expanded to their members. Personal and other vaults that are not user
+// created are not included, because their access cannot be listed.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - *AuditReport: The report.
+//   - error: An error if users, groups, members or grants cannot be listed.
+//
+// Example usage:
+//
+//	report, err := cli.GenerateAuditReport(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to generate audit report: %v", err)
+//	}
+//	err = report.WriteCSV(os.Stdout)
+func (cli *OpCLI) GenerateAuditReport(ctx context.Context) (*AuditReport, error) {
+	report := &AuditReport{GeneratedAt: time.Now().UTC()}
+
+	users, err := cli.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	report.Users = users
+
+	groups, err := cli.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	members := make(map[string][]GroupMember, len(groups))
+	for _, group := range groups {
+		groupMembers, err := group.ListMembers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of group %s: %w", group.Name, err)
+		}
+		members[group.ID] = groupMembers
+		report.Groups = append(report.Groups, AuditGroup{Group: group, Members: groupMembers})
+	}
+
+	vaults, err := cli.GetVaultDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vaults: %w", err)
+	}
+	for _, vault := range *vaults {
+		if !isUserCreatedVault(vault) {
+			continue
+		}
+
+		access, err := auditVault(ctx, vault, members)
+		if err != nil {
+			return nil, fmt.Errorf("failed to audit vault %s: %w", vault.Name, err)
+		}
+		report.Access = append(report.Access, access...)
+	}
+
+	slices.SortStableFunc(report.Access, func(a, b AuditAccess) int {
+		return cmp.Or(
+			cmp.Compare(a.User.Email, b.User.Email),
+			cmp.Compare(a.Vault.Name, b.Vault.Name),
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(a.groupName(), b.groupName()),
+		)
+	})
+
+	if cli.isServiceAccount {
+		if report.ServiceAccountLimits, err = cli.GetServiceAccountRateLimits(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get service account limits: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// auditVault lists the direct and group access to a vault.
+func auditVault(ctx context.Context, vault Vault, members map[string][]GroupMember) ([]AuditAccess, error) {
+	users, err := vault.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := vault.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var access []AuditAccess
+	vault.cli, vault.Permissions = nil, nil
+	for _, user := range users {
+		permissions := user.Permissions
+		user.Permissions = nil
+		access = append(access, AuditAccess{User: user, Vault: vault, Permissions: permissions, Path: AccessDirect})
+	}
+
+	for _, group := range groups {
+		permissions := group.Permissions
+		group.Permissions = nil
+		for _, member := range members[group.ID] {
+			access = append(access, AuditAccess{
+				User:        member.User,
+				Vault:       vault,
+				Permissions: permissions,
+				Path:        AccessGroup,
+				Group:       &group,
+			})
+		}
+	}
+
+	return access, nil
+}
+
+// groupName returns the name of the group of a group access.
+func (a AuditAccess) groupName() string {
+	if a.Group == nil {
+		return ""
+	}
+	return a.Group.Name
+}
+
+// WriteJSON writes the complete report as indented JSON.
+//
+// Parameters:
+//   - w: The writer the report is written to.
+//
+// Returns:
+//   - error: An error if the report cannot be written.
+func (r *AuditReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write audit report: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the access entries of the report as CSV with a header row,
+// one row per user, vault and path. Permissions are separated by commas.
+//
+// Parameters:
+//   - w: The writer the report is written to.
+//
+// Returns:
+//   - error: An error if the report cannot be written.
+func (r *AuditReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"user_email", "user_name", "user_type", "user_state", "vault", "path", "group", "permissions"}); err != nil {
+		return fmt.Errorf("failed to write audit report: %w", err)
+	}
+
+	for _, access := range r.Access {
+		err := writer.Write([]string{
+			access.User.Email,
+			access.User.Name,
+			string(access.User.Type),
+			string(access.User.State),
+			access.Vault.Name,
+			string(access.Path),
+			access.groupName(),
+			FormatPermissions(access.Permissions),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write audit report: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write audit report: %w", err)
+	}
+	return nil
+}
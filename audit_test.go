@@ -0,0 +1,42 @@
+package onepassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestAuditReportRenderers(t *testing.T) {
+	jane := User{Name: "Jane Doe", Email: "jane@example.com", Type: UserTypeMember, State: UserStateActive}
+	developers := Group{Name: "Developers"}
+	report := &AuditReport{
+		Users: []User{jane},
+		Access: []AuditAccess{
+			{User: jane, Vault: Vault{Name: "Engineering"}, Permissions: []Permission{PermissionViewItems}, Path: AccessDirect},
+			{User: jane, Vault: Vault{Name: "Engineering"}, Permissions: []Permission{PermissionViewItems, PermissionEditItems}, Path: AccessGroup, Group: &developers},
+		},
+	}
+
+	var csvOutput bytes.Buffer
+	if err := report.WriteCSV(&csvOutput); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	expected := "user_email,user_name,user_type,user_state,vault,path,group,permissions\n" +
+		"jane@example.com,Jane Doe,MEMBER,ACTIVE,Engineering,direct,,view_items\n" +
+		"jane@example.com,Jane Doe,MEMBER,ACTIVE,Engineering,group,Developers,\"view_items,edit_items\"\n"
+	if csvOutput.String() != expected {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", csvOutput.String(), expected)
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := report.WriteJSON(&jsonOutput); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded AuditReport
+	if err := json.Unmarshal(jsonOutput.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if len(decoded.Access) != 2 || decoded.Access[1].Group == nil || decoded.Access[1].Group.Name != "Developers" {
+		t.Errorf("WriteJSON() access = %+v; want the report's access", decoded.Access)
+	}
+}
@@ -0,0 +1,287 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupportedByBackend is returned by operations of OpCLI that the
+// configured Backend cannot serve, e.g. looking up an item without its vault.
+var ErrUnsupportedByBackend = errors.New("operation not supported by the backend")
+
+// Backend provides access to vaults and items independent of how the data is
+// retrieved. It is implemented by CLIBackend, which runs the 1Password CLI,
+// and by ConnectBackend, which talks to a 1Password Connect server over HTTP.
+//
+// Items and vaults returned by a Backend are plain values: changes to an item
+// are written back with UpdateItem of the same Backend.
+type Backend interface {
+	// ListVaults returns all vaults accessible to the backend.
+	ListVaults(ctx context.Context) ([]Vault, error)
+	// GetVault returns the vault with the given ID.
+	GetVault(ctx context.Context, vaultID string) (*Vault, error)
+	// ListItems returns the items of a vault. Depending on the backend, the
+	// returned items may not include their fields.
+	ListItems(ctx context.Context, vaultID string) ([]Item, error)
+	// GetItem returns an item including all fields.
+	GetItem(ctx context.Context, vaultID, itemID string) (*Item, error)
+	// CreateItem creates an item in the vault referenced by item.Vault.ID.
+	CreateItem(ctx context.Context, item *Item) (*Item, error)
+	// UpdateItem replaces an existing item.
+	UpdateItem(ctx context.Context, item *Item) (*Item, error)
+	// DeleteItem deletes an item.
+	DeleteItem(ctx context.Context, vaultID, itemID string) error
+}
+
+var (
+	_ Backend = (*CLIBackend)(nil)
+	_ Backend = (*ConnectBackend)(nil)
+)
+
+// SetBackend sets the Backend that serves the vault and item operations of
+// the OpCLI instance: GetVaultDetails, GetVaultDetailsByID,
+// GetVaultDetailsByName, GetItemsByVault, CreateItem, Item.Save and
+// Item.Delete. GetItemByID and GetItemByName return ErrUnsupportedByBackend,
+// as a Backend can only look up items within a vault, and so do
+// CreateItemPreview and CreateItem with password generation. All other
+// operations still run the 1Password CLI. If backend is nil, the CLI serves
+// every operation.
+//
+// Operations of the Backend are described to execution hooks, metrics and
+// tracing like the equivalent command of the 1Password CLI, e.g. "item edit",
+// and operations that change data are skipped in dry-run mode.
+//
+// Parameters:
+//   - backend: The Backend to use, e.g. a ConnectBackend.
+func (cli *OpCLI) SetBackend(backend Backend) {
+	if b, ok := backend.(*CLIBackend); ok && b.cli == cli {
+		// The instance serves the operations itself
+		backend = nil
+	}
+	cli.backend = backend
+}
+
+// runBackend calls fn, which runs an operation of the configured Backend,
+// like run runs a command: args describe the operation as the equivalent
+// command of the 1Password CLI, so execution hooks, metrics, tracing and
+// dry-run mode apply to it. Before hooks may reject the operation, but their
+// rewritten arguments have no effect. It reports whether the operation was
+// skipped in dry-run mode.
+func (cli *OpCLI) runBackend(ctx context.Context, args []string, fn func(ctx context.Context) error) (bool, error) {
+	cmd := &Command{Args: args}
+	info, err := cli.runBeforeHooks(ctx, cmd)
+	if err != nil {
+		cli.runAfterHooks(ctx, info, CommandResult{}, err)
+		return false, err
+	}
+
+	if output, skipped := cli.skipForDryRun(ctx, cmd, info); skipped {
+		cli.runAfterHooks(ctx, info, CommandResult{Stdout: output, DryRun: true}, nil)
+		return true, nil
+	}
+
+	ctx, span := cli.startSpan(ctx, info)
+
+	start := time.Now()
+	err = fn(ctx)
+	result := CommandResult{Duration: time.Since(start)}
+
+	endSpan(span, result, err)
+	cli.runAfterHooks(ctx, info, result, err)
+
+	return false, err
+}
+
+// backendVaults returns the vaults of the configured Backend.
+func (cli *OpCLI) backendVaults(ctx context.Context) (*[]Vault, error) {
+	var vaults []Vault
+	_, err := cli.runBackend(ctx, []string{"vault", "list"}, func(ctx context.Context) (err error) {
+		vaults, err = cli.backend.ListVaults(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range vaults {
+		vaults[i].cli = cli
+	}
+	return &vaults, nil
+}
+
+// backendVault returns a vault of the configured Backend by its ID.
+func (cli *OpCLI) backendVault(ctx context.Context, vaultID string) (*Vault, error) {
+	var vault *Vault
+	_, err := cli.runBackend(ctx, []string{"vault", "get", vaultID}, func(ctx context.Context) (err error) {
+		vault, err = cli.backend.GetVault(ctx, vaultID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	vault.cli = cli
+	return vault, nil
+}
+
+// backendVaultByName returns the vault of the configured Backend with the
+// given name.
+func (cli *OpCLI) backendVaultByName(ctx context.Context, name string) (*Vault, error) {
+	vaults, err := cli.backendVaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *Vault
+	for i := range *vaults {
+		if (*vaults)[i].Name != name {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("vault %q: %w", name, ErrMoreThanOneMatch)
+		}
+		match = &(*vaults)[i]
+	}
+	if match == nil {
+		return nil, fmt.Errorf("vault %q: %w", name, ErrNotFound)
+	}
+	return match, nil
+}
+
+// backendItems returns the items of a vault of the configured Backend.
+func (cli *OpCLI) backendItems(ctx context.Context, vaultID string) (*[]Item, error) {
+	var items []Item
+	_, err := cli.runBackend(ctx, []string{"item", "list", "--vault", vaultID}, func(ctx context.Context) (err error) {
+		items, err = cli.backend.ListItems(ctx, vaultID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].cli = cli
+	}
+	return &items, nil
+}
+
+// backendCreateItem creates an item with the configured Backend. In dry-run
+// mode, an empty item is returned like for the placeholder output of the CLI.
+func (cli *OpCLI) backendCreateItem(ctx context.Context, item *Item) (*Item, error) {
+	var createdItem *Item
+	skipped, err := cli.runBackend(ctx, []string{"item", "create", "--vault", item.Vault.ID}, func(ctx context.Context) (err error) {
+		createdItem, err = cli.backend.CreateItem(ctx, item)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if skipped {
+		createdItem = &Item{}
+	}
+	createdItem.cli = cli
+	return createdItem, nil
+}
+
+// backendUpdateItem replaces an item with the configured Backend.
+func (cli *OpCLI) backendUpdateItem(ctx context.Context, item *Item) error {
+	_, err := cli.runBackend(ctx, []string{"item", "edit", item.ID, "--vault", item.Vault.ID}, func(ctx context.Context) error {
+		_, err := cli.backend.UpdateItem(ctx, item)
+		return err
+	})
+	return err
+}
+
+// backendDeleteItem deletes an item with the configured Backend.
+func (cli *OpCLI) backendDeleteItem(ctx context.Context, item *Item) error {
+	_, err := cli.runBackend(ctx, []string{"item", "delete", item.ID, "--vault", item.Vault.ID}, func(ctx context.Context) error {
+		return cli.backend.DeleteItem(ctx, item.Vault.ID, item.ID)
+	})
+	return err
+}
+
+// CLIBackend is a Backend that runs the 1Password CLI through an OpCLI instance.
+type CLIBackend struct {
+	cli *OpCLI
+}
+
+// NewCLIBackend creates a Backend that uses the given signed-in OpCLI instance.
+//
+// Parameters:
+//   - cli: The OpCLI instance used to run commands.
+//
+// Returns:
+//   - *CLIBackend: The CLI backed Backend.
+func NewCLIBackend(cli *OpCLI) *CLIBackend {
+	return &CLIBackend{cli: cli}
+}
+
+// ListVaults returns all vaults accessible to the signed-in account.
+func (b *CLIBackend) ListVaults(ctx context.Context) ([]Vault, error) {
+	vaults, err := b.cli.GetVaultDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return *vaults, nil
+}
+
+// GetVault returns the vault with the given ID.
+func (b *CLIBackend) GetVault(ctx context.Context, vaultID string) (*Vault, error) {
+	return b.cli.GetVaultDetailsByID(ctx, vaultID)
+}
+
+// ListItems returns the items of a vault without their fields.
+func (b *CLIBackend) ListItems(ctx context.Context, vaultID string) ([]Item, error) {
+	items, err := b.cli.GetItemsByVault(ctx, Vault{ID: vaultID})
+	if err != nil {
+		return nil, err
+	}
+	return *items, nil
+}
+
+// GetItem returns an item including all fields.
+func (b *CLIBackend) GetItem(ctx context.Context, vaultID, itemID string) (*Item, error) {
+	output, err := b.cli.ExecuteOpCommand(ctx, "item", "get", itemID, "--vault", vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Item
+	if err := b.cli.unmarshalItem(output, &item); err != nil {
+		return nil, err
+	}
+	item.cli = b.cli
+
+	return &item, nil
+}
+
+// CreateItem creates an item in the vault referenced by item.Vault.ID.
+func (b *CLIBackend) CreateItem(ctx context.Context, item *Item) (*Item, error) {
+	createdItem, err := b.cli.CreateItem(ctx, item, false)
+	if err != nil {
+		return nil, err
+	}
+	createdItem.cli = b.cli
+
+	return createdItem, nil
+}
+
+// UpdateItem replaces an existing item.
+func (b *CLIBackend) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item.ID == "" {
+		return nil, fmt.Errorf("item ID is empty, cannot update item")
+	}
+
+	updatedItem, err := b.cli.updateItemWithStruct(ctx, *item)
+	if err != nil {
+		return nil, err
+	}
+	updatedItem.cli = b.cli
+
+	return updatedItem, nil
+}
+
+// DeleteItem deletes an item.
+func (b *CLIBackend) DeleteItem(ctx context.Context, vaultID, itemID string) error {
+	_, err := b.cli.ExecuteOpCommand(ctx, "item", "delete", itemID, "--vault", vaultID)
+	return err
+}
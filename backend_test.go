@@ -0,0 +1,186 @@
+package onepassword_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+	"github.com/sthayduk/onepassword-cli-go/onepasswordtest"
+)
+
+func TestCLIBackend(t *testing.T) {
+	ctx := context.Background()
+	fake := onepasswordtest.New()
+	vault := fake.AddVault("Private")
+	fake.AddItem(onepassword.Item{Title: "Existing", Category: onepassword.CategoryLogin, Vault: vault})
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	var backend onepassword.Backend = onepassword.NewCLIBackend(cli)
+
+	vaults, err := backend.ListVaults(ctx)
+	if err != nil || len(vaults) != 1 || vaults[0].Name != "Private" {
+		t.Fatalf("ListVaults() = %+v, %v; want Private", vaults, err)
+	}
+
+	created, err := backend.CreateItem(ctx, &onepassword.Item{Title: "New", Category: onepassword.CategoryPassword, Vault: vault})
+	if err != nil {
+		t.Fatalf("CreateItem() error = %v", err)
+	}
+
+	items, err := backend.ListItems(ctx, vault.ID)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("ListItems() = %d items, %v; want 2", len(items), err)
+	}
+
+	created.Title = "Renamed"
+	updated, err := backend.UpdateItem(ctx, created)
+	if err != nil || updated.Title != "Renamed" || updated.Version != 2 {
+		t.Fatalf("UpdateItem() = %+v, %v; want Renamed in version 2", updated, err)
+	}
+
+	if err := backend.DeleteItem(ctx, vault.ID, created.ID); err != nil {
+		t.Fatalf("DeleteItem() error = %v", err)
+	}
+	if _, err := backend.GetItem(ctx, vault.ID, created.ID); !errors.Is(err, onepassword.ErrNotFound) {
+		t.Errorf("GetItem() after delete error = %v; want ErrNotFound", err)
+	}
+}
+
+func TestOpCLIWithBackend(t *testing.T) {
+	ctx := context.Background()
+	fake := onepasswordtest.New()
+	vault := fake.AddVault("Private")
+	fake.AddItem(onepassword.Item{Title: "Existing", Category: onepassword.CategoryLogin, Vault: vault})
+
+	fakeCLI, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	// No 1Password CLI is needed for the operations served by the backend
+	t.Setenv("PATH", "")
+	cli, err := onepassword.NewOpCLI(onepassword.WithBackend(onepassword.NewCLIBackend(fakeCLI)))
+	if err != nil {
+		t.Fatalf("NewOpCLI(WithBackend) error = %v", err)
+	}
+
+	vaults, err := cli.GetVaultDetails(ctx)
+	if err != nil || len(*vaults) != 1 || (*vaults)[0].Name != "Private" {
+		t.Fatalf("GetVaultDetails() = %+v, %v; want Private", vaults, err)
+	}
+	byName, err := cli.GetVaultDetailsByName(ctx, "Private")
+	if err != nil || byName.ID != vault.ID {
+		t.Fatalf("GetVaultDetailsByName() = %+v, %v; want %s", byName, err, vault.ID)
+	}
+	if _, err := cli.GetVaultDetailsByName(ctx, "Shared"); !errors.Is(err, onepassword.ErrNotFound) {
+		t.Errorf("GetVaultDetailsByName() of a missing vault error = %v; want ErrNotFound", err)
+	}
+
+	created, err := cli.CreateItem(ctx, &onepassword.Item{Title: "New", Category: onepassword.CategoryPassword, Vault: vault}, false)
+	if err != nil {
+		t.Fatalf("CreateItem() error = %v", err)
+	}
+	if _, err := cli.CreateItem(ctx, &onepassword.Item{Title: "Generated", Category: onepassword.CategoryPassword, Vault: vault}, true); !errors.Is(err, onepassword.ErrUnsupportedByBackend) {
+		t.Errorf("CreateItem() with password generation error = %v; want ErrUnsupportedByBackend", err)
+	}
+
+	created.Title = "Renamed"
+	if err := created.Save(ctx); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	items, err := cli.GetItemsByVault(ctx, vault)
+	if err != nil || len(*items) != 2 {
+		t.Fatalf("GetItemsByVault() = %v, %v; want 2 items", items, err)
+	}
+	var renamed *onepassword.Item
+	for i := range *items {
+		if (*items)[i].ID == created.ID {
+			renamed = &(*items)[i]
+		}
+	}
+	if renamed == nil || renamed.Title != "Renamed" {
+		t.Fatalf("GetItemsByVault() = %+v; want the renamed item", *items)
+	}
+
+	if err := renamed.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := fakeCLI.GetItemByID(ctx, created.ID); !errors.Is(err, onepassword.ErrNotFound) {
+		t.Errorf("GetItemByID() after delete error = %v; want ErrNotFound", err)
+	}
+
+	if _, err := cli.GetItemByID(ctx, created.ID); !errors.Is(err, onepassword.ErrUnsupportedByBackend) {
+		t.Errorf("GetItemByID() error = %v; want ErrUnsupportedByBackend", err)
+	}
+}
+
+func TestOpCLIWithBackendDryRun(t *testing.T) {
+	ctx := context.Background()
+	fake := onepasswordtest.New()
+	vault := fake.AddVault("Private")
+	fake.AddItem(onepassword.Item{Title: "Existing", Category: onepassword.CategoryLogin, Vault: vault})
+
+	fakeCLI, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	var names []string
+	cli, err := onepassword.NewOpCLI(
+		onepassword.WithBackend(onepassword.NewCLIBackend(fakeCLI)),
+		onepassword.WithDryRun(),
+		onepassword.WithLogger(nil),
+		onepassword.WithAfterExec(func(ctx context.Context, info onepassword.CommandInfo, result onepassword.CommandResult, err error) {
+			names = append(names, fmt.Sprintf("%s dry-run=%t", info.Name, result.DryRun))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewOpCLI(WithBackend) error = %v", err)
+	}
+
+	items, err := cli.GetItemsByVault(ctx, vault)
+	if err != nil || len(*items) != 1 {
+		t.Fatalf("GetItemsByVault() = %v, %v; want 1 item", items, err)
+	}
+	existing := (*items)[0]
+
+	if _, err := cli.CreateItem(ctx, &onepassword.Item{Title: "New", Category: onepassword.CategoryPassword, Vault: vault}, false); err != nil {
+		t.Fatalf("CreateItem() error = %v", err)
+	}
+	existing.Title = "Renamed"
+	if err := existing.Save(ctx); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := existing.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := cli.CreateItemPreview(ctx, &onepassword.Item{Title: "New", Vault: vault}, false); !errors.Is(err, onepassword.ErrUnsupportedByBackend) {
+		t.Errorf("CreateItemPreview() error = %v; want ErrUnsupportedByBackend", err)
+	}
+
+	// Nothing was written through the backend
+	stored, err := fakeCLI.GetItemsByVault(ctx, vault)
+	if err != nil || len(*stored) != 1 || (*stored)[0].Title != "Existing" {
+		t.Errorf("items of the backend = %+v, %v; want the unchanged item", stored, err)
+	}
+
+	expected := []string{
+		"item list dry-run=false",
+		"item create dry-run=true",
+		"item edit dry-run=true",
+		"item delete dry-run=true",
+	}
+	if !slices.Equal(names, expected) {
+		t.Errorf("after hooks = %q; want %q", names, expected)
+	}
+	if got := len(cli.DryRunCommands()); got != 3 {
+		t.Errorf("DryRunCommands() = %d commands; want 3", got)
+	}
+}
@@ -0,0 +1,550 @@
+package onepassword
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// backupMagic identifies backup archives.
+	backupMagic = "OPBACKUP"
+
+	// backupFormatVersion is the version of the archive format.
+	backupFormatVersion = 1
+
+	// backupSaltSize is the size of the salt of the key derivation.
+	backupSaltSize = 16
+
+	// vaultTypeUserCreated is the type of vaults created by users, the only
+	// vaults that can be recreated and shared by Restore.
+	vaultTypeUserCreated = "USER_CREATED"
+)
+
+// backupKDFIterations is the number of PBKDF2 iterations used for new
+// archives. The value is stored in the archive, so it can be raised without
+// breaking existing archives.
+var backupKDFIterations = 600_000
+
+// minBackupKDFIterations is the lowest number of PBKDF2 iterations accepted
+// in an archive. The number is read from the header before it is
+// authenticated, so it is bounded below by this value and above by ten
+// times backupKDFIterations, keeping a crafted archive from weakening the
+// key or tying up the CPU for hours before the passphrase is rejected.
+var minBackupKDFIterations = 100_000
+
+// ErrBackupPassphrase is returned by Restore if the archive cannot be
+// decrypted, because the passphrase is wrong or the archive was modified.
+var ErrBackupPassphrase = errors.New("wrong passphrase or corrupted backup")
+
+// BackupOptions selects the content of a backup.
+//
+// Fields:
+//   - Vaults: The names or IDs of the vaults to back up. If empty, all vaults are backed up.
+//   - Documents: Include the files of Document items. Other file attachments are not backed up.
+//   - Passphrase: The passphrase the archive is encrypted with. Required.
+type BackupOptions struct {
+	Vaults     []string
+	Documents  bool
+	Passphrase string
+}
+
+// RestoreOptions controls how a backup is restored.
+//
+// Fields:
+//   - Passphrase: The passphrase the archive was encrypted with.
+//   - Vaults: The names of the vaults in the archive to restore. If empty, all vaults are restored.
+//   - SkipPermissions: Do not grant the vault permissions stored in the archive.
+type RestoreOptions struct {
+	Passphrase      string
+	Vaults          []string
+	SkipPermissions bool
+}
+
+// RestoreReport summarizes a restore, so a disaster-recovery drill can be
+// checked against the backed up content.
+//
+// Fields:
+//   - CreatedVaults: The vaults that did not exist and were created.
+//   - CreatedItems: The number of items that were created.
+//   - SkippedItems: The number of items that were skipped because an item
+//     with the same title and category exists in the vault.
+//   - Grants: The number of users and groups that were granted permissions.
+type RestoreReport struct {
+	CreatedVaults []Vault
+	CreatedItems  int
+	SkippedItems  int
+	Grants        int
+}
+
+// backupArchive is the decrypted content of a backup.
+type backupArchive struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	Account   string        `json:"account,omitempty"`
+	Vaults    []backupVault `json:"vaults"`
+}
+
+// backupVault is a vault with its items and permission grants.
+type backupVault struct {
+	Vault  Vault         `json:"vault"`
+	Items  []backupItem  `json:"items"`
+	Users  []backupGrant `json:"users,omitempty"`
+	Groups []backupGrant `json:"groups,omitempty"`
+}
+
+// backupItem is an item with the file of a Document item.
+type backupItem struct {
+	Item     Item            `json:"item"`
+	Document *backupDocument `json:"document,omitempty"`
+}
+
+// backupDocument is the file of a Document item.
+type backupDocument struct {
+	FileName string `json:"file_name"`
+	Content  []byte `json:"content"`
+}
+
+// backupGrant records the permissions of a user or group on a vault. Users
+// are restored by email and groups by name, so grants can be restored into
+// another account.
+type backupGrant struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Email       string       `json:"email,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Backup writes vault metadata, items and permission grants to w as an
+// archive encrypted with AES-256-GCM and a key derived from the passphrase.
+// The archive is portable and can be restored into any account with Restore.
+// It is assembled in memory before it is written.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - w: The writer the archive is written to.
+//   - opts: The vaults to back up and the passphrase.
+//
+// Returns:
+//   - error: An error if a vault, item or grant cannot be read or the archive cannot be written.
+//
+// Example usage:
+//
+//	file, err := os.Create("backup.opbak")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer file.Close()
+//	err = cli.Backup(ctx, file, onepassword.BackupOptions{Documents: true, Passphrase: passphrase})
+func (cli *OpCLI) Backup(ctx context.Context, w io.Writer, opts BackupOptions) error {
+	if opts.Passphrase == "" {
+		return errors.New("backup passphrase cannot be empty")
+	}
+
+	vaults, err := cli.backupVaults(ctx, opts.Vaults)
+	if err != nil {
+		return err
+	}
+
+	archive := backupArchive{Version: backupFormatVersion, CreatedAt: time.Now().UTC()}
+	if cli.Account != nil {
+		archive.Account = cli.Account.URL
+	}
+
+	for _, vault := range vaults {
+		backup, err := cli.backupVault(ctx, vault, opts.Documents)
+		if err != nil {
+			return fmt.Errorf("failed to back up vault %s: %w", vault.Name, err)
+		}
+		archive.Vaults = append(archive.Vaults, *backup)
+	}
+
+	return writeBackupArchive(w, &archive, opts.Passphrase)
+}
+
+// backupVaults returns the vaults selected by names or IDs, or all vaults.
+func (cli *OpCLI) backupVaults(ctx context.Context, identifiers []string) ([]Vault, error) {
+	if len(identifiers) == 0 {
+		vaults, err := cli.GetVaultDetails(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list vaults: %w", err)
+		}
+		return *vaults, nil
+	}
+
+	vaults := make([]Vault, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		vault, err := cli.getVaultDetails(ctx, identifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vault %s: %w", identifier, err)
+		}
+		vaults = append(vaults, *vault)
+	}
+	return vaults, nil
+}
+
+// backupVault reads the items and grants of a vault.
+func (cli *OpCLI) backupVault(ctx context.Context, vault Vault, documents bool) (*backupVault, error) {
+	backup := &backupVault{Vault: vault, Items: []backupItem{}}
+	backup.Vault.cli = nil
+
+	// The listing is collected first, so no command runs while it is streamed
+	listing, err := cli.GetItemsByVault(ctx, vault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	for _, listed := range *listing {
+
+		// Listings do not include fields, so every item is read
+		output, err := cli.ExecuteOpCommand(ctx, "item", "get", listed.ID, "--vault", vault.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item %s: %w", listed.ID, err)
+		}
+
+		var item backupItem
+		if err := cli.unmarshal(output, &item.Item); err != nil {
+			return nil, fmt.Errorf("failed to decode item %s: %w", listed.ID, err)
+		}
+
+		if documents && item.Item.Category == CategoryDocument {
+			if item.Document, err = cli.backupDocument(ctx, vault, output); err != nil {
+				return nil, fmt.Errorf("failed to back up document %s: %w", listed.ID, err)
+			}
+		}
+
+		backup.Items = append(backup.Items, item)
+	}
+
+	if !isUserCreatedVault(vault) {
+		return backup, nil
+	}
+
+	users, err := vault.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, user := range users {
+		backup.Users = append(backup.Users, backupGrant{
+			ID:          user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			Permissions: user.Permissions,
+		})
+	}
+
+	groups, err := vault.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	for _, group := range groups {
+		backup.Groups = append(backup.Groups, backupGrant{
+			ID:          group.ID,
+			Name:        group.Name,
+			Permissions: group.Permissions,
+		})
+	}
+
+	return backup, nil
+}
+
+// backupDocument downloads the file of a Document item. itemJSON is the
+// output of "op item get", which lists the file name.
+func (cli *OpCLI) backupDocument(ctx context.Context, vault Vault, itemJSON []byte) (*backupDocument, error) {
+	var details struct {
+		ID    string `json:"id"`
+		Files []struct {
+			Name string `json:"name"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(itemJSON, &details); err != nil {
+		return nil, err
+	}
+
+	// op document get prints the raw file, so --format is not passed
+	args := []string{"document", "get", details.ID, "--vault", vault.ID}
+	if cli.Account != nil && cli.Account.UserUUID != "" {
+		args = append(args, "--account", cli.Account.UserUUID)
+	}
+	content, err := cli.executeOpCommandWith(ctx, execSettings{noDefaultArgs: true}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &backupDocument{Content: content}
+	if len(details.Files) > 0 {
+		document.FileName = details.Files[0].Name
+	}
+	return document, nil
+}
+
+// Restore recreates the content of an archive written by Backup. Vaults are
+// matched by name and created if they do not exist. Items are created in
+// their vault unless an item with the same title and category exists, so an
+// interrupted restore can be run again. Permissions are granted to users by
+// email and to groups by name; built-in groups and vaults that are not user
+// created are skipped. Errors of single items and grants do not stop the
+// restore and are returned together.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - r: The reader the archive is read from.
+//   - opts: The passphrase and the vaults to restore.
+//
+// Returns:
+//   - *RestoreReport: The restored content, also if errors occurred.
+//   - error: ErrBackupPassphrase if the archive cannot be decrypted, or the errors of the restore.
+func (cli *OpCLI) Restore(ctx context.Context, r io.Reader, opts RestoreOptions) (*RestoreReport, error) {
+	archive, err := readBackupArchive(r, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	vaults, err := cli.GetVaultDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vaults: %w", err)
+	}
+
+	report := &RestoreReport{}
+	var errs []error
+	for _, backup := range archive.Vaults {
+		if len(opts.Vaults) > 0 && !slices.Contains(opts.Vaults, backup.Vault.Name) {
+			continue
+		}
+
+		if err := cli.restoreVault(ctx, backup, *vaults, opts, report); err != nil {
+			errs = append(errs, fmt.Errorf("vault %s: %w", backup.Vault.Name, err))
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// restoreVault restores a vault of an archive.
+func (cli *OpCLI) restoreVault(ctx context.Context, backup backupVault, vaults []Vault, opts RestoreOptions, report *RestoreReport) error {
+	index := slices.IndexFunc(vaults, func(v Vault) bool { return v.Name == backup.Vault.Name })
+
+	var vault *Vault
+	switch {
+	case index >= 0:
+		vault = &vaults[index]
+	case !isUserCreatedVault(backup.Vault):
+		return fmt.Errorf("%s vault does not exist and cannot be created", backup.Vault.Type)
+	default:
+		var err error
+		vault, err = cli.CreateVault(ctx, backup.Vault.Name, backup.Vault.Description, IconVaultDoor, true)
+		if err != nil {
+			return fmt.Errorf("failed to create vault: %w", err)
+		}
+		report.CreatedVaults = append(report.CreatedVaults, *vault)
+	}
+
+	existing := make(map[string]bool)
+	for item, err := range vault.AllItems(ctx) {
+		if err != nil {
+			return fmt.Errorf("failed to list items: %w", err)
+		}
+		existing[string(item.Category)+"/"+item.Title] = true
+	}
+
+	var errs []error
+	for _, backupItem := range backup.Items {
+		key := string(backupItem.Item.Category) + "/" + backupItem.Item.Title
+		if existing[key] {
+			report.SkippedItems++
+			continue
+		}
+
+		if err := cli.restoreItem(ctx, *vault, backupItem); err != nil {
+			errs = append(errs, fmt.Errorf("item %s: %w", backupItem.Item.Title, err))
+			continue
+		}
+		existing[key] = true
+		report.CreatedItems++
+	}
+
+	if !opts.SkipPermissions && isUserCreatedVault(*vault) {
+		errs = append(errs, cli.restoreGrants(ctx, vault, backup, report))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isUserCreatedVault reports whether a vault was created by a user. Vaults
+// listed without a type are treated as user created.
+func isUserCreatedVault(vault Vault) bool {
+	return vault.Type == "" || vault.Type == vaultTypeUserCreated
+}
+
+// restoreItem creates an item of an archive in a vault.
+func (cli *OpCLI) restoreItem(ctx context.Context, vault Vault, backup backupItem) error {
+	if backup.Document != nil {
+		args := []string{"document", "create", "--vault", vault.ID, "--title", backup.Item.Title}
+		if backup.Document.FileName != "" {
+			args = append(args, "--file-name", backup.Document.FileName)
+		}
+		if len(backup.Item.Tags) > 0 {
+			args = append(args, "--tags", strings.Join(backup.Item.Tags, ","))
+		}
+		_, err := cli.executeOpCommandWith(ctx, execSettings{stdin: backup.Document.Content}, args...)
+		return err
+	}
+
+	item := backup.Item
+	item.ID = ""
+	item.Version = 0
+	item.Vault = Vault{ID: vault.ID}
+	for i := range item.Fields {
+		item.Fields[i].Reference = ""
+	}
+
+	_, err := cli.CreateItem(ctx, &item, false)
+	return err
+}
+
+// restoreGrants grants the permissions of an archive on a vault.
+func (cli *OpCLI) restoreGrants(ctx context.Context, vault *Vault, backup backupVault, report *RestoreReport) error {
+	var errs []error
+	var grants []PermissionGrant
+
+	for _, grant := range backup.Users {
+		user, err := cli.GetUserByEmail(ctx, grant.Email)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", grant.Email, err))
+			continue
+		}
+		grants = append(grants, PermissionGrant{User: user, Permissions: grant.Permissions})
+	}
+
+	for _, grant := range backup.Groups {
+		group, err := cli.GetGroupByName(ctx, grant.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("group %s: %w", grant.Name, err))
+			continue
+		}
+		if group.IsBuiltIn() {
+			continue
+		}
+		grants = append(grants, PermissionGrant{Group: group, Permissions: grant.Permissions})
+	}
+
+	for _, result := range vault.GrantPermissions(ctx, grants) {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		report.Grants++
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeBackupArchive compresses and encrypts an archive. The header consists
+// of the magic, the format version, the PBKDF2 iterations, the salt and the
+// nonce, and is authenticated along with the content.
+func writeBackupArchive(w io.Writer, archive *backupArchive, passphrase string) error {
+	var plaintext bytes.Buffer
+	compressor := gzip.NewWriter(&plaintext)
+	if err := json.NewEncoder(compressor).Encode(archive); err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress backup: %w", err)
+	}
+
+	header := make([]byte, 0, len(backupMagic)+1+4+backupSaltSize)
+	header = append(header, backupMagic...)
+	header = append(header, backupFormatVersion)
+	header = binary.BigEndian.AppendUint32(header, uint32(backupKDFIterations))
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	header = append(header, salt...)
+
+	aead, err := backupCipher(passphrase, salt, backupKDFIterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+
+	if _, err := w.Write(aead.Seal(header, nonce, plaintext.Bytes(), header)); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// readBackupArchive decrypts and decodes an archive written by writeBackupArchive.
+func readBackupArchive(r io.Reader, passphrase string) (*backupArchive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	prefixSize := len(backupMagic) + 1 + 4 + backupSaltSize
+	if len(data) < prefixSize || string(data[:len(backupMagic)]) != backupMagic {
+		return nil, errors.New("not a backup archive")
+	}
+	if version := data[len(backupMagic)]; version != backupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", version)
+	}
+	iterations := int(binary.BigEndian.Uint32(data[len(backupMagic)+1:]))
+	if iterations < minBackupKDFIterations || iterations > 10*backupKDFIterations {
+		return nil, fmt.Errorf("unsupported number of key derivation iterations %d", iterations)
+	}
+	salt := data[prefixSize-backupSaltSize : prefixSize]
+
+	aead, err := backupCipher(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := prefixSize + aead.NonceSize()
+	if len(data) < headerSize {
+		return nil, errors.New("not a backup archive")
+	}
+
+	plaintext, err := aead.Open(nil, data[prefixSize:headerSize], data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, ErrBackupPassphrase
+	}
+
+	decompressor, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress backup: %w", err)
+	}
+	var archive backupArchive
+	if err := json.NewDecoder(decompressor).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to decode backup: %w", err)
+	}
+	return &archive, nil
+}
+
+// backupCipher derives the key of an archive from the passphrase.
+func backupCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive backup key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,87 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestBackupArchive(t *testing.T) {
+	iterations, minIterations := backupKDFIterations, minBackupKDFIterations
+	backupKDFIterations, minBackupKDFIterations = 1000, 500
+	t.Cleanup(func() { backupKDFIterations, minBackupKDFIterations = iterations, minIterations })
+
+	archive := &backupArchive{
+		Version: backupFormatVersion,
+		Vaults: []backupVault{{
+			Vault: Vault{Name: "Engineering"},
+			Items: []backupItem{{
+				Item:     Item{Title: "Contract", Category: CategoryDocument},
+				Document: &backupDocument{FileName: "contract.pdf", Content: []byte("%PDF")},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeBackupArchive(&buf, archive, "correct horse"); err != nil {
+		t.Fatalf("writeBackupArchive() error = %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("Engineering")) {
+		t.Fatal("writeBackupArchive() wrote plaintext")
+	}
+
+	tampered := bytes.Clone(buf.Bytes())
+	tampered[len(tampered)-1] ^= 0xff
+
+	// withIterations returns the archive with another iteration count in its header
+	withIterations := func(n uint32) []byte {
+		data := bytes.Clone(buf.Bytes())
+		binary.BigEndian.PutUint32(data[len(backupMagic)+1:], n)
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		passphrase  string
+		expectedErr error
+		wantErr     bool
+	}{
+		{name: "Valid", data: buf.Bytes(), passphrase: "correct horse"},
+		{name: "Wrong passphrase", data: buf.Bytes(), passphrase: "wrong", expectedErr: ErrBackupPassphrase},
+		{name: "Tampered", data: tampered, passphrase: "correct horse", expectedErr: ErrBackupPassphrase},
+		{name: "Not an archive", data: []byte("PK\x03\x04"), passphrase: "correct horse", wantErr: true},
+		{name: "Too many iterations", data: withIterations(0xFFFFFFFF), passphrase: "correct horse", wantErr: true},
+		{name: "Too few iterations", data: withIterations(1), passphrase: "correct horse", wantErr: true},
+		{name: "Other valid iterations", data: withIterations(2000), passphrase: "correct horse", expectedErr: ErrBackupPassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := readBackupArchive(bytes.NewReader(tt.data), tt.passphrase)
+			if tt.expectedErr != nil || tt.wantErr {
+				if err == nil || (tt.expectedErr != nil && !errors.Is(err, tt.expectedErr)) {
+					t.Fatalf("readBackupArchive() error = %v; want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readBackupArchive() error = %v", err)
+			}
+
+			document := restored.Vaults[0].Items[0].Document
+			if restored.Vaults[0].Vault.Name != "Engineering" || document == nil || string(document.Content) != "%PDF" {
+				t.Errorf("readBackupArchive() = %+v; want the written archive", restored)
+			}
+		})
+	}
+}
+
+func TestBackupRequiresPassphrase(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	if err := cli.Backup(context.Background(), &bytes.Buffer{}, BackupOptions{}); err == nil {
+		t.Error("Backup() without passphrase succeeded")
+	}
+}
@@ -0,0 +1,216 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CacheEntity identifies a kind of entity that can be cached by OpCLI.
+type CacheEntity string
+
+const (
+	CacheVaults   CacheEntity = "vault"
+	CacheUsers    CacheEntity = "user"
+	CacheGroups   CacheEntity = "group"
+	CacheAccounts CacheEntity = "account"
+	CacheItems    CacheEntity = "item"
+)
+
+// CacheStats contains the number of cache hits and misses for an entity.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the share of lookups that were served from the cache, or
+// zero if there were no lookups.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// entityCacheEntry is the cached output of a "get" command.
+type entityCacheEntry struct {
+	output    []byte
+	fetchedAt time.Time
+}
+
+// entityCache is a read-through cache for the output of "vault get",
+// "user get", "group get" and of the "item list" used by title lookups. It
+// is disabled for an entity unless a TTL is set.
+type entityCache struct {
+	mu      sync.Mutex
+	ttl     map[CacheEntity]time.Duration
+	entries map[CacheEntity]map[string]entityCacheEntry
+	stats   map[CacheEntity]CacheStats
+}
+
+// SetCacheTTL sets how long lookups of an entity by ID or name are cached.
+// The cache is disabled by default. Successful commands that modify an
+// entity invalidate all cached entries of its kind.
+//
+// For CacheAccounts, the TTL is applied to the account list cache described
+// at SetAccountCacheTTL, which is enabled by default. For CacheItems, the
+// item list of GetItemsByTitlePrefix and GetItemsByTitlePattern is cached.
+//
+// Parameters:
+//   - entity: The kind of entity, e.g. CacheVaults.
+//   - ttl: The time entries are cached. Zero or a negative duration disables the cache.
+func (cli *OpCLI) SetCacheTTL(entity CacheEntity, ttl time.Duration) {
+	if entity == CacheAccounts {
+		if ttl == 0 {
+			ttl = -1
+		}
+		cli.SetAccountCacheTTL(ttl)
+		return
+	}
+
+	c := &cli.entityCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl == nil {
+		c.ttl = make(map[CacheEntity]time.Duration)
+	}
+	c.ttl[entity] = ttl
+	delete(c.entries, entity)
+}
+
+// CacheStats returns the number of cache hits and misses per entity since
+// the OpCLI instance was created.
+//
+// Returns:
+//   - map[CacheEntity]CacheStats: The statistics of every entity with lookups.
+func (cli *OpCLI) CacheStats() map[CacheEntity]CacheStats {
+	c := &cli.entityCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[CacheEntity]CacheStats, len(c.stats))
+	for entity, s := range c.stats {
+		stats[entity] = s
+	}
+	return stats
+}
+
+// recordLookup counts a cache hit or miss for the entity.
+// The caller must hold c.mu.
+func (c *entityCache) recordLookup(entity CacheEntity, hit bool) {
+	if c.stats == nil {
+		c.stats = make(map[CacheEntity]CacheStats)
+	}
+
+	s := c.stats[entity]
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+	c.stats[entity] = s
+}
+
+// lookup returns the cached output for the entity identifier if it is still valid.
+func (c *entityCache) lookup(entity CacheEntity, identifier string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.ttl[entity]
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	entry, ok := c.entries[entity][identifier]
+	hit := ok && time.Since(entry.fetchedAt) < ttl
+	c.recordLookup(entity, hit)
+	if !hit {
+		return nil, false
+	}
+
+	return slices.Clone(entry.output), true
+}
+
+// store caches the output for the entity identifier if caching is enabled.
+func (c *entityCache) store(entity CacheEntity, identifier string, output []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl[entity] <= 0 {
+		return
+	}
+
+	if c.entries == nil {
+		c.entries = make(map[CacheEntity]map[string]entityCacheEntry)
+	}
+	if c.entries[entity] == nil {
+		c.entries[entity] = make(map[string]entityCacheEntry)
+	}
+	c.entries[entity][identifier] = entityCacheEntry{
+		output:    slices.Clone(output),
+		fetchedAt: time.Now(),
+	}
+}
+
+// invalidate removes all cached entries of the entity.
+func (c *entityCache) invalidate(entity CacheEntity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, entity)
+}
+
+// invalidateForCommand invalidates the cached entries that may be changed by
+// a successful command. Commands other than "get" and "list" of vaults,
+// users, groups and items are considered to modify the entity.
+func (c *entityCache) invalidateForCommand(args []string) {
+	if len(args) < 2 {
+		return
+	}
+
+	entity := CacheEntity(args[0])
+	switch entity {
+	case CacheVaults, CacheUsers, CacheGroups, CacheItems:
+	default:
+		return
+	}
+
+	switch args[1] {
+	case "get", "list":
+		return
+	}
+	// Previews with --dry-run do not modify anything
+	if slices.Contains(args, "--dry-run") {
+		return
+	}
+
+	c.invalidate(entity)
+}
+
+// getEntity runs "<entity> get <identifier>" through the read-through cache.
+func (cli *OpCLI) getEntity(ctx context.Context, entity CacheEntity, identifier string) ([]byte, error) {
+	if output, ok := cli.entityCache.lookup(entity, identifier); ok {
+		cli.log().Debug("using cached entity", "entity", entity, "identifier", identifier)
+		return output, nil
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, string(entity), "get", identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.entityCache.store(entity, identifier, output)
+
+	return output, nil
+}
+
+// recordAccountCacheLookup counts a lookup of the account list cache.
+func (cli *OpCLI) recordAccountCacheLookup(hit bool) {
+	cli.entityCache.mu.Lock()
+	defer cli.entityCache.mu.Unlock()
+
+	cli.entityCache.recordLookup(CacheAccounts, hit)
+}
@@ -0,0 +1,95 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEntityCache(t *testing.T) {
+	var calls int
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		calls++
+		return []byte(`{"id":"abcdefghijklmnopqrstuvwxyz","name":"Private"}`), nil, nil
+	}))
+	cli.SetCacheTTL(CacheVaults, time.Minute)
+
+	ctx := context.Background()
+	steps := []struct {
+		name          string
+		run           func() error
+		expectedCalls int
+	}{
+		{
+			name:          "First lookup",
+			run:           func() error { _, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz"); return err },
+			expectedCalls: 1,
+		},
+		{
+			name:          "Cached lookup",
+			run:           func() error { _, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz"); return err },
+			expectedCalls: 1,
+		},
+		{
+			name:          "Modification",
+			run:           func() error { return cli.UpdateVaultIcon(ctx, "abcdefghijklmnopqrstuvwxyz", IconApplication) },
+			expectedCalls: 2,
+		},
+		{
+			name:          "Lookup after modification",
+			run:           func() error { _, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz"); return err },
+			expectedCalls: 3,
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+		if calls != step.expectedCalls {
+			t.Errorf("%s: executor calls = %d; want %d", step.name, calls, step.expectedCalls)
+		}
+	}
+
+	stats := cli.CacheStats()[CacheVaults]
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("CacheStats() = %+v; want 1 hit and 2 misses", stats)
+	}
+}
+
+func TestEntityCacheSignInOtherAccount(t *testing.T) {
+	var calls int
+	cli := &OpCLI{Path: "op"}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		calls++
+		return []byte(`{"id":"abcdefghijklmnopqrstuvwxyz","name":"Private of ` + cli.Account.Email + `"}`), nil, nil
+	}))
+	cli.SetLogger(nil)
+	cli.SetCacheTTL(CacheVaults, time.Minute)
+
+	ctx := context.Background()
+	steps := []struct {
+		name          string
+		account       *Account
+		expectedName  string
+		expectedCalls int
+	}{
+		{name: "First account", account: &Account{UserUUID: "first-uuid", Email: "first"}, expectedName: "Private of first", expectedCalls: 1},
+		{name: "Same account", account: &Account{UserUUID: "first-uuid", Email: "first"}, expectedName: "Private of first", expectedCalls: 1},
+		{name: "Other account", account: &Account{UserUUID: "second-uuid", Email: "second"}, expectedName: "Private of second", expectedCalls: 2},
+	}
+
+	for _, step := range steps {
+		if err := cli.completeSignIn(step.account, "token"); err != nil {
+			t.Fatalf("%s: completeSignIn() error = %v", step.name, err)
+		}
+		vault, err := cli.GetVaultDetailsByID(ctx, "abcdefghijklmnopqrstuvwxyz")
+		if err != nil {
+			t.Fatalf("%s: GetVaultDetailsByID() error = %v", step.name, err)
+		}
+		if vault.Name != step.expectedName || calls != step.expectedCalls {
+			t.Errorf("%s: vault %q after %d calls; want %q after %d", step.name, vault.Name, calls, step.expectedName, step.expectedCalls)
+		}
+	}
+}
@@ -3,15 +3,17 @@ package onepassword
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
-
-	"golang.org/x/term"
+	"sync"
+	"time"
 )
 
 // OpCLI represents the 1Password CLI executor
@@ -19,9 +21,37 @@ type OpCLI struct {
 	Path             string
 	accesstoken      string
 	cache            itemCache
-	logger           slog.Logger
+	accountCache     accountCache
+	logger           *slog.Logger
 	isServiceAccount bool
+	emailValidator   EmailValidator
 	Account          *Account
+
+	credentialProvider    CredentialProvider
+	sessionStore          SessionStore
+	disableSessionRefresh bool
+	sessionCheck          bool
+	refreshMu             sync.Mutex
+	executor              CommandExecutor
+	timeout               time.Duration
+	env                   []string
+	rateLimiter           *rateLimiter
+	entityCache           entityCache
+	hooks                 execHooks
+	tracer                Tracer
+	dryRun                dryRun
+	pool                  commandPool
+	configDir             string
+	running               runningCommands
+	logCommands           bool
+	keepAlive             keepAlive
+	lifecycle             lifecycle
+	permissions           permissionRegistry
+	plan                  accountPlan
+	decoding              decoding
+	output                outputSettings
+	templates             templateCache
+	backend               Backend
 }
 
 // OpCliError represents an error from the 1Password CLI operations
@@ -38,6 +68,27 @@ func (e *OpCliError) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the underlying error of the command execution.
+func (e *OpCliError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the stderr output of the CLI indicates the target
+// error, e.g. ErrNotFound, ErrItemNotFound or ErrSessionExpired.
+func (e *OpCliError) Is(target error) bool {
+	kind := classifyCLIError(e.StderrOutput)
+	return kind != nil && errors.Is(kind, target)
+}
+
+// ExitCode returns the exit code of the CLI, or -1 if the command did not exit.
+func (e *OpCliError) ExitCode() int {
+	var exitErr interface{ ExitCode() int }
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
 // itemCache maintains a local cache of 1Password items for faster lookups
 type itemCache struct {
 	items       map[string]*Item // key is item title
@@ -45,22 +96,67 @@ type itemCache struct {
 }
 
 // NewOpCLI initializes a new instance of the OpCLI struct.
-// It locates the 1Password CLI executable and sets up an empty item cache.
+// It applies the given options, locates the 1Password CLI executable unless
+// a path was set with WithPath or a CommandExecutor with WithCommandExecutor,
+// and sets up an empty item cache.
+//
+// Parameters:
+// - opts: Options to configure the instance, e.g. WithPath or WithTimeout.
 //
 // Returns:
 // - A pointer to an OpCLI instance.
-func NewOpCLI() *OpCLI {
+// - An error if an option is invalid or the 1Password CLI cannot be found.
+func NewOpCLI(opts ...Option) (*OpCLI, error) {
+	cli := &OpCLI{
+		cache: itemCache{items: make(map[string]*Item)},
+	}
 
-	// Find the 1Password CLI executable
-	opPath, err := FindOpExecutable()
-	if err != nil {
-		slog.Error("1Password CLI not found", "error", err)
+	for _, opt := range opts {
+		if err := opt(cli); err != nil {
+			cli.Close()
+			return nil, err
+		}
 	}
 
-	return &OpCLI{
-		Path:  opPath,
-		cache: itemCache{items: make(map[string]*Item)},
+	// A custom CommandExecutor, e.g. a fake in tests, or a Backend serving
+	// the vault and item operations may not need the executable
+	if cli.Path == "" && (cli.executor != nil || cli.backend != nil) {
+		cli.Path = "op"
 	}
+
+	if cli.Path == "" {
+		// Find the 1Password CLI executable
+		cli.log().Debug("searching for op executable in PATH")
+		opPath, err := FindOpExecutable()
+		if err != nil {
+			cli.Close()
+			return nil, fmt.Errorf("1Password CLI not found: %w", err)
+		}
+		cli.Path = opPath
+	}
+
+	return cli, nil
+}
+
+// SetLogger sets the logger used by the OpCLI instance. If logger is nil,
+// log output is discarded. Without a logger, the default logger of the slog
+// package is used.
+//
+// Parameters:
+//   - logger: The logger to use.
+func (cli *OpCLI) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	cli.logger = logger
+}
+
+// log returns the logger of the OpCLI instance.
+func (cli *OpCLI) log() *slog.Logger {
+	if cli.logger == nil {
+		return slog.Default()
+	}
+	return cli.logger
 }
 
 // FindOpExecutable searches for the "op" executable in the system's PATH.
@@ -72,7 +168,6 @@ func NewOpCLI() *OpCLI {
 // - The full path to the "op" executable if found.
 // - An error if the executable is not found in any of the directories in PATH.
 func FindOpExecutable() (string, error) {
-	slog.Debug("searching for op executable in PATH")
 	paths := filepath.SplitList(os.Getenv("PATH"))
 	executableName := "op"
 	if runtime.GOOS == "windows" {
@@ -104,94 +199,21 @@ func TestOpCli(opPath string) error {
 	return nil
 }
 
-// VerifyOpExecutable verifies the digital signature of the specified executable file
-// to ensure it is signed by AgileBits Inc. (the developers of 1Password).
-//
-// The verification process varies depending on the operating system:
-//   - On macOS, it uses the `codesign` command to check the signature authority.
-//   - On Windows, it uses PowerShell's `Get-AuthenticodeSignature` to validate the signature
-//     and ensure it is signed by AgileBits.
-//   - On Linux, it uses GPG to verify the signature against AgileBits' public GPG key.
-//
-// If the verification fails or the executable is not signed by AgileBits, an error is returned.
-// On Linux, if GPG is not available, the function logs a warning and skips the verification.
-//
-// Parameters:
-//   - path: The file path to the executable to be verified.
-//
-// Returns:
-//   - An error if the verification fails or the executable is not signed by AgileBits.
-//     Returns nil if the verification succeeds or is skipped (e.g., on Linux without GPG).
-func VerifyOpExecutable(path string) error {
-	if runtime.GOOS == "darwin" {
-		// Use codesign on macOS
-		cmd := exec.Command("codesign", "-d", "-vvv", path)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("signature verification failed: %v", err)
-		}
-
-		// Check for AgileBits signature
-		outputStr := string(output)
-		if !strings.Contains(outputStr, "Authority=Developer ID Application: AgileBits Inc.") {
-			return fmt.Errorf("invalid signature: not signed by AgileBits")
-		}
-	} else if runtime.GOOS == "windows" {
-		// Use PowerShell Get-AuthenticodeSignature on Windows with publisher check
-		cmd := exec.Command("powershell", "-Command", fmt.Sprintf(`
-			$sig = Get-AuthenticodeSignature '%s'
-			if ($sig.Status -ne 'Valid') { 
-				exit 1 
-			}
-			if ($sig.SignerCertificate.Subject -notlike '*AgileBits*') {
-				exit 1
-			}`, path))
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("signature verification failed or not signed by AgileBits: %v", err)
-		}
-	} else if runtime.GOOS == "linux" {
-		// Check if gpg is available
-		if _, err := exec.LookPath("gpg"); err != nil {
-			slog.Warn("gpg not found, skipping signature verification on Linux")
-			return nil
-		}
-
-		// AgileBits GPG key ID for 1Password packages
-		const agileBitsKeyID = "3FEF9748469ADBE15DA7CA80AC2D62742012EA22"
-
-		// Verify if key is in keyring, if not fetch it
-		checkKey := exec.Command("gpg", "--list-keys", agileBitsKeyID)
-		if err := checkKey.Run(); err != nil {
-			slog.Info("fetching AgileBits GPG key")
-			fetchKey := exec.Command("gpg", "--keyserver", "keyserver.ubuntu.com", "--recv-keys", agileBitsKeyID)
-			if err := fetchKey.Run(); err != nil {
-				return fmt.Errorf("failed to fetch AgileBits GPG key: %v", err)
-			}
-		}
-
-		// Verify signature
-		cmd := exec.Command("gpg", "--verify", path)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("GPG signature verification failed: %v", err)
-		}
-
-		// Verify it's signed by AgileBits
-		verify := exec.Command("gpg", "--verify", "--debug-level", "1", path)
-		output, _ := verify.CombinedOutput()
-		if !strings.Contains(string(output), agileBitsKeyID) {
-			return fmt.Errorf("invalid signature: not signed by AgileBits")
-		}
-	}
-	return nil
-}
-
 // SignIn attempts to sign in to a 1Password account using the provided account details.
-// It first tries a passwordless sign-in method. If that fails and the error indicates
-// that password authentication is required, it prompts the user for a password and
-// retries the sign-in process.
+// Existing sessions are reused if the CLI still accepts them: the session token
+// held by the account, an OP_SESSION_<uuid> environment variable, or a token
+// from the configured SessionStore.
+//
+// Otherwise it first tries a passwordless sign-in method. If that fails and the error indicates
+// that password authentication is required, it requests the password from the
+// configured CredentialProvider and retries the sign-in process. If the account
+// requires a second factor, the one-time password is requested from the
+// CredentialProvider as well and passed to the CLI after the password.
 //
-// Upon successful sign-in, the session token is stored in an environment variable
-// and the account's sign-in information is updated.
+// Upon successful sign-in, the session token is stored on the account and passed
+// to every command of this OpCLI instance through its environment.
+// If the account differs from the previously active one, the cached items,
+// vault, user and group lookups and the detected account plan are discarded.
 //
 // Parameters:
 //   - ctx: The context for managing the command execution lifecycle.
@@ -199,90 +221,126 @@ func VerifyOpExecutable(path string) error {
 //
 // Returns:
 //   - An error if the sign-in process fails, or nil if the sign-in is successful.
+//     ErrSecondFactorRequired if the account requires a one-time password that
+//     the CredentialProvider cannot supply.
 func (cli *OpCLI) SignIn(ctx context.Context, account *Account) error {
-	slog.Debug("attempting to sign in to 1Password")
+	if err := cli.checkServiceAccountSupport([]string{"signin"}); err != nil {
+		return err
+	}
 
-	slog.Debug("signing in to account",
+	cli.log().Debug("attempting to sign in to 1Password")
+
+	cli.log().Debug("signing in to account",
 		"account", account.UserUUID,
 		"email", account.Email)
 
-	signinCmd := exec.CommandContext(ctx, cli.Path, "signin", "--account", account.UserUUID, "--raw")
-	var stderr bytes.Buffer
-	var stdout bytes.Buffer
-	signinCmd.Stderr = &stderr
-	signinCmd.Stdout = &stdout
+	// Reuse an existing session if it is still accepted by the CLI
+	if token, ok := cli.existingSession(ctx, account); ok {
+		return cli.completeSignIn(account, token)
+	}
 
 	var sessionToken string
-	slog.Debug("attempting passwordless signin")
-	err := signinCmd.Run()
+	cli.log().Debug("attempting passwordless signin")
+	stdout, stderr, err := cli.run(ctx, cli.command("signin", "--account", account.UserUUID, "--raw"))
 	if err == nil {
-		sessionToken = strings.TrimSpace(stdout.String())
-		if sessionToken != "" {
-			if err := os.Setenv("OP_SESSION_"+account.UserUUID, sessionToken); err != nil {
-				return fmt.Errorf("failed to set session token: %v", err)
-			}
-		}
-
-		slog.Debug("passwordless signin successful", "sessionToken", sessionToken)
-		account.SetSignInInfo(sessionToken)
-		cli.Account = account
-
-		slog.Info("connected to 1Password", "url", account.URL, "email", account.Email)
-		return nil
+		sessionToken = strings.TrimSpace(string(stdout))
+		cli.log().Debug("passwordless signin successful")
+		return cli.completeSignIn(account, sessionToken)
 	}
 
-	stderrOutput := stderr.String()
-	slog.Debug("initial signin attempt failed", "error", err, "stderr", stderrOutput)
+	stderrOutput := string(stderr)
+	cli.log().Debug("initial signin attempt failed", "error", err, "stderr", stderrOutput)
 
 	if strings.Contains(strings.ToLower(stderrOutput), "enter the password for") ||
 		strings.Contains(strings.ToLower(stderrOutput), "authentication") {
 
-		slog.Debug("password authentication required")
-		password, err := readPassword()
+		cli.log().Debug("password authentication required")
+		password, err := cli.credentials().Password(ctx, account)
 		if err != nil {
-			return fmt.Errorf("error reading password: %v", err)
+			return fmt.Errorf("error reading password: %w", err)
 		}
 
-		cmd := exec.CommandContext(ctx, cli.Path, "signin", "--account", account.UserUUID, "--raw")
+		cmd := cli.command("signin", "--account", account.UserUUID, "--raw")
 		cmd.Stdin = strings.NewReader(password)
-		output, err := cmd.Output()
+		output, stderr, err := cli.run(ctx, cmd)
+
+		// Accounts with two-factor authentication prompt for a one-time
+		// password after the password, which is read from the next line
+		if err != nil && isSecondFactorPrompt(string(stderr)) {
+			cli.log().Debug("one-time password required")
+			code, totpErr := cli.credentials().TOTP(ctx, account)
+			if totpErr != nil {
+				return fmt.Errorf("%w: %w", ErrSecondFactorRequired, totpErr)
+			}
+
+			cmd = cli.command("signin", "--account", account.UserUUID, "--raw")
+			cmd.Stdin = strings.NewReader(password + "\n" + strings.TrimSpace(code) + "\n")
+			output, stderr, err = cli.run(ctx, cmd)
+		}
+
 		if err != nil {
-			slog.Error("password signin failed", "error", err)
-			return fmt.Errorf("signin failed: %v", err)
+			cli.log().Error("password signin failed", "error", err)
+			return fmt.Errorf("signin failed: %w", &OpCliError{Err: err, StderrOutput: string(stderr)})
 		}
 
 		sessionToken = strings.TrimSpace(string(output))
 		if sessionToken == "" {
 			return fmt.Errorf("no session token received from signin")
 		}
+	} else {
+		return fmt.Errorf("signin failed: %w", &OpCliError{Err: err, StderrOutput: stderrOutput})
+	}
+
+	return cli.completeSignIn(account, sessionToken)
+}
 
-		if err := os.Setenv("OP_SESSION_"+account.UserUUID, sessionToken); err != nil {
-			return fmt.Errorf("failed to set session token: %v", err)
+// completeSignIn stores the session token of a successful sign-in on the
+// account and in the configured SessionStore, and makes the account the
+// active account of the OpCLI instance.
+func (cli *OpCLI) completeSignIn(account *Account, sessionToken string) error {
+	if sessionToken != "" && cli.sessionStore != nil {
+		if err := cli.sessionStore.Save(account, sessionToken); err != nil {
+			cli.log().Warn("failed to persist session token", "account", account.UserUUID, "error", err)
 		}
-	} else {
-		return fmt.Errorf("signin failed: %s", stderr.String())
 	}
 
 	account.SetSignInInfo(sessionToken)
-	cli.Account = account
+	cli.setAccount(account)
 
-	slog.Info("connected to 1Password", "url", account.URL, "email", account.Email)
+	cli.log().Info("connected to 1Password", "url", account.URL, "email", account.Email)
 	return nil
 }
 
-// readPassword prompts the user to enter their 1Password password securely.
-// It disables input echoing to ensure the password is not displayed on the screen.
-// Returns the entered password as a string or an error if the password could not be read.
-func readPassword() (string, error) {
-	slog.Debug("prompting for 1Password password")
-	fmt.Print("Enter your 1Password password: ")
-	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Println() // Add a newline after the password input
-	if err != nil {
-		slog.Error("failed to read password", "error", err)
-		return "", err
+// setAccount makes account the active account. The caches are cleared if it
+// is another account than before, so vaults and items of the previous
+// account are not served for it.
+func (cli *OpCLI) setAccount(account *Account) {
+	if cli.Account == nil || cli.Account.UserUUID != account.UserUUID {
+		cli.clearCaches()
 	}
-	return string(bytePassword), nil
+	cli.Account = account
+}
+
+// environ returns the environment for commands of this OpCLI instance.
+func (cli *OpCLI) environ() []string {
+	env := os.Environ()
+
+	if cli.configDir != "" {
+		env = append(env, "OP_CONFIG_DIR="+cli.configDir)
+	}
+
+	if cli.isServiceAccount && cli.accesstoken != "" {
+		env = append(env, "OP_SERVICE_ACCOUNT_TOKEN="+cli.accesstoken)
+	}
+
+	if cli.Account != nil && cli.Account.UserUUID != "" && cli.Account.sessionToken != "" {
+		env = append(env, "OP_SESSION_"+cli.Account.UserUUID+"="+cli.Account.sessionToken)
+	}
+
+	env = append(env, cli.outputEnv()...)
+	env = append(env, cli.env...)
+
+	return env
 }
 
 // Execute runs a 1Password CLI command with the specified arguments.
@@ -301,58 +359,74 @@ func readPassword() (string, error) {
 //
 // Args:
 //
+//	ctx: The context for the command execution.
 //	args: A variadic list of strings representing the command arguments.
 //
 // Returns:
 //
 //	[]byte: The output of the command for non-interactive commands.
 //	error: An error if the command fails or if there is an issue with execution.
-func (cli *OpCLI) Execute(args ...string) ([]byte, error) {
+func (cli *OpCLI) Execute(ctx context.Context, args ...string) ([]byte, error) {
 	var cmdArgs []string
 	if len(args) == 0 {
 		return nil, fmt.Errorf("no arguments provided")
 	}
 
+	if err := cli.checkServiceAccountSupport(args); err != nil {
+		return nil, err
+	}
+	if err := cli.checkPlanSupport(args); err != nil {
+		return nil, err
+	}
+
 	if args[0] != "signin" {
 		cmdArgs = append(args, "--format=json")
 	} else {
 		cmdArgs = args
 	}
 
-	cmd := exec.Command(cli.Path, cmdArgs...)
+	cmd := cli.command(cmdArgs...)
 
 	// For non-interactive commands, capture stderr and return output
 	if !isInteractiveCommand(args) {
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-		output, err := cmd.Output()
+		output, stderr, err := cli.run(ctx, cmd)
 		if err != nil {
-			return nil, &OpCliError{
+			return nil, cli.planError(ctx, args, &OpCliError{
 				Err:          err,
-				StderrOutput: stderr.String(),
-			}
+				StderrOutput: string(stderr),
+			})
 		}
 		return output, nil
 	}
 
 	// For signin command, handle password input
 	if args[0] == "signin" {
-		password, err := readPassword()
+		password, err := cli.credentials().Password(ctx, cli.Account)
 		if err != nil {
 			return nil, fmt.Errorf("error reading password: %v", err)
 		}
 
-		command := fmt.Sprintf("%s %s", cli.Path, strings.Join(cmdArgs, " "))
-		signinCmd := cli.pipePasswordCommand(password, command)
-		return signinCmd.Output()
+		signinCmd := cli.pipePasswordCommand(password, cmdArgs...)
+		output, stderr, err := cli.run(ctx, signinCmd)
+		if err != nil {
+			return nil, &OpCliError{
+				Err:          err,
+				StderrOutput: string(stderr),
+			}
+		}
+		return output, nil
 	}
 
-	// For other interactive commands, run them directly
+	// For other interactive commands, run them directly. The standard error
+	// is shown to the user and captured for the returned error.
 	cmd.Stdin = os.Stdin
 	cmd.Stderr = os.Stderr
-	output, err := cmd.Output() // Updated to use Output() instead of Run()
+	output, stderr, err := cli.run(ctx, cmd)
 	if err != nil {
-		return nil, err
+		return nil, &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		}
 	}
 	return output, nil
 }
@@ -386,37 +460,19 @@ func isInteractiveCommand(args []string) bool {
 	return false
 }
 
-// pipePasswordCommand creates an *exec.Cmd to execute a given command with the
-// specified password piped into its standard input. The password is written
-// to the stdin of the command in a separate goroutine.
+// pipePasswordCommand creates a Command to execute the 1Password CLI with the
+// given arguments and the specified password piped into its standard input.
+// The arguments are passed to the CLI unchanged.
 //
 // Parameters:
 //   - password: The password string to be piped into the command's stdin.
-//   - command: The command to be executed, provided as a string.
+//   - args: The arguments of the command.
 //
 // Returns:
-//   - *exec.Cmd: The configured command object ready for execution, or nil if
-//     an error occurs while creating the stdin pipe.
-//
-// Note:
-//   - The function logs errors using slog if it fails to create the stdin pipe
-//     or write the password to stdin.
-func (cli *OpCLI) pipePasswordCommand(password, command string) *exec.Cmd {
-	cmd := exec.Command(cli.Path, strings.Fields(command)...)
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		slog.Error("failed to create stdin pipe", "error", err)
-		return nil
-	}
-
-	go func() {
-		defer stdin.Close()
-		_, err := stdin.Write([]byte(password + "\n"))
-		if err != nil {
-			slog.Error("failed to write password to stdin", "error", err)
-		}
-	}()
+//   - *Command: The configured command ready for execution.
+func (cli *OpCLI) pipePasswordCommand(password string, args ...string) *Command {
+	cmd := cli.command(args...)
+	cmd.Stdin = strings.NewReader(password + "\n")
 
 	return cmd
 }
@@ -427,6 +483,7 @@ func (cli *OpCLI) pipePasswordCommand(password, command string) *exec.Cmd {
 //
 // Parameters:
 //
+//	ctx  - The context for the command execution.
 //	args - A variadic list of strings representing the command-line arguments
 //	       to pass to the 1Password CLI.
 //
@@ -442,27 +499,170 @@ func (cli *OpCLI) pipePasswordCommand(password, command string) *exec.Cmd {
 //
 // Example:
 //
-//	output, err := cli.ExecuteOpCommand("list", "items")
+//	output, err := cli.ExecuteOpCommand(ctx, "list", "items")
 //	if err != nil {
 //	    log.Fatalf("Command failed: %v", err)
 //	}
 //	fmt.Println(string(output))
-func (cli *OpCLI) ExecuteOpCommand(args ...string) ([]byte, error) {
-	if cli.Account == nil || cli.Account.UserUUID == "" {
-		return nil, fmt.Errorf("account information is missing")
+func (cli *OpCLI) ExecuteOpCommand(ctx context.Context, args ...string) ([]byte, error) {
+	return cli.executeOpCommandWith(ctx, execSettings{}, args...)
+}
+
+// execSettings contains settings of a single command run by executeOpCommandWith.
+type execSettings struct {
+	// dir is the working directory of the command.
+	dir string
+	// stdout receives the standard output as it is written instead of
+	// buffering it. The returned output is empty if stdout is set.
+	stdout io.Writer
+	// stderr receives the standard error as it is written.
+	stderr io.Writer
+	// stdin is the standard input of the command. It is kept in memory, so
+	// the command can be run again after a new sign-in.
+	stdin []byte
+	// env contains additional environment variables of the command.
+	env []string
+	// format is the output format passed with --format. Defaults to json.
+	format string
+	// noDefaultArgs disables the --account and --format arguments and the
+	// requirement of an active account.
+	noDefaultArgs bool
+}
+
+// executeOpCommandWith is like ExecuteOpCommand, but runs the command with the
+// given settings.
+func (cli *OpCLI) executeOpCommandWith(ctx context.Context, settings execSettings, args ...string) ([]byte, error) {
+	// Register the whole command, including refreshes and retries, so Close
+	// only discards the session once no command reads it anymore
+	ctx, finish, err := cli.running.start(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer finish()
 
-	// Append --account and the account ID to the command arguments
-	args = append(args, cli.getDefaultArgs()...)
+	if (cli.Account == nil || cli.Account.UserUUID == "") && !settings.noDefaultArgs {
+		return nil, ErrMissingAccount
+	}
+
+	if err := cli.checkServiceAccountSupport(args); err != nil {
+		return nil, err
+	}
+	if err := cli.checkPlanSupport(args); err != nil {
+		return nil, err
+	}
 
-	cmd := exec.Command(cli.Path, args...)
-	output, err := cmd.Output()
+	// Sign in again before running the command if the session is known to be expired
+	if cli.canRefreshSession() && cli.Account.IsSessionExpired() {
+		cli.log().Debug("session expired, signing in again", "account", cli.Account.UserUUID)
+		if err := cli.refreshSession(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := cli.checkSession(); err != nil {
+		return nil, err
+	}
+
+	if err := cli.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	output, err := cli.runOpCommand(ctx, settings, args...)
+
+	// Retry the command once if the CLI reports an invalid session
+	if err != nil && cli.canRefreshSession() && errors.Is(err, ErrSessionExpired) {
+		cli.log().Debug("session rejected by CLI, signing in again", "account", cli.Account.UserUUID)
+		if err := cli.refreshSession(ctx); err != nil {
+			return nil, err
+		}
+		if err := cli.waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
+		output, err = cli.runOpCommand(ctx, settings, args...)
+	}
+
+	if err != nil {
+		return nil, cli.planError(ctx, args, fmt.Errorf("failed to execute command '%v': %w", args, cli.asSessionExpiredError(err)))
+	}
+
+	if cli.Account != nil {
+		cli.Account.touchSession()
+	}
+	cli.entityCache.invalidateForCommand(args)
+
+	return output, nil
+}
+
+// runOpCommand executes a 1Password CLI command with the default arguments
+// appended using the given settings and returns an OpCliError including
+// stderr if the command fails.
+func (cli *OpCLI) runOpCommand(ctx context.Context, settings execSettings, args ...string) ([]byte, error) {
+	if !settings.noDefaultArgs {
+		// Append --account and the account ID to the command arguments
+		if settings.format != "" {
+			args = append(args, "--account", cli.Account.UserUUID, "--format="+settings.format)
+		} else {
+			args = append(args, cli.getDefaultArgs()...)
+		}
+	}
+
+	cmd := cli.command(args...)
+	cmd.Dir = settings.dir
+	cmd.Stdout = settings.stdout
+	cmd.Stderr = settings.stderr
+	cmd.Env = append(cmd.Env, settings.env...)
+	if settings.stdin != nil {
+		cmd.Stdin = bytes.NewReader(settings.stdin)
+	}
+	output, stderr, err := cli.run(ctx, cmd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute command '%v': %w", args, err)
+		return nil, &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		}
 	}
 	return output, nil
 }
 
+// SetSessionRefresh enables or disables the automatic session refresh in
+// ExecuteOpCommand. It is enabled by default for accounts signed in with
+// SignIn and never applies to service accounts.
+//
+// Parameters:
+//   - enabled: Whether expired sessions should be refreshed automatically.
+func (cli *OpCLI) SetSessionRefresh(enabled bool) {
+	cli.disableSessionRefresh = !enabled
+}
+
+// canRefreshSession reports whether the session of the active account was
+// created by SignIn and may be refreshed automatically.
+func (cli *OpCLI) canRefreshSession() bool {
+	return !cli.disableSessionRefresh &&
+		!cli.isServiceAccount &&
+		cli.Account != nil &&
+		cli.Account.hasSession()
+}
+
+// refreshSession signs in to the active account again. Concurrent callers
+// share a single sign-in: if another goroutine refreshed the session in the
+// meantime, the refresh is skipped.
+func (cli *OpCLI) refreshSession(ctx context.Context) error {
+	cli.refreshMu.Lock()
+	defer cli.refreshMu.Unlock()
+
+	if cli.Account.IsSessionValid() && time.Since(cli.Account.signInTime) < sessionRefreshGrace {
+		return nil
+	}
+
+	if err := cli.SignIn(ctx, cli.Account); err != nil {
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+	return nil
+}
+
+// sessionRefreshGrace is the time after a sign-in during which another
+// refresh is considered redundant.
+const sessionRefreshGrace = 10 * time.Second
+
 // containsArgument checks if a specific argument is present in a slice of strings.
 // It iterates through the provided slice and returns true if the argument is found,
 // otherwise it returns false.
@@ -0,0 +1,158 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// signOutTimeout limits the sign-out of Close.
+const signOutTimeout = 10 * time.Second
+
+// lifecycle tracks the background work of an OpCLI instance that is stopped
+// by Close.
+type lifecycle struct {
+	mu             sync.Mutex
+	closed         bool
+	stopFuncs      []func()
+	signOutOnClose bool
+	ownsConfigDir  bool
+}
+
+// onClose registers a function that stops background work of the OpCLI
+// instance, e.g. a watcher. It is called by Close, or immediately if the
+// instance is already closed.
+func (cli *OpCLI) onClose(stop func()) {
+	cli.lifecycle.mu.Lock()
+	if !cli.lifecycle.closed {
+		cli.lifecycle.stopFuncs = append(cli.lifecycle.stopFuncs, stop)
+		cli.lifecycle.mu.Unlock()
+		return
+	}
+	cli.lifecycle.mu.Unlock()
+	stop()
+}
+
+// isClosed reports whether Close has been called.
+func (cli *OpCLI) isClosed() bool {
+	cli.lifecycle.mu.Lock()
+	defer cli.lifecycle.mu.Unlock()
+
+	return cli.lifecycle.closed
+}
+
+// SetSignOutOnClose sets whether Close signs out of the active account with
+// "op signout" and deletes its session from the SessionStore. Sessions of
+// service accounts do not need to be signed out.
+//
+// Parameters:
+//   - enabled: Whether Close signs out.
+func (cli *OpCLI) SetSignOutOnClose(enabled bool) {
+	cli.lifecycle.mu.Lock()
+	defer cli.lifecycle.mu.Unlock()
+
+	cli.lifecycle.signOutOnClose = enabled
+}
+
+// Close ends the lifecycle of the OpCLI instance so no secrets linger for
+// the lifetime of the process:
+//   - The keep-alive and other background goroutines are stopped.
+//   - Running commands are cancelled and their op processes are waited on.
+//     Commands started after Close fail with ErrClosed.
+//   - If enabled with SetSignOutOnClose, the active account is signed out.
+//   - The session token and service account token are discarded and all
+//     caches are cleared.
+//   - A configuration directory created by WithTempConfigDir is removed.
+//
+// Go strings cannot be overwritten in place, so discarded secrets are only
+// released for garbage collection. Calling Close more than once has no effect.
+//
+// Returns:
+//   - error: An error if the sign-out or the removal of the temporary
+//     configuration directory fails.
+func (cli *OpCLI) Close() error {
+	cli.lifecycle.mu.Lock()
+	if cli.lifecycle.closed {
+		cli.lifecycle.mu.Unlock()
+		return nil
+	}
+	cli.lifecycle.closed = true
+	stopFuncs := cli.lifecycle.stopFuncs
+	cli.lifecycle.stopFuncs = nil
+	signOut := cli.lifecycle.signOutOnClose
+	ownsConfigDir := cli.lifecycle.ownsConfigDir
+	cli.lifecycle.mu.Unlock()
+
+	cli.StopKeepAlive()
+	for _, stop := range stopFuncs {
+		stop()
+	}
+
+	var errs []error
+	if signOut {
+		if err := cli.signOut(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	cli.running.close()
+
+	cli.accesstoken = ""
+	if cli.Account != nil {
+		cli.Account.setSessionToken("")
+	}
+	cli.clearCaches()
+
+	if ownsConfigDir && cli.configDir != "" {
+		if err := os.RemoveAll(cli.configDir); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove config directory: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// signOut signs out of the active account and deletes its stored session.
+func (cli *OpCLI) signOut() error {
+	account := cli.Account
+	if cli.isServiceAccount || account == nil || !account.hasSession() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signOutTimeout)
+	defer cancel()
+
+	_, stderr, err := cli.run(ctx, cli.command("signout", "--account", account.UserUUID))
+	if err != nil {
+		return fmt.Errorf("failed to sign out: %w", &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		})
+	}
+
+	if cli.sessionStore != nil {
+		if err := cli.sessionStore.Delete(account); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return fmt.Errorf("failed to delete stored session: %w", err)
+		}
+	}
+	return nil
+}
+
+// clearCaches discards all cached items, entities and accounts.
+func (cli *OpCLI) clearCaches() {
+	cli.cache = itemCache{items: make(map[string]*Item)}
+
+	cli.entityCache.mu.Lock()
+	cli.entityCache.entries = nil
+	cli.entityCache.mu.Unlock()
+
+	cli.invalidateAccountCache()
+	cli.clearTemplates()
+
+	cli.plan.mu.Lock()
+	cli.plan.accountType = ""
+	cli.plan.mu.Unlock()
+}
@@ -0,0 +1,84 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCloseLifecycle(t *testing.T) {
+	tests := []struct {
+		name            string
+		signOut         bool
+		expectedSignOut bool
+	}{
+		{name: "Keep session", signOut: false},
+		{name: "Sign out", signOut: true, expectedSignOut: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands [][]string
+			executor := CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				commands = append(commands, cmd.Args)
+				return nil, nil, nil
+			})
+
+			account := &Account{UserUUID: "user-uuid"}
+			account.SetSignInInfo("session-token")
+			cli, err := NewOpCLI(WithCommandExecutor(executor), WithAccount(account), WithTempConfigDir())
+			if err != nil {
+				t.Fatalf("NewOpCLI() error = %v", err)
+			}
+			cli.SetSignOutOnClose(tt.signOut)
+			dir := cli.ConfigDir()
+
+			stopped := false
+			cli.onClose(func() { stopped = true })
+
+			if err := cli.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			signedOut := slices.ContainsFunc(commands, func(args []string) bool { return args[0] == "signout" })
+			if signedOut != tt.expectedSignOut {
+				t.Errorf("signed out = %t; want %t", signedOut, tt.expectedSignOut)
+			}
+			if !stopped {
+				t.Errorf("background work was not stopped")
+			}
+			if account.sessionToken != "" {
+				t.Errorf("session token was not discarded")
+			}
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				t.Errorf("temporary config directory %s was not removed", dir)
+			}
+			if _, err := cli.ExecuteOpCommand(context.Background(), "whoami"); !errors.Is(err, ErrClosed) {
+				t.Errorf("command after Close error = %v; want %v", err, ErrClosed)
+			}
+			if err := cli.Close(); err != nil {
+				t.Errorf("second Close() error = %v; want nil", err)
+			}
+		})
+	}
+}
+
+func TestNewOpCLIClosesWithoutExecutable(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("PATH", "")
+
+	if _, err := NewOpCLI(WithTempConfigDir()); err == nil {
+		t.Fatal("NewOpCLI() without op in PATH succeeded; want an error")
+	}
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temporary config directory %s was not removed", entries[0].Name())
+	}
+}
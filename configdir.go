@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"fmt"
+	"os"
+)
+
+// configDirPerm is the permission the 1Password CLI requires for its
+// configuration directory.
+const configDirPerm = 0o700
+
+// SetConfigDir runs all commands of this OpCLI instance with a dedicated
+// configuration directory by setting OP_CONFIG_DIR in their environment.
+// This keeps the configuration and device state of multiple instances,
+// tests, or tenants apart from each other and from the global configuration
+// of the user. The directory is created if it does not exist.
+//
+// Parameters:
+//   - dir: The configuration directory. An empty string restores the default
+//     configuration directory of the 1Password CLI.
+//
+// Returns:
+//   - error: An error if the directory cannot be created or is not private.
+func (cli *OpCLI) SetConfigDir(dir string) error {
+	// A directory set by the caller is never removed by Close
+	cli.lifecycle.ownsConfigDir = false
+
+	if dir == "" {
+		cli.configDir = ""
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, configDirPerm); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// The CLI refuses to use a configuration directory that is accessible by
+	// other users, so an existing directory is restricted to its owner.
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to access config directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("config directory %s is not a directory", dir)
+	}
+	if info.Mode().Perm() != configDirPerm {
+		if err := os.Chmod(dir, configDirPerm); err != nil {
+			return fmt.Errorf("failed to restrict permissions of config directory: %w", err)
+		}
+	}
+
+	cli.configDir = dir
+	return nil
+}
+
+// ConfigDir returns the configuration directory set with SetConfigDir,
+// WithConfigDir, or WithTempConfigDir, or an empty string if commands use the
+// default configuration directory of the 1Password CLI.
+func (cli *OpCLI) ConfigDir() string {
+	return cli.configDir
+}
+
+// newTempConfigDir creates an empty private configuration directory in the
+// temporary directory of the system.
+func newTempConfigDir() (string, error) {
+	dir, err := os.MkdirTemp("", "op-config-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary config directory: %w", err)
+	}
+	return dir, nil
+}
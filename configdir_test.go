@@ -0,0 +1,64 @@
+package onepassword
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestConfigDir(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  func(t *testing.T) Option
+	}{
+		{
+			name: "Dedicated directory",
+			opt:  func(t *testing.T) Option { return WithConfigDir(filepath.Join(t.TempDir(), "tenant")) },
+		},
+		{
+			name: "Temporary directory",
+			opt:  func(t *testing.T) Option { return WithTempConfigDir() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var env []string
+			executor := CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				env = cmd.Env
+				return []byte(`{}`), nil, nil
+			})
+
+			cli, err := NewOpCLI(tt.opt(t), WithCommandExecutor(executor), WithAccount(&Account{UserUUID: "user-uuid"}))
+			if err != nil {
+				t.Fatalf("NewOpCLI() error = %v", err)
+			}
+			dir := cli.ConfigDir()
+			t.Cleanup(func() { os.RemoveAll(dir) })
+
+			if _, err := cli.ExecuteOpCommand(context.Background(), "whoami"); err != nil {
+				t.Fatalf("ExecuteOpCommand() error = %v", err)
+			}
+			if !slices.Contains(env, "OP_CONFIG_DIR="+dir) {
+				t.Errorf("command environment does not set OP_CONFIG_DIR=%s", dir)
+			}
+
+			info, err := os.Stat(dir)
+			if err != nil || info.Mode().Perm() != configDirPerm {
+				t.Errorf("config directory %s: %v, %v; want permissions %o", dir, info, err, os.FileMode(configDirPerm))
+			}
+		})
+	}
+
+	t.Run("Not a directory", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(file, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := (&OpCLI{}).SetConfigDir(file); err == nil {
+			t.Errorf("SetConfigDir() with a file succeeded; want an error")
+		}
+	})
+}
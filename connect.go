@@ -0,0 +1,220 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// connectCredentialsFile is the name of the credentials file written by
+// "op connect server create".
+const connectCredentialsFile = "1password-credentials.json"
+
+// ConnectServer represents a 1Password Connect server.
+type ConnectServer struct {
+	cli *OpCLI `json:"-"` // Reference to the OpCLI instance for update operations
+
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	State         string    `json:"state"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatorID     string    `json:"creator_id"`
+	TokensVersion int       `json:"tokens_version"`
+}
+
+// ConnectServerCreateOptions contains the optional settings for CreateConnectServer.
+//
+// Fields:
+//   - Vaults: The vaults (names or IDs) the Connect server is granted access to.
+type ConnectServerCreateOptions struct {
+	Vaults []string
+}
+
+// CreateConnectServer creates a new 1Password Connect server.
+// It executes the "connect server create" command in a temporary directory and
+// returns the created server together with the contents of the
+// 1password-credentials.json file required to deploy the server.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - name (string): The name of the Connect server.
+//   - opts (ConnectServerCreateOptions): Optional settings for the new server.
+//
+// Returns:
+//   - (*ConnectServer): A pointer to the created ConnectServer object.
+//   - ([]byte): The contents of the credentials file.
+//   - (error): An error if the operation fails.
+func (cli *OpCLI) CreateConnectServer(ctx context.Context, name string, opts ...ConnectServerCreateOptions) (*ConnectServer, []byte, error) {
+	args := []string{"connect", "server", "create", name}
+	if len(opts) > 0 && len(opts[0].Vaults) > 0 {
+		args = append(args, "--vaults", strings.Join(opts[0].Vaults, ","))
+	}
+
+	// The CLI writes the credentials file to the working directory
+	dir, err := os.MkdirTemp("", "op-connect-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := cli.executeOpCommandWith(ctx, execSettings{dir: dir}, args...); err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := os.ReadFile(filepath.Join(dir, connectCredentialsFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Connect credentials file: %w", err)
+	}
+
+	server, err := cli.GetConnectServer(ctx, name)
+	if err != nil {
+		return nil, credentials, err
+	}
+
+	return server, credentials, nil
+}
+
+// ListConnectServers retrieves all Connect servers of the account.
+// It executes the "connect server list" command and parses the output into a slice of ConnectServer objects.
+//
+// Returns:
+//   - ([]ConnectServer): A slice of ConnectServer objects.
+//   - (error): An error if the operation fails.
+func (cli *OpCLI) ListConnectServers(ctx context.Context) ([]ConnectServer, error) {
+	output, err := cli.ExecuteOpCommand(ctx, "connect", "server", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []ConnectServer
+	err = json.Unmarshal(output, &servers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the cli reference for each server
+	for i := range servers {
+		servers[i].cli = cli
+	}
+
+	return servers, nil
+}
+
+// GetConnectServer retrieves a Connect server by its name or ID.
+// It executes the "connect server get" command and parses the output into a ConnectServer object.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - server (string): The name or ID of the Connect server.
+//
+// Returns:
+//   - (*ConnectServer): A pointer to the ConnectServer object.
+//   - (error): An error if the operation fails.
+func (cli *OpCLI) GetConnectServer(ctx context.Context, server string) (*ConnectServer, error) {
+	output, err := cli.ExecuteOpCommand(ctx, "connect", "server", "get", server)
+	if err != nil {
+		return nil, err
+	}
+
+	var connectServer ConnectServer
+	err = json.Unmarshal(output, &connectServer)
+	if err != nil {
+		return nil, err
+	}
+
+	connectServer.cli = cli
+
+	return &connectServer, nil
+}
+
+// Reload re-fetches the Connect server using the "connect server get" command
+// and updates the receiver with the current state.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (server *ConnectServer) Reload(ctx context.Context) error {
+	updatedServer, err := server.cli.GetConnectServer(ctx, server.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload Connect server: %w", err)
+	}
+
+	*server = *updatedServer
+
+	return nil
+}
+
+// SetName updates the name of the Connect server.
+// It executes the "connect server edit" command with the new name and reloads the server afterwards.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - name (string): The new name for the Connect server.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (server *ConnectServer) SetName(ctx context.Context, name string) error {
+	_, err := server.cli.ExecuteOpCommand(ctx, "connect", "server", "edit", server.ID, "--name", name)
+	if err != nil {
+		return err
+	}
+
+	return server.Reload(ctx)
+}
+
+// Delete removes the Connect server.
+// It executes the "connect server delete" command using the server's ID.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (server *ConnectServer) Delete(ctx context.Context) error {
+	_, err := server.cli.ExecuteOpCommand(ctx, "connect", "server", "delete", server.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GrantVault grants the Connect server access to a vault.
+// It executes the "connect vault grant" command.
+//
+// The 1Password CLI does not provide a command to list the vaults a Connect
+// server has access to, so the current grants cannot be retrieved.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - vault (string): The name or ID of the vault.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (server *ConnectServer) GrantVault(ctx context.Context, vault string) error {
+	_, err := server.cli.ExecuteOpCommand(ctx, "connect", "vault", "grant", "--server", server.ID, "--vault", vault)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeVault revokes the access of the Connect server to a vault.
+// It executes the "connect vault revoke" command.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - vault (string): The name or ID of the vault.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (server *ConnectServer) RevokeVault(ctx context.Context, vault string) error {
+	_, err := server.cli.ExecuteOpCommand(ctx, "connect", "vault", "revoke", "--server", server.ID, "--vault", vault)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
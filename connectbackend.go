@@ -0,0 +1,331 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ConnectError represents an error response of a 1Password Connect server.
+type ConnectError struct {
+	StatusCode int    `json:"status"`
+	Message    string `json:"message"`
+}
+
+// Error returns the string representation of the Connect error
+func (e *ConnectError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("connect server returned %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("connect server returned %d", e.StatusCode)
+}
+
+// Is reports whether the status code of the response indicates the target
+// error, e.g. ErrNotFound for 404 Not Found.
+func (e *ConnectError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusForbidden:
+		return target == ErrPermissionDenied
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	}
+	return false
+}
+
+// ConnectBackend is a Backend that talks to a 1Password Connect server over
+// HTTP. It does not require the 1Password CLI.
+//
+// Fields:
+//   - URL: The base URL of the Connect server, e.g. "http://localhost:8080".
+//   - Token: The Connect access token.
+//   - HTTPClient: The client used for requests. If nil, http.DefaultClient is used.
+type ConnectBackend struct {
+	URL        string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewConnectBackend creates a Backend for the Connect server at the given URL.
+//
+// Parameters:
+//   - serverURL: The base URL of the Connect server.
+//   - token: The Connect access token.
+//
+// Returns:
+//   - *ConnectBackend: The Connect backed Backend.
+func NewConnectBackend(serverURL, token string) *ConnectBackend {
+	return &ConnectBackend{
+		URL:   strings.TrimSuffix(serverURL, "/"),
+		Token: token,
+	}
+}
+
+// connectVault is the representation of a vault in the Connect API.
+type connectVault struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description,omitempty"`
+	AttributeVersion int       `json:"attributeVersion"`
+	ContentVersion   int       `json:"contentVersion"`
+	Items            int       `json:"items"`
+	Type             string    `json:"type"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// connectField is the representation of an item field in the Connect API.
+type connectField struct {
+	ID              string           `json:"id,omitempty"`
+	Label           string           `json:"label,omitempty"`
+	Value           string           `json:"value,omitempty"`
+	Type            FieldType        `json:"type,omitempty"`
+	Purpose         string           `json:"purpose,omitempty"`
+	Section         *Section         `json:"section,omitempty"`
+	Entropy         float64          `json:"entropy,omitempty"`
+	PasswordDetails *PasswordDetails `json:"passwordDetails,omitempty"`
+}
+
+// connectItem is the representation of an item in the Connect API.
+type connectItem struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	Vault struct {
+		ID string `json:"id"`
+	} `json:"vault"`
+	Category     Category       `json:"category"`
+	URLs         []ItemURL      `json:"urls,omitempty"`
+	Favorite     bool           `json:"favorite,omitempty"`
+	Tags         []string       `json:"tags,omitempty"`
+	Version      int            `json:"version,omitempty"`
+	Sections     []Section      `json:"sections,omitempty"`
+	Fields       []connectField `json:"fields,omitempty"`
+	LastEditedBy string         `json:"lastEditedBy,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt,omitzero"`
+	UpdatedAt    time.Time      `json:"updatedAt,omitzero"`
+}
+
+// toVault converts a Connect vault into a Vault.
+func (v connectVault) toVault() Vault {
+	return Vault{
+		ID:               v.ID,
+		Name:             v.Name,
+		Description:      v.Description,
+		AttributeVersion: v.AttributeVersion,
+		ContentVersion:   v.ContentVersion,
+		Items:            v.Items,
+		Type:             v.Type,
+		CreatedAt:        v.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        v.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toItem converts a Connect item into an Item.
+func (i connectItem) toItem() Item {
+	item := Item{
+		ID:           i.ID,
+		Title:        i.Title,
+		LastEditedBy: i.LastEditedBy,
+		Vault:        Vault{ID: i.Vault.ID},
+		Category:     i.Category,
+		Favorite:     i.Favorite,
+		Version:      i.Version,
+		CreatedAt:    i.CreatedAt,
+		UpdatedAt:    i.UpdatedAt,
+		Tags:         i.Tags,
+		URLs:         i.URLs,
+		Sections:     i.Sections,
+	}
+
+	for _, f := range i.Fields {
+		item.Fields = append(item.Fields, Field{
+			ID:              f.ID,
+			Label:           f.Label,
+			Value:           f.Value,
+			Type:            f.Type,
+			Purpose:         FieldPurpose(strings.ToLower(f.Purpose)),
+			Section:         f.Section,
+			Entropy:         f.Entropy,
+			PasswordDetails: f.PasswordDetails,
+		})
+	}
+
+	return item
+}
+
+// newConnectItem converts an Item into its Connect representation.
+func newConnectItem(item *Item) connectItem {
+	c := connectItem{
+		ID:       item.ID,
+		Title:    item.Title,
+		Category: item.Category,
+		URLs:     item.URLs,
+		Favorite: item.Favorite,
+		Tags:     item.Tags,
+		Version:  item.Version,
+		Sections: item.Sections,
+	}
+	c.Vault.ID = item.Vault.ID
+
+	for _, f := range item.Fields {
+		c.Fields = append(c.Fields, connectField{
+			ID:      f.ID,
+			Label:   f.Label,
+			Value:   f.Value,
+			Type:    f.Type,
+			Purpose: strings.ToUpper(string(f.Purpose)),
+			Section: f.Section,
+		})
+	}
+
+	return c
+}
+
+// do sends a request to the Connect server and decodes the JSON response into out.
+func (b *ConnectBackend) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to serialize request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.URL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := b.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to connect server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		connectErr := &ConnectError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(connectErr)
+		connectErr.StatusCode = resp.StatusCode
+		return connectErr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode connect server response: %w", err)
+	}
+
+	return nil
+}
+
+// itemsPath returns the API path of the items of a vault.
+func itemsPath(vaultID string) string {
+	return "/v1/vaults/" + url.PathEscape(vaultID) + "/items"
+}
+
+// ListVaults returns all vaults accessible to the Connect token.
+func (b *ConnectBackend) ListVaults(ctx context.Context) ([]Vault, error) {
+	var connectVaults []connectVault
+	if err := b.do(ctx, http.MethodGet, "/v1/vaults", nil, &connectVaults); err != nil {
+		return nil, err
+	}
+
+	vaults := make([]Vault, 0, len(connectVaults))
+	for _, v := range connectVaults {
+		vaults = append(vaults, v.toVault())
+	}
+
+	return vaults, nil
+}
+
+// GetVault returns the vault with the given ID.
+func (b *ConnectBackend) GetVault(ctx context.Context, vaultID string) (*Vault, error) {
+	var v connectVault
+	if err := b.do(ctx, http.MethodGet, "/v1/vaults/"+url.PathEscape(vaultID), nil, &v); err != nil {
+		return nil, err
+	}
+
+	vault := v.toVault()
+	return &vault, nil
+}
+
+// ListItems returns the items of a vault without their fields.
+func (b *ConnectBackend) ListItems(ctx context.Context, vaultID string) ([]Item, error) {
+	var connectItems []connectItem
+	if err := b.do(ctx, http.MethodGet, itemsPath(vaultID), nil, &connectItems); err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(connectItems))
+	for _, i := range connectItems {
+		items = append(items, i.toItem())
+	}
+
+	return items, nil
+}
+
+// GetItem returns an item including all fields.
+func (b *ConnectBackend) GetItem(ctx context.Context, vaultID, itemID string) (*Item, error) {
+	var i connectItem
+	if err := b.do(ctx, http.MethodGet, itemsPath(vaultID)+"/"+url.PathEscape(itemID), nil, &i); err != nil {
+		return nil, err
+	}
+
+	item := i.toItem()
+	return &item, nil
+}
+
+// CreateItem creates an item in the vault referenced by item.Vault.ID.
+func (b *ConnectBackend) CreateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item.ID != "" {
+		return nil, fmt.Errorf("item ID should be empty for new items")
+	}
+
+	var i connectItem
+	if err := b.do(ctx, http.MethodPost, itemsPath(item.Vault.ID), newConnectItem(item), &i); err != nil {
+		return nil, err
+	}
+
+	createdItem := i.toItem()
+	return &createdItem, nil
+}
+
+// UpdateItem replaces an existing item.
+func (b *ConnectBackend) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item.ID == "" {
+		return nil, fmt.Errorf("item ID is empty, cannot update item")
+	}
+
+	var i connectItem
+	path := itemsPath(item.Vault.ID) + "/" + url.PathEscape(item.ID)
+	if err := b.do(ctx, http.MethodPut, path, newConnectItem(item), &i); err != nil {
+		return nil, err
+	}
+
+	updatedItem := i.toItem()
+	return &updatedItem, nil
+}
+
+// DeleteItem deletes an item.
+func (b *ConnectBackend) DeleteItem(ctx context.Context, vaultID, itemID string) error {
+	return b.do(ctx, http.MethodDelete, itemsPath(vaultID)+"/"+url.PathEscape(itemID), nil, nil)
+}
@@ -0,0 +1,63 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConnectBackendGetItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status":401,"message":"Invalid token signature"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/vaults/vault1/items/item1":
+			w.Write([]byte(`{
+				"id": "item1",
+				"title": "Database",
+				"vault": {"id": "vault1"},
+				"category": "LOGIN",
+				"fields": [{"id": "password", "label": "password", "type": "CONCEALED", "purpose": "PASSWORD", "value": "secret"}],
+				"createdAt": "2024-01-02T03:04:05Z"
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":404,"message":"item not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	backend := NewConnectBackend(server.URL+"/", "token")
+
+	item, err := backend.GetItem(context.Background(), "vault1", "item1")
+	if err != nil {
+		t.Fatalf("GetItem() error = %v", err)
+	}
+	if item.Title != "Database" || item.Vault.ID != "vault1" {
+		t.Errorf("GetItem() = %+v; want title Database in vault vault1", item)
+	}
+	if len(item.Fields) != 1 || item.Fields[0].Purpose != FieldPurposePassword || item.Fields[0].Value != "secret" {
+		t.Errorf("GetItem() fields = %+v; want password field", item.Fields)
+	}
+	if item.CreatedAt.IsZero() {
+		t.Errorf("GetItem() CreatedAt is zero")
+	}
+
+	_, err = backend.GetItem(context.Background(), "vault1", "missing")
+	var connectErr *ConnectError
+	if !errors.As(err, &connectErr) || connectErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetItem() error = %v; want ConnectError with status 404", err)
+	}
+
+	backend.Token = "wrong"
+	_, err = backend.GetItem(context.Background(), "vault1", "item1")
+	if !errors.As(err, &connectErr) || connectErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetItem() error = %v; want ConnectError with status 401", err)
+	}
+}
@@ -0,0 +1,164 @@
+package onepassword
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrCredentialUnavailable is returned by a CredentialProvider that cannot
+// supply the requested credential.
+var ErrCredentialUnavailable = errors.New("credential not available")
+
+// ErrSecondFactorRequired is returned by SignIn if the account requires a
+// one-time password and the CredentialProvider cannot supply one.
+var ErrSecondFactorRequired = errors.New("second factor required")
+
+// secondFactorPrompts are lower case substrings of the stderr output of
+// "op signin" that indicate a prompt for a one-time password.
+var secondFactorPrompts = []string{
+	"authentication code",
+	"one-time password",
+	"second factor",
+	"two-factor",
+	"2fa",
+	"totp",
+}
+
+// isSecondFactorPrompt reports whether the stderr output of "op signin"
+// prompts for a one-time password.
+func isSecondFactorPrompt(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, prompt := range secondFactorPrompts {
+		if strings.Contains(stderr, prompt) {
+			return true
+		}
+	}
+	return false
+}
+
+// CredentialProvider supplies the secrets required to sign in to an account.
+// Implementations can prompt in their own UI, read from a secrets manager or
+// return fixtures in tests.
+type CredentialProvider interface {
+	// Password returns the password of the account.
+	Password(ctx context.Context, account *Account) (string, error)
+	// TOTP returns a one-time password for accounts that require a second factor.
+	TOTP(ctx context.Context, account *Account) (string, error)
+}
+
+// SetCredentialProvider sets the CredentialProvider used whenever a sign-in
+// requires a password or one-time password, including automatic session
+// refreshes. Passing nil restores the TerminalCredentialProvider.
+//
+// Parameters:
+//   - provider: The CredentialProvider to use.
+func (cli *OpCLI) SetCredentialProvider(provider CredentialProvider) {
+	cli.credentialProvider = provider
+}
+
+// SetPasswordProvider sets a function that supplies the account password.
+// It is a shorthand for SetCredentialProvider with a PasswordProvider.
+//
+// Parameters:
+//   - provider: The PasswordProvider to use.
+func (cli *OpCLI) SetPasswordProvider(provider PasswordProvider) {
+	if provider == nil {
+		cli.credentialProvider = nil
+		return
+	}
+	cli.credentialProvider = provider
+}
+
+// credentials returns the configured CredentialProvider or the terminal prompt.
+func (cli *OpCLI) credentials() CredentialProvider {
+	if cli.credentialProvider != nil {
+		return cli.credentialProvider
+	}
+	return TerminalCredentialProvider{Logger: cli.log()}
+}
+
+// PasswordProvider is a CredentialProvider that only supplies passwords.
+type PasswordProvider func() (string, error)
+
+// Password returns the password supplied by the function.
+func (f PasswordProvider) Password(ctx context.Context, account *Account) (string, error) {
+	return f()
+}
+
+// TOTP always returns ErrCredentialUnavailable.
+func (f PasswordProvider) TOTP(ctx context.Context, account *Account) (string, error) {
+	return "", ErrCredentialUnavailable
+}
+
+// StaticCredentialProvider is a CredentialProvider returning fixed values,
+// which is mainly useful in tests.
+type StaticCredentialProvider struct {
+	PasswordValue string
+	TOTPValue     string
+}
+
+// Password returns the fixed password, or ErrCredentialUnavailable if it is empty.
+func (p StaticCredentialProvider) Password(ctx context.Context, account *Account) (string, error) {
+	if p.PasswordValue == "" {
+		return "", ErrCredentialUnavailable
+	}
+	return p.PasswordValue, nil
+}
+
+// TOTP returns the fixed one-time password, or ErrCredentialUnavailable if it is empty.
+func (p StaticCredentialProvider) TOTP(ctx context.Context, account *Account) (string, error) {
+	if p.TOTPValue == "" {
+		return "", ErrCredentialUnavailable
+	}
+	return p.TOTPValue, nil
+}
+
+// TerminalCredentialProvider prompts for credentials on the terminal. It is
+// used when no other CredentialProvider is configured, with the logger of
+// the OpCLI instance.
+type TerminalCredentialProvider struct {
+	// Logger logs the prompts. If nil, the default logger of the slog
+	// package is used.
+	Logger *slog.Logger
+}
+
+// log returns the logger of the provider.
+func (p TerminalCredentialProvider) log() *slog.Logger {
+	if p.Logger == nil {
+		return slog.Default()
+	}
+	return p.Logger
+}
+
+// Password prompts the user to enter their 1Password password securely.
+// It disables input echoing to ensure the password is not displayed on the screen.
+func (p TerminalCredentialProvider) Password(ctx context.Context, account *Account) (string, error) {
+	p.log().Debug("prompting for 1Password password")
+	fmt.Print("Enter your 1Password password: ")
+	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println() // Add a newline after the password input
+	if err != nil {
+		p.log().Error("failed to read password", "error", err)
+		return "", err
+	}
+	return string(bytePassword), nil
+}
+
+// TOTP prompts the user to enter a one-time password from their authenticator.
+func (p TerminalCredentialProvider) TOTP(ctx context.Context, account *Account) (string, error) {
+	p.log().Debug("prompting for one-time password")
+	fmt.Print("Enter your one-time password: ")
+	code, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		p.log().Error("failed to read one-time password", "error", err)
+		return "", err
+	}
+	return strings.TrimSpace(code), nil
+}
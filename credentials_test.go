@@ -0,0 +1,59 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSignInSecondFactor(t *testing.T) {
+	tests := []struct {
+		name          string
+		credentials   StaticCredentialProvider
+		expectedStdin string
+		expectedErr   error
+	}{
+		{
+			name:          "One-time password from provider",
+			credentials:   StaticCredentialProvider{PasswordValue: "password", TOTPValue: "123456"},
+			expectedStdin: "password\n123456\n",
+		},
+		{
+			name:        "No one-time password available",
+			credentials: StaticCredentialProvider{PasswordValue: "password"},
+			expectedErr: ErrSecondFactorRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lastStdin string
+			cli := &OpCLI{Path: "op"}
+			cli.SetCredentialProvider(tt.credentials)
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				if cmd.Stdin == nil {
+					return nil, []byte("Enter the password for user@example.com at example.1password.com:"), errors.New("exit status 1")
+				}
+				stdin, _ := io.ReadAll(cmd.Stdin)
+				lastStdin = string(stdin)
+				if lastStdin == "password" {
+					return nil, []byte("Enter your six-digit authentication code:"), errors.New("exit status 1")
+				}
+				return []byte("session-token\n"), nil, nil
+			}))
+
+			account := &Account{UserUUID: "user-uuid", Email: "user@example.com"}
+			err := cli.SignIn(context.Background(), account)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("SignIn() error = %v; want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			if lastStdin != tt.expectedStdin || account.sessionToken != "session-token" {
+				t.Errorf("SignIn() sent %q and got session %q; want %q and session-token", lastStdin, account.sessionToken, tt.expectedStdin)
+			}
+		})
+	}
+}
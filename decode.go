@@ -0,0 +1,323 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DecodeMode controls how the JSON output of the 1Password CLI is checked
+// against the types of the package.
+type DecodeMode int
+
+const (
+	// DecodeTolerant ignores fields the package does not know and records
+	// them for diagnostics, see OpCLI.UnrecognizedFields. This is the default.
+	DecodeTolerant DecodeMode = iota
+
+	// DecodeStrict fails with a DecodeError if the output contains fields the
+	// package does not know or lacks fields it requires. It is intended for
+	// CI jobs that validate the package against a new CLI version.
+	DecodeStrict
+)
+
+// requiredFields lists the JSON fields that must be present in the output
+// for each type. Other fields are omitted by the CLI depending on the
+// command, e.g. the vault of an item only has an ID and a name.
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeFor[Item]():    {"id", "title", "category", "vault"},
+	reflect.TypeFor[Vault]():   {"id", "name"},
+	reflect.TypeFor[User]():    {"id", "email"},
+	reflect.TypeFor[Group]():   {"id", "name"},
+	reflect.TypeFor[Field]():   {"id", "type"},
+	reflect.TypeFor[Section](): {"id"},
+	reflect.TypeFor[Account](): {"user_uuid", "account_uuid"},
+}
+
+// unmarshalerType is the type of json.Unmarshaler, whose implementations
+// are not inspected.
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// DecodeError is returned in DecodeStrict mode if the output of a command
+// does not match the type it is decoded into. It matches ErrSchemaMismatch
+// with errors.Is.
+//
+// Fields:
+//   - Type: The name of the type the output was decoded into.
+//   - Unknown: The paths of fields in the output that the type does not have, e.g. "fields[].totp".
+//   - Missing: The paths of required fields that are not in the output.
+type DecodeError struct {
+	Type    string
+	Unknown []string
+	Missing []string
+
+	// unknownValues are enum fields with values unknown to this package,
+	// which are decoded leniently and only logged.
+	unknownValues []unknownValue
+}
+
+// unknownValue is the value of an enum field that is unknown to this package.
+type unknownValue struct {
+	path  string
+	value any
+}
+
+// enumType is implemented by the enum types of this package, which decode
+// unknown values leniently instead of failing.
+type enumType interface {
+	knownValue(raw string) bool
+}
+
+func (e *DecodeError) Error() string {
+	var problems []string
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown fields "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing fields "+strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("%v: %s has %s", ErrSchemaMismatch, e.Type, strings.Join(problems, " and "))
+}
+
+// Is reports whether target is ErrSchemaMismatch.
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrSchemaMismatch
+}
+
+// UnrecognizedField is a field of the CLI output that the package does not
+// know, recorded in DecodeTolerant mode.
+//
+// Fields:
+//   - Type: The name of the type the output was decoded into.
+//   - Path: The path of the field, e.g. "fields[].totp".
+//   - Count: How often the field was seen.
+type UnrecognizedField struct {
+	Type  string
+	Path  string
+	Count int
+}
+
+// decoding holds the decode mode and the fields recorded in tolerant mode.
+type decoding struct {
+	mu           sync.Mutex
+	mode         DecodeMode
+	unrecognized map[[2]string]int
+}
+
+// SetDecodeMode sets how the output of commands is checked.
+//
+// Parameters:
+//   - mode: DecodeTolerant or DecodeStrict.
+func (cli *OpCLI) SetDecodeMode(mode DecodeMode) {
+	cli.decoding.mu.Lock()
+	defer cli.decoding.mu.Unlock()
+
+	cli.decoding.mode = mode
+}
+
+// UnrecognizedFields returns the fields of the CLI output that the package
+// does not know and that were ignored in DecodeTolerant mode, sorted by
+// type and path. They indicate that the installed CLI is newer than the
+// package.
+//
+// Returns:
+//   - []UnrecognizedField: The recorded fields.
+func (cli *OpCLI) UnrecognizedFields() []UnrecognizedField {
+	cli.decoding.mu.Lock()
+	defer cli.decoding.mu.Unlock()
+
+	fields := make([]UnrecognizedField, 0, len(cli.decoding.unrecognized))
+	for key, count := range cli.decoding.unrecognized {
+		fields = append(fields, UnrecognizedField{Type: key[0], Path: key[1], Count: count})
+	}
+	slices.SortFunc(fields, func(a, b UnrecognizedField) int {
+		return strings.Compare(a.Type+"."+a.Path, b.Type+"."+b.Path)
+	})
+	return fields
+}
+
+// unmarshal decodes the JSON output of a command into v and checks it
+// according to the decode mode.
+func (cli *OpCLI) unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if cli == nil {
+		return nil
+	}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	result := &DecodeError{Type: t.Name()}
+	inspectJSON(raw, reflect.TypeOf(v), "", result)
+	for _, unknown := range result.unknownValues {
+		cli.log().Debug("unknown enum value in CLI output", "type", result.Type, "field", unknown.path, "value", unknown.value)
+	}
+	if len(result.Unknown) == 0 && len(result.Missing) == 0 {
+		return nil
+	}
+
+	cli.decoding.mu.Lock()
+	defer cli.decoding.mu.Unlock()
+
+	if cli.decoding.mode == DecodeStrict {
+		return result
+	}
+
+	if cli.decoding.unrecognized == nil {
+		cli.decoding.unrecognized = make(map[[2]string]int)
+	}
+	for _, path := range result.Unknown {
+		key := [2]string{result.Type, path}
+		if cli.decoding.unrecognized[key] == 0 {
+			cli.log().Debug("ignoring unrecognized field in CLI output", "type", result.Type, "field", path)
+		}
+		cli.decoding.unrecognized[key]++
+	}
+	return nil
+}
+
+// inspectJSON compares a decoded JSON value with the type it was decoded
+// into and records unknown and missing fields. Types that implement
+// json.Unmarshaler are not inspected.
+func inspectJSON(value any, t reflect.Type, path string, result *DecodeError) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		if enum, ok := reflect.Zero(t).Interface().(enumType); ok {
+			if raw, ok := value.(string); !ok || !enum.knownValue(raw) {
+				result.unknownValues = append(result.unknownValues, unknownValue{path: path, value: value})
+			}
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := lookupJSONField(fields, key)
+			if !ok {
+				result.Unknown = appendUnique(result.Unknown, joinJSONPath(path, key))
+				continue
+			}
+			inspectJSON(object[key], fieldType, joinJSONPath(path, key), result)
+		}
+		for _, key := range requiredFields[t] {
+			if !hasJSONKey(object, key) {
+				result.Missing = appendUnique(result.Missing, joinJSONPath(path, key))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		elements, _ := value.([]any)
+		elementPath := path + "[]"
+		if path == "" {
+			elementPath = ""
+		}
+		for _, element := range elements {
+			inspectJSON(element, t.Elem(), elementPath, result)
+		}
+	case reflect.Map:
+		object, _ := value.(map[string]any)
+		for _, key := range sortedKeys(object) {
+			inspectJSON(object[key], t.Elem(), joinJSONPath(path, key), result)
+		}
+	}
+}
+
+// jsonFields returns the JSON names of the exported fields of a struct type,
+// including the fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					if _, ok := fields[embeddedName]; !ok {
+						fields[embeddedName] = embeddedType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupJSONField finds the field of a JSON key like encoding/json, which
+// prefers an exact match and falls back to a case-insensitive one.
+func lookupJSONField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if fieldType, ok := fields[key]; ok {
+		return fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
+
+// hasJSONKey reports whether an object has a key, compared like
+// lookupJSONField.
+func hasJSONKey(object map[string]any, key string) bool {
+	if _, ok := object[key]; ok {
+		return true
+	}
+	for name := range object {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinJSONPath appends a key to a field path.
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// appendUnique appends a value to a slice unless it is already contained.
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,166 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name            string
+		mode            DecodeMode
+		input           string
+		target          func() any
+		expectedUnknown []string
+		expectedMissing []string
+		wantErr         bool
+	}{
+		{
+			name:   "Known fields",
+			mode:   DecodeStrict,
+			input:  `{"id":"v1","name":"Private","items":3,"created_at":"2024-01-01T00:00:00Z"}`,
+			target: func() any { return &Vault{} },
+		},
+		{
+			name:            "Unknown field tolerated",
+			input:           `{"id":"v1","name":"Private","sharing":"on"}`,
+			target:          func() any { return &Vault{} },
+			expectedUnknown: []string{"sharing"},
+		},
+		{
+			name:            "Unknown field strict",
+			mode:            DecodeStrict,
+			input:           `{"id":"v1","name":"Private","sharing":"on"}`,
+			target:          func() any { return &Vault{} },
+			expectedUnknown: []string{"sharing"},
+			wantErr:         true,
+		},
+		{
+			name:            "Missing field strict",
+			mode:            DecodeStrict,
+			input:           `{"id":"u1"}`,
+			target:          func() any { return &User{} },
+			expectedMissing: []string{"email"},
+			wantErr:         true,
+		},
+		{
+			name:   "Missing field tolerated",
+			input:  `{"id":"u1"}`,
+			target: func() any { return &User{} },
+		},
+		{
+			name:            "Nested unknown field",
+			mode:            DecodeStrict,
+			input:           `{"id":"i1","title":"Login","category":"LOGIN","vault":{"id":"v1","name":"Private"},"fields":[{"id":"password","type":"CONCEALED","strength":"FANTASTIC"}]}`,
+			target:          func() any { return &Item{} },
+			expectedUnknown: []string{"fields[].strength"},
+			wantErr:         true,
+		},
+		{
+			name:   "Embedded struct",
+			mode:   DecodeStrict,
+			input:  `[{"id":"u1","email":"a@example.com","role":"MEMBER"}]`,
+			target: func() any { return &[]GroupMember{} },
+		},
+		{
+			name:   "Case-insensitive match",
+			mode:   DecodeStrict,
+			input:  `{"ID":"g1","Name":"Admins"}`,
+			target: func() any { return &Group{} },
+		},
+		{
+			name:    "Invalid JSON",
+			input:   `{"id":`,
+			target:  func() any { return &Vault{} },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{}
+			cli.SetDecodeMode(tt.mode)
+
+			err := cli.unmarshal([]byte(tt.input), tt.target())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unmarshal() error = %v; wantErr %v", err, tt.wantErr)
+			}
+
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				if !errors.Is(err, ErrSchemaMismatch) {
+					t.Errorf("unmarshal() error = %v; want ErrSchemaMismatch", err)
+				}
+				if !slices.Equal(decodeErr.Unknown, tt.expectedUnknown) || !slices.Equal(decodeErr.Missing, tt.expectedMissing) {
+					t.Errorf("unmarshal() unknown = %v, missing = %v; want %v, %v",
+						decodeErr.Unknown, decodeErr.Missing, tt.expectedUnknown, tt.expectedMissing)
+				}
+			}
+
+			if tt.mode == DecodeTolerant && !tt.wantErr {
+				var paths []string
+				for _, field := range cli.UnrecognizedFields() {
+					paths = append(paths, field.Path)
+				}
+				if !slices.Equal(paths, tt.expectedUnknown) {
+					t.Errorf("UnrecognizedFields() = %v; want %v", paths, tt.expectedUnknown)
+				}
+			}
+		})
+	}
+}
+
+func TestUnrecognizedFields(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return []byte(`[{"id":"v1","name":"A","sharing":"on"},{"id":"v2","name":"B","sharing":"off","tier":1}]`), nil, nil
+	}))
+
+	vaults, err := List[Vault](context.Background(), cli, "vault", "list")
+	if err != nil || len(vaults) != 2 {
+		t.Fatalf("List() = %v, %v; want 2 vaults", vaults, err)
+	}
+
+	expected := []UnrecognizedField{
+		{Type: "Vault", Path: "sharing", Count: 2},
+		{Type: "Vault", Path: "tier", Count: 1},
+	}
+	if got := cli.UnrecognizedFields(); !slices.Equal(got, expected) {
+		t.Errorf("UnrecognizedFields() = %v; want %v", got, expected)
+	}
+
+	cli.SetDecodeMode(DecodeStrict)
+	if _, err := List[Vault](context.Background(), cli, "vault", "list"); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("List() in strict mode error = %v; want ErrSchemaMismatch", err)
+	}
+}
+
+func TestUnknownEnumValuesLogged(t *testing.T) {
+	var logs bytes.Buffer
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return []byte(`[{"id":"u1","type":"ROBOT","state":"active"}]`), nil, nil
+	}))
+
+	users, err := cli.ListUsers(context.Background())
+	if err != nil || len(users) != 1 {
+		t.Fatalf("ListUsers() = %v, %v; want 1 user", users, err)
+	}
+	if users[0].Type != UserTypeUnknown || users[0].State != UserStateActive {
+		t.Errorf("ListUsers() = %+v; want unknown type and active state", users[0])
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, "unknown enum value in CLI output") || !strings.Contains(output, "field=type value=ROBOT") {
+		t.Errorf("log output = %q; want the unknown type", output)
+	}
+	if strings.Contains(output, "field=state") {
+		t.Errorf("log output = %q; want no entry for the known state", output)
+	}
+}
@@ -0,0 +1,245 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// secretDirPerm is the permission of directories created for secret files.
+	secretDirPerm = 0o700
+
+	// secretFilePerm is the permission of secret files.
+	secretFilePerm = 0o600
+)
+
+// envNamePattern matches valid names of environment variables.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DefaultSecretsDir returns the directory in which WriteDockerSecrets creates
+// secret files if no directory is given. On Linux, this is the tmpfs mounted
+// at /dev/shm, so secrets are never written to disk. On other systems, it is
+// the temporary directory.
+func DefaultSecretsDir() string {
+	if runtime.GOOS == "linux" {
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			return "/dev/shm"
+		}
+	}
+	return os.TempDir()
+}
+
+// DockerSecrets is a set of secret files written by WriteDockerSecrets, e.g.
+// to be mounted as Docker secrets or passed to a compose file with
+// "secrets: name: file: <path>".
+//
+// Fields:
+//   - Dir: The directory containing the secret files.
+//   - Files: The paths of the secret files, keyed by secret name.
+type DockerSecrets struct {
+	Dir   string
+	Files map[string]string
+
+	ownsDir bool
+	once    sync.Once
+	err     error
+}
+
+// Cleanup removes the secret files, and the directory if it was created by
+// WriteDockerSecrets. It is also called by OpCLI.Close. Calling Cleanup more
+// than once has no effect.
+//
+// Returns:
+//   - error: An error if a file or the directory cannot be removed.
+func (s *DockerSecrets) Cleanup() error {
+	s.once.Do(func() {
+		var errs []error
+		for _, path := range s.Files {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+		if s.ownsDir {
+			if err := os.RemoveAll(s.Dir); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		s.err = errors.Join(errs...)
+	})
+	return s.err
+}
+
+// WriteDockerSecrets reads secret references and writes each value to a file
+// named after its secret, readable only by the current user. The files are
+// removed by DockerSecrets.Cleanup or when the OpCLI instance is closed.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - dir: The directory for the secret files. If empty, a new directory is created in DefaultSecretsDir.
+//   - secrets: The secret references, keyed by secret name, e.g. {"db_password": "op://Prod/DB/password"}.
+//
+// Returns:
+//   - *DockerSecrets: The written secret files.
+//   - error: An error if a secret name is invalid, a reference cannot be read or a file cannot be written.
+//     Files written before the error are removed.
+//
+// Example usage:
+//
+//	secrets, err := cli.WriteDockerSecrets(ctx, "", map[string]string{
+//	    "db_password": "op://Prod/Database/password",
+//	})
+//	if err != nil {
+//	    log.Fatalf("Failed to write secrets: %v", err)
+//	}
+//	defer secrets.Cleanup()
+func (cli *OpCLI) WriteDockerSecrets(ctx context.Context, dir string, secrets map[string]string) (*DockerSecrets, error) {
+	for name := range secrets {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid secret name %q", name)
+		}
+	}
+
+	values, err := cli.ResolveSecrets(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DockerSecrets{Dir: dir, Files: make(map[string]string, len(values))}
+	if dir == "" {
+		result.Dir, err = os.MkdirTemp(DefaultSecretsDir(), "op-secrets-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secrets directory: %w", err)
+		}
+		result.ownsDir = true
+	} else {
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			result.ownsDir = true
+		}
+		if err := os.MkdirAll(dir, secretDirPerm); err != nil {
+			return nil, fmt.Errorf("failed to create secrets directory: %w", err)
+		}
+	}
+
+	for _, name := range sortedKeys(values) {
+		path := filepath.Join(result.Dir, name)
+		if err := writeSecretFile(path, []byte(values[name])); err != nil {
+			result.Cleanup()
+			return nil, err
+		}
+		result.Files[name] = path
+	}
+
+	cli.onClose(func() {
+		if err := result.Cleanup(); err != nil {
+			cli.log().Warn("failed to remove secret files", "dir", result.Dir, "error", err)
+		}
+	})
+
+	return result, nil
+}
+
+// RenderEnvFile reads secret references and renders them as an env file that
+// can be used with "env_file" in a compose file. Values that contain
+// whitespace, quotes or other special characters are quoted, and "$" is
+// escaped as "$$" in double-quoted values, so compose does not interpolate
+// it. Note that "docker run --env-file" does not remove quotes.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - secrets: The secret references, keyed by environment variable name.
+//
+// Returns:
+//   - []byte: The env file, with variables sorted by name.
+//   - error: An error if a variable name is invalid or a reference cannot be read.
+func (cli *OpCLI) RenderEnvFile(ctx context.Context, secrets map[string]string) ([]byte, error) {
+	for name := range secrets {
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid environment variable name %q", name)
+		}
+	}
+
+	values, err := cli.ResolveSecrets(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	for _, name := range sortedKeys(values) {
+		builder.WriteString(name)
+		builder.WriteByte('=')
+		builder.WriteString(quoteEnvValue(values[name]))
+		builder.WriteByte('\n')
+	}
+
+	return []byte(builder.String()), nil
+}
+
+// WriteEnvFile renders the secret references with RenderEnvFile and writes
+// the env file to path, readable only by the current user. The caller is
+// responsible for removing the file.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - path: The path of the env file, preferably on a tmpfs like DefaultSecretsDir.
+//   - secrets: The secret references, keyed by environment variable name.
+//
+// Returns:
+//   - error: An error if the env file cannot be rendered or written.
+func (cli *OpCLI) WriteEnvFile(ctx context.Context, path string, secrets map[string]string) error {
+	content, err := cli.RenderEnvFile(ctx, secrets)
+	if err != nil {
+		return err
+	}
+
+	return writeSecretFile(path, content)
+}
+
+// quoteEnvValue quotes a value of an env file if necessary.
+func quoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/@+=%", r))
+	}) {
+		return value
+	}
+
+	// Single quoted values are taken literally
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", "$$")
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// writeSecretFile writes a file that is only accessible by the current user.
+// An existing file is replaced.
+func writeSecretFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".op-secret-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	err = tmp.Chmod(secretFilePerm)
+	if err == nil {
+		_, err = tmp.Write(data)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package onepassword
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newSecretsCLI returns an OpCLI instance that reads the value of a
+// reference from the given map.
+func newSecretsCLI(values map[string]string) *OpCLI {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return []byte(values[cmd.Args[2]]), nil, nil
+	}))
+	return cli
+}
+
+func TestWriteDockerSecrets(t *testing.T) {
+	cli := newSecretsCLI(map[string]string{"op://Prod/Database/password": "s3cr3t"})
+	ctx := context.Background()
+
+	dir := filepath.Join(t.TempDir(), "secrets")
+	secrets, err := cli.WriteDockerSecrets(ctx, dir, map[string]string{"db_password": "op://Prod/Database/password"})
+	if err != nil {
+		t.Fatalf("WriteDockerSecrets() error = %v", err)
+	}
+
+	path := secrets.Files["db_password"]
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "s3cr3t" {
+		t.Fatalf("secret file = %q, %v; want %q", content, err, "s3cr3t")
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != secretFilePerm {
+			t.Errorf("secret file mode = %v, %v; want %v", info.Mode().Perm(), err, os.FileMode(secretFilePerm))
+		}
+	}
+
+	// Close removes the secrets and the directory created for them
+	if err := cli.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("secrets directory exists after Close(): %v", err)
+	}
+
+	if _, err := newSecretsCLI(nil).WriteDockerSecrets(ctx, dir, map[string]string{"../escape": "op://Prod/Database/password"}); err == nil {
+		t.Error("WriteDockerSecrets() with path in secret name succeeded")
+	}
+}
+
+func TestRenderEnvFile(t *testing.T) {
+	cli := newSecretsCLI(map[string]string{
+		"op://Prod/Database/user":     "admin",
+		"op://Prod/Database/password": "p@ss word$1",
+		"op://Prod/Database/key":      "line1\nit's $HOME",
+	})
+
+	content, err := cli.RenderEnvFile(context.Background(), map[string]string{
+		"DB_USER":     "op://Prod/Database/user",
+		"DB_PASSWORD": "op://Prod/Database/password",
+		"DB_KEY":      "op://Prod/Database/key",
+	})
+	if err != nil {
+		t.Fatalf("RenderEnvFile() error = %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`DB_KEY="line1\nit's $$HOME"`,
+		`DB_PASSWORD='p@ss word$1'`,
+		`DB_USER=admin`,
+	}, "\n") + "\n"
+	if string(content) != expected {
+		t.Errorf("RenderEnvFile() =\n%s\nwant\n%s", content, expected)
+	}
+
+	if _, err := cli.RenderEnvFile(context.Background(), map[string]string{"1INVALID": "op://Prod/Database/user"}); err == nil {
+		t.Error("RenderEnvFile() with invalid variable name succeeded")
+	}
+}
@@ -0,0 +1,124 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// mutatingVerbs are the subcommands of the 1Password CLI that change data.
+var mutatingVerbs = []string{
+	"add", "clear", "confirm", "create", "delete", "edit", "forget", "grant",
+	"init", "move", "provision", "reactivate", "remove", "revoke", "share",
+	"suspend",
+}
+
+// nestedCommandNouns are the second words of nested subcommands, e.g. "user"
+// in "vault user grant" or "template" in "item template get".
+var nestedCommandNouns = []string{"group", "server", "template", "token", "user", "vault"}
+
+// dryRun holds the state of the dry-run mode of an OpCLI instance.
+type dryRun struct {
+	mu       sync.Mutex
+	enabled  bool
+	commands []CommandInfo
+}
+
+// SetDryRun enables or disables the dry-run mode. In dry-run mode, commands
+// that change data, e.g. "item create", "vault edit", "group user grant" or
+// "user suspend", are not run. The command that would have run is logged and
+// recorded, see DryRunCommands, and an empty JSON object is returned as a
+// placeholder result. The standard input is never echoed, as it may contain
+// secrets like item passwords or account credentials. After hooks are called
+// for skipped commands with CommandResult.DryRun set. Commands that only read
+// data are run as usual.
+//
+// Parameters:
+//   - enabled: Whether commands that change data are skipped.
+func (cli *OpCLI) SetDryRun(enabled bool) {
+	cli.dryRun.mu.Lock()
+	defer cli.dryRun.mu.Unlock()
+
+	cli.dryRun.enabled = enabled
+}
+
+// DryRunCommands returns the commands that were skipped in dry-run mode, in
+// the order they would have run.
+//
+// Returns:
+//   - []CommandInfo: The skipped commands.
+//
+// Example usage:
+//
+//	cli.SetDryRun(true)
+//	// ... run automation ...
+//	for _, cmd := range cli.DryRunCommands() {
+//	    fmt.Println("would run: op", strings.Join(cmd.Args, " "))
+//	}
+func (cli *OpCLI) DryRunCommands() []CommandInfo {
+	cli.dryRun.mu.Lock()
+	defer cli.dryRun.mu.Unlock()
+
+	return slices.Clone(cli.dryRun.commands)
+}
+
+// dryRunKey is the context key that enables the dry-run mode for the
+// commands of a single operation, see withDryRun.
+type dryRunKey struct{}
+
+// withDryRun returns a context in which commands that change data are
+// skipped and recorded like in dry-run mode, even if it is not enabled for
+// the OpCLI instance.
+func withDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// isDryRun reports whether commands that change data are skipped in the
+// context, because dry-run mode is enabled for the instance or the operation.
+func (cli *OpCLI) isDryRun(ctx context.Context) bool {
+	cli.dryRun.mu.Lock()
+	enabled := cli.dryRun.enabled
+	cli.dryRun.mu.Unlock()
+
+	forced, _ := ctx.Value(dryRunKey{}).(bool)
+	return enabled || forced
+}
+
+// dryRunOutput is the placeholder output of commands skipped in dry-run mode.
+var dryRunOutput = []byte("{}")
+
+// skipForDryRun records the command and returns its placeholder output if it
+// changes data and dry-run mode is enabled.
+func (cli *OpCLI) skipForDryRun(ctx context.Context, cmd *Command, info CommandInfo) ([]byte, bool) {
+	if !isMutatingCommand(cmd.Args) || !cli.isDryRun(ctx) {
+		return nil, false
+	}
+
+	cli.dryRun.mu.Lock()
+	cli.dryRun.commands = append(cli.dryRun.commands, info)
+	cli.dryRun.mu.Unlock()
+
+	cli.log().InfoContext(ctx, "dry run, skipping command",
+		"command", info.Name,
+		"args", strings.Join(redactArgs(info.Args), " "))
+
+	return slices.Clone(dryRunOutput), true
+}
+
+// isMutatingCommand reports whether the arguments run a command that changes
+// data, e.g. "item edit" or "vault user grant". Commands run with the
+// --dry-run flag of the CLI only preview their result and do not change data.
+func isMutatingCommand(args []string) bool {
+	if len(args) < 2 || slices.Contains(args, "--dry-run") {
+		return false
+	}
+
+	verb := args[1]
+	// Nested commands like "vault user grant" or "connect server create"
+	if slices.Contains(nestedCommandNouns, verb) && len(args) > 2 {
+		verb = args[2]
+	}
+
+	return slices.Contains(mutatingVerbs, verb)
+}
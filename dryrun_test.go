@@ -0,0 +1,156 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIsMutatingCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{name: "Item list", args: []string{"item", "list"}, expected: false},
+		{name: "Item get named like a verb", args: []string{"item", "get", "delete"}, expected: false},
+		{name: "Vault user list", args: []string{"vault", "user", "list", "vault-id"}, expected: false},
+		{name: "Item create", args: []string{"item", "create", "--vault", "Private"}, expected: true},
+		{name: "Vault edit", args: []string{"vault", "edit", "vault-id", "--name", "New"}, expected: true},
+		{name: "Vault user grant", args: []string{"vault", "user", "grant", "--vault", "vault-id"}, expected: true},
+		{name: "Connect server delete", args: []string{"connect", "server", "delete", "server-id"}, expected: true},
+		{name: "User suspend", args: []string{"user", "suspend", "user-id"}, expected: true},
+		{name: "Whoami", args: []string{"whoami"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMutatingCommand(tt.args); got != tt.expected {
+				t.Errorf("isMutatingCommand(%q) = %v; want %v", tt.args, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedRun    bool
+		expectedOutput string
+	}{
+		{
+			name:           "Read command runs",
+			args:           []string{"vault", "list"},
+			expectedRun:    true,
+			expectedOutput: `[]`,
+		},
+		{
+			name:           "Delete is skipped",
+			args:           []string{"item", "delete", "item-id"},
+			expectedOutput: `{}`,
+		},
+		{
+			name:           "Create does not echo the template",
+			args:           []string{"item", "create", "--vault", "Private"},
+			stdin:          `{"title":"New","fields":[{"value":"secret"}]}`,
+			expectedOutput: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			var results []CommandResult
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetDryRun(true)
+			cli.OnAfterExec(func(ctx context.Context, info CommandInfo, result CommandResult, err error) {
+				results = append(results, result)
+			})
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				ran = true
+				return []byte(`[]`), nil, nil
+			}))
+
+			cmd := cli.command(tt.args...)
+			if tt.stdin != "" {
+				cmd.Stdin = strings.NewReader(tt.stdin)
+			}
+			output, _, err := cli.run(context.Background(), cmd)
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if ran != tt.expectedRun {
+				t.Errorf("command ran = %v; want %v", ran, tt.expectedRun)
+			}
+			if string(output) != tt.expectedOutput {
+				t.Errorf("run() output = %q; want %q", output, tt.expectedOutput)
+			}
+
+			if len(results) != 1 || results[0].DryRun == tt.expectedRun {
+				t.Errorf("after hook results = %+v; want one with DryRun %v", results, !tt.expectedRun)
+			}
+
+			recorded := cli.DryRunCommands()
+			if tt.expectedRun {
+				if len(recorded) != 0 {
+					t.Errorf("DryRunCommands() = %v; want none", recorded)
+				}
+				return
+			}
+			if len(recorded) != 1 || !slices.Equal(recorded[0].Args, tt.args) {
+				t.Errorf("DryRunCommands() = %v; want %q", recorded, tt.args)
+			}
+		})
+	}
+}
+
+func TestVaultDeleteWithReport(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          VaultDeleteOptions
+		clientDryRun  bool
+		expectDeleted bool
+	}{
+		{name: "Dry run option", opts: VaultDeleteOptions{DryRun: true}},
+		{name: "Client dry run", clientDryRun: true},
+		{name: "Delete", expectDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetLogger(nil)
+			cli.SetDryRun(tt.clientDryRun)
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				if commandName(cmd.Args) == "vault delete" {
+					deleted = true
+					return nil, nil, nil
+				}
+				return []byte(`{"id":"abcdefghijklmnopqrstuvwxyz","name":"Old","items":3}`), nil, nil
+			}))
+
+			vault := &Vault{ID: "abcdefghijklmnopqrstuvwxyz", cli: cli}
+			report, err := vault.DeleteWithReport(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("DeleteWithReport() error = %v", err)
+			}
+
+			expected := VaultDeleteReport{VaultID: vault.ID, Name: "Old", Items: 3, Deleted: tt.expectDeleted}
+			if *report != expected {
+				t.Errorf("DeleteWithReport() = %+v; want %+v", *report, expected)
+			}
+			if deleted != tt.expectDeleted {
+				t.Errorf("vault deleted = %v; want %v", deleted, tt.expectDeleted)
+			}
+
+			recorded := cli.DryRunCommands()
+			if tt.expectDeleted != (len(recorded) == 0) {
+				t.Errorf("DryRunCommands() = %v; want the skipped delete in dry-run mode", recorded)
+			}
+		})
+	}
+}
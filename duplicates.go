@@ -0,0 +1,147 @@
+package onepassword
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DuplicateKey is the normalized identity of an item used by
+// FindDuplicateItems. Titles and usernames are compared case-insensitively
+// with collapsed whitespace, URLs without scheme, "www." prefix and
+// trailing slash.
+//
+// Fields:
+//   - Title: The normalized title.
+//   - Username: The normalized username, taken from the additional information of the item list.
+//   - URL: The normalized primary URL, or the first URL if none is primary.
+type DuplicateKey struct {
+	Title    string
+	Username string
+	URL      string
+}
+
+// DuplicateGroup is a set of items that are likely duplicates of each other.
+//
+// Fields:
+//   - Key: The normalized identity shared by the items.
+//   - Items: The items, oldest first.
+type DuplicateGroup struct {
+	Key   DuplicateKey
+	Items []Item
+}
+
+// IDs returns the IDs of the items in the group.
+func (g DuplicateGroup) IDs() []string {
+	ids := make([]string, len(g.Items))
+	for i, item := range g.Items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// FindDuplicateItems groups the items of the given vaults, or of all vaults
+// if none are given, by normalized title, username and primary URL, and
+// returns the groups with more than one item. Duplicates are found across
+// the given vaults. The items are listed with "item list", without fetching
+// their fields.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - vaults: The vaults to search. All vaults if empty.
+//
+// Returns:
+//   - []DuplicateGroup: The groups of likely duplicates, sorted by title.
+//   - error: An error if the items cannot be listed.
+//
+// Example usage:
+//
+//	groups, err := cli.FindDuplicateItems(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to find duplicates: %v", err)
+//	}
+//	for _, group := range groups {
+//	    fmt.Printf("%s: %v\n", group.Key.Title, group.IDs())
+//	}
+func (cli *OpCLI) FindDuplicateItems(ctx context.Context, vaults ...Vault) ([]DuplicateGroup, error) {
+	scopes := []ListItemsOptions{{}}
+	if len(vaults) > 0 {
+		scopes = nil
+		for _, vault := range vaults {
+			scopes = append(scopes, ListItemsOptions{Vault: vault.ID})
+		}
+	}
+
+	byKey := make(map[DuplicateKey][]Item)
+	for _, scope := range scopes {
+		err := cli.StreamItems(ctx, func(item Item) error {
+			key := duplicateKey(item)
+			byKey[key] = append(byKey[key], item)
+			return nil
+		}, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list items: %w", err)
+		}
+	}
+
+	var groups []DuplicateGroup
+	for key, items := range byKey {
+		if len(items) < 2 {
+			continue
+		}
+		slices.SortStableFunc(items, func(a, b Item) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+		groups = append(groups, DuplicateGroup{Key: key, Items: items})
+	}
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		return cmp.Or(
+			strings.Compare(a.Key.Title, b.Key.Title),
+			strings.Compare(a.Key.Username, b.Key.Username),
+			strings.Compare(a.Key.URL, b.Key.URL),
+		)
+	})
+
+	return groups, nil
+}
+
+// duplicateKey returns the normalized identity of an item.
+func duplicateKey(item Item) DuplicateKey {
+	return DuplicateKey{
+		Title:    normalizeText(item.Title),
+		Username: normalizeText(item.AdditionalInfo),
+		URL:      normalizeItemURL(primaryURL(item.URLs)),
+	}
+}
+
+// primaryURL returns the primary URL, or the first URL if none is primary.
+func primaryURL(urls []ItemURL) string {
+	for _, url := range urls {
+		if url.Primary {
+			return url.Href
+		}
+	}
+	if len(urls) > 0 {
+		return urls[0].Href
+	}
+	return ""
+}
+
+// normalizeText lower-cases a string and collapses its whitespace.
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// normalizeItemURL lower-cases a URL and removes its scheme, "www." prefix
+// and trailing slashes, e.g. "example.com/login" for
+// "https://www.Example.com/login/".
+func normalizeItemURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		url = rest
+	}
+	url = strings.TrimPrefix(url, "www.")
+	return strings.TrimRight(url, "/")
+}
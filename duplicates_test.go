@@ -0,0 +1,47 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFindDuplicateItems(t *testing.T) {
+	outputs := map[string]string{
+		"v1": `[
+			{"id":"1","title":"GitHub","category":"LOGIN","additional_information":"octocat","urls":[{"href":"https://github.com/login","primary":true}],"created_at":"2024-01-01T00:00:00Z"},
+			{"id":"2","title":"Database","category":"LOGIN","additional_information":"admin"}
+		]`,
+		"v2": `[
+			{"id":"3","title":" github ","category":"LOGIN","additional_information":"OctoCat","urls":[{"href":"http://www.GitHub.com/login/"}],"created_at":"2023-01-01T00:00:00Z"},
+			{"id":"4","title":"GitHub","category":"LOGIN","additional_information":"other","urls":[{"href":"https://github.com/login","primary":true}]},
+			{"id":"5","title":"Database","category":"LOGIN","additional_information":"admin"}
+		]`,
+	}
+
+	var listed []string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		vault := cmd.Args[slices.Index(cmd.Args, "--vault")+1]
+		listed = append(listed, vault)
+		return []byte(outputs[vault]), nil, nil
+	}))
+
+	groups, err := cli.FindDuplicateItems(context.Background(), Vault{ID: "v1"}, Vault{ID: "v2"})
+	if err != nil {
+		t.Fatalf("FindDuplicateItems() error = %v", err)
+	}
+	if !slices.Equal(listed, []string{"v1", "v2"}) {
+		t.Errorf("listed vaults = %v; want v1 and v2", listed)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("FindDuplicateItems() = %d groups; want 2: %+v", len(groups), groups)
+	}
+	if groups[0].Key != (DuplicateKey{Title: "database", Username: "admin"}) || !slices.Equal(groups[0].IDs(), []string{"2", "5"}) {
+		t.Errorf("groups[0] = %+v, IDs %v; want items 2 and 5", groups[0].Key, groups[0].IDs())
+	}
+	if groups[1].Key != (DuplicateKey{Title: "github", Username: "octocat", URL: "github.com/login"}) || !slices.Equal(groups[1].IDs(), []string{"3", "1"}) {
+		t.Errorf("groups[1] = %+v, IDs %v; want items 3 and 1, oldest first", groups[1].Key, groups[1].IDs())
+	}
+}
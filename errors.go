@@ -0,0 +1,136 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors reported by the 1Password CLI. An OpCliError matches one of these
+// errors with errors.Is if its stderr output indicates the failure, e.g.:
+//
+//	if errors.Is(err, onepassword.ErrNotFound) {
+//	    // create the item
+//	}
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrMoreThanOneMatch = errors.New("more than one match")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrSessionExpired   = errors.New("session expired")
+	ErrRateLimited      = errors.New("rate limited")
+)
+
+// Errors for specific objects that were not found. Each of them also matches
+// ErrNotFound with errors.Is.
+var (
+	ErrItemNotFound    = fmt.Errorf("item %w", ErrNotFound)
+	ErrVaultNotFound   = fmt.Errorf("vault %w", ErrNotFound)
+	ErrUserNotFound    = fmt.Errorf("user %w", ErrNotFound)
+	ErrGroupNotFound   = fmt.Errorf("group %w", ErrNotFound)
+	ErrAccountNotFound = fmt.Errorf("account %w", ErrNotFound)
+	ErrFieldNotFound   = fmt.Errorf("field %w", ErrNotFound)
+	ErrSectionNotFound = fmt.Errorf("section %w", ErrNotFound)
+	ErrTagNotFound     = fmt.Errorf("tag %w", ErrNotFound)
+	ErrURLNotFound     = fmt.Errorf("url %w", ErrNotFound)
+)
+
+// Errors returned by the package for invalid operations.
+var (
+	// ErrMissingAccount is returned by commands that require an active
+	// account if the OpCLI instance has none, e.g. before SignIn.
+	ErrMissingAccount = errors.New("account information is missing")
+
+	// ErrDuplicateSectionID is returned when a section is added to an item
+	// that already has a section with the same ID.
+	ErrDuplicateSectionID = errors.New("section ID is not unique within item")
+
+	// ErrLastURL is returned when the last URL of an item is removed, which
+	// the 1Password CLI does not support.
+	ErrLastURL = errors.New("cannot delete the last URL due to a known issue in the 1Password CLI")
+
+	// ErrNoClient is returned by methods of items that are not associated
+	// with an OpCLI instance.
+	ErrNoClient = errors.New("item is not associated with an OpCLI instance")
+
+	// ErrUnknownRole is returned when a Role that is not one of the
+	// predefined roles is granted.
+	ErrUnknownRole = errors.New("unknown role")
+
+	// ErrUnknownPermission is returned when a value cannot be parsed as a
+	// Permission.
+	ErrUnknownPermission = errors.New("unknown permission")
+
+	// ErrUnsupportedPermission is returned when a permission is granted
+	// that the account plan or vault type does not support.
+	ErrUnsupportedPermission = errors.New("unsupported permission")
+
+	// ErrInvalidReference is returned for malformed secret references.
+	ErrInvalidReference = errors.New("invalid secret reference")
+
+	// ErrSchemaMismatch is returned in DecodeStrict mode when the output of
+	// the CLI does not match the types of this package.
+	ErrSchemaMismatch = errors.New("CLI output does not match the expected schema")
+)
+
+// cliErrorPatterns maps the errors reported by the CLI to lower case
+// substrings of the stderr output that indicate them.
+var cliErrorPatterns = []struct {
+	err      error
+	patterns []string
+}{
+	{ErrSessionExpired, []string{
+		"session expired",
+		"not currently signed in",
+		"invalid session token",
+		"you are not signed in",
+		"authentication required",
+	}},
+	{ErrRateLimited, []string{
+		"rate limit",
+		"too many requests",
+		"(429)",
+	}},
+	{ErrMoreThanOneMatch, []string{
+		"more than one",
+	}},
+	{ErrPermissionDenied, []string{
+		"permission denied",
+		"do not have permission",
+		"don't have permission",
+		"not authorized",
+		"forbidden",
+		"(403)",
+	}},
+	{ErrItemNotFound, []string{
+		"isn't an item",
+		"no item found",
+	}},
+	{ErrVaultNotFound, []string{
+		"isn't a vault",
+	}},
+	{ErrUserNotFound, []string{
+		"isn't a user",
+	}},
+	{ErrGroupNotFound, []string{
+		"isn't a group",
+	}},
+	{ErrNotFound, []string{
+		"isn't a connect server",
+		"not found",
+		"(404)",
+	}},
+}
+
+// classifyCLIError returns the error reported by the CLI for the given
+// stderr output, or nil if the output does not indicate a known failure.
+func classifyCLIError(stderr string) error {
+	stderr = strings.ToLower(stderr)
+	for _, entry := range cliErrorPatterns {
+		for _, pattern := range entry.patterns {
+			if strings.Contains(stderr, pattern) {
+				return entry.err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOpCliErrorIs(t *testing.T) {
+	tests := []struct {
+		name     string
+		stderr   string
+		expected error
+	}{
+		{
+			name:     "Item not found",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 "Database" isn't an item. Specify the item with its UUID, name, or domain.`,
+			expected: ErrItemNotFound,
+		},
+		{
+			name:     "Vault not found",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 "Shared" isn't a vault in this account. Specify the vault with its ID or name.`,
+			expected: ErrVaultNotFound,
+		},
+		{
+			name:     "Connect server not found",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 "Server" isn't a connect server in this account.`,
+			expected: ErrNotFound,
+		},
+		{
+			name:     "More than one match",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 More than one item matches "Database". Try again and specify the item by its ID`,
+			expected: ErrMoreThanOneMatch,
+		},
+		{
+			name:     "Permission denied",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 You do not have permission to perform this action`,
+			expected: ErrPermissionDenied,
+		},
+		{
+			name:     "Session expired",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 You are not currently signed in. Please run 'op signin --help' for instructions`,
+			expected: ErrSessionExpired,
+		},
+		{
+			name:     "Rate limited",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 Too many requests (429)`,
+			expected: ErrRateLimited,
+		},
+		{
+			name:     "Unknown error",
+			stderr:   `[ERROR] 2024/01/02 03:04:05 something went wrong`,
+			expected: nil,
+		},
+	}
+
+	sentinels := []error{ErrNotFound, ErrItemNotFound, ErrVaultNotFound, ErrMoreThanOneMatch, ErrPermissionDenied, ErrSessionExpired, ErrRateLimited}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to execute command: %w", &OpCliError{
+				Err:          errors.New("exit status 1"),
+				StderrOutput: tt.stderr,
+			})
+
+			for _, sentinel := range sentinels {
+				want := tt.expected != nil && errors.Is(tt.expected, sentinel)
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(err, %v) = %t; want %t", sentinel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestItemSentinelErrors(t *testing.T) {
+	item := &Item{
+		Fields:   []Field{{ID: "password", Label: "password"}},
+		Sections: []Section{{ID: "details"}},
+		URLs:     []ItemURL{{Href: "https://example.com"}},
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "Field by ID", err: func() error { _, err := item.GetFieldByID("missing"); return err }(), expected: ErrFieldNotFound},
+		{name: "Field by label", err: func() error { _, err := item.GetFieldsByLabel("missing"); return err }(), expected: ErrFieldNotFound},
+		{name: "Tag", err: item.DeleteTag("missing"), expected: ErrTagNotFound},
+		{name: "Duplicate section ID", err: item.AddSection(Section{ID: "details"}), expected: ErrDuplicateSectionID},
+		{name: "Last URL", err: item.DeleteURLs("https://example.com"), expected: ErrLastURL},
+		{name: "No client", err: item.Save(context.Background()), expected: ErrNoClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.expected) {
+				t.Errorf("error = %v; want %v", tt.err, tt.expected)
+			}
+		})
+	}
+
+	if !errors.Is(ErrFieldNotFound, ErrNotFound) {
+		t.Errorf("ErrFieldNotFound does not match ErrNotFound")
+	}
+}
@@ -0,0 +1,62 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Events API features that can be granted to an Events API integration token.
+const (
+	EventsAPIFeatureSignInAttempts = "signinattempts"
+	EventsAPIFeatureItemUsages     = "itemusages"
+	EventsAPIFeatureAuditEvents    = "auditevents"
+)
+
+// CreateEventsAPIToken creates an Events API integration and returns its bearer token.
+// It executes the "events-api create" command.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - name (string): The name of the Events API integration.
+//   - features ([]string): The event types the token can access, e.g. EventsAPIFeatureAuditEvents.
+//     If empty, the token can access all event types.
+//   - expiresIn (time.Duration): How long the token is valid. If zero, the token does not expire.
+//
+// Returns:
+//   - (string): The bearer token of the integration.
+//   - (error): An error if the operation fails.
+//
+// Example usage:
+//
+//	token, err := cli.CreateEventsAPIToken(ctx, "SIEM", []string{onepassword.EventsAPIFeatureAuditEvents}, 90*24*time.Hour)
+//	if err != nil {
+//	    log.Fatalf("Failed to create Events API token: %v", err)
+//	}
+func (cli *OpCLI) CreateEventsAPIToken(ctx context.Context, name string, features []string, expiresIn time.Duration) (string, error) {
+	if expiresIn < 0 {
+		return "", fmt.Errorf("invalid token expiry: %v", expiresIn)
+	}
+
+	args := []string{"events-api", "create", name}
+	if len(features) > 0 {
+		args = append(args, "--features", strings.Join(features, ","))
+	}
+	if expiresIn > 0 {
+		args = append(args, "--expires-in", fmt.Sprintf("%ds", int64(expiresIn.Round(time.Second)/time.Second)))
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", errors.New("events-api create returned no token")
+	}
+
+	return token, nil
+}
@@ -7,6 +7,6 @@ go 1.24.1
 require github.com/sthayduk/onepassword-cli-go v0.0.0-20250415142856-06b60e5d52f7
 
 require (
-	golang.org/x/sys v0.32.0 // indirect
-	golang.org/x/term v0.31.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/term v0.32.0 // indirect
 )
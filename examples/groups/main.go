@@ -9,11 +9,14 @@ import (
 
 func main() {
 	// Initialize the 1Password CLI client
-	cli := onepassword.NewOpCLI()
+	cli, err := onepassword.NewOpCLI()
+	if err != nil {
+		log.Fatalf("Failed to initialize 1Password CLI: %v", err)
+	}
 
 	// Sign in to 1Password
 	ctx := context.Background()
-	account, err := cli.GetAccountDetailsByEmail("stefan.hayduk@itdesign.at")
+	account, err := cli.GetAccountDetailsByEmail(ctx, "stefan.hayduk@itdesign.at")
 	if err != nil {
 		log.Fatalf("Failed to retrieve account details: %v", err)
 	}
@@ -23,7 +26,7 @@ func main() {
 	}
 
 	// Get the list of groups
-	groups, err := cli.GetGroups()
+	groups, err := cli.GetGroups(ctx)
 	if err != nil {
 		log.Fatalf("Failed to retrieve groups: %v", err)
 	}
@@ -33,22 +36,22 @@ func main() {
 		log.Printf("Group ID: %s, Name: %s", group.ID, group.Name)
 	}
 
-	group, err := cli.GetGroupByName("grp1P-RiskExperts-EMS")
+	group, err := cli.GetGroupByName(ctx, "grp1P-RiskExperts-EMS")
 	if err != nil {
 		log.Fatalf("Failed to retrieve group: %v", err)
 	}
 	log.Printf("Retrieved Group ID: %s, Name: %s", group.ID, group.Name)
 
-	members, err := group.ListMembers()
+	members, err := group.ListMembers(ctx)
 	if err != nil {
 		log.Fatalf("Failed to retrieve group members: %v", err)
 	}
 	for _, member := range members {
-		log.Printf("Member ID: %s, Name: %s", member.ID, member.Name)
+		log.Printf("Member ID: %s, Name: %s, Role: %s", member.ID, member.Name, member.Role)
 	}
 
 	// Add new group to 1Password
-	newGroup, err := cli.CreateGroup("New Group", "This is a new group")
+	newGroup, err := cli.CreateGroup(ctx, "New Group", "This is a new group")
 	if err != nil {
 		log.Fatalf("Failed to create new group: %v", err)
 	}
@@ -56,26 +59,26 @@ func main() {
 
 	// Add a member to the new group
 	memberID := "user.email@example.com" // Replace with the actual member ID
-	user, err := cli.GetUserByEmail(memberID)
+	user, err := cli.GetUserByEmail(ctx, memberID)
 	if err != nil {
 		log.Fatalf("Failed to retrieve user: %v", err)
 	}
 
-	if err := newGroup.AddMember(*user); err != nil {
+	if err := newGroup.AddMember(ctx, *user); err != nil {
 		log.Fatalf("Failed to add member to group: %v", err)
 	}
 
-	if err := newGroup.AddManager(*user); err != nil {
+	if err := newGroup.AddManager(ctx, *user); err != nil {
 		log.Fatalf("Failed to add manager to group: %v", err)
 	}
 
 	// Remove a member from the new group
-	if err := newGroup.RemoveMember(*user); err != nil {
+	if err := newGroup.RemoveMember(ctx, *user); err != nil {
 		log.Fatalf("Failed to remove member from group: %v", err)
 	}
 
 	// Delete the new group
-	if err := newGroup.Delete(); err != nil {
+	if err := newGroup.Delete(ctx); err != nil {
 		log.Fatalf("Failed to delete group: %v", err)
 	}
 
@@ -10,11 +10,14 @@ import (
 
 func main() {
 	// Initialize the 1Password CLI client
-	cli := onepassword.NewOpCLI()
+	cli, err := onepassword.NewOpCLI()
+	if err != nil {
+		log.Fatalf("Failed to initialize 1Password CLI: %v", err)
+	}
 
 	// Sign in to 1Password
 	ctx := context.Background()
-	account, err := cli.GetAccountDetailsByEmail("stefan.hayduk@itdesign.at")
+	account, err := cli.GetAccountDetailsByEmail(ctx, "stefan.hayduk@itdesign.at")
 	if err != nil {
 		log.Fatalf("Failed to retrieve account details: %v", err)
 	}
@@ -24,7 +27,7 @@ func main() {
 	}
 
 	// Example: Retrieve all items
-	items, err := cli.GetItems()
+	items, err := cli.GetItems(ctx)
 	if err != nil {
 		log.Fatalf("Failed to retrieve items: %v", err)
 	}
@@ -35,7 +38,7 @@ func main() {
 	}
 
 	// Example: Create Item from Template
-	templates, err := cli.GetItemTemplates()
+	templates, err := cli.GetItemTemplates(ctx)
 	if err != nil {
 		log.Fatalf("Failed to retrieve templates: %v", err)
 	}
@@ -44,7 +47,7 @@ func main() {
 		fmt.Printf("ID: %s, Title: %s\n", template.UUID, template.Name)
 	}
 
-	itemTemplate, err := cli.GetItemTemplateByName("Login")
+	itemTemplate, err := cli.GetItemTemplate(ctx, onepassword.CategoryLogin)
 	if err != nil {
 		log.Fatalf("Failed to create item from template: %v", err)
 	}
@@ -52,12 +55,12 @@ func main() {
 	itemTemplate.Title = "Example Item"
 	itemTemplate.AddUserName("exampleuser")
 
-	if _, err := cli.CreateItem(itemTemplate, true); err != nil {
+	if _, err := cli.CreateItem(ctx, itemTemplate, true); err != nil {
 		log.Fatalf("Failed to create item from template: %v", err)
 	}
 
 	// Example: Add a URL to an item
-	item, err := cli.GetItemByName(itemTemplate.Title)
+	item, err := cli.GetItemByName(ctx, itemTemplate.Title)
 	if err != nil {
 		log.Fatalf("Failed to retrieve item: %v", err)
 	}
@@ -69,7 +72,7 @@ func main() {
 	}
 	item.AddURL(newURL)
 
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item: %v", err)
 	}
 
@@ -93,7 +96,7 @@ func main() {
 		item.AddURL(url)
 	}
 
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after adding URLs: %v", err)
 	}
 
@@ -107,7 +110,7 @@ func main() {
 		}
 	}
 
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after URL removal: %v", err)
 	}
 
@@ -131,7 +134,7 @@ func main() {
 		log.Fatalf("Failed to add field to section: %v", err)
 	}
 
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after adding section and field: %v", err)
 	}
 
@@ -142,7 +145,7 @@ func main() {
 		log.Fatalf("Failed to delete section: %v", err)
 	}
 
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after deleting section: %v", err)
 	}
 
@@ -151,7 +154,7 @@ func main() {
 	// Add Tag to item
 	item.AddTag("example-tag")
 	item.AddTag("example-tag-2/credit-card")
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after adding tags: %v", err)
 	}
 	fmt.Println("Added tags to item.")
@@ -160,13 +163,13 @@ func main() {
 	if err := item.DeleteTag("example-tag"); err != nil {
 		log.Fatalf("Failed to remove tag: %v", err)
 	}
-	if err := item.Save(); err != nil {
+	if err := item.Save(ctx); err != nil {
 		log.Fatalf("Failed to save item after removing tag: %v", err)
 	}
 	fmt.Println("Removed tag from item.")
 
 	// Example: Delete an item
-	if err := item.Delete(); err != nil {
+	if err := item.Delete(ctx); err != nil {
 		log.Fatalf("Failed to delete item: %v", err)
 	}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/sthayduk/onepassword-cli-go"
@@ -8,13 +9,17 @@ import (
 
 func main() {
 
-	cli := onepassword.NewOpCLI()
-	err := cli.SignInWithServiceAccount("your-service-account-token")
+	cli, err := onepassword.NewOpCLI()
+	if err != nil {
+		log.Fatalf("Failed to initialize 1Password CLI: %v", err)
+	}
+	ctx := context.Background()
+	err = cli.SignInWithServiceAccount(ctx, "your-service-account-token")
 	if err != nil {
 		log.Fatalf("Failed to sign in: %v", err)
 	}
 
-	items, err := cli.GetItems()
+	items, err := cli.GetItems(ctx)
 	if err != nil {
 		log.Fatalf("Failed to get items: %v", err)
 	}
@@ -36,7 +36,10 @@ func main() {
 }
 
 func signin(ctx context.Context, log *log.Logger) error {
-	clt := onepassword.NewOpCLI()
+	clt, err := onepassword.NewOpCLI()
+	if err != nil {
+		return fmt.Errorf("failed to initialize 1Password CLI: %w", err)
+	}
 
 	// Get email from environment variable or use default
 	email := getEnv("OP_EMAIL", "stefan.hayduk@itdesign.at")
@@ -50,7 +53,7 @@ func signin(ctx context.Context, log *log.Logger) error {
 		return fmt.Errorf("CLI verification failed: %w", err)
 	}
 
-	account, err := clt.GetAccountDetailsByEmail(email)
+	account, err := clt.GetAccountDetailsByEmail(ctx, email)
 	if err != nil {
 		return fmt.Errorf("failed to get account details: %w", err)
 	}
@@ -10,13 +10,16 @@ import (
 
 func main() {
 	// Initialize the 1Password CLI client
-	cli := onepassword.NewOpCLI()
+	cli, err := onepassword.NewOpCLI()
+	if err != nil {
+		log.Fatalf("Failed to initialize 1Password CLI: %v", err)
+	}
 
 	// Create a context for the sign-in operation
 	ctx := context.Background()
 
 	// Sign in to 1Password
-	account, err := cli.GetAccountDetailsByEmail("stefan.hayduk@itdesign.at")
+	account, err := cli.GetAccountDetailsByEmail(ctx, "stefan.hayduk@itdesign.at")
 	if err != nil {
 		log.Fatalf("Failed to retrieve account details: %v", err)
 	}
@@ -26,7 +29,7 @@ func main() {
 	}
 
 	// Example: Get all vault details
-	vaults, err := cli.GetVaultDetails()
+	vaults, err := cli.GetVaultDetails(ctx)
 	if err != nil {
 		log.Fatalf("Failed to retrieve vaults: %v", err)
 	}
@@ -38,7 +41,7 @@ func main() {
 
 	// Example: Get details of a specific vault by ID
 	vaultID := "bbq6cjaznuofmtvfv5nejy36eq" // Replace with a valid vault ID
-	vault, err := cli.GetVaultDetailsByID(vaultID)
+	vault, err := cli.GetVaultDetailsByID(ctx, vaultID)
 	if err != nil {
 		log.Fatalf("Failed to retrieve vault details for ID %s: %v", vaultID, err)
 	}
@@ -48,41 +51,41 @@ func main() {
 		vault.Name, vault.ContentVersion, vault.CreatedAt, vault.UpdatedAt, vault.Items, vault.Description, vault.Type)
 
 	// Example: Create a new vault
-	newVault, err := cli.CreateVault("New Vault", "This is a vault description.", onepassword.IconApplication, true)
+	newVault, err := cli.CreateVault(ctx, "New Vault", "This is a vault description.", onepassword.IconApplication, true)
 	if err != nil {
 		log.Fatalf("Failed to create new vault: %v", err)
 	}
 	fmt.Printf("\nCreated new vault: ID: %s, Name: %s\n", newVault.ID, newVault.Name)
 
 	// Example: Update an existing vault
-	if err := newVault.SetIcon(onepassword.IconAirplane); err != nil {
+	if err := newVault.SetIcon(ctx, onepassword.IconAirplane); err != nil {
 		log.Fatalf("Failed to set icon for vault: %v", err)
 	}
 
 	// Example: Set Permissions for the vault
-	group, err := cli.GetGroupByName("GroupName") // Replace with a valid group name
+	group, err := cli.GetGroupByName(ctx, "GroupName") // Replace with a valid group name
 	if err != nil {
 		log.Fatalf("Failed to retrieve group details: %v", err)
 	}
 
-	if err := newVault.GrantGroupPermission(*group, onepassword.PermissionCopyAndShareItems); err != nil {
+	if err := newVault.GrantGroupPermission(ctx, *group, onepassword.PermissionCopyAndShareItems); err != nil {
 		log.Fatalf("Failed to grant group permission: %v", err)
 	}
 	fmt.Printf("Granted group permission for group: %s\n", group.Name)
 
 	// Example: Set Permissions for the vault
-	user, err := cli.GetUserByEmail("user.mail@example.com") // Replace with a valid user email
+	user, err := cli.GetUserByEmail(ctx, "user.mail@example.com") // Replace with a valid user email
 	if err != nil {
 		log.Fatalf("Failed to retrieve user details: %v", err)
 	}
 
-	if err := newVault.GrantUserPermission(*user, onepassword.PermissionMoveItems); err != nil {
+	if err := newVault.GrantUserPermission(ctx, *user, onepassword.PermissionMoveItems); err != nil {
 		log.Fatalf("Failed to grant user permission: %v", err)
 	}
 	fmt.Printf("Granted user permission for user: %s\n", user.Email)
 
 	// Example: Delete a vault
-	if err := newVault.Delete(); err != nil {
+	if err := newVault.Delete(ctx); err != nil {
 		log.Fatalf("Failed to delete vault: %v", err)
 	}
 	fmt.Printf("Deleted vault with ID: %s\n", newVault.ID)
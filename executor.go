@@ -0,0 +1,253 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Command describes a single invocation of the 1Password CLI.
+//
+// Fields:
+//   - Path: The path to the op executable.
+//   - Args: The arguments passed to the executable.
+//   - Env: The environment of the process, including session tokens.
+//   - Dir: The working directory. If empty, the working directory of the current process is used.
+//   - Stdin: The standard input of the process. If nil, the process reads from the null device.
+//   - Stdout: An optional writer that receives the standard output of the process as it is written,
+//     e.g. to decode large listings while they are read. If set, the standard output is not returned.
+//   - Stderr: An optional writer that receives the standard error of the process as it is written,
+//     e.g. for interactive commands. The standard error is returned by the executor in any case.
+type Command struct {
+	Path   string
+	Args   []string
+	Env    []string
+	Dir    string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// CommandExecutor runs commands of the 1Password CLI. All commands of an
+// OpCLI instance are run through its CommandExecutor, which allows tests to
+// replace the op executable with a fake.
+type CommandExecutor interface {
+	// Execute runs the command and returns its standard output and standard
+	// error. The command is stopped when the context is done.
+	Execute(ctx context.Context, cmd *Command) (stdout []byte, stderr []byte, err error)
+}
+
+// CommandExecutorFunc is an adapter to use an ordinary function as a CommandExecutor.
+type CommandExecutorFunc func(ctx context.Context, cmd *Command) ([]byte, []byte, error)
+
+// Execute calls f(ctx, cmd).
+func (f CommandExecutorFunc) Execute(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+	return f(ctx, cmd)
+}
+
+// ExecCommandExecutor is the default CommandExecutor. It runs commands as
+// subprocesses using os/exec.
+//
+// When the context of a command is done, the process receives SIGTERM and is
+// killed if it does not exit within GracePeriod. Its output pipes are drained
+// and the process is waited on, so cancelled commands do not leave zombie
+// processes behind. On Unix, the process runs in its own process group and
+// lingering child processes are killed as well, unless the command reads from
+// the terminal and has to stay in its foreground process group.
+//
+// Fields:
+//   - GracePeriod: The time a cancelled process has to exit. Defaults to 5 seconds if zero.
+type ExecCommandExecutor struct {
+	GracePeriod time.Duration
+}
+
+// Execute runs the command as a subprocess.
+func (e ExecCommandExecutor) Execute(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+	execCmd := exec.CommandContext(ctx, cmd.Path, cmd.Args...)
+	execCmd.Env = cmd.Env
+	execCmd.Dir = cmd.Dir
+	execCmd.Stdin = cmd.Stdin
+
+	setProcessGroup(execCmd, isInteractive(cmd))
+	execCmd.Cancel = func() error {
+		return terminateProcess(execCmd)
+	}
+	execCmd.WaitDelay = e.GracePeriod
+	if execCmd.WaitDelay <= 0 {
+		execCmd.WaitDelay = defaultGracePeriod
+	}
+
+	var stdout, stderr bytes.Buffer
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	if cmd.Stdout != nil {
+		execCmd.Stdout = cmd.Stdout
+	}
+	if cmd.Stderr != nil {
+		execCmd.Stderr = io.MultiWriter(&stderr, cmd.Stderr)
+	}
+
+	err := execCmd.Run()
+	if ctx.Err() != nil && execCmd.Process != nil {
+		killProcessGroup(execCmd)
+		if cause := context.Cause(ctx); err != nil && !errors.Is(err, cause) {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+	}
+
+	if cmd.Stdout != nil {
+		return nil, stderr.Bytes(), err
+	}
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// isInteractive reports whether the command reads from the terminal: its
+// standard input is the standard input of the current process, e.g. for
+// interactive commands that prompt the user, or a terminal.
+func isInteractive(cmd *Command) bool {
+	if cmd.Stdin == os.Stdin {
+		return true
+	}
+	file, ok := cmd.Stdin.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// SetCommandExecutor sets the CommandExecutor used to run commands of the
+// 1Password CLI. If executor is nil, commands are run as subprocesses.
+//
+// Parameters:
+//   - executor: The CommandExecutor to use.
+func (cli *OpCLI) SetCommandExecutor(executor CommandExecutor) {
+	cli.executor = executor
+}
+
+// command creates a Command for the 1Password CLI. The session token of the
+// active account and the service account token are passed to the command
+// through its environment instead of the process environment, so multiple
+// OpCLI instances for different accounts can coexist.
+func (cli *OpCLI) command(args ...string) *Command {
+	if outputArgs := cli.outputArgs(); len(outputArgs) > 0 {
+		args = append(slices.Clip(args), outputArgs...)
+	}
+
+	return &Command{
+		Path: cli.Path,
+		Args: args,
+		Env:  cli.environ(),
+	}
+}
+
+// run executes the command with the configured CommandExecutor and the
+// default timeout of the OpCLI instance. The execution hooks are called
+// before and after the command, which is traced if a Tracer is set.
+func (cli *OpCLI) run(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+	info, err := cli.runBeforeHooks(ctx, cmd)
+	if err != nil {
+		cli.runAfterHooks(ctx, info, CommandResult{}, err)
+		return nil, nil, err
+	}
+
+	if output, skipped := cli.skipForDryRun(ctx, cmd, info); skipped {
+		cli.runAfterHooks(ctx, info, CommandResult{Stdout: output, DryRun: true}, nil)
+		return output, nil, nil
+	}
+
+	ctx, finish, err := cli.running.start(ctx)
+	if err != nil {
+		cli.runAfterHooks(ctx, info, CommandResult{}, err)
+		return nil, nil, err
+	}
+	defer finish()
+
+	release, err := cli.pool.acquire(ctx)
+	if err != nil {
+		cli.runAfterHooks(ctx, info, CommandResult{}, err)
+		return nil, nil, err
+	}
+	defer release()
+
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+
+	executor := cli.executor
+	if executor == nil {
+		executor = ExecCommandExecutor{}
+	}
+
+	ctx, span := cli.startSpan(ctx, info)
+
+	start := time.Now()
+	stdout, stderr, err := executor.Execute(ctx, cmd)
+	duration := time.Since(start)
+
+	// Executors that do not stream the standard output return it instead
+	if cmd.Stdout != nil && len(stdout) > 0 {
+		if _, writeErr := cmd.Stdout.Write(stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+		stdout = nil
+	}
+
+	attrs := []any{
+		"command", commandName(cmd.Args),
+		"duration", duration,
+	}
+	if cli.Account != nil && cli.Account.UserUUID != "" {
+		attrs = append(attrs, "account", cli.Account.UserUUID)
+	}
+	if cli.logCommands {
+		attrs = append(attrs, commandLogAttrs(cmd, stderr, err)...)
+	}
+	if err != nil {
+		cli.log().Debug("op command failed", append(attrs, "error", err)...)
+	} else {
+		cli.log().Debug("op command completed", attrs...)
+	}
+
+	result := CommandResult{Stdout: stdout, Stderr: stderr, Duration: duration}
+	endSpan(span, result, err)
+	cli.runAfterHooks(ctx, info, result, err)
+
+	return stdout, stderr, err
+}
+
+// singleWordCommands are the commands of the 1Password CLI without
+// subcommands, whose second word is already an argument, e.g. "read op://...".
+var singleWordCommands = []string{"completion", "inject", "read", "run", "signin", "signout", "update", "whoami"}
+
+// commandName returns the subcommand of the arguments, e.g. "item get" or
+// "vault user grant", without any further arguments that may contain names
+// or secrets.
+func commandName(args []string) string {
+	return strings.Join(args[:commandWords(args)], " ")
+}
+
+// commandWords returns the number of leading arguments that name the
+// subcommand: one for commands like "whoami", three for nested commands like
+// "vault user grant" and two otherwise.
+func commandWords(args []string) int {
+	words := 0
+	for words < len(args) && words < 3 && !strings.HasPrefix(args[words], "-") {
+		words++
+	}
+
+	switch {
+	case words > 1 && slices.Contains(singleWordCommands, args[0]):
+		return 1
+	case words == 3 && !slices.Contains(nestedCommandNouns, args[1]):
+		return 2
+	}
+	return words
+}
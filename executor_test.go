@@ -0,0 +1,204 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExecuteOpCommandUsesExecutor(t *testing.T) {
+	tests := []struct {
+		name         string
+		stdout       string
+		stderr       string
+		err          error
+		expectedArgs []string
+		expectedErr  string
+	}{
+		{
+			name:         "Success",
+			stdout:       `[]`,
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=json"},
+		},
+		{
+			name:         "Failure with stderr",
+			stderr:       "[ERROR] vault not found",
+			err:          errors.New("exit status 1"),
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=json"},
+			expectedErr:  "[ERROR] vault not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				gotArgs = cmd.Args
+				return []byte(tt.stdout), []byte(tt.stderr), tt.err
+			}))
+
+			output, err := cli.ExecuteOpCommand(context.Background(), "vault", "list")
+			if !slices.Equal(gotArgs, tt.expectedArgs) {
+				t.Errorf("executor args = %q; want %q", gotArgs, tt.expectedArgs)
+			}
+
+			if tt.expectedErr == "" {
+				if err != nil || string(output) != tt.stdout {
+					t.Errorf("ExecuteOpCommand() = %q, %v; want %q, nil", output, err, tt.stdout)
+				}
+				return
+			}
+
+			var cliErr *OpCliError
+			if !errors.As(err, &cliErr) || cliErr.StderrOutput != tt.expectedErr {
+				t.Errorf("ExecuteOpCommand() error = %v; want OpCliError with stderr %q", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"item", "get", "Database", "--vault", "Private"}, expected: "item get"},
+		{args: []string{"signin", "--account", "user-uuid", "--raw"}, expected: "signin"},
+		{args: []string{"vault", "user", "grant", "--vault", "Private"}, expected: "vault user grant"},
+		{args: []string{"item", "template", "get", "Login"}, expected: "item template get"},
+		{args: []string{"read", "op://Private/Database/password"}, expected: "read"},
+		{args: []string{"--version"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := commandName(tt.args); got != tt.expected {
+			t.Errorf("commandName(%q) = %q; want %q", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestPipePasswordCommandKeepsArguments(t *testing.T) {
+	cli := &OpCLI{Path: "op"}
+	args := []string{"signin", "--account", "My Company"}
+
+	cmd := cli.pipePasswordCommand("secret", args...)
+	if cmd.Path != "op" || !slices.Equal(cmd.Args, args) {
+		t.Errorf("pipePasswordCommand() = %q %q; want op %q", cmd.Path, cmd.Args, args)
+	}
+}
+
+func TestCommandFailuresIncludeStderr(t *testing.T) {
+	const stderr = `[ERROR] 2024/01/02 03:04:05 "Missing" isn't a vault in this account.`
+
+	tests := []struct {
+		name string
+		run  func(cli *OpCLI) error
+	}{
+		{
+			name: "ExecuteOpCommand",
+			run: func(cli *OpCLI) error {
+				_, err := cli.ExecuteOpCommand(context.Background(), "vault", "get", "Missing")
+				return err
+			},
+		},
+		{
+			name: "CreateItem",
+			run: func(cli *OpCLI) error {
+				_, err := cli.CreateItem(context.Background(), &Item{Title: "New", Vault: Vault{ID: "Missing"}}, false)
+				return err
+			},
+		},
+		{
+			name: "Save",
+			run: func(cli *OpCLI) error {
+				item := &Item{ID: "item-id", Title: "Existing", cli: cli}
+				return item.Save(context.Background())
+			},
+		},
+		{
+			name: "RefreshAccountDetails",
+			run: func(cli *OpCLI) error {
+				_, err := cli.RefreshAccountDetails(context.Background())
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				return nil, []byte(stderr), errors.New("exit status 1")
+			}))
+
+			err := tt.run(cli)
+			var cliErr *OpCliError
+			if !errors.As(err, &cliErr) || cliErr.StderrOutput != stderr {
+				t.Errorf("error = %v; want OpCliError with stderr %q", err, stderr)
+			}
+			if !errors.Is(err, ErrVaultNotFound) {
+				t.Errorf("errors.Is(err, ErrVaultNotFound) = false; want true")
+			}
+		})
+	}
+}
+
+func TestItemWritesRefreshExpiredSession(t *testing.T) {
+	const expired = `[ERROR] 2024/01/02 03:04:05 You are not currently signed in. Please run 'op signin --help' for instructions`
+
+	tests := []struct {
+		name string
+		run  func(cli *OpCLI) error
+	}{
+		{
+			name: "CreateItem",
+			run: func(cli *OpCLI) error {
+				_, err := cli.CreateItem(context.Background(), &Item{Title: "New", Vault: Vault{ID: "vault-id"}}, false)
+				return err
+			},
+		},
+		{
+			name: "Save",
+			run: func(cli *OpCLI) error {
+				item := &Item{ID: "item-id", Title: "Existing", cli: cli}
+				return item.Save(context.Background())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdins []string
+			account := &Account{UserUUID: "user-uuid"}
+			account.SetSignInInfo("old-token")
+			account.signInTime = time.Now().Add(-time.Minute)
+			cli := &OpCLI{Path: "op", Account: account}
+			cli.SetLogger(nil)
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				switch cmd.Args[0] {
+				case "whoami":
+					return nil, []byte(expired), errors.New("exit status 1")
+				case "signin":
+					return []byte("new-token\n"), nil, nil
+				}
+				stdin, _ := io.ReadAll(cmd.Stdin)
+				stdins = append(stdins, string(stdin))
+				if !slices.Contains(cmd.Env, "OP_SESSION_user-uuid=new-token") {
+					return nil, []byte(expired), errors.New("exit status 1")
+				}
+				return []byte(`{"id":"item-id","title":"Saved"}`), nil, nil
+			}))
+
+			if err := tt.run(cli); err != nil {
+				t.Fatalf("error = %v; want the command to be retried after signing in again", err)
+			}
+			if len(stdins) != 2 || stdins[0] == "" || stdins[0] != stdins[1] {
+				t.Errorf("item templates sent = %q; want the same template twice", stdins)
+			}
+		})
+	}
+}
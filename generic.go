@@ -0,0 +1,93 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+)
+
+// cliBinder is implemented by the types of the package that keep a reference
+// to the OpCLI instance they were retrieved with, so their methods can run
+// further commands.
+type cliBinder interface {
+	bindCLI(cli *OpCLI)
+}
+
+func (item *Item) bindCLI(cli *OpCLI)   { item.cli = cli }
+func (vault *Vault) bindCLI(cli *OpCLI) { vault.cli = cli }
+func (user *User) bindCLI(cli *OpCLI)   { user.cli = cli }
+func (group *Group) bindCLI(cli *OpCLI) { group.cli = cli }
+
+// bind associates a decoded value with the OpCLI instance if its type supports it.
+func bind[T any](cli *OpCLI, value *T) {
+	if binder, ok := any(value).(cliBinder); ok {
+		binder.bindCLI(cli)
+	}
+}
+
+// Get runs a 1Password CLI command with ExecuteOpCommand and decodes its JSON
+// output into a value of type T. It is intended for subcommands the package
+// does not wrap yet. Values of the types of the package, e.g. Item or Vault,
+// are associated with the OpCLI instance, so their methods can be used.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - cli: The OpCLI instance that runs the command.
+//   - args: The arguments of the command, without --account and --format.
+//
+// Returns:
+//   - T: The decoded output.
+//   - error: An error if the command fails or its output cannot be decoded.
+//
+// Example usage:
+//
+//	type itemSummary struct {
+//	    ID      string `json:"id"`
+//	    Title   string `json:"title"`
+//	    Version int    `json:"version"`
+//	}
+//	summary, err := onepassword.Get[itemSummary](ctx, cli, "item", "get", "Contract", "--vault", "Legal")
+func Get[T any](ctx context.Context, cli *OpCLI, args ...string) (T, error) {
+	var value T
+
+	output, err := cli.ExecuteOpCommand(ctx, args...)
+	if err != nil {
+		return value, err
+	}
+
+	if err := cli.unmarshal(output, &value); err != nil {
+		return value, fmt.Errorf("failed to decode output of %q: %w", commandName(args), err)
+	}
+	bind(cli, &value)
+
+	return value, nil
+}
+
+// List runs a 1Password CLI command that prints a JSON array and decodes its
+// elements into a slice of T. The output is decoded while the command is
+// running, like the list methods of the package. Elements of the types of the
+// package are associated with the OpCLI instance.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - cli: The OpCLI instance that runs the command.
+//   - args: The arguments of the command, without --account and --format.
+//
+// Returns:
+//   - []T: The decoded elements. The slice is empty, not nil, if the list is empty.
+//   - error: An error if the command fails or its output cannot be decoded.
+//
+// Example usage:
+//
+//	favorites, err := onepassword.List[onepassword.Item](ctx, cli, "item", "list", "--favorite")
+func List[T any](ctx context.Context, cli *OpCLI, args ...string) ([]T, error) {
+	list, err := collectList[T](ctx, cli, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		bind(cli, &list[i])
+	}
+
+	return list, nil
+}
@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return []byte(`{"id":"vault-id","name":"Private"}`), nil, nil
+	}))
+
+	vault, err := Get[Vault](context.Background(), cli, "vault", "get", "Private")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if vault.ID != "vault-id" || vault.cli != cli {
+		t.Errorf("Get() = %+v; want vault-id bound to the client", vault)
+	}
+
+	type custom struct {
+		Name string `json:"name"`
+	}
+	value, err := Get[custom](context.Background(), cli, "vault", "get", "Private")
+	if err != nil || value.Name != "Private" {
+		t.Errorf("Get() = %+v, %v; want Private", value, err)
+	}
+
+	if _, err := Get[[]string](context.Background(), cli, "vault", "get", "Private"); err == nil {
+		t.Error("Get() with mismatching type succeeded")
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		expectedCount int
+		wantErr       bool
+	}{
+		{name: "Elements", output: `[{"id":"a"},{"id":"b"}]`, expectedCount: 2},
+		{name: "Empty output", output: ``, expectedCount: 0},
+		{name: "Not an array", output: `{"id":"a"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				return []byte(tt.output), nil, nil
+			}))
+
+			users, err := List[User](context.Background(), cli, "user", "list")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("List() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(users) != tt.expectedCount {
+				t.Fatalf("List() returned %d users; want %d", len(users), tt.expectedCount)
+			}
+			for _, user := range users {
+				if user.cli != cli {
+					t.Errorf("List() user %s is not bound to the client", user.ID)
+				}
+			}
+		})
+	}
+}
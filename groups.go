@@ -1,10 +1,65 @@
 package onepassword
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// GroupRole represents the role of a user within a group.
+type GroupRole string
+
+const (
+	GroupRoleMember  GroupRole = "MEMBER"
+	GroupRoleManager GroupRole = "MANAGER"
+)
+
+var knownGroupRoles = map[GroupRole]bool{
+	GroupRoleMember:  true,
+	GroupRoleManager: true,
+}
+
+// UnmarshalJSON decodes a GroupRole leniently. Values are normalized to upper
+// case, and unknown values are decoded as GroupRoleMember.
+func (r *GroupRole) UnmarshalJSON(data []byte) error {
+	*r = GroupRole(decodeEnum(data, knownGroupRoles, GroupRoleMember))
+	return nil
+}
+
+// knownValue reports whether raw is a GroupRole known to this package.
+func (GroupRole) knownValue(raw string) bool {
+	return knownGroupRoles[normalizeEnum[GroupRole](raw)]
+}
+
+// GroupMember represents a user within a group together with their role.
+type GroupMember struct {
+	User
+	Role GroupRole `json:"role"`
+}
+
+// IsManager reports whether the member has the manager role in the group.
+func (member *GroupMember) IsManager() bool {
+	return member.Role == GroupRoleManager
+}
+
+// GroupType represents the type of a group.
+type GroupType string
+
+const (
+	GroupTypeUserDefined       GroupType = "USER_DEFINED"
+	GroupTypeOwners            GroupType = "OWNERS"
+	GroupTypeAdministrators    GroupType = "ADMINISTRATORS"
+	GroupTypeRecovery          GroupType = "RECOVERY"
+	GroupTypeTeamMembers       GroupType = "TEAM_MEMBERS"
+	GroupTypeSecurity          GroupType = "SECURITY"
+	GroupTypeProvisionManagers GroupType = "PROVISION_MANAGERS"
+)
+
+// ErrBuiltInGroup is returned when an operation is attempted that is not
+// permitted on the built-in groups of an account.
+var ErrBuiltInGroup = errors.New("operation not permitted on built-in group")
+
 type Group struct {
 	cli *OpCLI `json:"-"` // Reference to the OpCLI instance for update operations
 
@@ -15,24 +70,47 @@ type Group struct {
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
 	Permissions []Permission `json:"permissions,omitempty"`
-	Type        string       `json:"type"`
+	Type        GroupType    `json:"type"`
 }
 
-// ListGroups retrieves a list of all groups available in the 1Password CLI.
-// It executes the "group list" command and parses the output into a slice of Group objects.
+// IsBuiltIn reports whether the group is one of the groups that 1Password
+// creates for every account, such as Owners or Administrators.
+func (group *Group) IsBuiltIn() bool {
+	return group.Type != "" && group.Type != GroupTypeUserDefined
+}
+
+// ListGroupsOptions filters the groups returned by ListGroups.
+//
+// Fields:
+//   - Vault: Only list groups with access to this vault (name or ID).
+//   - User: Only list groups this user belongs to (name, email or ID).
+type ListGroupsOptions struct {
+	Vault string
+	User  string
+}
+
+// GetGroups retrieves a list of all groups available in the 1Password CLI.
+// It is equivalent to calling ListGroups without options.
 //
 // Returns:
 //   - ([]Group): A slice of Group objects.
 //   - (error): An error if the operation fails.
-func (cli *OpCLI) GetGroups() ([]Group, error) {
-
-	output, err := cli.ExecuteOpCommand("group", "list")
-	if err != nil {
-		return nil, err
-	}
+func (cli *OpCLI) GetGroups(ctx context.Context) ([]Group, error) {
+	return cli.ListGroups(ctx)
+}
 
-	var groups []Group
-	err = json.Unmarshal([]byte(output), &groups)
+// ListGroups retrieves a list of groups available in the 1Password CLI.
+// It executes the "group list" command and parses the output into a slice of Group objects.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - opts (ListGroupsOptions): Optional filters by vault or user.
+//
+// Returns:
+//   - ([]Group): A slice of Group objects.
+//   - (error): An error if the operation fails.
+func (cli *OpCLI) ListGroups(ctx context.Context, opts ...ListGroupsOptions) ([]Group, error) {
+	groups, err := collectList[Group](ctx, cli, groupListArgs(opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -45,24 +123,39 @@ func (cli *OpCLI) GetGroups() ([]Group, error) {
 	return groups, nil
 }
 
+// groupListArgs returns the arguments of "group list" for the options.
+func groupListArgs(opts ...ListGroupsOptions) []string {
+	args := []string{"group", "list"}
+	if len(opts) > 0 {
+		if opts[0].Vault != "" {
+			args = append(args, "--vault", opts[0].Vault)
+		}
+		if opts[0].User != "" {
+			args = append(args, "--user", opts[0].User)
+		}
+	}
+	return args
+}
+
 // getGroup retrieves a specific group by its ID or name.
 // It executes the "group get" command and parses the output into a Group object.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - groupID (string): The ID or name of the group to retrieve.
 //
 // Returns:
 //   - (*Group): A pointer to the Group object.
 //   - (error): An error if the operation fails.
-func (cli *OpCLI) getGroup(groupID string) (*Group, error) {
+func (cli *OpCLI) getGroup(ctx context.Context, groupID string) (*Group, error) {
 	// Execute the command to get a group by ID
-	output, err := cli.ExecuteOpCommand("group", "get", groupID)
+	output, err := cli.getEntity(ctx, CacheGroups, groupID)
 	if err != nil {
 		return nil, err
 	}
 
 	var group Group
-	err = json.Unmarshal([]byte(output), &group)
+	err = cli.unmarshal(output, &group)
 	if err != nil {
 		return nil, err
 	}
@@ -76,47 +169,72 @@ func (cli *OpCLI) getGroup(groupID string) (*Group, error) {
 // It internally calls getGroup with the group name.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - name (string): The name of the group to retrieve.
 //
 // Returns:
 //   - (*Group): A pointer to the Group object.
 //   - (error): An error if the operation fails.
-func (cli *OpCLI) GetGroupByName(name string) (*Group, error) {
-	return cli.getGroup(name)
+func (cli *OpCLI) GetGroupByName(ctx context.Context, name string) (*Group, error) {
+	return cli.getGroup(ctx, name)
 }
 
 // GetGroupByID retrieves a group by its ID.
 // It internally calls getGroup with the group ID.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - id (string): The ID of the group to retrieve.
 //
 // Returns:
 //   - (*Group): A pointer to the Group object.
 //   - (error): An error if the operation fails.
-func (cli *OpCLI) GetGroupByID(id string) (*Group, error) {
-	return cli.getGroup(id)
+func (cli *OpCLI) GetGroupByID(ctx context.Context, id string) (*Group, error) {
+	return cli.getGroup(ctx, id)
+}
+
+// GetGroupsByUser retrieves the groups a user is a member of with a single
+// "group list --user" command, instead of listing the members of every group.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the command execution.
+//   - user (User): The user, identified by ID or, if the ID is empty, by email.
+//
+// Returns:
+//   - ([]Group): A slice of the groups the user belongs to.
+//   - (error): An error if the user has neither ID nor email or the operation fails.
+func (cli *OpCLI) GetGroupsByUser(ctx context.Context, user User) ([]Group, error) {
+	identifier := user.ID
+	if identifier == "" {
+		identifier = user.Email
+	}
+	if identifier == "" {
+		return nil, fmt.Errorf("user ID or email is required")
+	}
+
+	return cli.ListGroups(ctx, ListGroupsOptions{User: identifier})
 }
 
 // CreateGroup creates a new group with the specified name and description.
 // It executes the "group create" command and parses the output into a Group object.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - name (string): The name of the group to create.
 //   - description (string): The description of the group.
 //
 // Returns:
 //   - (*Group): A pointer to the newly created Group object.
 //   - (error): An error if the operation fails.
-func (cli *OpCLI) CreateGroup(name string, description string) (*Group, error) {
+func (cli *OpCLI) CreateGroup(ctx context.Context, name string, description string) (*Group, error) {
 	// Execute the command to create a group
-	output, err := cli.ExecuteOpCommand("group", "create", name, "--description", description)
+	output, err := cli.ExecuteOpCommand(ctx, "group", "create", name, "--description", description)
 	if err != nil {
 		return nil, err
 	}
 
 	var group Group
-	err = json.Unmarshal([]byte(output), &group)
+	err = cli.unmarshal(output, &group)
 	if err != nil {
 		return nil, err
 	}
@@ -126,14 +244,35 @@ func (cli *OpCLI) CreateGroup(name string, description string) (*Group, error) {
 	return &group, nil
 }
 
+// Reload re-fetches the group using the "group get" command and updates the
+// receiver with the current state.
+//
+// Returns:
+//   - (error): An error if the operation fails.
+func (group *Group) Reload(ctx context.Context) error {
+	updatedGroup, err := group.cli.getGroup(ctx, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload group: %w", err)
+	}
+
+	*group = *updatedGroup
+
+	return nil
+}
+
 // Delete removes the group from the 1Password CLI.
 // It executes the "group delete" command using the group's ID.
+// Built-in groups cannot be deleted.
 //
 // Returns:
-//   - (error): An error if the operation fails.
-func (group *Group) Delete() error {
+//   - (error): An error if the operation fails, or ErrBuiltInGroup for built-in groups.
+func (group *Group) Delete(ctx context.Context) error {
+	if group.IsBuiltIn() {
+		return fmt.Errorf("%w: cannot delete %s", ErrBuiltInGroup, group.Name)
+	}
+
 	// Execute the command to delete a group
-	_, err := group.cli.ExecuteOpCommand("group", "delete", group.ID)
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "delete", group.ID)
 	if err != nil {
 		return err
 	}
@@ -142,79 +281,116 @@ func (group *Group) Delete() error {
 }
 
 // SetName updates the name of the group.
-// It executes the "group edit" command with the new name.
+// It executes the "group edit" command with the new name and reloads the group afterwards.
+// Built-in groups cannot be renamed.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - name (string): The new name for the group.
 //
 // Returns:
-//   - (error): An error if the operation fails.
-func (group *Group) SetName(name string) error {
+//   - (error): An error if the operation fails, or ErrBuiltInGroup for built-in groups.
+func (group *Group) SetName(ctx context.Context, name string) error {
+	if group.IsBuiltIn() {
+		return fmt.Errorf("%w: cannot rename %s", ErrBuiltInGroup, group.Name)
+	}
+
 	// Execute the command to set the group name
-	_, err := group.cli.ExecuteOpCommand("group", "edit", group.ID, "--name", name)
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "edit", group.ID, "--name", name)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return group.Reload(ctx)
 }
 
 // SetDescription updates the description of the group.
-// It executes the "group edit" command with the new description.
+// It executes the "group edit" command with the new description and reloads the group afterwards.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - description (string): The new description for the group.
 //
 // Returns:
 //   - (error): An error if the operation fails.
-func (group *Group) SetDescription(description string) error {
+func (group *Group) SetDescription(ctx context.Context, description string) error {
 	// Execute the command to set the group description
-	_, err := group.cli.ExecuteOpCommand("group", "edit", group.ID, "--description", description)
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "edit", group.ID, "--description", description)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return group.Reload(ctx)
 }
 
 // ListMembers retrieves a list of all users who are members of the group.
-// It executes the "group user list" command and parses the output into a slice of User objects.
+// It executes the "group user list" command and parses the output into a slice of GroupMember objects,
+// which include the role of each user within the group.
 //
 // Returns:
-//   - ([]User): A slice of User objects.
+//   - ([]GroupMember): A slice of GroupMember objects.
 //   - (error): An error if the operation fails.
-func (group *Group) ListMembers() ([]User, error) {
+func (group *Group) ListMembers(ctx context.Context) ([]GroupMember, error) {
 	// Execute the command to list group members
-	output, err := group.cli.ExecuteOpCommand("group", "user", "list", group.ID)
+	output, err := group.cli.ExecuteOpCommand(ctx, "group", "user", "list", group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []GroupMember
+	err = group.cli.unmarshal(output, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the cli reference for each member
+	for i := range members {
+		members[i].cli = group.cli
+	}
+
+	return members, nil
+}
+
+// ListVaults retrieves all vaults the group has access to.
+// It executes the "vault list --group" command. Each returned Vault has its
+// Permissions field populated with the permissions granted to the group.
+//
+// Returns:
+//   - ([]Vault): A slice of Vault objects.
+//   - (error): An error if the operation fails.
+func (group *Group) ListVaults(ctx context.Context) ([]Vault, error) {
+	// Execute the command to list the vaults of the group
+	output, err := group.cli.ExecuteOpCommand(ctx, "vault", "list", "--group", group.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	var users []User
-	err = json.Unmarshal([]byte(output), &users)
+	var vaults []Vault
+	err = group.cli.unmarshal(output, &vaults)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set the cli reference for each user
-	for i := range users {
-		users[i].cli = group.cli
+	// Set the cli reference for each vault
+	for i := range vaults {
+		vaults[i].cli = group.cli
 	}
 
-	return users, nil
+	return vaults, nil
 }
 
 // AddMember adds a user to the group with the default role of "member".
 // It executes the "group user grant" command with the user's ID and the group's ID.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - user (User): The user to add to the group.
 //
 // Returns:
 //   - (error): An error if the operation fails.
-func (group *Group) AddMember(user User) error {
+func (group *Group) AddMember(ctx context.Context, user User) error {
 	// Execute the command to add a user to the group
-	_, err := group.cli.ExecuteOpCommand("group", "user", "grant",
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "user", "grant",
 		"--group", group.ID,
 		"--user", user.ID,
 		"--role", "member")
@@ -230,13 +406,14 @@ func (group *Group) AddMember(user User) error {
 // It executes the "group user revoke" command with the user's ID and the group's ID.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - user (User): The user to remove from the group.
 //
 // Returns:
 //   - (error): An error if the operation fails.
-func (group *Group) RemoveMember(user User) error {
+func (group *Group) RemoveMember(ctx context.Context, user User) error {
 	// Execute the command to remove a user from the group
-	_, err := group.cli.ExecuteOpCommand("group", "user", "revoke",
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "user", "revoke",
 		"--group", group.ID,
 		"--user", user.ID)
 
@@ -251,13 +428,14 @@ func (group *Group) RemoveMember(user User) error {
 // It executes the "group user grant" command with the user's ID and the group's ID.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - user (User): The user to add as a manager to the group.
 //
 // Returns:
 //   - (error): An error if the operation fails.
-func (group *Group) AddManager(user User) error {
+func (group *Group) AddManager(ctx context.Context, user User) error {
 	// Execute the command to add a manager to the group
-	_, err := group.cli.ExecuteOpCommand("group", "user", "grant",
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "user", "grant",
 		"--group", group.ID,
 		"--user", user.ID,
 		"--role", "manager")
@@ -273,13 +451,14 @@ func (group *Group) AddManager(user User) error {
 // It executes the "group user revoke" command with the user's ID and the group's ID.
 //
 // Parameters:
+//   - ctx (context.Context): The context for the command execution.
 //   - user (User): The user to remove as a manager from the group.
 //
 // Returns:
 //   - (error): An error if the operation fails.
-func (group *Group) RemoveManager(user User) error {
+func (group *Group) RemoveManager(ctx context.Context, user User) error {
 	// Execute the command to remove a manager from the group
-	_, err := group.cli.ExecuteOpCommand("group", "user", "revoke",
+	_, err := group.cli.ExecuteOpCommand(ctx, "group", "user", "revoke",
 		"--group", group.ID,
 		"--user", user.ID)
 
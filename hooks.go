@@ -0,0 +1,127 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CommandInfo describes a command of the 1Password CLI passed to execution hooks.
+//
+// Fields:
+//   - Name: The subcommand, e.g. "item get", without arguments that may contain names or secrets.
+//   - Args: The arguments passed to the executable. Before hooks may change them to rewrite the command.
+//   - Account: The user UUID of the active account, if any.
+type CommandInfo struct {
+	Name    string
+	Args    []string
+	Account string
+}
+
+// CommandResult contains the outcome of a command passed to after hooks.
+//
+// Fields:
+//   - Stdout: The standard output. It is empty if the output was streamed to a decoder.
+//   - Stderr: The standard error.
+//   - Duration: The time the command took to run.
+//   - DryRun: Whether the command was skipped in dry-run mode, see SetDryRun. Stdout
+//     then holds the placeholder result.
+type CommandResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	Duration time.Duration
+	DryRun   bool
+}
+
+// BeforeExecHook is called before a command is run. It may change info.Args to
+// rewrite the command. If it returns an error, the command is not run and the
+// error is returned to the caller, e.g. to enforce an allow-list of commands.
+type BeforeExecHook func(ctx context.Context, info *CommandInfo) error
+
+// AfterExecHook is called after a command was run, e.g. for auditing or metrics.
+// err is the error returned by the CommandExecutor, or the error of a before
+// hook that prevented the command from running.
+type AfterExecHook func(ctx context.Context, info CommandInfo, result CommandResult, err error)
+
+// execHooks holds the execution hooks of an OpCLI instance.
+type execHooks struct {
+	mu     sync.RWMutex
+	before []BeforeExecHook
+	after  []AfterExecHook
+}
+
+// OnBeforeExec registers a hook that is called before every command of the
+// 1Password CLI. Hooks are called in the order they were registered.
+//
+// Parameters:
+//   - hook: The hook to call.
+//
+// Example usage:
+//
+//	cli.OnBeforeExec(func(ctx context.Context, info *onepassword.CommandInfo) error {
+//	    if info.Name == "item delete" {
+//	        return errors.New("deleting items is not allowed")
+//	    }
+//	    return nil
+//	})
+func (cli *OpCLI) OnBeforeExec(hook BeforeExecHook) {
+	cli.hooks.mu.Lock()
+	defer cli.hooks.mu.Unlock()
+
+	cli.hooks.before = append(cli.hooks.before, hook)
+}
+
+// OnAfterExec registers a hook that is called after every command of the
+// 1Password CLI. Hooks are called in the order they were registered.
+//
+// Parameters:
+//   - hook: The hook to call.
+func (cli *OpCLI) OnAfterExec(hook AfterExecHook) {
+	cli.hooks.mu.Lock()
+	defer cli.hooks.mu.Unlock()
+
+	cli.hooks.after = append(cli.hooks.after, hook)
+}
+
+// runBeforeHooks calls the before hooks for the command and applies
+// rewritten arguments to it.
+func (cli *OpCLI) runBeforeHooks(ctx context.Context, cmd *Command) (CommandInfo, error) {
+	cli.hooks.mu.RLock()
+	hooks := slices.Clone(cli.hooks.before)
+	cli.hooks.mu.RUnlock()
+
+	info := CommandInfo{
+		Name: commandName(cmd.Args),
+		Args: slices.Clone(cmd.Args),
+	}
+	if cli.Account != nil {
+		info.Account = cli.Account.UserUUID
+	}
+
+	if len(hooks) == 0 {
+		return info, nil
+	}
+
+	for _, hook := range hooks {
+		if err := hook(ctx, &info); err != nil {
+			return info, err
+		}
+	}
+
+	cmd.Args = slices.Clone(info.Args)
+	info.Name = commandName(info.Args)
+
+	return info, nil
+}
+
+// runAfterHooks calls the after hooks for the command.
+func (cli *OpCLI) runAfterHooks(ctx context.Context, info CommandInfo, result CommandResult, err error) {
+	cli.hooks.mu.RLock()
+	hooks := slices.Clone(cli.hooks.after)
+	cli.hooks.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(ctx, info, result, err)
+	}
+}
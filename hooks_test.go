@@ -0,0 +1,93 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestExecHooks(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	tests := []struct {
+		name         string
+		before       BeforeExecHook
+		expectedArgs []string
+		expectedRun  bool
+		expectedErr  error
+	}{
+		{
+			name:         "No changes",
+			before:       func(ctx context.Context, info *CommandInfo) error { return nil },
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=json"},
+			expectedRun:  true,
+		},
+		{
+			name: "Rewrite arguments",
+			before: func(ctx context.Context, info *CommandInfo) error {
+				info.Args = append(info.Args, "--permission", "manage_vault")
+				return nil
+			},
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=json", "--permission", "manage_vault"},
+			expectedRun:  true,
+		},
+		{
+			name: "Deny command",
+			before: func(ctx context.Context, info *CommandInfo) error {
+				if info.Name == "vault list" {
+					return errDenied
+				}
+				return nil
+			},
+			expectedErr: errDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			ran := false
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				ran = true
+				gotArgs = cmd.Args
+				return []byte(`[]`), nil, nil
+			}))
+
+			var afterInfo CommandInfo
+			var afterResult CommandResult
+			var afterErr error
+			afterCalls := 0
+			cli.OnBeforeExec(tt.before)
+			cli.OnAfterExec(func(ctx context.Context, info CommandInfo, result CommandResult, err error) {
+				afterCalls++
+				afterInfo, afterResult, afterErr = info, result, err
+			})
+
+			_, err := cli.ExecuteOpCommand(context.Background(), "vault", "list")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ExecuteOpCommand() error = %v; want %v", err, tt.expectedErr)
+			}
+			if ran != tt.expectedRun {
+				t.Fatalf("command ran = %v; want %v", ran, tt.expectedRun)
+			}
+			if tt.expectedRun && !slices.Equal(gotArgs, tt.expectedArgs) {
+				t.Errorf("executor args = %q; want %q", gotArgs, tt.expectedArgs)
+			}
+
+			if afterCalls != 1 {
+				t.Fatalf("after hook called %d times; want 1", afterCalls)
+			}
+			if afterInfo.Name != "vault list" || afterInfo.Account != "user-uuid" {
+				t.Errorf("after hook info = %+v; want vault list for user-uuid", afterInfo)
+			}
+			if !errors.Is(afterErr, tt.expectedErr) {
+				t.Errorf("after hook error = %v; want %v", afterErr, tt.expectedErr)
+			}
+			if tt.expectedRun && string(afterResult.Stdout) != `[]` {
+				t.Errorf("after hook stdout = %q; want %q", afterResult.Stdout, `[]`)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// opDownloadURL is the base URL of the 1Password CLI releases.
+	opDownloadURL = "https://cache.agilebits.com/dist/1P/op2/pkg"
+
+	// opUpdateURL is the endpoint that reports the latest 1Password CLI version.
+	opUpdateURL = "https://app-updates.agilebits.com/check/1/0/CLI2/en/2.0.0/N"
+)
+
+// InstallOptions configures the download of the 1Password CLI by InstallOp.
+//
+// Fields:
+//   - Version: The version to install, e.g. "2.30.0". If empty, the latest version is installed.
+//   - Dir: The directory the executable is installed to. If empty, a versioned
+//     directory in the user cache directory is used.
+//   - HTTPClient: The client used for downloads. If nil, http.DefaultClient is used.
+//   - SkipVerify: Skip the signature verification of the downloaded executable. Without
+//     it, InstallOp fails with ErrSignatureUnverified if the signature cannot be checked.
+//   - Logger: The logger of the signature verification. If nil, the default logger of
+//     the slog package is used.
+type InstallOptions struct {
+	Version    string
+	Dir        string
+	HTTPClient *http.Client
+	SkipVerify bool
+	Logger     *slog.Logger
+}
+
+// InstallOp downloads the 1Password CLI for the current operating system and
+// architecture from the 1Password CDN, verifies its signature with
+// VerifyOpExecutable and installs it to a cache directory. If the requested
+// version is already installed, the download is skipped.
+//
+// Parameters:
+//   - ctx: The context for the download.
+//   - opts: Options for the installation.
+//
+// Returns:
+//   - string: The path to the installed executable.
+//   - error: An error if the download, extraction or verification fails, or
+//     ErrSignatureUnverified if the signature cannot be checked on this system.
+//
+// Example usage:
+//
+//	path, err := onepassword.InstallOp(ctx, onepassword.InstallOptions{})
+//	if err != nil {
+//	    log.Fatalf("Failed to install 1Password CLI: %v", err)
+//	}
+//	cli, err := onepassword.NewOpCLI(onepassword.WithPath(path))
+func InstallOp(ctx context.Context, opts InstallOptions) (string, error) {
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	version := strings.TrimPrefix(opts.Version, "v")
+	if version == "" {
+		latest, err := latestOpVersion(ctx, client, opUpdateURL)
+		if err != nil {
+			return "", err
+		}
+		version = latest
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine cache directory: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "onepassword-cli-go", "op", version)
+	}
+
+	executableName := "op"
+	if runtime.GOOS == "windows" {
+		executableName += ".exe"
+	}
+	path := filepath.Join(dir, executableName)
+
+	if _, err := os.Stat(path); err != nil {
+		if err := downloadOp(ctx, client, version, dir); err != nil {
+			return "", err
+		}
+	}
+
+	if !opts.SkipVerify {
+		// The version is known, so the executable is not run before it is verified
+		verifyOpts := VerifyOptions{Version: version, HTTPClient: client, Logger: opts.Logger}
+		info, err := VerifyOpSignatureWith(path, verifyOpts)
+		if err != nil {
+			return "", fmt.Errorf("failed to verify downloaded 1Password CLI: %w", err)
+		}
+		if info == nil {
+			// The verification was skipped, e.g. because gpg is not installed
+			return "", fmt.Errorf("failed to verify downloaded 1Password CLI: %w", ErrSignatureUnverified)
+		}
+	}
+
+	return path, nil
+}
+
+// opDownloadURLFor returns the download URL of the 1Password CLI archive.
+func opDownloadURLFor(version, goos, goarch string) string {
+	return fmt.Sprintf("%s/v%s/op_%s_%s_v%s.zip", opDownloadURL, version, goos, goarch, version)
+}
+
+// latestOpVersion retrieves the latest version of the 1Password CLI from the
+// update endpoint.
+func latestOpVersion(ctx context.Context, client *http.Client, updateURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, updateURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve latest 1Password CLI version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to retrieve latest 1Password CLI version: %s", resp.Status)
+	}
+
+	var update struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&update); err != nil {
+		return "", fmt.Errorf("failed to parse latest 1Password CLI version: %w", err)
+	}
+	if update.Version == "" {
+		return "", fmt.Errorf("no 1Password CLI version reported")
+	}
+
+	return update.Version, nil
+}
+
+// downloadOp downloads the 1Password CLI archive and extracts it into dir.
+func downloadOp(ctx context.Context, client *http.Client, version, dir string) error {
+	url := opDownloadURLFor(version, runtime.GOOS, runtime.GOARCH)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download 1Password CLI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download 1Password CLI from %s: %s", url, resp.Status)
+	}
+
+	archive, err := os.CreateTemp("", "op-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	size, err := io.Copy(archive, resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to download 1Password CLI: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+
+	return extractOp(archive, size, dir)
+}
+
+// extractOp extracts the executable and its signature from the archive into dir.
+func extractOp(archive io.ReaderAt, size int64, dir string) error {
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return fmt.Errorf("failed to open 1Password CLI archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		name := filepath.Base(file.Name)
+		switch name {
+		case "op", "op.exe", "op.sig":
+		default:
+			continue
+		}
+
+		if err := extractFile(file, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractFile writes a file of a zip archive to path.
+func extractFile(file *zip.File, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+	defer src.Close()
+
+	// Write to a temporary file first so an interrupted extraction does not
+	// leave a partial executable behind
+	tmp := path + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,79 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestExtractOp(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"op":        "binary",
+		"op.sig":    "signature",
+		"README.md": "ignored",
+	} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := extractOp(bytes.NewReader(buf.Bytes()), int64(buf.Len()), dir); err != nil {
+		t.Fatalf("extractOp() error = %v", err)
+	}
+
+	for name, expected := range map[string]string{"op": "binary", "op.sig": "signature"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(content) != expected {
+			t.Errorf("extracted %s = %q, %v; want %q", name, content, err, expected)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); !os.IsNotExist(err) {
+		t.Errorf("README.md was extracted; want only the executable and signature")
+	}
+}
+
+func TestOpDownloadURLFor(t *testing.T) {
+	expected := "https://cache.agilebits.com/dist/1P/op2/pkg/v2.30.0/op_linux_amd64_v2.30.0.zip"
+	if got := opDownloadURLFor("2.30.0", "linux", "amd64"); got != expected {
+		t.Errorf("opDownloadURLFor() = %q; want %q", got, expected)
+	}
+}
+
+func TestInstallOpUnverified(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the signature is checked with gpg on Linux only")
+	}
+
+	// An installed executable is verified without a download
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "op"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Without gpg, the signature cannot be checked
+	t.Setenv("PATH", "")
+
+	opts := InstallOptions{Version: "2.30.0", Dir: dir, Logger: slog.New(slog.DiscardHandler)}
+	if _, err := InstallOp(context.Background(), opts); !errors.Is(err, ErrSignatureUnverified) {
+		t.Errorf("InstallOp() error = %v; want ErrSignatureUnverified", err)
+	}
+
+	opts.SkipVerify = true
+	if path, err := InstallOp(context.Background(), opts); err != nil || path != filepath.Join(dir, "op") {
+		t.Errorf("InstallOp() with SkipVerify = %q, %v; want the installed executable", path, err)
+	}
+}
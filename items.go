@@ -1,28 +1,30 @@
 package onepassword
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"slices"
 	"strings"
 	"time"
 )
 
-// Category represents the type of item in 1Password
+// Category represents the type of item in 1Password. The name of each
+// category is also the name of its built-in item template, see GetItemTemplate.
 type Category string
 
 const (
 	CategoryAPICredential   Category = "API Credential"
 	CategoryBankAccount     Category = "Bank Account"
 	CategoryCreditCard      Category = "Credit Card"
+	CategoryCryptoWallet    Category = "Crypto Wallet"
 	CategoryDatabase        Category = "Database"
 	CategoryDocument        Category = "Document"
 	CategoryDriverLicense   Category = "Driver License"
 	CategoryEmailAccount    Category = "Email Account"
 	CategoryIdentity        Category = "Identity"
 	CategoryLogin           Category = "Login"
+	CategoryMedicalRecord   Category = "Medical Record"
 	CategoryMembership      Category = "Membership"
 	CategoryOutdoorLicense  Category = "Outdoor License"
 	CategoryPassport        Category = "Passport"
@@ -72,6 +74,7 @@ const (
 	FieldTypeMonthYear FieldType = "MONTH_YEAR" // A date with the format YYYYMM or YYYY/MM.
 	FieldTypePhone     FieldType = "PHONE"      // A phone number.
 	FieldTypeOTP       FieldType = "OTP"        // A one-time password. Accepts an otpauth:// URI as the value.
+	FieldTypeSSHKey    FieldType = "SSHKEY"     // An SSH key. The value is the private key.
 	FieldTypeFile      FieldType = "N/A"        // A file attachment. Accepts the path to the file as the value. Can only be added with assignment statements.
 )
 
@@ -100,6 +103,14 @@ const (
 	StrengthTerrible  PasswordStrength = "TERRIBLE"
 )
 
+// ItemState is the state of an item. Active items have no state.
+type ItemState string
+
+const (
+	ItemStateActive   ItemState = ""
+	ItemStateArchived ItemState = "ARCHIVED"
+)
+
 // ItemURL represents a URL associated with an item
 type ItemURL struct {
 	Href    string `json:"href"`
@@ -132,6 +143,8 @@ type Field struct {
 	Section         *Section         `json:"section,omitempty"`
 	PasswordDetails *PasswordDetails `json:"password_details,omitempty"`
 	Entropy         float64          `json:"entropy,omitempty"`
+
+	unknown unknownJSON `json:"-"` // Keys of the CLI output the package does not model, sent back on save
 }
 
 // Item represents a 1Password item
@@ -145,6 +158,7 @@ type Item struct {
 	Vault          Vault     `json:"vault"`
 	Category       Category  `json:"category"`
 	Favorite       bool      `json:"favorite"`
+	State          ItemState `json:"state,omitempty"`
 	Version        int       `json:"version"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
@@ -152,6 +166,8 @@ type Item struct {
 	URLs           []ItemURL `json:"urls,omitempty"`
 	Sections       []Section `json:"sections,omitempty"`
 	Fields         []Field   `json:"fields,omitempty"`
+
+	unknown unknownJSON `json:"-"` // Keys of the CLI output the package does not model, sent back on save
 }
 
 // ToJSON converts the Item struct into a JSON-encoded byte slice.
@@ -251,14 +267,14 @@ func (item *Item) AddNotes(notes string) {
 //
 // Returns:
 // - *Field: A pointer to the Field struct if found.
-// - error: An error object if the field is not found.
+// - error: ErrFieldNotFound if the field is not found.
 func (item *Item) GetFieldByID(fieldID string) (*Field, error) {
 	for _, field := range item.Fields {
 		if field.ID == fieldID {
 			return &field, nil
 		}
 	}
-	return nil, fmt.Errorf("Field with ID '%s' not found", fieldID)
+	return nil, fmt.Errorf("%w: ID '%s'", ErrFieldNotFound, fieldID)
 }
 
 // SetFavorite sets the favorite status of the item.
@@ -277,7 +293,7 @@ func (item *Item) SetAsFavorite(favorite bool) {
 //
 // Returns:
 // - []*Field: A slice of pointers to Field structs matching the label.
-// - error: An error object if no fields with the given label are found.
+// - error: ErrFieldNotFound if no fields with the given label are found.
 func (item *Item) GetFieldsByLabel(fieldLabel string) ([]*Field, error) {
 	var fields []*Field
 
@@ -291,7 +307,7 @@ func (item *Item) GetFieldsByLabel(fieldLabel string) ([]*Field, error) {
 		return fields, nil
 	}
 
-	return nil, fmt.Errorf("Field with Label '%s' not found", fieldLabel)
+	return nil, fmt.Errorf("%w: label '%s'", ErrFieldNotFound, fieldLabel)
 }
 
 // GetFieldsByPurpose retrieves fields by their purpose.
@@ -301,7 +317,7 @@ func (item *Item) GetFieldsByLabel(fieldLabel string) ([]*Field, error) {
 //
 // Returns:
 // - []*Field: A slice of pointers to Field structs matching the purpose.
-// - error: An error object if no fields with the given purpose are found.
+// - error: ErrFieldNotFound if no fields with the given purpose are found.
 func (item *Item) GetFieldsByPurpose(fieldPurpose FieldPurpose) ([]*Field, error) {
 	var fields []*Field
 	for _, field := range item.Fields {
@@ -314,7 +330,7 @@ func (item *Item) GetFieldsByPurpose(fieldPurpose FieldPurpose) ([]*Field, error
 		return fields, nil
 	}
 
-	return nil, fmt.Errorf("Field with Purpose '%s' not found", fieldPurpose)
+	return nil, fmt.Errorf("%w: purpose '%s'", ErrFieldNotFound, fieldPurpose)
 }
 
 // NewField creates a new Field instance with the specified label, value, and type.
@@ -348,10 +364,10 @@ func (item *Item) AddField(field Field) {
 // - field: The Field struct to be removed from the item.
 //
 // Returns:
-// - error: An error object if the field with the specified ID is not found.
+// - error: ErrFieldNotFound if the field with the specified ID is not found.
 func (item *Item) DeleteField(field Field) error {
 	if len(item.Fields) == 0 {
-		return fmt.Errorf("No fields found to remove")
+		return fmt.Errorf("%w: item has no fields", ErrFieldNotFound)
 	}
 
 	for i, f := range item.Fields {
@@ -360,7 +376,7 @@ func (item *Item) DeleteField(field Field) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Field with ID '%s' not found", field.ID)
+	return fmt.Errorf("%w: ID '%s'", ErrFieldNotFound, field.ID)
 }
 
 // UpdateField updates an existing field in the Item's Fields slice with the provided field.
@@ -375,7 +391,7 @@ func (item *Item) DeleteField(field Field) error {
 //   - error: An error if no fields are present or if the specified field ID is not found.
 func (item *Item) UpdateField(field Field) error {
 	if len(item.Fields) == 0 {
-		return fmt.Errorf("No fields found to update")
+		return fmt.Errorf("%w: item has no fields", ErrFieldNotFound)
 	}
 
 	for i, f := range item.Fields {
@@ -384,7 +400,7 @@ func (item *Item) UpdateField(field Field) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Field with ID '%s' not found", field.ID)
+	return fmt.Errorf("%w: ID '%s'", ErrFieldNotFound, field.ID)
 }
 
 // DeleteTag removes a tag from the item by its name.
@@ -393,10 +409,10 @@ func (item *Item) UpdateField(field Field) error {
 // - tag: A string representing the name of the tag to remove.
 //
 // Returns:
-// - error: An error object if the tag with the specified name is not found.
+// - error: ErrTagNotFound if the tag with the specified name is not found.
 func (item *Item) DeleteTag(tag string) error {
 	if len(item.Tags) == 0 {
-		return fmt.Errorf("No tags found to remove")
+		return fmt.Errorf("%w: item has no tags", ErrTagNotFound)
 	}
 
 	for i, t := range item.Tags {
@@ -405,7 +421,7 @@ func (item *Item) DeleteTag(tag string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Tag '%s' not found", tag)
+	return fmt.Errorf("%w: '%s'", ErrTagNotFound, tag)
 }
 
 // AddTag appends a new tag to the item's Tags slice.
@@ -432,12 +448,12 @@ func (item *Item) isSectionIDUnique(sectionID string) bool {
 // - section: The Section struct to be added to the item.
 //
 // Returns:
-// - error: An error object if the section ID is not unique.
+// - error: ErrDuplicateSectionID if the section ID is not unique.
 //
 // This method appends the provided section to the item's Sections slice.
 func (item *Item) AddSection(section Section) error {
 	if !item.isSectionIDUnique(section.ID) {
-		return fmt.Errorf("SectionID is not unique within item")
+		return ErrDuplicateSectionID
 	}
 	item.Sections = append(item.Sections, section)
 	return nil
@@ -498,7 +514,7 @@ func (item *Item) RenameSection(section Section, newLabel string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Section not found in item")
+	return ErrSectionNotFound
 }
 
 // AddFieldToSection adds a new field to a specific section in the item.
@@ -528,7 +544,7 @@ func (item *Item) AddFieldToSection(section Section, field Field) error {
 	}
 
 	if !sectionFound {
-		return fmt.Errorf("Section not found in item")
+		return ErrSectionNotFound
 	}
 
 	return nil
@@ -556,7 +572,7 @@ func (item *Item) MoveFieldToSection(field Field, section Section) error {
 	}
 
 	if foundSection == nil {
-		return fmt.Errorf("Section not found in item")
+		return ErrSectionNotFound
 	}
 
 	// Find the field in the item and update its section
@@ -567,7 +583,7 @@ func (item *Item) MoveFieldToSection(field Field, section Section) error {
 		}
 	}
 
-	return fmt.Errorf("Field not found in item")
+	return ErrFieldNotFound
 }
 
 // DeleteFieldFromSection removes a field from a specific section in the item.
@@ -596,7 +612,7 @@ func (item *Item) DeleteFieldFromSection(section Section, field Field) error {
 	}
 
 	if !itemFound {
-		return fmt.Errorf("Field not found in section")
+		return ErrFieldNotFound
 	}
 
 	return nil
@@ -610,18 +626,25 @@ func (item *Item) DeleteFieldFromSection(section Section, field Field) error {
 // This method uses the UpdateItemWithStruct method of the OpCLI instance to
 // save the item. It ensures that the cli field and item ID are properly set
 // before attempting to save.
-func (item *Item) Save() error {
+func (item *Item) Save(ctx context.Context) error {
 	if item.cli == nil {
-		return fmt.Errorf("cli is nil, cannot save item")
+		return fmt.Errorf("cannot save item: %w", ErrNoClient)
 	}
 	if item.ID == "" {
 		return fmt.Errorf("item ID is empty, cannot save item")
 	}
 
+	if item.cli.backend != nil {
+		if err := item.cli.backendUpdateItem(ctx, item); err != nil {
+			return fmt.Errorf("failed to save item: %w", err)
+		}
+		return nil
+	}
+
 	// Use the new UpdateItemWithStruct method to save the item
-	item, err := item.cli.updateItemWithStruct(*item)
+	item, err := item.cli.updateItemWithStruct(ctx, *item)
 	if err != nil {
-		return fmt.Errorf("failed to save item: %v", err)
+		return fmt.Errorf("failed to save item: %w", err)
 	}
 
 	return nil
@@ -635,17 +658,24 @@ func (item *Item) Save() error {
 // This method uses the DeleteItem method of the OpCLI instance to delete the
 // item. It ensures that the cli field and item ID are properly set before
 // attempting to delete.
-func (item *Item) Delete() error {
+func (item *Item) Delete(ctx context.Context) error {
 	if item.cli == nil {
-		return fmt.Errorf("cli is nil, cannot delete item")
+		return fmt.Errorf("cannot delete item: %w", ErrNoClient)
 	}
 	if item.ID == "" {
 		return fmt.Errorf("item ID is empty, cannot delete item")
 	}
 
+	if item.cli.backend != nil {
+		if err := item.cli.backendDeleteItem(ctx, item); err != nil {
+			return fmt.Errorf("failed to delete item: %w", err)
+		}
+		return nil
+	}
+
 	// Use the new DeleteItem method to delete the item
-	if err := item.cli.deleteItem(*item); err != nil {
-		return fmt.Errorf("failed to delete item: %v", err)
+	if err := item.cli.deleteItem(ctx, *item); err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
 	}
 	return nil
 }
@@ -673,17 +703,17 @@ func (item *Item) AddURL(url ItemURL) {
 // - href: A string representing the Href of the URLs to remove.
 //
 // Returns:
-// - error: An error object if no URLs with the given Href are found or if the last URL cannot be deleted.
+// - error: ErrURLNotFound if no URLs with the given Href are found, or ErrLastURL if the last URL cannot be deleted.
 //
 // Note: The 1Password CLI has a known issue where the last URL cannot be deleted. This method will
 // return an error if attempting to delete the last remaining URL.
 func (item *Item) DeleteURLs(href string) error {
 	if len(item.URLs) == 0 {
-		return fmt.Errorf("no URLs found to remove")
+		return fmt.Errorf("%w: item has no URLs", ErrURLNotFound)
 	}
 
 	if len(item.URLs) == 1 {
-		return fmt.Errorf("cannot delete the last URL due to a known issue in the 1Password CLI")
+		return ErrLastURL
 	}
 
 	updatedURLs := item.URLs[:0] // Create a new slice to hold non-matching URLs
@@ -698,7 +728,7 @@ func (item *Item) DeleteURLs(href string) error {
 	}
 
 	if !found {
-		return fmt.Errorf("no URLs with href '%s' found", href)
+		return fmt.Errorf("%w: href '%s'", ErrURLNotFound, href)
 	}
 
 	item.URLs = updatedURLs
@@ -720,24 +750,8 @@ type ItemTemplate struct {
 // This method executes the "item list" command using the CLI and parses the
 // JSON output into a slice of Item structs. It also populates the cli field
 // for each item.
-func (cli *OpCLI) GetItems() (*[]Item, error) {
-	output, err := cli.ExecuteOpCommand("item", "list")
-	if err != nil {
-		return nil, err
-	}
-
-	var items []Item
-	err = json.Unmarshal(output, &items)
-	if err != nil {
-		return nil, err
-	}
-
-	// Populate the cli field for each item
-	for i := range items {
-		items[i].cli = cli
-	}
-
-	return &items, nil
+func (cli *OpCLI) GetItems(ctx context.Context) (*[]Item, error) {
+	return cli.listItems(ctx, ListItemsOptions{})
 }
 
 // GetItemsByVault retrieves a list of items from a specified vault using the 1Password CLI.
@@ -745,29 +759,17 @@ func (cli *OpCLI) GetItems() (*[]Item, error) {
 // Each item in the returned list is associated with the OpCLI instance.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - vault: A Vault object representing the vault from which to retrieve items.
 //
 // Returns:
 //   - A pointer to a slice of Item objects retrieved from the specified vault.
 //   - An error if the command execution or JSON unmarshalling fails.
-func (cli *OpCLI) GetItemsByVault(vault Vault) (*[]Item, error) {
-	output, err := cli.ExecuteOpCommand("item", "list", "--vault", vault.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	var items []Item
-	err = json.Unmarshal(output, &items)
-	if err != nil {
-		return nil, err
+func (cli *OpCLI) GetItemsByVault(ctx context.Context, vault Vault) (*[]Item, error) {
+	if cli.backend != nil {
+		return cli.backendItems(ctx, vault.ID)
 	}
-
-	// Populate the cli field for each item
-	for i := range items {
-		items[i].cli = cli
-	}
-
-	return &items, nil
+	return cli.listItems(ctx, ListItemsOptions{Vault: vault.ID})
 }
 
 // GetItemsByCategory retrieves a list of items filtered by the specified categories.
@@ -775,36 +777,20 @@ func (cli *OpCLI) GetItemsByVault(vault Vault) (*[]Item, error) {
 // into a slice of Item structs, and associates each item with the OpCLI instance.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - categories: A slice of Category values to filter the items by.
 //
 // Returns:
 //   - A pointer to a slice of Item structs containing the filtered items.
 //   - An error if the command execution or JSON unmarshaling fails.
-func (cli *OpCLI) GetItemsByCategory(categories []Category) (*[]Item, error) {
-	var items []Item
-
-	categoryString := FormatCategories(categories)
-	output, err := cli.ExecuteOpCommand("item", "list", "--category", categoryString)
-	if err != nil {
-		return nil, err
-	}
-
-	err = json.Unmarshal(output, &items) // Unmarshal the output into items
-	if err != nil {
-		return nil, err
-	}
-
-	// Populate the cli field for each item
-	for i := range items {
-		items[i].cli = cli
-	}
-
-	return &items, nil
+func (cli *OpCLI) GetItemsByCategory(ctx context.Context, categories []Category) (*[]Item, error) {
+	return cli.listItems(ctx, ListItemsOptions{Categories: categories})
 }
 
 // getItem retrieves the details of a specific item by its identifier.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - identifier: A string representing the unique identifier of the item.
 //
 // Returns:
@@ -813,14 +799,18 @@ func (cli *OpCLI) GetItemsByCategory(categories []Category) (*[]Item, error) {
 //
 // This method executes the "item get" command using the CLI and parses the
 // JSON output into an Item struct. It also populates the cli field for the item.
-func (cli *OpCLI) getItem(identifier string) (*Item, error) {
-	output, err := cli.ExecuteOpCommand("item", "get", identifier)
+func (cli *OpCLI) getItem(ctx context.Context, identifier string) (*Item, error) {
+	if cli.backend != nil {
+		return nil, fmt.Errorf("item lookup without vault: %w", ErrUnsupportedByBackend)
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, "item", "get", identifier)
 	if err != nil {
 		return nil, err
 	}
 
 	var item Item
-	err = json.Unmarshal(output, &item)
+	err = cli.unmarshalItem(output, &item)
 	if err != nil {
 		return nil, err
 	}
@@ -834,30 +824,33 @@ func (cli *OpCLI) getItem(identifier string) (*Item, error) {
 // GetItemByName retrieves an item by its name.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - itemName: A string representing the name of the item.
 //
 // Returns:
 // - *Item: A pointer to the Item struct containing the item's details.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) GetItemByName(itemName string) (*Item, error) {
-	return cli.getItem(itemName)
+func (cli *OpCLI) GetItemByName(ctx context.Context, itemName string) (*Item, error) {
+	return cli.getItem(ctx, itemName)
 }
 
 // GetItemByID retrieves an item by its ID.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - itemID: A string representing the unique identifier of the item.
 //
 // Returns:
 // - *Item: A pointer to the Item struct containing the item's details.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) GetItemByID(itemID string) (*Item, error) {
-	return cli.getItem(itemID)
+func (cli *OpCLI) GetItemByID(ctx context.Context, itemID string) (*Item, error) {
+	return cli.getItem(ctx, itemID)
 }
 
 // GetItemTemplateByName retrieves an item template by its name.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - templateName: A string representing the name of the template.
 //
 // Returns:
@@ -866,8 +859,10 @@ func (cli *OpCLI) GetItemByID(itemID string) (*Item, error) {
 //
 // This method executes the "item template get" command using the CLI and parses
 // the JSON output into an Item struct. It also populates the cli field for the item.
-func (cli *OpCLI) GetItemTemplateByName(templateName string) (*Item, error) {
-	output, err := cli.ExecuteOpCommand("item", "template", "get", templateName)
+// The output is cached on the client, as templates only change with the CLI
+// version, so each template is only fetched once.
+func (cli *OpCLI) GetItemTemplateByName(ctx context.Context, templateName string) (*Item, error) {
+	output, err := cli.cachedTemplate(ctx, templateName)
 	if err != nil {
 		return nil, err
 	}
@@ -883,6 +878,20 @@ func (cli *OpCLI) GetItemTemplateByName(templateName string) (*Item, error) {
 	return &item, nil
 }
 
+// GetItemTemplate retrieves the built-in item template of a category, e.g.
+// CategoryLogin. See GetItemTemplateByName.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - category: The category of the template.
+//
+// Returns:
+// - *Item: A pointer to the Item struct containing the template's details.
+// - error: An error object if the operation fails.
+func (cli *OpCLI) GetItemTemplate(ctx context.Context, category Category) (*Item, error) {
+	return cli.GetItemTemplateByName(ctx, string(category))
+}
+
 // GetItemTemplates retrieves a list of all item templates using the 1Password CLI.
 //
 // Returns:
@@ -890,9 +899,10 @@ func (cli *OpCLI) GetItemTemplateByName(templateName string) (*Item, error) {
 // - error: An error object if the operation fails.
 //
 // This method executes the "item template list" command using the CLI and parses
-// the JSON output into a slice of ItemTemplate structs.
-func (cli *OpCLI) GetItemTemplates() (*[]ItemTemplate, error) {
-	output, err := cli.ExecuteOpCommand("item", "template", "list")
+// the JSON output into a slice of ItemTemplate structs. The output is cached on
+// the client like that of GetItemTemplateByName.
+func (cli *OpCLI) GetItemTemplates(ctx context.Context) (*[]ItemTemplate, error) {
+	output, err := cli.cachedTemplateList(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -910,6 +920,7 @@ func (cli *OpCLI) GetItemTemplates() (*[]ItemTemplate, error) {
 // It accepts an Item object and a boolean flag indicating whether to generate a password.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - item: A pointer to the Item struct representing the item to be created. The ID field
 //     of the item must be empty for new items.
 //   - genPassword: A boolean flag indicating whether to generate a password for the item.
@@ -923,41 +934,81 @@ func (cli *OpCLI) GetItemTemplates() (*[]ItemTemplate, error) {
 // Notes:
 //   - The function requires the OpCLI instance to have valid account information (Account.UserUUID).
 //   - The "op" CLI tool must be installed and accessible via the path specified in the OpCLI.Path field.
-func (cli *OpCLI) CreateItem(item *Item, genPassword bool) (*Item, error) {
+func (cli *OpCLI) CreateItem(ctx context.Context, item *Item, genPassword bool) (*Item, error) {
+	if cli.backend != nil {
+		if genPassword {
+			return nil, fmt.Errorf("password generation: %w", ErrUnsupportedByBackend)
+		}
+		return cli.backendCreateItem(ctx, item)
+	}
+
+	return cli.createItem(ctx, item, genPassword, false)
+}
+
+// CreateItemPreview runs "op item create --dry-run" and returns the item the
+// CLI would create, including generated passwords and the assigned field
+// IDs, without writing anything to the vault. It validates the item like
+// CreateItem, so templates can be checked before they are used.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - item: A pointer to the Item struct to preview. The ID field must be empty.
+//   - genPassword: A boolean flag indicating whether to generate a password for the item.
+//
+// Returns:
+//   - A pointer to the resolved Item struct. It has no ID, as no item was created.
+//   - An error if the item is invalid or the "op item create" command fails.
+//
+// Example usage:
+//
+//	preview, err := cli.CreateItemPreview(ctx, item, true)
+//	if err != nil {
+//	    log.Fatalf("Invalid item template: %v", err)
+//	}
+//	fmt.Println(preview.Fields)
+func (cli *OpCLI) CreateItemPreview(ctx context.Context, item *Item, genPassword bool) (*Item, error) {
+	if cli.backend != nil {
+		return nil, fmt.Errorf("item preview: %w", ErrUnsupportedByBackend)
+	}
+
+	return cli.createItem(ctx, item, genPassword, true)
+}
+
+// createItem runs "op item create" with the item as JSON template, or only
+// previews the created item with --dry-run.
+func (cli *OpCLI) createItem(ctx context.Context, item *Item, genPassword, dryRun bool) (*Item, error) {
 
 	if item.ID != "" {
 		return nil, fmt.Errorf("item ID should be empty for new items")
 	}
 
 	if cli.Account == nil || cli.Account.UserUUID == "" {
-		return nil, fmt.Errorf("account information is missing")
-	}
-
-	args := cli.getDefaultArgs()
-
-	jsonData, err := json.Marshal(item)
-	if err != nil {
-		return nil, fmt.Errorf("failed to serialize item to JSON: %w", err)
+		return nil, ErrMissingAccount
 	}
 
-	var cmd *exec.Cmd
+	args := []string{"item", "create"}
 	if genPassword {
 		// Generate a password if required
-		cmd = exec.Command(cli.Path, append([]string{"item", "create", "--generate-password"}, args...)...)
-	} else {
-		cmd = exec.Command(cli.Path, append([]string{"item", "create"}, args...)...)
+		args = append(args, "--generate-password")
+	}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+
+	jsonData, err := marshalItemPayload(*item, true)
+	if err != nil {
+		return nil, err
 	}
-	cmd.Stdin = bytes.NewReader(jsonData)
 
-	// Execute the "op item create" command and capture output
-	output, err := cmd.Output()
+	// Execute the "op item create" command with the template on stdin
+	output, err := cli.executeOpCommandWith(ctx, execSettings{stdin: jsonData}, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute 'op item create': %w", err)
+		return nil, fmt.Errorf("failed to create item: %w", err)
 	}
 
 	// Unmarshal the output into the createdItem struct
 	var createdItem Item
-	if err := json.Unmarshal(output, &createdItem); err != nil {
+	if err := cli.unmarshalItem(output, &createdItem); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal created item: %w", err)
 	}
 
@@ -967,18 +1018,19 @@ func (cli *OpCLI) CreateItem(item *Item, genPassword bool) (*Item, error) {
 // deleteItem deletes an item by its ID using the 1Password CLI.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - itemID: A string representing the unique identifier of the item to delete.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (cli *OpCLI) deleteItem(item Item) error {
+func (cli *OpCLI) deleteItem(ctx context.Context, item Item) error {
 	if item.ID == "" {
 		return fmt.Errorf("item ID cannot be empty")
 	}
 
-	_, err := cli.ExecuteOpCommand("item", "delete", item.ID)
+	_, err := cli.ExecuteOpCommand(ctx, "item", "delete", item.ID)
 	if err != nil {
-		return fmt.Errorf("failed to delete item with ID '%s': %v", item.ID, err)
+		return fmt.Errorf("failed to delete item with ID '%s': %w", item.ID, err)
 	}
 
 	return nil
@@ -987,6 +1039,7 @@ func (cli *OpCLI) deleteItem(item Item) error {
 // updateItemWithStruct updates an existing item in 1Password using the provided Item struct.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - identifier: The unique identifier or name of the item to update.
 // - item: The Item struct containing the updated item data.
 //
@@ -994,33 +1047,27 @@ func (cli *OpCLI) deleteItem(item Item) error {
 // - error: An error object if the operation fails.
 //
 // This method uses the "op item edit" command to update the item.
-func (cli *OpCLI) updateItemWithStruct(item Item) (*Item, error) {
+func (cli *OpCLI) updateItemWithStruct(ctx context.Context, item Item) (*Item, error) {
 
 	if cli.Account == nil || cli.Account.UserUUID == "" {
-		return nil, fmt.Errorf("account information is missing")
+		return nil, ErrMissingAccount
 	}
 
-	args := cli.getDefaultArgs()
-
-	// Serialize the Item struct to JSON
-	jsonData, err := json.Marshal(item)
+	// Serialize the writable fields of the Item struct to JSON
+	jsonData, err := marshalItemPayload(item, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to serialize item to JSON: %w", err)
+		return nil, err
 	}
 
-	// Execute the "op item edit" command
-	cmd := exec.Command(cli.Path, append([]string{"item", "edit", item.ID}, args...)...)
-	cmd.Stdin = bytes.NewReader(jsonData)
-
-	// Execute the "op item edit" command and capture output
-	output, err := cmd.Output()
+	// Execute the "op item edit" command with the template on stdin
+	output, err := cli.executeOpCommandWith(ctx, execSettings{stdin: jsonData}, "item", "edit", item.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute 'op item edit': %w", err)
+		return nil, fmt.Errorf("failed to edit item: %w", err)
 	}
 
 	// Unmarshal the output into the updatedItem struct
 	var updatedItem Item
-	if err := json.Unmarshal(output, &updatedItem); err != nil {
+	if err := cli.unmarshalItem(output, &updatedItem); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal updated item: %w", err)
 	}
 
@@ -0,0 +1,102 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// errStopIteration ends a streamed listing when the loop over an iterator is left early.
+var errStopIteration = errors.New("iteration stopped")
+
+// seqList returns an iterator over the elements of a list command. The
+// command is started when the iteration begins and its output is decoded
+// while it is running. Leaving the loop early stops the command. If the
+// command fails or its output cannot be decoded, the error is yielded with a
+// zero element as the last pair.
+func seqList[T any](ctx context.Context, cli *OpCLI, args ...string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		err := streamList(ctx, cli, func(element T) error {
+			bind(cli, &element)
+			if !yield(element, nil) {
+				return errStopIteration
+			}
+			return nil
+		}, args...)
+		if err != nil && !errors.Is(err, errStopIteration) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// AllItems returns an iterator over the items of the account. Unlike
+// GetItems, the items are yielded while they are decoded from the output of
+// the 1Password CLI, and leaving the loop early stops the command.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: Optional ListItemsOptions to filter the items.
+//
+// Returns:
+//   - iter.Seq2[Item, error]: The items. An error is yielded as the last pair if the listing fails.
+//
+// Example usage:
+//
+//	for item, err := range cli.AllItems(ctx, onepassword.ListItemsOptions{Vault: "Private"}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if item.Title == "GitHub" {
+//	        break
+//	    }
+//	}
+func (cli *OpCLI) AllItems(ctx context.Context, opts ...ListItemsOptions) iter.Seq2[Item, error] {
+	return seqList[Item](ctx, cli, itemListArgs(opts...)...)
+}
+
+// AllVaults returns an iterator over the vaults of the account.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - iter.Seq2[Vault, error]: The vaults. An error is yielded as the last pair if the listing fails.
+func (cli *OpCLI) AllVaults(ctx context.Context) iter.Seq2[Vault, error] {
+	return seqList[Vault](ctx, cli, "vault", "list")
+}
+
+// AllUsers returns an iterator over the users of the account.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: Optional ListUsersOptions to filter the users by group or vault.
+//
+// Returns:
+//   - iter.Seq2[User, error]: The users. An error is yielded as the last pair if the listing fails.
+func (cli *OpCLI) AllUsers(ctx context.Context, opts ...ListUsersOptions) iter.Seq2[User, error] {
+	return seqList[User](ctx, cli, userListArgs(opts...)...)
+}
+
+// AllGroups returns an iterator over the groups of the account.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: Optional ListGroupsOptions to filter the groups by vault or user.
+//
+// Returns:
+//   - iter.Seq2[Group, error]: The groups. An error is yielded as the last pair if the listing fails.
+func (cli *OpCLI) AllGroups(ctx context.Context, opts ...ListGroupsOptions) iter.Seq2[Group, error] {
+	return seqList[Group](ctx, cli, groupListArgs(opts...)...)
+}
+
+// AllItems returns an iterator over the items of the vault.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - iter.Seq2[Item, error]: The items. An error is yielded as the last pair if the listing fails.
+func (vault *Vault) AllItems(ctx context.Context) iter.Seq2[Item, error] {
+	return vault.cli.AllItems(ctx, ListItemsOptions{Vault: vault.ID})
+}
@@ -0,0 +1,136 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAllItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdout      string
+		err         error
+		stopAt      string
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "All items",
+			stdout:   `[{"id":"a","title":"A"},{"id":"b","title":"B"},{"id":"c","title":"C"}]`,
+			expected: []string{"A", "B", "C"},
+		},
+		{
+			name:     "Break early",
+			stdout:   `[{"id":"a","title":"A"},{"id":"b","title":"B"},{"id":"c","title":"C"}]`,
+			stopAt:   "B",
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "Empty output",
+			stdout:   ``,
+			expected: nil,
+		},
+		{
+			name:        "Command fails",
+			err:         errors.New("exit status 1"),
+			expectedErr: true,
+		},
+		{
+			name:        "Truncated output",
+			stdout:      `[{"id":"a","title":"A"},{"id":`,
+			expected:    []string{"A"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				if _, err := cmd.Stdout.Write([]byte(tt.stdout)); err != nil {
+					return nil, nil, err
+				}
+				return nil, nil, tt.err
+			}))
+
+			var got []string
+			var gotErr error
+			for item, err := range cli.AllItems(context.Background()) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				if item.cli != cli {
+					t.Errorf("AllItems() item %s is not bound to the client", item.ID)
+				}
+				got = append(got, item.Title)
+				if item.Title == tt.stopAt {
+					break
+				}
+			}
+
+			if (gotErr != nil) != tt.expectedErr {
+				t.Fatalf("AllItems() error = %v; want error %v", gotErr, tt.expectedErr)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("AllItems() titles = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVaultAllItems(t *testing.T) {
+	var gotArgs []string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		gotArgs = cmd.Args
+		_, err := cmd.Stdout.Write([]byte(`[{"id":"a","title":"A"}]`))
+		return nil, nil, err
+	}))
+
+	vault := Vault{ID: "vault-id", cli: cli}
+	for _, err := range vault.AllItems(context.Background()) {
+		if err != nil {
+			t.Fatalf("AllItems() error = %v", err)
+		}
+	}
+
+	expectedArgs := []string{"item", "list", "--vault", "vault-id", "--account", "user-uuid", "--format=json"}
+	if !slices.Equal(gotArgs, expectedArgs) {
+		t.Errorf("AllItems() args = %q; want %q", gotArgs, expectedArgs)
+	}
+}
+
+func TestAllUsersAndGroups(t *testing.T) {
+	var gotArgs [][]string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		gotArgs = append(gotArgs, cmd.Args)
+		_, err := cmd.Stdout.Write([]byte(`[{"id":"a","name":"A"},{"id":"b","name":"B"}]`))
+		return nil, nil, err
+	}))
+
+	var users []string
+	for user, err := range cli.AllUsers(context.Background(), ListUsersOptions{Group: "Admins"}) {
+		if err != nil {
+			t.Fatalf("AllUsers() error = %v", err)
+		}
+		users = append(users, user.ID)
+	}
+	var groups []string
+	for group, err := range cli.AllGroups(context.Background(), ListGroupsOptions{Vault: "Private"}) {
+		if err != nil {
+			t.Fatalf("AllGroups() error = %v", err)
+		}
+		groups = append(groups, group.ID)
+	}
+
+	if !slices.Equal(users, []string{"a", "b"}) || !slices.Equal(groups, []string{"a", "b"}) {
+		t.Errorf("AllUsers() = %q, AllGroups() = %q; want [a b]", users, groups)
+	}
+	if len(gotArgs) != 2 || !slices.Contains(gotArgs[0], "--group") || !slices.Contains(gotArgs[1], "--vault") {
+		t.Errorf("unexpected args %q", gotArgs)
+	}
+}
@@ -0,0 +1,140 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// defaultKeepAliveInterval is the idle time after which the keep-alive
+// renews the session. It leaves a buffer before sessions expire after 30
+// minutes of inactivity.
+const defaultKeepAliveInterval = 20 * time.Minute
+
+// keepAliveTimeout limits a single keep-alive command.
+const keepAliveTimeout = 30 * time.Second
+
+// keepAlive is the state of the background keep-alive of an OpCLI instance.
+type keepAlive struct {
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartKeepAlive keeps the session of the active account alive while the
+// OpCLI instance is idle, e.g. in long-running daemons. Whenever no command
+// has run for the interval, a background goroutine runs "op whoami", which
+// resets the inactivity timeout of the session. A random jitter of up to a
+// tenth of the interval spreads the commands of many instances. Sessions that
+// have already expired are not renewed; the next command signs in again.
+//
+// The keep-alive is stopped by StopKeepAlive or Close. Calling StartKeepAlive
+// again restarts it with the new interval. It cannot be started after Close.
+//
+// Parameters:
+//   - interval: The idle time after which the session is renewed. Defaults to
+//     20 minutes if zero.
+//
+// Returns:
+//   - error: ErrClosed if the instance is closed, or an error if the interval
+//     is negative or not shorter than the 30 minute inactivity timeout of
+//     sessions.
+func (cli *OpCLI) StartKeepAlive(interval time.Duration) error {
+	if interval == 0 {
+		interval = defaultKeepAliveInterval
+	}
+	if interval < 0 || interval >= 30*time.Minute {
+		return fmt.Errorf("invalid keep-alive interval: %s", interval)
+	}
+
+	cli.StopKeepAlive()
+
+	cli.keepAlive.mu.Lock()
+	defer cli.keepAlive.mu.Unlock()
+
+	// Close stops the keep-alive after marking the instance as closed, so a
+	// keep-alive started before is stopped by Close
+	if cli.isClosed() {
+		return ErrClosed
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	cli.keepAlive.stop, cli.keepAlive.done = stop, done
+	go cli.runKeepAlive(interval, stop, done)
+	return nil
+}
+
+// StopKeepAlive stops the keep-alive started with StartKeepAlive and waits
+// until a running keep-alive command has finished.
+func (cli *OpCLI) StopKeepAlive() {
+	cli.keepAlive.mu.Lock()
+	stop, done := cli.keepAlive.stop, cli.keepAlive.done
+	cli.keepAlive.stop, cli.keepAlive.done = nil, nil
+	cli.keepAlive.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// runKeepAlive renews the session whenever it has been idle for the interval.
+func (cli *OpCLI) runKeepAlive(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	timer := time.NewTimer(cli.nextKeepAlive(interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+
+		// Commands run in the meantime may have postponed the renewal
+		if account := cli.Account; account != nil && account.hasSession() && !account.IsSessionExpired() &&
+			time.Since(account.lastActivity()) >= interval-interval/10 {
+			cli.renewSession(stop)
+		}
+		timer.Reset(cli.nextKeepAlive(interval))
+	}
+}
+
+// renewSession runs a cheap command to reset the inactivity timeout of the
+// session. The command is cancelled if the keep-alive is stopped.
+func (cli *OpCLI) renewSession(stop chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), keepAliveTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if _, err := cli.ExecuteOpCommand(ctx, "whoami"); err != nil {
+		cli.log().Warn("failed to keep session alive", "account", cli.Account.UserUUID, "error", err)
+		return
+	}
+	cli.log().Debug("kept session alive", "account", cli.Account.UserUUID)
+}
+
+// nextKeepAlive returns the time until the session has been idle for the
+// interval, shortened by a random jitter.
+func (cli *OpCLI) nextKeepAlive(interval time.Duration) time.Duration {
+	wait := interval - keepAliveJitter(interval)
+	if account := cli.Account; account != nil && account.hasSession() {
+		wait -= time.Since(account.lastActivity())
+	}
+	return max(wait, interval/10)
+}
+
+// keepAliveJitter returns a random duration of up to a tenth of the interval.
+func keepAliveJitter(interval time.Duration) time.Duration {
+	return rand.N(interval/10 + 1)
+}
@@ -0,0 +1,47 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeepAlive(t *testing.T) {
+	var commands atomic.Int32
+	account := &Account{UserUUID: "user-uuid"}
+	account.SetSignInInfo("session-token")
+
+	cli := &OpCLI{Path: "op", Account: account}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		if cmd.Args[0] == "whoami" {
+			commands.Add(1)
+		}
+		return []byte(`{}`), nil, nil
+	}))
+
+	if err := cli.StartKeepAlive(50 * time.Millisecond); err != nil {
+		t.Fatalf("StartKeepAlive() error = %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err := cli.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	renewals := commands.Load()
+	if renewals == 0 {
+		t.Fatalf("keep-alive ran no commands; want at least one")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := commands.Load(); got != renewals {
+		t.Errorf("keep-alive ran %d commands after Close; want none", got-renewals)
+	}
+
+	if err := cli.StartKeepAlive(time.Minute); !errors.Is(err, ErrClosed) {
+		t.Errorf("StartKeepAlive() after Close error = %v; want %v", err, ErrClosed)
+	}
+	if err := cli.StartKeepAlive(time.Hour); err == nil {
+		t.Errorf("StartKeepAlive() with an interval longer than the session timeout succeeded; want an error")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package onepassword
+
+import "errors"
+
+// errCredentialManagerUnavailable is returned by the Credential Manager
+// helpers on platforms other than Windows.
+var errCredentialManagerUnavailable = errors.New("windows credential manager is not available on this platform")
+
+func credentialRead(target string) (string, error) {
+	return "", errCredentialManagerUnavailable
+}
+
+func credentialWrite(target, user, secret string) error {
+	return errCredentialManagerUnavailable
+}
+
+func credentialDelete(target string) error {
+	return errCredentialManagerUnavailable
+}
@@ -0,0 +1,95 @@
+package onepassword
+
+import (
+	"slices"
+	"strings"
+)
+
+// sensitiveFlags are flags of the 1Password CLI whose values are secrets.
+var sensitiveFlags = []string{
+	"--session",
+	"--password",
+	"--secret-key",
+	"--token",
+	"--otp",
+}
+
+// booleanFlags are flags of the 1Password CLI that do not take a value, so
+// the argument following them is not a flag value.
+var booleanFlags = []string{
+	"--all", "--archive", "--cache", "--debug", "--dry-run", "--favorite",
+	"--force", "--generate-password", "--help", "--include-archive",
+	"--iso-timestamps", "--long", "--me", "--no-color", "--no-newline",
+	"--raw", "--reveal", "--signin", "--version",
+}
+
+// SetCommandLogging enables or disables detailed debug logging of commands.
+// When enabled, every command is logged at debug level with its command
+// line, duration, exit code and, on failure, the stderr output of op.
+// Secrets are redacted from the command line: values of flags like --session
+// and the values of field assignments such as "password=secret".
+//
+// Parameters:
+//   - enabled: Whether commands are logged in detail.
+func (cli *OpCLI) SetCommandLogging(enabled bool) {
+	cli.logCommands = enabled
+}
+
+// commandLogAttrs returns the attributes of the detailed debug log entry for
+// a finished command.
+func commandLogAttrs(cmd *Command, stderr []byte, err error) []any {
+	exitCode := 0
+	if err != nil {
+		exitCode = (&OpCliError{Err: err}).ExitCode()
+	}
+
+	attrs := []any{
+		"args", strings.Join(redactCommandLine(cmd.Args), " "),
+		"exit_code", exitCode,
+	}
+	if err != nil && len(stderr) > 0 {
+		attrs = append(attrs, "stderr", strings.TrimSpace(string(stderr)))
+	}
+	return attrs
+}
+
+// redactCommandLine returns the arguments with secrets replaced. Unlike
+// redactArgs, names and IDs are kept to help diagnose failed commands.
+func redactCommandLine(args []string) []string {
+	redacted := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(arg, "=")
+			if !slices.Contains(sensitiveFlags, name) {
+				redacted[i] = arg
+				continue
+			}
+			if hasValue {
+				redacted[i] = name + "=" + redactedArg
+				continue
+			}
+			redacted[i] = arg
+			if i+1 < len(args) {
+				i++
+				redacted[i] = redactedArg
+			}
+		case strings.Contains(arg, "="):
+			// Field assignments like "password=secret" of item create and edit
+			name, _, _ := strings.Cut(arg, "=")
+			redacted[i] = name + "=" + redactedArg
+		case isTokenLike(arg):
+			redacted[i] = redactedArg
+		default:
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
+
+// isTokenLike reports whether an argument looks like a service account
+// token or another JSON web token.
+func isTokenLike(arg string) bool {
+	return strings.HasPrefix(arg, "ops_") || strings.HasPrefix(arg, "eyJ")
+}
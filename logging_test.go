@@ -0,0 +1,70 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRedactCommandLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Names and IDs are kept",
+			args:     []string{"item", "get", "Database", "--vault", "Private", "--format=json"},
+			expected: []string{"item", "get", "Database", "--vault", "Private", "--format=json"},
+		},
+		{
+			name:     "Field assignments",
+			args:     []string{"item", "edit", "Database", "password=secret", "notes[text]=hello"},
+			expected: []string{"item", "edit", "Database", "password=[redacted]", "notes[text]=[redacted]"},
+		},
+		{
+			name:     "Sensitive flags",
+			args:     []string{"whoami", "--session", "token", "--secret-key=A3-KEY"},
+			expected: []string{"whoami", "--session", "[redacted]", "--secret-key=[redacted]"},
+		},
+		{
+			name:     "Tokens",
+			args:     []string{"connect", "token", "validate", "ops_eyJzaWduSW4"},
+			expected: []string{"connect", "token", "validate", "[redacted]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactCommandLine(tt.args); !slices.Equal(got, tt.expected) {
+				t.Errorf("redactCommandLine() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandLogging(t *testing.T) {
+	var logs bytes.Buffer
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	cli.SetCommandLogging(true)
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		return nil, []byte("[ERROR] permission denied\n"), errors.New("exit status 1")
+	}))
+
+	cli.ExecuteOpCommand(context.Background(), "item", "edit", "Database", "password=secret")
+
+	output := logs.String()
+	for _, expected := range []string{`args="item edit Database password=[redacted] --account user-uuid --format=json"`, "exit_code=-1", `stderr="[ERROR] permission denied"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("log output %q does not contain %q", output, expected)
+		}
+	}
+	if strings.Contains(output, "secret") {
+		t.Errorf("log output %q contains the secret", output)
+	}
+}
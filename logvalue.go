@@ -0,0 +1,87 @@
+package onepassword
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer. An item is logged as a compact summary
+// of its ID, title, category and vault, without the values of its fields, so
+// items can be passed to a logger without leaking secrets.
+func (item Item) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", item.ID),
+		slog.String("title", item.Title),
+		slog.String("category", string(item.Category)),
+	}
+	if vault := item.Vault.Name; vault != "" {
+		attrs = append(attrs, slog.String("vault", vault))
+	} else if item.Vault.ID != "" {
+		attrs = append(attrs, slog.String("vault", item.Vault.ID))
+	}
+	if item.Version != 0 {
+		attrs = append(attrs, slog.Int("version", item.Version))
+	}
+	if item.State != ItemStateActive {
+		attrs = append(attrs, slog.String("state", string(item.State)))
+	}
+	if len(item.Fields) > 0 {
+		attrs = append(attrs, slog.Int("fields", len(item.Fields)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. A field is logged with its ID, label,
+// type and purpose. Its value is never logged; a non-empty value is replaced
+// with a redaction marker.
+func (f Field) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", f.ID),
+		slog.String("label", f.Label),
+		slog.String("type", string(f.Type)),
+	}
+	if f.Purpose != "" {
+		attrs = append(attrs, slog.String("purpose", string(f.Purpose)))
+	}
+	if f.Section != nil && f.Section.ID != "" {
+		attrs = append(attrs, slog.String("section", f.Section.ID))
+	}
+	if f.Value != "" {
+		attrs = append(attrs, slog.String("value", redactedArg))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. An account is logged with its URL,
+// email and IDs, and whether it holds a session, but never with the session
+// token. It has a pointer receiver, because the session state of an account
+// is updated concurrently.
+func (a *Account) LogValue() slog.Value {
+	if a == nil {
+		return slog.StringValue("<nil>")
+	}
+
+	attrs := []slog.Attr{
+		slog.String("url", a.URL),
+		slog.String("email", a.Email),
+		slog.String("user_uuid", a.UserUUID),
+		slog.String("account_uuid", a.AccountUUID),
+		slog.Bool("session", a.hasSession()),
+	}
+	if a.hasSession() {
+		attrs = append(attrs, slog.Bool("session_expired", a.IsSessionExpired()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. A rate limit is logged with its type,
+// action, usage and the time until it resets.
+func (r ServiceAccountRateLimit) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", r.Type),
+		slog.String("action", r.Action),
+		slog.Int("limit", r.Limit),
+		slog.Int("used", r.Used),
+		slog.Int("remaining", r.Remaining),
+		slog.Duration("reset_in", r.ResetIn()),
+	)
+}
@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogValue(t *testing.T) {
+	account := &Account{URL: "my.1password.com", Email: "jane@example.com", UserUUID: "user-uuid"}
+	account.SetSignInInfo("session-token")
+
+	item := Item{
+		ID:       "item-id",
+		Title:    "Database",
+		Category: CategoryLogin,
+		Vault:    Vault{ID: "vault-id", Name: "Infrastructure"},
+		Fields: []Field{
+			{ID: "username", Label: "username", Type: FieldTypeString, Purpose: FieldPurposeUsername, Value: "admin"},
+			{ID: "password", Label: "password", Type: FieldTypeConcealed, Purpose: FieldPurposePassword, Value: "hunter2"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		value     any
+		expected  []string
+		forbidden []string
+	}{
+		{
+			name:      "Item",
+			value:     item,
+			expected:  []string{"id=item-id", "title=Database", `category=Login`, "vault=Infrastructure", "fields=2"},
+			forbidden: []string{"hunter2", "admin"},
+		},
+		{
+			name:      "Item pointer",
+			value:     &item,
+			expected:  []string{"id=item-id"},
+			forbidden: []string{"hunter2"},
+		},
+		{
+			name:      "Field",
+			value:     item.Fields[1],
+			expected:  []string{"label=password", "type=CONCEALED", "value=[redacted]"},
+			forbidden: []string{"hunter2"},
+		},
+		{
+			name:      "Account",
+			value:     account,
+			expected:  []string{"email=jane@example.com", "user_uuid=user-uuid", "session=true", "session_expired=false"},
+			forbidden: []string{"session-token"},
+		},
+		{
+			name:     "Rate limit",
+			value:    ServiceAccountRateLimit{Type: "token", Action: "read", Limit: 1000, Used: 10, Remaining: 990, Reset: 60, retrievedAt: time.Now()},
+			expected: []string{"type=token", "action=read", "limit=1000", "remaining=990"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			slog.New(slog.NewTextHandler(&logs, nil)).Info("test", "value", tt.value)
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(logs.String(), expected) {
+					t.Errorf("log = %q; want it to contain %q", logs.String(), expected)
+				}
+			}
+			for _, forbidden := range tt.forbidden {
+				if strings.Contains(logs.String(), forbidden) {
+					t.Errorf("log = %q; must not contain %q", logs.String(), forbidden)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package onepassword
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsLatencyBuckets are the upper bounds in seconds of the command latency histogram.
+var metricsLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics collects metrics about the commands an OpCLI instance runs and
+// exposes them in the Prometheus text exposition format. It implements
+// http.Handler, so it can be served as a scrape endpoint without depending
+// on a Prometheus client library. To register the metrics with an existing
+// Prometheus registry, use the collector of the optional onepasswordprom
+// module, which reads them with Snapshot.
+//
+// The following metrics are exposed:
+//   - onepassword_cli_commands_total: Commands by subcommand, e.g. "item get".
+//   - onepassword_cli_command_errors_total: Failed commands by subcommand and error class.
+//   - onepassword_cli_dry_run_commands_total: Commands skipped in dry-run mode by subcommand.
+//   - onepassword_cli_command_duration_seconds: A histogram of command latencies by subcommand.
+//   - onepassword_cli_cache_hits_total, onepassword_cli_cache_misses_total and
+//     onepassword_cli_cache_hit_ratio: Cache statistics by entity, see CacheStats.
+//   - onepassword_cli_rate_limit_tokens: The tokens left in the client-side rate limiter, see SetRateLimit.
+//   - onepassword_cli_service_account_rate_limit_remaining: The remaining service account
+//     requests by type and action, as of the last call to GetServiceAccountRateLimits.
+type Metrics struct {
+	cli *OpCLI
+
+	mu         sync.Mutex
+	commands   map[string]*commandMetrics
+	rateLimits []ServiceAccountRateLimit
+}
+
+// commandMetrics contains the metrics of a single subcommand.
+type commandMetrics struct {
+	count   uint64
+	dryRuns uint64
+	errors  map[string]uint64
+	buckets []uint64
+	sum     time.Duration
+}
+
+// NewMetrics creates Metrics for the OpCLI instance and registers an
+// execution hook that records every command.
+//
+// Parameters:
+//   - cli: The OpCLI instance to collect metrics for.
+//
+// Returns:
+//   - *Metrics: The metrics collector.
+//
+// Example usage:
+//
+//	metrics := onepassword.NewMetrics(cli)
+//	http.Handle("/metrics", metrics)
+func NewMetrics(cli *OpCLI) *Metrics {
+	m := &Metrics{
+		cli:      cli,
+		commands: make(map[string]*commandMetrics),
+	}
+	cli.OnAfterExec(m.record)
+	return m
+}
+
+// record is the execution hook that records a command.
+func (m *Metrics) record(ctx context.Context, info CommandInfo, result CommandResult, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.commands[info.Name]
+	if !ok {
+		c = &commandMetrics{
+			errors:  make(map[string]uint64),
+			buckets: make([]uint64, len(metricsLatencyBuckets)),
+		}
+		m.commands[info.Name] = c
+	}
+
+	// Skipped commands did not run, so they have no latency
+	if result.DryRun {
+		c.dryRuns++
+		return
+	}
+
+	c.count++
+	c.sum += result.Duration
+	for i, bound := range metricsLatencyBuckets {
+		if result.Duration.Seconds() <= bound {
+			c.buckets[i]++
+		}
+	}
+
+	if err != nil {
+		c.errors[errorClass(err, result.Stderr)]++
+		return
+	}
+
+	if info.Name == "service-account rate-limit" {
+		var rateLimits []ServiceAccountRateLimit
+		if json.Unmarshal(result.Stdout, &rateLimits) == nil {
+			m.rateLimits = rateLimits
+		}
+	}
+}
+
+// MetricsSnapshot is a point-in-time copy of the metrics, e.g. to export them
+// with a metrics library.
+//
+// Fields:
+//   - Commands: The metrics of every subcommand that was run, sorted by name.
+//   - Cache: The cache statistics by entity, see CacheStats.
+//   - RateLimitTokens: The tokens left in the client-side rate limiter, or nil if no rate limit is set.
+//   - ServiceAccountRateLimits: The service account rate limits of the last call to GetServiceAccountRateLimits.
+type MetricsSnapshot struct {
+	Commands                 []CommandMetrics
+	Cache                    map[CacheEntity]CacheStats
+	RateLimitTokens          *float64
+	ServiceAccountRateLimits []ServiceAccountRateLimit
+}
+
+// CommandMetrics are the metrics of a single subcommand.
+//
+// Fields:
+//   - Command: The subcommand, e.g. "item get".
+//   - Count: The number of commands that were run.
+//   - DryRuns: The number of commands skipped in dry-run mode.
+//   - Errors: The number of failed commands by error class, e.g. "not_found".
+//   - Buckets: The cumulative number of commands by the upper bound of their latency in seconds.
+//   - Sum: The total latency of the commands.
+type CommandMetrics struct {
+	Command string
+	Count   uint64
+	DryRuns uint64
+	Errors  map[string]uint64
+	Buckets map[float64]uint64
+	Sum     time.Duration
+}
+
+// Snapshot returns a copy of the current metrics.
+//
+// Returns:
+//   - MetricsSnapshot: The metrics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{Cache: m.cli.CacheStats()}
+	if limiter := m.cli.rateLimiter; limiter != nil {
+		tokens := limiter.available(time.Now())
+		snapshot.RateLimitTokens = &tokens
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, c := range m.commands {
+		command := CommandMetrics{
+			Command: name,
+			Count:   c.count,
+			DryRuns: c.dryRuns,
+			Errors:  maps.Clone(c.errors),
+			Buckets: make(map[float64]uint64, len(metricsLatencyBuckets)),
+			Sum:     c.sum,
+		}
+		for i, bound := range metricsLatencyBuckets {
+			command.Buckets[bound] = c.buckets[i]
+		}
+		snapshot.Commands = append(snapshot.Commands, command)
+	}
+	slices.SortFunc(snapshot.Commands, func(a, b CommandMetrics) int {
+		return strings.Compare(a.Command, b.Command)
+	})
+	snapshot.ServiceAccountRateLimits = slices.Clone(m.rateLimits)
+
+	return snapshot
+}
+
+// errorClass returns the label used for the error of a failed command.
+func errorClass(err error, stderr []byte) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+
+	kind := classifyCLIError(string(stderr))
+	switch {
+	case kind == nil:
+	case errors.Is(kind, ErrNotFound):
+		return "not_found"
+	case errors.Is(kind, ErrMoreThanOneMatch):
+		return "more_than_one_match"
+	case errors.Is(kind, ErrPermissionDenied):
+		return "permission_denied"
+	case errors.Is(kind, ErrSessionExpired):
+		return "session_expired"
+	case errors.Is(kind, ErrRateLimited):
+		return "rate_limited"
+	}
+
+	return "other"
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := m.WriteTo(w); err != nil {
+		m.cli.log().Debug("failed to write metrics", "error", err)
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+//
+// Parameters:
+//   - w: The writer the metrics are written to.
+//
+// Returns:
+//   - int64: The number of bytes written.
+//   - error: An error if writing fails.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+
+	m.writeCommands(buf)
+	m.writeCache(buf)
+	m.writeRateLimits(buf)
+
+	err := buf.Flush()
+	return counter.n, err
+}
+
+// writeCommands writes the command counters and latency histograms.
+func (m *Metrics) writeCommands(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.commands))
+	for name := range m.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_commands_total Number of 1Password CLI commands run.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_commands_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "onepassword_cli_commands_total{command=%s} %d\n", quoteLabel(name), m.commands[name].count)
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_command_errors_total Number of failed 1Password CLI commands.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_command_errors_total counter")
+	for _, name := range names {
+		c := m.commands[name]
+		classes := make([]string, 0, len(c.errors))
+		for class := range c.errors {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(w, "onepassword_cli_command_errors_total{command=%s,class=%s} %d\n", quoteLabel(name), quoteLabel(class), c.errors[class])
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_dry_run_commands_total Number of 1Password CLI commands skipped in dry-run mode.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_dry_run_commands_total counter")
+	for _, name := range names {
+		if c := m.commands[name]; c.dryRuns > 0 {
+			fmt.Fprintf(w, "onepassword_cli_dry_run_commands_total{command=%s} %d\n", quoteLabel(name), c.dryRuns)
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_command_duration_seconds Latency of 1Password CLI commands.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_command_duration_seconds histogram")
+	for _, name := range names {
+		c := m.commands[name]
+		label := quoteLabel(name)
+		for i, bound := range metricsLatencyBuckets {
+			fmt.Fprintf(w, "onepassword_cli_command_duration_seconds_bucket{command=%s,le=\"%s\"} %d\n", label, formatFloat(bound), c.buckets[i])
+		}
+		fmt.Fprintf(w, "onepassword_cli_command_duration_seconds_bucket{command=%s,le=\"+Inf\"} %d\n", label, c.count)
+		fmt.Fprintf(w, "onepassword_cli_command_duration_seconds_sum{command=%s} %s\n", label, formatFloat(c.sum.Seconds()))
+		fmt.Fprintf(w, "onepassword_cli_command_duration_seconds_count{command=%s} %d\n", label, c.count)
+	}
+}
+
+// writeCache writes the cache statistics of the OpCLI instance.
+func (m *Metrics) writeCache(w io.Writer) {
+	stats := m.cli.CacheStats()
+	entities := make([]CacheEntity, 0, len(stats))
+	for entity := range stats {
+		entities = append(entities, entity)
+	}
+	slices.Sort(entities)
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_cache_hits_total Number of lookups served from the cache.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_cache_hits_total counter")
+	for _, entity := range entities {
+		fmt.Fprintf(w, "onepassword_cli_cache_hits_total{entity=%s} %d\n", quoteLabel(string(entity)), stats[entity].Hits)
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_cache_misses_total Number of lookups not served from the cache.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_cache_misses_total counter")
+	for _, entity := range entities {
+		fmt.Fprintf(w, "onepassword_cli_cache_misses_total{entity=%s} %d\n", quoteLabel(string(entity)), stats[entity].Misses)
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_cache_hit_ratio Share of lookups served from the cache.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_cache_hit_ratio gauge")
+	for _, entity := range entities {
+		fmt.Fprintf(w, "onepassword_cli_cache_hit_ratio{entity=%s} %s\n", quoteLabel(string(entity)), formatFloat(stats[entity].HitRate()))
+	}
+}
+
+// writeRateLimits writes the client-side and service account rate limits.
+func (m *Metrics) writeRateLimits(w io.Writer) {
+	if limiter := m.cli.rateLimiter; limiter != nil {
+		fmt.Fprintln(w, "# HELP onepassword_cli_rate_limit_tokens Tokens left in the client-side rate limiter.")
+		fmt.Fprintln(w, "# TYPE onepassword_cli_rate_limit_tokens gauge")
+		fmt.Fprintf(w, "onepassword_cli_rate_limit_tokens %s\n", formatFloat(limiter.available(time.Now())))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.rateLimits) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "# HELP onepassword_cli_service_account_rate_limit_remaining Remaining service account requests.")
+	fmt.Fprintln(w, "# TYPE onepassword_cli_service_account_rate_limit_remaining gauge")
+	for _, limit := range m.rateLimits {
+		fmt.Fprintf(w, "onepassword_cli_service_account_rate_limit_remaining{type=%s,action=%s} %d\n", quoteLabel(limit.Type), quoteLabel(limit.Action), limit.Remaining)
+	}
+}
+
+// quoteLabel quotes a label value for the Prometheus text exposition format.
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// formatFloat formats a sample value for the Prometheus text exposition format.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,98 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}, isServiceAccount: true}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		switch commandName(cmd.Args) {
+		case "item get":
+			return nil, []byte(`[ERROR] "missing" isn't an item`), errors.New("exit status 1")
+		case "service-account rate-limit":
+			return []byte(`[{"type":"token","action":"read","limit":1000,"used":10,"remaining":990,"reset":60}]`), nil, nil
+		}
+		return []byte(`[]`), nil, nil
+	}))
+	if err := cli.SetRateLimit(10, time.Second, 5); err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+
+	metrics := NewMetrics(cli)
+
+	ctx := context.Background()
+	cli.ExecuteOpCommand(ctx, "vault", "list")
+	cli.ExecuteOpCommand(ctx, "vault", "list")
+	cli.ExecuteOpCommand(ctx, "item", "get", "missing")
+	if _, err := cli.GetServiceAccountRateLimits(ctx); err != nil {
+		t.Fatalf("GetServiceAccountRateLimits() error = %v", err)
+	}
+	cli.SetDryRun(true)
+	cli.ExecuteOpCommand(ctx, "item", "delete", "item-id")
+
+	var out strings.Builder
+	if _, err := metrics.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Command count", expected: `onepassword_cli_commands_total{command="vault list"} 2`},
+		{name: "Error class", expected: `onepassword_cli_command_errors_total{command="item get",class="not_found"} 1`},
+		{name: "Dry run", expected: `onepassword_cli_dry_run_commands_total{command="item delete"} 1`},
+		{name: "Histogram count", expected: `onepassword_cli_command_duration_seconds_count{command="vault list"} 2`},
+		{name: "Histogram infinity bucket", expected: `onepassword_cli_command_duration_seconds_bucket{command="item get",le="+Inf"} 1`},
+		{name: "Rate limiter", expected: `onepassword_cli_rate_limit_tokens `},
+		{name: "Service account rate limit", expected: `onepassword_cli_service_account_rate_limit_remaining{type="token",action="read"} 990`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(out.String(), tt.expected) {
+				t.Errorf("metrics do not contain %q:\n%s", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestMetricsSnapshot(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		if commandName(cmd.Args) == "item get" {
+			return nil, []byte(`[ERROR] "missing" isn't an item`), errors.New("exit status 1")
+		}
+		return []byte(`[]`), nil, nil
+	}))
+	metrics := NewMetrics(cli)
+
+	ctx := context.Background()
+	cli.ExecuteOpCommand(ctx, "vault", "list")
+	cli.ExecuteOpCommand(ctx, "item", "get", "missing")
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot.Commands) != 2 || snapshot.Commands[0].Command != "item get" || snapshot.Commands[1].Command != "vault list" {
+		t.Fatalf("Snapshot().Commands = %+v; want item get and vault list", snapshot.Commands)
+	}
+	if got := snapshot.Commands[0].Errors["not_found"]; got != 1 {
+		t.Errorf("not_found errors = %d; want 1", got)
+	}
+	if got := snapshot.Commands[1].Buckets[30]; got != 1 {
+		t.Errorf("commands within 30s = %d; want 1", got)
+	}
+	if snapshot.RateLimitTokens != nil {
+		t.Errorf("RateLimitTokens = %v; want nil without a rate limit", *snapshot.RateLimitTokens)
+	}
+
+	// The snapshot is a copy
+	snapshot.Commands[0].Errors["not_found"] = 10
+	if got := metrics.Snapshot().Commands[0].Errors["not_found"]; got != 1 {
+		t.Errorf("not_found errors after changing the snapshot = %d; want 1", got)
+	}
+}
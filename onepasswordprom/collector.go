@@ -0,0 +1,103 @@
+// Package onepasswordprom exports the metrics of an OpCLI instance with the
+// Prometheus client library. It is a separate module, so the core package
+// does not depend on the client library.
+package onepasswordprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+// Collector is a prometheus.Collector for onepassword.Metrics. It exposes
+// the same metrics as the text output of onepassword.Metrics.
+type Collector struct {
+	metrics *onepassword.Metrics
+
+	commands           *prometheus.Desc
+	errors             *prometheus.Desc
+	dryRuns            *prometheus.Desc
+	duration           *prometheus.Desc
+	cacheHits          *prometheus.Desc
+	cacheMisses        *prometheus.Desc
+	cacheHitRatio      *prometheus.Desc
+	rateLimitTokens    *prometheus.Desc
+	serviceAccountRate *prometheus.Desc
+}
+
+// NewCollector creates a Collector for the metrics.
+//
+// Parameters:
+//   - metrics: The metrics created with onepassword.NewMetrics.
+//
+// Returns:
+//   - *Collector: The collector.
+//
+// Example usage:
+//
+//	metrics := onepassword.NewMetrics(cli)
+//	prometheus.MustRegister(onepasswordprom.NewCollector(metrics))
+func NewCollector(metrics *onepassword.Metrics) *Collector {
+	return &Collector{
+		metrics: metrics,
+		commands: prometheus.NewDesc("onepassword_cli_commands_total",
+			"Number of 1Password CLI commands run.", []string{"command"}, nil),
+		errors: prometheus.NewDesc("onepassword_cli_command_errors_total",
+			"Number of failed 1Password CLI commands.", []string{"command", "class"}, nil),
+		dryRuns: prometheus.NewDesc("onepassword_cli_dry_run_commands_total",
+			"Number of 1Password CLI commands skipped in dry-run mode.", []string{"command"}, nil),
+		duration: prometheus.NewDesc("onepassword_cli_command_duration_seconds",
+			"Latency of 1Password CLI commands.", []string{"command"}, nil),
+		cacheHits: prometheus.NewDesc("onepassword_cli_cache_hits_total",
+			"Number of lookups served from the cache.", []string{"entity"}, nil),
+		cacheMisses: prometheus.NewDesc("onepassword_cli_cache_misses_total",
+			"Number of lookups not served from the cache.", []string{"entity"}, nil),
+		cacheHitRatio: prometheus.NewDesc("onepassword_cli_cache_hit_ratio",
+			"Share of lookups served from the cache.", []string{"entity"}, nil),
+		rateLimitTokens: prometheus.NewDesc("onepassword_cli_rate_limit_tokens",
+			"Tokens left in the client-side rate limiter.", nil, nil),
+		serviceAccountRate: prometheus.NewDesc("onepassword_cli_service_account_rate_limit_remaining",
+			"Remaining service account requests.", []string{"type", "action"}, nil),
+	}
+}
+
+// Describe sends the descriptors of the metrics.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.commands
+	ch <- c.errors
+	ch <- c.dryRuns
+	ch <- c.duration
+	ch <- c.cacheHits
+	ch <- c.cacheMisses
+	ch <- c.cacheHitRatio
+	ch <- c.rateLimitTokens
+	ch <- c.serviceAccountRate
+}
+
+// Collect sends the current values of the metrics.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.metrics.Snapshot()
+
+	for _, command := range snapshot.Commands {
+		ch <- prometheus.MustNewConstMetric(c.commands, prometheus.CounterValue, float64(command.Count), command.Command)
+		for class, count := range command.Errors {
+			ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(count), command.Command, class)
+		}
+		if command.DryRuns > 0 {
+			ch <- prometheus.MustNewConstMetric(c.dryRuns, prometheus.CounterValue, float64(command.DryRuns), command.Command)
+		}
+		ch <- prometheus.MustNewConstHistogram(c.duration, command.Count, command.Sum.Seconds(), command.Buckets, command.Command)
+	}
+
+	for entity, stats := range snapshot.Cache {
+		ch <- prometheus.MustNewConstMetric(c.cacheHits, prometheus.CounterValue, float64(stats.Hits), string(entity))
+		ch <- prometheus.MustNewConstMetric(c.cacheMisses, prometheus.CounterValue, float64(stats.Misses), string(entity))
+		ch <- prometheus.MustNewConstMetric(c.cacheHitRatio, prometheus.GaugeValue, stats.HitRate(), string(entity))
+	}
+
+	if snapshot.RateLimitTokens != nil {
+		ch <- prometheus.MustNewConstMetric(c.rateLimitTokens, prometheus.GaugeValue, *snapshot.RateLimitTokens)
+	}
+	for _, limit := range snapshot.ServiceAccountRateLimits {
+		ch <- prometheus.MustNewConstMetric(c.serviceAccountRate, prometheus.GaugeValue, float64(limit.Remaining), limit.Type, limit.Action)
+	}
+}
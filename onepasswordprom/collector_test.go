@@ -0,0 +1,63 @@
+package onepasswordprom_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+	"github.com/sthayduk/onepassword-cli-go/onepasswordprom"
+)
+
+func TestCollector(t *testing.T) {
+	cli, err := onepassword.NewOpCLI(
+		onepassword.WithAccount(&onepassword.Account{UserUUID: "user-uuid"}),
+		onepassword.WithLogger(nil),
+		onepassword.WithCommandExecutor(onepassword.CommandExecutorFunc(func(ctx context.Context, cmd *onepassword.Command) ([]byte, []byte, error) {
+			if cmd.Args[0] == "item" {
+				return nil, []byte(`[ERROR] "missing" isn't an item`), errors.New("exit status 1")
+			}
+			return []byte(`[]`), nil, nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	collector := onepasswordprom.NewCollector(onepassword.NewMetrics(cli))
+
+	ctx := context.Background()
+	cli.ExecuteOpCommand(ctx, "vault", "list")
+	cli.ExecuteOpCommand(ctx, "vault", "list")
+	cli.ExecuteOpCommand(ctx, "item", "get", "missing")
+
+	// The pedantic registry checks that collected metrics match their descriptors
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	expected := `
+# HELP onepassword_cli_command_errors_total Number of failed 1Password CLI commands.
+# TYPE onepassword_cli_command_errors_total counter
+onepassword_cli_command_errors_total{class="not_found",command="item get"} 1
+# HELP onepassword_cli_commands_total Number of 1Password CLI commands run.
+# TYPE onepassword_cli_commands_total counter
+onepassword_cli_commands_total{command="item get"} 1
+onepassword_cli_commands_total{command="vault list"} 2
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"onepassword_cli_commands_total", "onepassword_cli_command_errors_total")
+	if err != nil {
+		t.Errorf("GatherAndCompare() error = %v", err)
+	}
+
+	if got := testutil.CollectAndCount(collector, "onepassword_cli_command_duration_seconds"); got != 2 {
+		t.Errorf("duration histograms = %d; want 2", got)
+	}
+	if got := testutil.CollectAndCount(collector, "onepassword_cli_rate_limit_tokens"); got != 0 {
+		t.Errorf("rate limit token gauges = %d; want 0 without a rate limit", got)
+	}
+}
@@ -0,0 +1,899 @@
+package onepasswordtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+// booleanFlags are the flags of the 1Password CLI that do not take a value.
+var booleanFlags = []string{"all", "archive", "dry-run", "favorite", "force", "generate-password", "include-archive", "iso-timestamps", "me", "no-color", "reveal"}
+
+// commandArgs are the parsed arguments of a command.
+type commandArgs struct {
+	raw        []string
+	positional []string
+	flags      map[string]string
+}
+
+// parseArgs splits the arguments of a command into positional arguments and
+// flags. The account, format, session and output flags are removed.
+func parseArgs(args []string) commandArgs {
+	parsed := commandArgs{flags: make(map[string]string)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			parsed.positional = append(parsed.positional, arg)
+			parsed.raw = append(parsed.raw, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		raw := []string{arg}
+		if !hasValue {
+			if slices.Contains(booleanFlags, name) {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+				raw = append(raw, value)
+			}
+		}
+
+		switch name {
+		case "account", "format", "session", "iso-timestamps", "no-color":
+			continue
+		}
+		parsed.flags[name] = value
+		parsed.raw = append(parsed.raw, raw...)
+	}
+
+	return parsed
+}
+
+// arg returns the positional argument at index i or an empty string.
+func (a commandArgs) arg(i int) string {
+	if i < len(a.positional) {
+		return a.positional[i]
+	}
+	return ""
+}
+
+// has reports whether a flag is set.
+func (a commandArgs) has(name string) bool {
+	_, ok := a.flags[name]
+	return ok
+}
+
+// dispatch runs the command and returns the value written as JSON output.
+func (f *Fake) dispatch(args commandArgs, stdin []byte) (any, error) {
+	command := strings.Join(args.positional[:min(2, len(args.positional))], " ")
+	switch command {
+	case "whoami":
+		return f.whoami(), nil
+	case "account list":
+		return []any{f.whoami()}, nil
+	case "account get":
+		return f.accountDetails(), nil
+	case "vault list":
+		return f.listVaults(args)
+	case "vault get":
+		return f.findVault(args.arg(2))
+	case "vault create":
+		return f.createVault(args.arg(2), args.flags["description"]), nil
+	case "vault edit":
+		return nil, f.editVault(args)
+	case "vault delete":
+		return nil, f.deleteVault(args.arg(2))
+	case "vault user", "vault group":
+		return f.vaultPermissions(args)
+	case "item list":
+		return f.listItems(args)
+	case "item get":
+		return f.findItem(args.arg(2), args.flags["vault"])
+	case "item create":
+		return f.createItemFromTemplate(args, stdin)
+	case "item edit":
+		return f.editItem(args, stdin)
+	case "item delete":
+		return nil, f.deleteItem(args.arg(2), args.flags["vault"], args.has("archive"))
+	case "item template":
+		return f.itemTemplates(args)
+	case "user list":
+		return f.listUsers(args)
+	case "user get":
+		if args.has("me") {
+			return f.findUser(f.me)
+		}
+		return f.findUser(args.arg(2))
+	case "user provision":
+		return f.createUser(args.flags["name"], args.flags["email"], onepassword.UserStatePending), nil
+	case "user confirm":
+		return nil, f.setUserState(args, onepassword.UserStateActive)
+	case "user suspend":
+		return nil, f.setUserState(args, onepassword.UserStateSuspended)
+	case "user reactivate":
+		return nil, f.setUserState(args, onepassword.UserStateActive)
+	case "user edit":
+		return nil, f.editUser(args)
+	case "user delete":
+		return nil, f.deleteUser(args.arg(2))
+	case "group list":
+		return f.listGroups(args)
+	case "group get":
+		return f.findGroup(args.arg(2))
+	case "group create":
+		return f.createGroup(args.arg(2), args.flags["description"]), nil
+	case "group edit":
+		return nil, f.editGroup(args)
+	case "group delete":
+		return nil, f.deleteGroup(args.arg(2))
+	case "group user":
+		return f.groupMembership(args)
+	}
+
+	return nil, fmt.Errorf("unknown command %q for the fake 1Password CLI", strings.Join(args.positional, " "))
+}
+
+// whoami returns the output of "op whoami".
+func (f *Fake) whoami() map[string]string {
+	return map[string]string{
+		"url":          f.account.URL,
+		"email":        f.account.Email,
+		"user_uuid":    f.account.UserUUID,
+		"account_uuid": f.account.AccountUUID,
+		"user_type":    "HUMAN",
+	}
+}
+
+// accountDetails returns the output of "op account get".
+func (f *Fake) accountDetails() map[string]string {
+	return map[string]string{
+		"id":     f.account.AccountUUID,
+		"name":   "Fake",
+		"domain": strings.TrimSuffix(f.account.URL, ".1password.com"),
+		"type":   string(f.accountType),
+		"state":  "ACTIVE",
+	}
+}
+
+// notFound returns the error of the CLI for an unknown entity.
+func notFound(kind, identifier string) error {
+	article := "a"
+	if kind == "item" {
+		article = "an"
+	}
+	return fmt.Errorf("%q isn't %s %s in this account. Specify the %s with its ID or name", identifier, article, kind, kind)
+}
+
+// moreThanOne returns the error of the CLI for an ambiguous identifier.
+func moreThanOne(kind, identifier string) error {
+	return fmt.Errorf("More than one %s matches %q. Try again and specify the %s by its ID", kind, identifier, kind)
+}
+
+// find returns the single element that matches the identifier.
+func find[T any](elements []*T, kind, identifier string, matches func(*T) bool) (*T, error) {
+	var found *T
+	for _, element := range elements {
+		if !matches(element) {
+			continue
+		}
+		if found != nil {
+			return nil, moreThanOne(kind, identifier)
+		}
+		found = element
+	}
+	if found == nil {
+		return nil, notFound(kind, identifier)
+	}
+	return found, nil
+}
+
+// findVault returns the vault with the given ID or name.
+func (f *Fake) findVault(identifier string) (*onepassword.Vault, error) {
+	vault, err := find(f.vaults, "vault", identifier, func(v *onepassword.Vault) bool {
+		return v.ID == identifier || v.Name == identifier
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := *vault
+	result.Items = f.countItems(vault.ID)
+	return &result, nil
+}
+
+// countItems returns the number of items in a vault.
+func (f *Fake) countItems(vaultID string) int {
+	count := 0
+	for _, item := range f.items {
+		if item.Vault.ID == vaultID {
+			count++
+		}
+	}
+	return count
+}
+
+// createVault adds a vault.
+func (f *Fake) createVault(name, description string) *onepassword.Vault {
+	timestamp := now().Format("2006-01-02T15:04:05Z")
+	vault := &onepassword.Vault{
+		ID:               f.newID(),
+		Name:             name,
+		Description:      description,
+		ContentVersion:   1,
+		AttributeVersion: 1,
+		CreatedAt:        timestamp,
+		UpdatedAt:        timestamp,
+		Type:             "USER_CREATED",
+	}
+	f.vaults = append(f.vaults, vault)
+	grant(f.vaultUserGrants, vault.ID, f.me, []onepassword.Permission{onepassword.PermissionManageVault})
+	return vault
+}
+
+// listVaults returns all vaults, or the vaults of a user or group with their permissions.
+func (f *Fake) listVaults(args commandArgs) (any, error) {
+	var grants map[string]map[string][]onepassword.Permission
+	var principal string
+	switch {
+	case args.has("user"):
+		user, err := f.findUser(args.flags["user"])
+		if err != nil {
+			return nil, err
+		}
+		grants, principal = f.vaultUserGrants, user.ID
+	case args.has("group"):
+		group, err := f.findGroup(args.flags["group"])
+		if err != nil {
+			return nil, err
+		}
+		grants, principal = f.vaultGroupGrants, group.ID
+	}
+
+	vaults := []onepassword.Vault{}
+	for _, vault := range f.vaults {
+		result := *vault
+		result.Items = f.countItems(vault.ID)
+		if grants != nil {
+			permissions, ok := grants[vault.ID][principal]
+			if !ok {
+				continue
+			}
+			result.Permissions = slices.Clone(permissions)
+		}
+		vaults = append(vaults, result)
+	}
+	return vaults, nil
+}
+
+// editVault changes the name or description of a vault.
+func (f *Fake) editVault(args commandArgs) error {
+	vault, err := find(f.vaults, "vault", args.arg(2), func(v *onepassword.Vault) bool {
+		return v.ID == args.arg(2) || v.Name == args.arg(2)
+	})
+	if err != nil {
+		return err
+	}
+
+	if name, ok := args.flags["name"]; ok {
+		vault.Name = name
+	}
+	if description, ok := args.flags["description"]; ok {
+		vault.Description = description
+	}
+	vault.AttributeVersion++
+	vault.UpdatedAt = now().Format("2006-01-02T15:04:05Z")
+	return nil
+}
+
+// deleteVault deletes a vault and its items.
+func (f *Fake) deleteVault(identifier string) error {
+	vault, err := f.findVault(identifier)
+	if err != nil {
+		return err
+	}
+
+	f.vaults = slices.DeleteFunc(f.vaults, func(v *onepassword.Vault) bool { return v.ID == vault.ID })
+	f.items = slices.DeleteFunc(f.items, func(i *onepassword.Item) bool { return i.Vault.ID == vault.ID })
+	delete(f.vaultUserGrants, vault.ID)
+	delete(f.vaultGroupGrants, vault.ID)
+	return nil
+}
+
+// vaultPermissions runs "vault user" and "vault group" subcommands.
+func (f *Fake) vaultPermissions(args commandArgs) (any, error) {
+	kind := args.arg(1)
+	action := args.arg(2)
+
+	if action == "list" {
+		vault, err := f.findVault(args.arg(3))
+		if err != nil {
+			return nil, err
+		}
+		if kind == "user" {
+			users := []onepassword.User{}
+			for _, user := range f.users {
+				if permissions, ok := f.vaultUserGrants[vault.ID][user.ID]; ok {
+					result := *user
+					result.Permissions = slices.Clone(permissions)
+					users = append(users, result)
+				}
+			}
+			return users, nil
+		}
+		groups := []onepassword.Group{}
+		for _, group := range f.groups {
+			if permissions, ok := f.vaultGroupGrants[vault.ID][group.ID]; ok {
+				result := *group
+				result.Permissions = slices.Clone(permissions)
+				groups = append(groups, result)
+			}
+		}
+		return groups, nil
+	}
+
+	vault, err := f.findVault(args.flags["vault"])
+	if err != nil {
+		return nil, err
+	}
+
+	grants := f.vaultUserGrants
+	principalID := ""
+	if kind == "user" {
+		user, err := f.findUser(args.flags["user"])
+		if err != nil {
+			return nil, err
+		}
+		principalID = user.ID
+	} else {
+		group, err := f.findGroup(args.flags["group"])
+		if err != nil {
+			return nil, err
+		}
+		grants, principalID = f.vaultGroupGrants, group.ID
+	}
+
+	// The CLI rejects unknown permissions
+	permissions, err := onepassword.ParsePermissions(args.flags["permissions"])
+	if err != nil {
+		return nil, err
+	}
+	switch action {
+	case "grant":
+		grant(grants, vault.ID, principalID, permissions)
+	case "revoke":
+		revoke(grants, vault.ID, principalID, permissions)
+	default:
+		return nil, fmt.Errorf("unknown command \"vault %s %s\"", kind, action)
+	}
+	return nil, nil
+}
+
+// findItem returns the item with the given ID or title, optionally in a vault.
+func (f *Fake) findItem(identifier, vaultIdentifier string) (*onepassword.Item, error) {
+	vaultID := ""
+	if vaultIdentifier != "" {
+		vault, err := f.findVault(vaultIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		vaultID = vault.ID
+	}
+
+	return find(f.items, "item", identifier, func(i *onepassword.Item) bool {
+		return (i.ID == identifier || i.Title == identifier) && (vaultID == "" || i.Vault.ID == vaultID)
+	})
+}
+
+// listItems returns the items without their fields and sections.
+func (f *Fake) listItems(args commandArgs) (any, error) {
+	vaultID := ""
+	if identifier, ok := args.flags["vault"]; ok {
+		vault, err := f.findVault(identifier)
+		if err != nil {
+			return nil, err
+		}
+		vaultID = vault.ID
+	}
+
+	var categories, tags []string
+	if value, ok := args.flags["categories"]; ok {
+		categories = strings.Split(strings.ToLower(value), ",")
+	}
+	if value, ok := args.flags["tags"]; ok {
+		tags = strings.Split(value, ",")
+	}
+
+	items := []onepassword.Item{}
+	for _, item := range f.items {
+		if vaultID != "" && item.Vault.ID != vaultID {
+			continue
+		}
+		if item.State == onepassword.ItemStateArchived && !args.has("include-archive") {
+			continue
+		}
+		if categories != nil && !slices.Contains(categories, strings.ToLower(string(item.Category))) {
+			continue
+		}
+		if tags != nil && !slices.ContainsFunc(item.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+			continue
+		}
+
+		result := *item
+		result.Fields = nil
+		result.Sections = nil
+		items = append(items, result)
+	}
+	return items, nil
+}
+
+// createItem adds an item.
+func (f *Fake) createItem(item onepassword.Item, generatePassword bool) (*onepassword.Item, error) {
+	created, err := f.newItem(item, generatePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	f.items = append(f.items, created)
+	result := *created
+	return &result, nil
+}
+
+// newItem returns the item that "item create" creates, without adding it.
+func (f *Fake) newItem(item onepassword.Item, generatePassword bool) (*onepassword.Item, error) {
+	identifier := item.Vault.ID
+	if identifier == "" {
+		identifier = item.Vault.Name
+	}
+	vault, err := f.findVault(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	created := item
+	created.ID = f.newID()
+	created.Vault = onepassword.Vault{ID: vault.ID, Name: vault.Name}
+	created.Version = 1
+	created.CreatedAt = now()
+	created.UpdatedAt = created.CreatedAt
+	created.LastEditedBy = f.me
+	created.Fields = slices.Clone(item.Fields)
+	created.Tags = slices.Clone(item.Tags)
+
+	if generatePassword {
+		f.generatePassword(&created)
+	}
+	f.prepareFields(&created)
+
+	return &created, nil
+}
+
+// generatePassword sets the password field of an item to a generated value.
+func (f *Fake) generatePassword(item *onepassword.Item) {
+	password := "generated-" + f.newID()
+	for i := range item.Fields {
+		if item.Fields[i].Purpose == onepassword.FieldPurposePassword {
+			item.Fields[i].Value = password
+			return
+		}
+	}
+	item.Fields = append(item.Fields, onepassword.Field{
+		ID:      "password",
+		Label:   "password",
+		Type:    onepassword.FieldTypeConcealed,
+		Purpose: onepassword.FieldPurposePassword,
+		Value:   password,
+	})
+}
+
+// prepareFields assigns missing field IDs and sets the secret references of the fields.
+func (f *Fake) prepareFields(item *onepassword.Item) {
+	for i := range item.Fields {
+		field := &item.Fields[i]
+		if field.ID == "" {
+			field.ID = f.newID()
+		}
+		label := field.Label
+		if label == "" {
+			label = field.ID
+		}
+		if field.Section != nil && field.Section.ID != "" {
+			field.Reference = fmt.Sprintf("op://%s/%s/%s/%s", item.Vault.Name, item.Title, field.Section.ID, label)
+		} else {
+			field.Reference = fmt.Sprintf("op://%s/%s/%s", item.Vault.Name, item.Title, label)
+		}
+	}
+}
+
+// createItemFromTemplate runs "item create" with a JSON template on standard input.
+func (f *Fake) createItemFromTemplate(args commandArgs, stdin []byte) (any, error) {
+	var item onepassword.Item
+	if len(stdin) > 0 {
+		if err := json.Unmarshal(stdin, &item); err != nil {
+			return nil, fmt.Errorf("invalid JSON in piped input: %v", err)
+		}
+	}
+	if title, ok := args.flags["title"]; ok {
+		item.Title = title
+	}
+	if category, ok := args.flags["category"]; ok {
+		item.Category = onepassword.Category(category)
+	}
+	if vault, ok := args.flags["vault"]; ok {
+		item.Vault = onepassword.Vault{ID: vault, Name: vault}
+	}
+
+	if args.has("dry-run") {
+		preview, err := f.newItem(item, args.has("generate-password"))
+		if err != nil {
+			return nil, err
+		}
+		preview.ID = ""
+		return preview, nil
+	}
+	return f.createItem(item, args.has("generate-password"))
+}
+
+// editItem runs "item edit" with a JSON template on standard input.
+func (f *Fake) editItem(args commandArgs, stdin []byte) (any, error) {
+	item, err := f.findItem(args.arg(2), args.flags["vault"])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stdin) > 0 {
+		var updated onepassword.Item
+		if err := json.Unmarshal(stdin, &updated); err != nil {
+			return nil, fmt.Errorf("invalid JSON in piped input: %v", err)
+		}
+		updated.ID = item.ID
+		updated.Vault = item.Vault
+		updated.CreatedAt = item.CreatedAt
+		updated.Version = item.Version
+		*item = updated
+	}
+	if title, ok := args.flags["title"]; ok {
+		item.Title = title
+	}
+	if tags, ok := args.flags["tags"]; ok {
+		item.Tags = strings.Split(tags, ",")
+	}
+	if args.has("generate-password") {
+		f.generatePassword(item)
+	}
+
+	item.Version++
+	item.UpdatedAt = now()
+	item.LastEditedBy = f.me
+	f.prepareFields(item)
+
+	result := *item
+	return &result, nil
+}
+
+// deleteItem deletes an item, or moves it to the archive.
+func (f *Fake) deleteItem(identifier, vaultIdentifier string, archive bool) error {
+	item, err := f.findItem(identifier, vaultIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if archive {
+		item.State = onepassword.ItemStateArchived
+		return nil
+	}
+
+	f.items = slices.DeleteFunc(f.items, func(i *onepassword.Item) bool { return i.ID == item.ID })
+	return nil
+}
+
+// itemTemplates runs "item template list" and "item template get".
+func (f *Fake) itemTemplates(args commandArgs) (any, error) {
+	switch args.arg(2) {
+	case "list":
+		templates := []onepassword.ItemTemplate{}
+		for _, template := range itemTemplates {
+			templates = append(templates, onepassword.ItemTemplate{UUID: template.uuid, Name: string(template.category)})
+		}
+		return templates, nil
+	case "get":
+		for _, template := range itemTemplates {
+			if strings.EqualFold(string(template.category), args.arg(3)) || template.uuid == args.arg(3) {
+				return onepassword.Item{Category: template.category, Fields: slices.Clone(template.fields)}, nil
+			}
+		}
+		return nil, fmt.Errorf("%q isn't a template", args.arg(3))
+	}
+	return nil, fmt.Errorf("unknown command \"item template %s\"", args.arg(2))
+}
+
+// itemTemplates are the templates returned by "item template".
+var itemTemplates = []struct {
+	uuid     string
+	category onepassword.Category
+	fields   []onepassword.Field
+}{
+	{"001", onepassword.CategoryLogin, []onepassword.Field{
+		{ID: "username", Label: "username", Type: onepassword.FieldTypeString, Purpose: onepassword.FieldPurposeUsername},
+		{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword},
+		{ID: "notesPlain", Label: "notesPlain", Type: onepassword.FieldTypeString, Purpose: onepassword.FieldPurposeNotes},
+	}},
+	{"003", onepassword.CategorySecureNote, []onepassword.Field{
+		{ID: "notesPlain", Label: "notesPlain", Type: onepassword.FieldTypeString, Purpose: onepassword.FieldPurposeNotes},
+	}},
+	{"005", onepassword.CategoryPassword, []onepassword.Field{
+		{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword},
+		{ID: "notesPlain", Label: "notesPlain", Type: onepassword.FieldTypeString, Purpose: onepassword.FieldPurposeNotes},
+	}},
+}
+
+// findUser returns the user with the given ID, email or name.
+func (f *Fake) findUser(identifier string) (*onepassword.User, error) {
+	user, err := find(f.users, "user", identifier, func(u *onepassword.User) bool {
+		return u.ID == identifier || strings.EqualFold(u.Email, identifier) || u.Name == identifier
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := *user
+	return &result, nil
+}
+
+// createUser adds a user.
+func (f *Fake) createUser(name, email string, state onepassword.UserState) *onepassword.User {
+	user := &onepassword.User{
+		ID:        f.newID(),
+		Name:      name,
+		Email:     email,
+		Type:      onepassword.UserTypeMember,
+		State:     state,
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	f.users = append(f.users, user)
+
+	result := *user
+	return &result
+}
+
+// listUsers returns all users, or the members of a group or vault.
+func (f *Fake) listUsers(args commandArgs) (any, error) {
+	var groupID, vaultID string
+	if identifier, ok := args.flags["group"]; ok {
+		group, err := f.findGroup(identifier)
+		if err != nil {
+			return nil, err
+		}
+		groupID = group.ID
+	}
+	if identifier, ok := args.flags["vault"]; ok {
+		vault, err := f.findVault(identifier)
+		if err != nil {
+			return nil, err
+		}
+		vaultID = vault.ID
+	}
+
+	users := []onepassword.User{}
+	for _, user := range f.users {
+		if groupID != "" {
+			if _, ok := f.groupMembers[groupID][user.ID]; !ok {
+				continue
+			}
+		}
+		if vaultID != "" {
+			if _, ok := f.vaultUserGrants[vaultID][user.ID]; !ok {
+				continue
+			}
+		}
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+// userByIdentifier returns the stored user with the given ID, email or name.
+func (f *Fake) userByIdentifier(identifier string) (*onepassword.User, error) {
+	return find(f.users, "user", identifier, func(u *onepassword.User) bool {
+		return u.ID == identifier || strings.EqualFold(u.Email, identifier) || u.Name == identifier
+	})
+}
+
+// setUserState runs "user confirm", "user suspend" and "user reactivate".
+func (f *Fake) setUserState(args commandArgs, state onepassword.UserState) error {
+	if args.has("all") {
+		for _, user := range f.users {
+			if user.State == onepassword.UserStatePending {
+				user.State = state
+				user.UpdatedAt = now()
+			}
+		}
+		return nil
+	}
+
+	user, err := f.userByIdentifier(args.arg(2))
+	if err != nil {
+		return err
+	}
+	user.State = state
+	user.UpdatedAt = now()
+	return nil
+}
+
+// editUser runs "user edit".
+func (f *Fake) editUser(args commandArgs) error {
+	user, err := f.userByIdentifier(args.arg(2))
+	if err != nil {
+		return err
+	}
+
+	if name, ok := args.flags["name"]; ok {
+		user.Name = name
+	}
+	user.UpdatedAt = now()
+	return nil
+}
+
+// deleteUser deletes a user and removes it from all groups and vaults.
+func (f *Fake) deleteUser(identifier string) error {
+	user, err := f.userByIdentifier(identifier)
+	if err != nil {
+		return err
+	}
+
+	f.users = slices.DeleteFunc(f.users, func(u *onepassword.User) bool { return u.ID == user.ID })
+	for _, members := range f.groupMembers {
+		delete(members, user.ID)
+	}
+	for _, grants := range f.vaultUserGrants {
+		delete(grants, user.ID)
+	}
+	return nil
+}
+
+// findGroup returns the group with the given ID or name.
+func (f *Fake) findGroup(identifier string) (*onepassword.Group, error) {
+	group, err := find(f.groups, "group", identifier, func(g *onepassword.Group) bool {
+		return g.ID == identifier || g.Name == identifier
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := *group
+	return &result, nil
+}
+
+// createGroup adds a user defined group.
+func (f *Fake) createGroup(name, description string) *onepassword.Group {
+	group := &onepassword.Group{
+		ID:          f.newID(),
+		Name:        name,
+		Description: description,
+		State:       "ACTIVE",
+		Type:        onepassword.GroupTypeUserDefined,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	}
+	f.groups = append(f.groups, group)
+
+	result := *group
+	return &result
+}
+
+// listGroups returns all groups, or the groups of a user or vault.
+func (f *Fake) listGroups(args commandArgs) (any, error) {
+	var userID, vaultID string
+	if identifier, ok := args.flags["user"]; ok {
+		user, err := f.findUser(identifier)
+		if err != nil {
+			return nil, err
+		}
+		userID = user.ID
+	}
+	if identifier, ok := args.flags["vault"]; ok {
+		vault, err := f.findVault(identifier)
+		if err != nil {
+			return nil, err
+		}
+		vaultID = vault.ID
+	}
+
+	groups := []onepassword.Group{}
+	for _, group := range f.groups {
+		if userID != "" {
+			if _, ok := f.groupMembers[group.ID][userID]; !ok {
+				continue
+			}
+		}
+		if vaultID != "" {
+			if _, ok := f.vaultGroupGrants[vaultID][group.ID]; !ok {
+				continue
+			}
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
+}
+
+// editGroup runs "group edit".
+func (f *Fake) editGroup(args commandArgs) error {
+	group, err := find(f.groups, "group", args.arg(2), func(g *onepassword.Group) bool {
+		return g.ID == args.arg(2) || g.Name == args.arg(2)
+	})
+	if err != nil {
+		return err
+	}
+
+	if name, ok := args.flags["name"]; ok {
+		group.Name = name
+	}
+	if description, ok := args.flags["description"]; ok {
+		group.Description = description
+	}
+	group.UpdatedAt = now()
+	return nil
+}
+
+// deleteGroup deletes a group and its memberships and grants.
+func (f *Fake) deleteGroup(identifier string) error {
+	group, err := f.findGroup(identifier)
+	if err != nil {
+		return err
+	}
+
+	f.groups = slices.DeleteFunc(f.groups, func(g *onepassword.Group) bool { return g.ID == group.ID })
+	delete(f.groupMembers, group.ID)
+	for _, grants := range f.vaultGroupGrants {
+		delete(grants, group.ID)
+	}
+	return nil
+}
+
+// groupMembership runs "group user list", "group user grant" and "group user revoke".
+func (f *Fake) groupMembership(args commandArgs) (any, error) {
+	switch args.arg(2) {
+	case "list":
+		group, err := f.findGroup(args.arg(3))
+		if err != nil {
+			return nil, err
+		}
+		members := []onepassword.GroupMember{}
+		for _, user := range f.users {
+			if role, ok := f.groupMembers[group.ID][user.ID]; ok {
+				members = append(members, onepassword.GroupMember{User: *user, Role: role})
+			}
+		}
+		return members, nil
+	case "grant", "revoke":
+		group, err := f.findGroup(args.flags["group"])
+		if err != nil {
+			return nil, err
+		}
+		user, err := f.findUser(args.flags["user"])
+		if err != nil {
+			return nil, err
+		}
+
+		if args.arg(2) == "revoke" {
+			delete(f.groupMembers[group.ID], user.ID)
+			return nil, nil
+		}
+
+		role := onepassword.GroupRoleMember
+		if strings.EqualFold(args.flags["role"], string(onepassword.GroupRoleManager)) {
+			role = onepassword.GroupRoleManager
+		}
+		if f.groupMembers[group.ID] == nil {
+			f.groupMembers[group.ID] = make(map[string]onepassword.GroupRole)
+		}
+		f.groupMembers[group.ID][user.ID] = role
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown command \"group user %s\"", args.arg(2))
+}
@@ -0,0 +1,342 @@
+// Package onepasswordtest provides an in-memory fake of the 1Password CLI for
+// testing code that uses the onepassword package without the op executable,
+// an account or credentials.
+//
+// A Fake implements onepassword.CommandExecutor. It keeps vaults, items,
+// users and groups in memory and answers commands with JSON in the format of
+// the 1Password CLI:
+//
+//	fake := onepasswordtest.New()
+//	vault := fake.AddVault("Private")
+//	fake.AddItem(onepassword.Item{Title: "Database", Category: onepassword.CategoryLogin, Vault: vault})
+//
+//	cli, err := fake.NewOpCLI()
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	item, err := cli.GetItemByName(ctx, "Database")
+package onepasswordtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+var _ onepassword.CommandExecutor = (*Fake)(nil)
+
+// Fake is an in-memory 1Password account that implements
+// onepassword.CommandExecutor. It is safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+
+	account     onepassword.Account
+	accountType onepassword.AccountType
+	me          string
+	nextID      int
+
+	vaults []*onepassword.Vault
+	items  []*onepassword.Item
+	users  []*onepassword.User
+	groups []*onepassword.Group
+
+	groupMembers     map[string]map[string]onepassword.GroupRole
+	vaultUserGrants  map[string]map[string][]onepassword.Permission
+	vaultGroupGrants map[string]map[string][]onepassword.Permission
+
+	commands [][]string
+}
+
+// New creates an empty fake account with a signed-in owner. The owner is
+// returned by "op user get --me" and is the account of OpCLI instances
+// created with NewOpCLI.
+//
+// Returns:
+//   - *Fake: The fake account.
+func New() *Fake {
+	f := &Fake{
+		groupMembers:     make(map[string]map[string]onepassword.GroupRole),
+		vaultUserGrants:  make(map[string]map[string][]onepassword.Permission),
+		vaultGroupGrants: make(map[string]map[string][]onepassword.Permission),
+	}
+
+	me := f.AddUser("Test Owner", "owner@example.com")
+	f.me = me.ID
+	f.account = onepassword.Account{
+		URL:         "example.1password.com",
+		Email:       me.Email,
+		UserUUID:    me.ID,
+		AccountUUID: f.newID(),
+	}
+	f.accountType = onepassword.AccountTypeBusiness
+
+	return f
+}
+
+// NewOpCLI creates an OpCLI instance that runs all commands against the fake
+// and is signed in as the owner of the fake account.
+//
+// Parameters:
+//   - opts: Additional options for onepassword.NewOpCLI.
+//
+// Returns:
+//   - *onepassword.OpCLI: The OpCLI instance.
+//   - error: An error if an option is invalid.
+func (f *Fake) NewOpCLI(opts ...onepassword.Option) (*onepassword.OpCLI, error) {
+	account := f.Account()
+	return onepassword.NewOpCLI(append([]onepassword.Option{
+		onepassword.WithCommandExecutor(f),
+		onepassword.WithAccount(&account),
+	}, opts...)...)
+}
+
+// Account returns the account of the signed-in owner.
+func (f *Fake) Account() onepassword.Account {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return onepassword.Account{
+		URL:         f.account.URL,
+		Email:       f.account.Email,
+		UserUUID:    f.account.UserUUID,
+		AccountUUID: f.account.AccountUUID,
+	}
+}
+
+// SetAccountType sets the plan reported by "op account get". New fakes are
+// Business accounts.
+//
+// Parameters:
+//   - accountType: The type of the fake account.
+func (f *Fake) SetAccountType(accountType onepassword.AccountType) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.accountType = accountType
+}
+
+// Commands returns the arguments of all commands run against the fake,
+// without the account and format flags.
+func (f *Fake) Commands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	commands := make([][]string, len(f.commands))
+	for i, command := range f.commands {
+		commands[i] = slices.Clone(command)
+	}
+	return commands
+}
+
+// AddVault adds a vault to the fake account.
+//
+// Parameters:
+//   - name: The name of the vault.
+//
+// Returns:
+//   - onepassword.Vault: The created vault.
+func (f *Fake) AddVault(name string) onepassword.Vault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return *f.createVault(name, "")
+}
+
+// AddItem adds an item to the fake account. The vault of the item is
+// referenced by item.Vault.ID or item.Vault.Name. IDs of the item and its
+// fields are generated if they are empty. Items with ItemStateArchived are
+// only listed with --include-archive.
+//
+// Parameters:
+//   - item: The item to add.
+//
+// Returns:
+//   - onepassword.Item: The created item.
+//   - error: An error if the vault of the item does not exist.
+func (f *Fake) AddItem(item onepassword.Item) (onepassword.Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	created, err := f.createItem(item, false)
+	if err != nil {
+		return onepassword.Item{}, err
+	}
+	return *created, nil
+}
+
+// AddUser adds an active member to the fake account.
+//
+// Parameters:
+//   - name: The name of the user.
+//   - email: The email address of the user.
+//
+// Returns:
+//   - onepassword.User: The created user.
+func (f *Fake) AddUser(name, email string) onepassword.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return *f.createUser(name, email, onepassword.UserStateActive)
+}
+
+// AddGroup adds a user defined group to the fake account.
+//
+// Parameters:
+//   - name: The name of the group.
+//
+// Returns:
+//   - onepassword.Group: The created group.
+func (f *Fake) AddGroup(name string) onepassword.Group {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return *f.createGroup(name, "")
+}
+
+// AddGroupMember adds a user to a group with the given role.
+//
+// Parameters:
+//   - groupID: The ID of the group.
+//   - userID: The ID of the user.
+//   - role: The role of the user in the group.
+func (f *Fake) AddGroupMember(groupID, userID string, role onepassword.GroupRole) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.groupMembers[groupID] == nil {
+		f.groupMembers[groupID] = make(map[string]onepassword.GroupRole)
+	}
+	f.groupMembers[groupID][userID] = role
+}
+
+// GrantUser grants a user permissions on a vault.
+//
+// Parameters:
+//   - vaultID: The ID of the vault.
+//   - userID: The ID of the user.
+//   - permissions: The permissions to grant.
+func (f *Fake) GrantUser(vaultID, userID string, permissions ...onepassword.Permission) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	grant(f.vaultUserGrants, vaultID, userID, permissions)
+}
+
+// GrantGroup grants a group permissions on a vault.
+//
+// Parameters:
+//   - vaultID: The ID of the vault.
+//   - groupID: The ID of the group.
+//   - permissions: The permissions to grant.
+func (f *Fake) GrantGroup(vaultID, groupID string, permissions ...onepassword.Permission) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	grant(f.vaultGroupGrants, vaultID, groupID, permissions)
+}
+
+// Execute runs a command against the in-memory account. Failures are
+// reported with the stderr output of the 1Password CLI, so errors returned
+// by the onepassword package match e.g. onepassword.ErrNotFound.
+func (f *Fake) Execute(ctx context.Context, cmd *onepassword.Command) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var stdin []byte
+	if cmd.Stdin != nil {
+		var err error
+		if stdin, err = io.ReadAll(cmd.Stdin); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	args := parseArgs(cmd.Args)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.commands = append(f.commands, args.raw)
+
+	result, err := f.dispatch(args, stdin)
+	if err != nil {
+		stderr := fmt.Sprintf("[ERROR] %s %s\n", time.Now().Format("2006/01/02 15:04:05"), err)
+		return nil, []byte(stderr), &exitError{code: 1}
+	}
+	if result == nil {
+		return nil, nil, nil
+	}
+
+	output, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, nil, nil
+}
+
+// exitError is the error of a failed command.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// ExitCode returns the exit code of the command.
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// newID returns a new 26 character ID in the format of the 1Password CLI.
+// The caller must hold f.mu unless the Fake is not shared yet.
+func (f *Fake) newID() string {
+	f.nextID++
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	id := []byte(fmt.Sprintf("%026d", f.nextID))
+	for i := range 6 {
+		id[i] = alphabet[(f.nextID+i*7)%26]
+	}
+	return string(id)
+}
+
+// grant adds permissions to a grant table.
+func grant(grants map[string]map[string][]onepassword.Permission, vaultID, principalID string, permissions []onepassword.Permission) {
+	if grants[vaultID] == nil {
+		grants[vaultID] = make(map[string][]onepassword.Permission)
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(grants[vaultID][principalID], permission) {
+			grants[vaultID][principalID] = append(grants[vaultID][principalID], permission)
+		}
+	}
+}
+
+// revoke removes permissions from a grant table. Without permissions, all
+// permissions are revoked.
+func revoke(grants map[string]map[string][]onepassword.Permission, vaultID, principalID string, permissions []onepassword.Permission) {
+	if len(permissions) == 0 {
+		delete(grants[vaultID], principalID)
+		return
+	}
+
+	remaining := slices.DeleteFunc(grants[vaultID][principalID], func(p onepassword.Permission) bool {
+		return slices.Contains(permissions, p)
+	})
+	if len(remaining) == 0 {
+		delete(grants[vaultID], principalID)
+		return
+	}
+	grants[vaultID][principalID] = remaining
+}
+
+// now returns the current time in the precision of the 1Password CLI.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
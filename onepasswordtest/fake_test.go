@@ -0,0 +1,830 @@
+package onepasswordtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+func TestFakeItems(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	private := fake.AddVault("Private")
+	shared := fake.AddVault("Shared")
+
+	for _, item := range []onepassword.Item{
+		{Title: "Database", Category: onepassword.CategoryLogin, Vault: private, Tags: []string{"prod"}},
+		{Title: "Wifi", Category: onepassword.CategoryPassword, Vault: shared},
+		{Title: "Notes", Category: onepassword.CategorySecureNote, Vault: shared, Tags: []string{"prod"}},
+	} {
+		if _, err := fake.AddItem(item); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     onepassword.ListItemsOptions
+		expected int
+	}{
+		{name: "All items", expected: 3},
+		{name: "By vault", opts: onepassword.ListItemsOptions{Vault: "Shared"}, expected: 2},
+		{name: "By category", opts: onepassword.ListItemsOptions{Categories: []onepassword.Category{onepassword.CategoryLogin}}, expected: 1},
+		{name: "By tag", opts: onepassword.ListItemsOptions{Tags: []string{"prod"}}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			err := cli.StreamItems(ctx, func(item onepassword.Item) error {
+				if len(item.Fields) != 0 {
+					t.Errorf("listed item %q includes fields", item.Title)
+				}
+				count++
+				return nil
+			}, tt.opts)
+			if err != nil {
+				t.Fatalf("StreamItems() error = %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("StreamItems() listed %d items; want %d", count, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Create, edit and delete", func(t *testing.T) {
+		item := &onepassword.Item{Title: "API", Category: onepassword.CategoryPassword, Vault: private}
+		item.AddPassword("initial")
+
+		created, err := cli.CreateItem(ctx, item, true)
+		if err != nil {
+			t.Fatalf("CreateItem() error = %v", err)
+		}
+		if created.ID == "" || created.Version != 1 {
+			t.Fatalf("CreateItem() = %+v; want an ID and version 1", created)
+		}
+
+		fetched, err := cli.GetItemByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetItemByID() error = %v", err)
+		}
+		passwords, err := fetched.GetFieldsByPurpose(onepassword.FieldPurposePassword)
+		if err != nil || len(passwords) != 1 || passwords[0].Value == "initial" {
+			t.Fatalf("password fields = %+v, %v; want one generated password", passwords, err)
+		}
+
+		fetched.AddTag("rotated")
+		if err := fetched.Save(ctx); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := fetched.Delete(ctx); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := cli.GetItemByID(ctx, created.ID); !errors.Is(err, onepassword.ErrNotFound) {
+			t.Errorf("GetItemByID() after delete error = %v; want ErrNotFound", err)
+		}
+	})
+}
+
+func TestFakeUsersAndGroups(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Engineering")
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	user, err := cli.ProvisionUser(ctx, "Jane Doe", "jane@example.com", "")
+	if err != nil {
+		t.Fatalf("ProvisionUser() error = %v", err)
+	}
+	if user.State != onepassword.UserStatePending {
+		t.Errorf("provisioned user state = %s; want %s", user.State, onepassword.UserStatePending)
+	}
+	if _, err := user.Confirm(ctx); err != nil || user.State != onepassword.UserStateActive {
+		t.Fatalf("Confirm() = %s, %v; want %s", user.State, err, onepassword.UserStateActive)
+	}
+
+	group, err := cli.CreateGroup(ctx, "Developers", "All developers")
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if err := group.AddManager(ctx, *user); err != nil {
+		t.Fatalf("AddManager() error = %v", err)
+	}
+	members, err := group.ListMembers(ctx)
+	if err != nil || len(members) != 1 || !members[0].IsManager() {
+		t.Fatalf("ListMembers() = %+v, %v; want Jane as manager", members, err)
+	}
+
+	v, err := cli.GetVaultDetailsByName(ctx, vault.Name)
+	if err != nil {
+		t.Fatalf("GetVaultDetailsByName() error = %v", err)
+	}
+	if err := v.GrantGroupPermission(ctx, *group, onepassword.PermissionEditItems); err != nil {
+		t.Fatalf("GrantGroupPermission() error = %v", err)
+	}
+	groups, err := v.ListGroups(ctx)
+	if err != nil || len(groups) != 1 || len(groups[0].Permissions) == 0 {
+		t.Fatalf("ListGroups() = %+v, %v; want Developers with permissions", groups, err)
+	}
+
+	vaults, err := group.ListVaults(ctx)
+	if err != nil || len(vaults) != 1 || vaults[0].ID != vault.ID {
+		t.Fatalf("group ListVaults() = %+v, %v; want Engineering", vaults, err)
+	}
+
+	me, err := cli.GetMe(ctx)
+	if err != nil || me.ID != fake.Account().UserUUID {
+		t.Fatalf("GetMe() = %+v, %v; want the owner", me, err)
+	}
+
+	if err := user.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := cli.GetUserByEmail(ctx, "jane@example.com"); !errors.Is(err, onepassword.ErrNotFound) {
+		t.Errorf("GetUserByEmail() after delete error = %v; want ErrNotFound", err)
+	}
+}
+
+func TestFakeAccountType(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	fake.SetAccountType(onepassword.AccountTypeTeams)
+	vault := fake.AddVault("Engineering")
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	accountType, err := cli.AccountType(ctx)
+	if err != nil || accountType != onepassword.AccountTypeTeams {
+		t.Fatalf("AccountType() = %s, %v; want %s", accountType, err, onepassword.AccountTypeTeams)
+	}
+
+	v, err := cli.GetVaultDetailsByName(ctx, vault.Name)
+	if err != nil {
+		t.Fatalf("GetVaultDetailsByName() error = %v", err)
+	}
+	me := onepassword.User{ID: fake.Account().UserUUID}
+	if err := v.GrantUserPermission(ctx, me, onepassword.PermissionEditItems); !errors.Is(err, onepassword.ErrUnsupportedPermission) {
+		t.Errorf("GrantUserPermission() error = %v; want ErrUnsupportedPermission", err)
+	}
+	if err := v.GrantUserPermission(ctx, me, onepassword.PermissionAllowEditing); err != nil {
+		t.Errorf("GrantUserPermission() error = %v", err)
+	}
+}
+
+func TestFakeApply(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	jane := fake.AddUser("Jane Doe", "jane@example.com")
+	john := fake.AddUser("John Doe", "john@example.com")
+	ops := fake.AddGroup("Operations")
+	fake.AddGroupMember(ops.ID, john.ID, onepassword.GroupRoleMember)
+	legacy := fake.AddVault("Legacy")
+	fake.GrantUser(legacy.ID, john.ID, onepassword.PermissionViewItems, onepassword.PermissionEditItems)
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	desired := onepassword.State{
+		Groups: []onepassword.GroupState{
+			{Name: "Developers", Managers: []string{"jane@example.com"}},
+			{Name: "Operations", Members: []string{}},
+		},
+		Vaults: []onepassword.VaultState{
+			{
+				Name:   "Engineering",
+				Groups: map[string][]onepassword.Permission{"Developers": onepassword.RoleEditor.Permissions()},
+			},
+			{
+				Name: "Legacy",
+				Users: map[string][]onepassword.Permission{
+					"owner@example.com": {onepassword.PermissionManageVault},
+					"john@example.com":  {onepassword.PermissionViewItems},
+				},
+			},
+		},
+	}
+
+	plan, err := cli.Plan(ctx, desired)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	expected := []string{
+		"create group Developers",
+		"grant group member Developers/jane@example.com: manager",
+		"revoke group member Operations/john@example.com",
+		"create vault Engineering",
+		"grant vault group Engineering/Developers: " + onepassword.FormatPermissions(onepassword.RoleEditor.Permissions()),
+		"revoke vault user Legacy/john@example.com: edit_items",
+	}
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+	if !slices.Equal(changes, expected) {
+		t.Fatalf("Plan() =\n%s\nwant\n%s", strings.Join(changes, "\n"), strings.Join(expected, "\n"))
+	}
+
+	if err := plan.Apply(ctx); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Applying the same state again is a no-op
+	plan, err = cli.Plan(ctx, desired)
+	if err != nil {
+		t.Fatalf("Plan() after Apply() error = %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Plan() after Apply() =\n%s\nwant no changes", plan)
+	}
+
+	developers, err := cli.GetGroupByName(ctx, "Developers")
+	if err != nil {
+		t.Fatalf("GetGroupByName() error = %v", err)
+	}
+	members, err := developers.ListMembers(ctx)
+	if err != nil || len(members) != 1 || members[0].ID != jane.ID || !members[0].IsManager() {
+		t.Errorf("ListMembers() = %+v, %v; want Jane as manager", members, err)
+	}
+}
+
+func TestFakeBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	source := New()
+	jane := source.AddUser("Jane Doe", "jane@example.com")
+	developers := source.AddGroup("Developers")
+	engineering := source.AddVault("Engineering")
+	source.GrantUser(engineering.ID, jane.ID, onepassword.PermissionViewItems)
+	source.GrantGroup(engineering.ID, developers.ID, onepassword.RoleEditor.Permissions()...)
+	for _, title := range []string{"Database", "Deploy key"} {
+		if _, err := source.AddItem(onepassword.Item{Title: title, Category: onepassword.CategoryLogin, Vault: engineering}); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+
+	sourceCLI, err := source.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	var archive bytes.Buffer
+	if err := sourceCLI.Backup(ctx, &archive, onepassword.BackupOptions{Passphrase: "passphrase"}); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// Restore into another account with the same users and groups
+	target := New()
+	target.AddUser("Jane Doe", "jane@example.com")
+	target.AddGroup("Developers")
+	targetCLI, err := target.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	if _, err := targetCLI.Restore(ctx, bytes.NewReader(archive.Bytes()), onepassword.RestoreOptions{Passphrase: "wrong"}); !errors.Is(err, onepassword.ErrBackupPassphrase) {
+		t.Fatalf("Restore() with wrong passphrase error = %v; want ErrBackupPassphrase", err)
+	}
+
+	report, err := targetCLI.Restore(ctx, bytes.NewReader(archive.Bytes()), onepassword.RestoreOptions{Passphrase: "passphrase"})
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	// The owner's grant is restored along with Jane and Developers
+	if len(report.CreatedVaults) != 1 || report.CreatedItems != 2 || report.Grants != 3 {
+		t.Errorf("Restore() report = %+v; want 1 vault, 2 items and 3 grants", report)
+	}
+
+	restored, err := targetCLI.GetVaultDetailsByName(ctx, "Engineering")
+	if err != nil {
+		t.Fatalf("GetVaultDetailsByName() error = %v", err)
+	}
+	groups, err := restored.ListGroups(ctx)
+	if err != nil || len(groups) != 1 || !slices.Contains(groups[0].Permissions, onepassword.PermissionEditItems) {
+		t.Errorf("ListGroups() = %+v, %v; want Developers with editor permissions", groups, err)
+	}
+
+	// Restoring again skips the existing items
+	report, err = targetCLI.Restore(ctx, bytes.NewReader(archive.Bytes()), onepassword.RestoreOptions{Passphrase: "passphrase"})
+	if err != nil {
+		t.Fatalf("second Restore() error = %v", err)
+	}
+	if len(report.CreatedVaults) != 0 || report.CreatedItems != 0 || report.SkippedItems != 2 {
+		t.Errorf("second Restore() report = %+v; want 2 skipped items", report)
+	}
+}
+
+func TestFakeAuditReport(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	jane := fake.AddUser("Jane Doe", "jane@example.com")
+	john := fake.AddUser("John Doe", "john@example.com")
+	developers := fake.AddGroup("Developers")
+	fake.AddGroupMember(developers.ID, john.ID, onepassword.GroupRoleMember)
+	fake.AddGroupMember(developers.ID, jane.ID, onepassword.GroupRoleManager)
+	engineering := fake.AddVault("Engineering")
+	fake.GrantUser(engineering.ID, jane.ID, onepassword.PermissionViewItems)
+	fake.GrantGroup(engineering.ID, developers.ID, onepassword.RoleEditor.Permissions()...)
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	report, err := cli.GenerateAuditReport(ctx)
+	if err != nil {
+		t.Fatalf("GenerateAuditReport() error = %v", err)
+	}
+
+	var access []string
+	for _, entry := range report.Access {
+		line := entry.User.Email + " " + entry.Vault.Name + " " + string(entry.Path)
+		if entry.Group != nil {
+			line += " " + entry.Group.Name
+		}
+		access = append(access, line)
+	}
+	expected := []string{
+		"jane@example.com Engineering direct",
+		"jane@example.com Engineering group Developers",
+		"john@example.com Engineering group Developers",
+		"owner@example.com Engineering direct",
+	}
+	if !slices.Equal(access, expected) {
+		t.Errorf("GenerateAuditReport() access =\n%s\nwant\n%s", strings.Join(access, "\n"), strings.Join(expected, "\n"))
+	}
+	if len(report.Groups) != 1 || len(report.Groups[0].Members) != 2 {
+		t.Errorf("GenerateAuditReport() groups = %+v; want Developers with 2 members", report.Groups)
+	}
+}
+
+func TestFakeRotatePassword(t *testing.T) {
+	ctx := context.Background()
+	errTarget := errors.New("target system unavailable")
+
+	tests := []struct {
+		name             string
+		local            bool
+		afterErr         error
+		expectedRestored bool
+	}{
+		{name: "Generated by op"},
+		{name: "Generated locally", local: true},
+		{name: "After hook fails", afterErr: errTarget, expectedRestored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := New()
+			vault := fake.AddVault("Infrastructure")
+			created, err := fake.AddItem(onepassword.Item{
+				Title:    "Database",
+				Category: onepassword.CategoryLogin,
+				Vault:    vault,
+				Fields: []onepassword.Field{
+					{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword, Value: "old-password"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("AddItem() error = %v", err)
+			}
+
+			cli, err := fake.NewOpCLI()
+			if err != nil {
+				t.Fatalf("NewOpCLI() error = %v", err)
+			}
+			item, err := cli.GetItemByID(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("GetItemByID() error = %v", err)
+			}
+
+			var rotation onepassword.Rotation
+			hooks := onepassword.RotationHooks{
+				After: func(ctx context.Context, r onepassword.Rotation) error {
+					rotation = r
+					return tt.afterErr
+				},
+			}
+			if tt.local {
+				hooks.Generate = func(recipe onepassword.PasswordRecipe) (string, error) { return recipe.Generate() }
+			}
+
+			err = item.RotatePassword(ctx, onepassword.PasswordRecipe{Length: 20, Letters: true, Digits: true}, hooks)
+			if !errors.Is(err, tt.afterErr) || (err != nil) != (tt.afterErr != nil) {
+				t.Fatalf("RotatePassword() error = %v; want %v", err, tt.afterErr)
+			}
+			if rotation.OldPassword != "old-password" || rotation.NewPassword == "" || rotation.NewPassword == "old-password" {
+				t.Errorf("After hook rotation = %+v; want old and new password", rotation)
+			}
+
+			stored, err := cli.GetItemByID(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("GetItemByID() error = %v", err)
+			}
+			fields, _ := stored.GetFieldsByPurpose(onepassword.FieldPurposePassword)
+			expected := rotation.NewPassword
+			if tt.expectedRestored {
+				expected = "old-password"
+			}
+			if fields[0].Value != expected {
+				t.Errorf("stored password = %q; want %q", fields[0].Value, expected)
+			}
+
+			previous, err := stored.GetFieldsByLabel("previous password")
+			if err != nil || previous[0].Value != "old-password" || previous[0].Type != onepassword.FieldTypeConcealed {
+				t.Errorf("previous password field = %+v, %v; want concealed old password", previous, err)
+			}
+		})
+	}
+}
+
+func TestFakeStrictDecoding(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Private")
+	created, err := fake.AddItem(onepassword.Item{
+		Title:    "Database",
+		Category: onepassword.CategoryLogin,
+		Vault:    vault,
+		Fields: []onepassword.Field{
+			{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword, Value: "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddItem() error = %v", err)
+	}
+
+	cli, err := fake.NewOpCLI(onepassword.WithDecodeMode(onepassword.DecodeStrict))
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	if _, err := cli.GetItemByID(ctx, created.ID); err != nil {
+		t.Errorf("GetItemByID() error = %v", err)
+	}
+	if _, err := cli.GetItems(ctx); err != nil {
+		t.Errorf("GetItems() error = %v", err)
+	}
+	if _, err := cli.GetVaultDetails(ctx); err != nil {
+		t.Errorf("GetVaultDetails() error = %v", err)
+	}
+	user, err := cli.ProvisionUser(ctx, "Jane Doe", "jane@example.com", "")
+	if err != nil {
+		t.Fatalf("ProvisionUser() error = %v", err)
+	}
+	group, err := cli.CreateGroup(ctx, "Developers", "")
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if err := group.AddMember(ctx, *user); err != nil {
+		t.Fatalf("AddMember() error = %v", err)
+	}
+	if _, err := group.ListMembers(ctx); err != nil {
+		t.Errorf("ListMembers() error = %v", err)
+	}
+	if _, err := cli.ListUsers(ctx); err != nil {
+		t.Errorf("ListUsers() error = %v", err)
+	}
+	if fields := cli.UnrecognizedFields(); len(fields) != 0 {
+		t.Errorf("UnrecognizedFields() = %v; want none", fields)
+	}
+}
+
+func TestFakeVaultStats(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Engineering")
+	fake.AddVault("Other")
+
+	for _, item := range []onepassword.Item{
+		{Title: "Database", Category: onepassword.CategoryLogin, Vault: vault, Tags: []string{"prod"}, Favorite: true},
+		{Title: "Runbook", Category: onepassword.CategorySecureNote, Vault: vault},
+		{Title: "Old API", Category: onepassword.CategoryPassword, Vault: vault},
+		{Title: "Elsewhere", Category: onepassword.CategoryLogin, Vault: onepassword.Vault{Name: "Other"}},
+	} {
+		if _, err := fake.AddItem(item); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	if _, err := cli.ExecuteOpCommand(ctx, "item", "delete", "Old API", "--archive"); err != nil {
+		t.Fatalf("archiving item failed: %v", err)
+	}
+
+	v, err := cli.GetVaultDetailsByName(ctx, "Engineering")
+	if err != nil {
+		t.Fatalf("GetVaultDetailsByName() error = %v", err)
+	}
+	stats, err := v.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if stats.Total != 3 || stats.Archived != 1 || stats.Favorites != 1 || stats.Untagged != 1 {
+		t.Errorf("Stats() = %+v; want 3 items, 1 archived, 1 favorite and 1 untagged", *stats)
+	}
+	if stats.Categories[onepassword.CategoryLogin] != 1 || stats.Categories[onepassword.CategoryPassword] != 0 || stats.Tags["prod"] != 1 {
+		t.Errorf("Stats() categories = %v, tags = %v; want the active items only", stats.Categories, stats.Tags)
+	}
+
+	items, err := cli.GetItemsByVault(ctx, *v)
+	if err != nil || len(*items) != 2 {
+		t.Errorf("GetItemsByVault() = %v, %v; want archived items to be hidden", items, err)
+	}
+}
+
+func TestFakeGetGroupsByUser(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	alice := fake.AddUser("Alice", "alice@example.com")
+	bob := fake.AddUser("Bob", "bob@example.com")
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	for _, name := range []string{"Developers", "Operations", "Finance"} {
+		group, err := cli.CreateGroup(ctx, name, "")
+		if err != nil {
+			t.Fatalf("CreateGroup() error = %v", err)
+		}
+		if name != "Finance" {
+			if err := group.AddMember(ctx, alice); err != nil {
+				t.Fatalf("AddMember() error = %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		user     onepassword.User
+		expected []string
+		wantErr  bool
+	}{
+		{name: "By ID", user: alice, expected: []string{"Developers", "Operations"}},
+		{name: "By email", user: onepassword.User{Email: alice.Email}, expected: []string{"Developers", "Operations"}},
+		{name: "No groups", user: bob, expected: nil},
+		{name: "No identifier", user: onepassword.User{Name: "Alice"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := cli.GetGroupsByUser(ctx, tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetGroupsByUser() error = %v; wantErr %v", err, tt.wantErr)
+			}
+
+			var names []string
+			for _, group := range groups {
+				names = append(names, group.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("GetGroupsByUser() = %v; want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFakeRegeneratePassword(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Infrastructure")
+	created, err := fake.AddItem(onepassword.Item{
+		Title:    "Database",
+		Category: onepassword.CategoryLogin,
+		Vault:    vault,
+		Fields: []onepassword.Field{
+			{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword, Value: "old-password"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddItem() error = %v", err)
+	}
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	item, err := cli.GetItemByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetItemByID() error = %v", err)
+	}
+
+	updated, err := item.RegeneratePassword(ctx, onepassword.PasswordRecipe{Length: 20, Letters: true})
+	if err != nil {
+		t.Fatalf("RegeneratePassword() error = %v", err)
+	}
+	password, err := updated.PasswordValue()
+	if err != nil || password == "" || password == "old-password" {
+		t.Errorf("PasswordValue() = %q, %v; want a new password", password, err)
+	}
+
+	stored, err := cli.GetItemByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetItemByID() error = %v", err)
+	}
+	if storedPassword, _ := stored.PasswordValue(); storedPassword != password {
+		t.Errorf("stored password = %q; want %q", storedPassword, password)
+	}
+}
+
+func TestFakePlanGating(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	fake.SetAccountType(onepassword.AccountTypeIndividual)
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	// The fake does not reject the command, so detection after a failure is
+	// covered by unit tests. Once the type is known, commands are rejected
+	// before they are run.
+	if _, err := cli.AccountType(ctx); err != nil {
+		t.Fatalf("AccountType() error = %v", err)
+	}
+
+	if _, err := cli.ListUsers(ctx); !errors.Is(err, onepassword.ErrUnsupportedPlan) {
+		t.Errorf("ListUsers() error = %v; want ErrUnsupportedPlan", err)
+	}
+	if _, err := cli.CreateGroup(ctx, "Developers", ""); !errors.Is(err, onepassword.ErrUnsupportedPlan) {
+		t.Errorf("CreateGroup() error = %v; want ErrUnsupportedPlan", err)
+	}
+	if me, err := cli.GetMe(ctx); err != nil || me.ID != fake.Account().UserUUID {
+		t.Errorf("GetMe() = %+v, %v; want the owner", me, err)
+	}
+	if slices.ContainsFunc(fake.Commands(), func(args []string) bool { return args[0] == "group" || slices.Equal(args, []string{"user", "list"}) }) {
+		t.Errorf("administrative command was run on an individual account: %v", fake.Commands())
+	}
+}
+
+func TestFakeRenameTag(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Engineering")
+
+	for _, item := range []onepassword.Item{
+		{Title: "Database", Category: onepassword.CategoryLogin, Vault: vault, Tags: []string{"prod/databases", "team"}},
+		{Title: "Cluster", Category: onepassword.CategoryLogin, Vault: vault, Tags: []string{"prod"}},
+		{Title: "Staging", Category: onepassword.CategoryLogin, Vault: vault, Tags: []string{"production"}},
+	} {
+		if _, err := fake.AddItem(item); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	items, err := cli.GetItemsByTagTree(ctx, "prod")
+	if err != nil || len(*items) != 2 {
+		t.Fatalf("GetItemsByTagTree() = %v, %v; want 2 items", items, err)
+	}
+
+	results, err := cli.MoveTag(ctx, "prod", "envs", onepassword.BatchOptions{})
+	if err != nil {
+		t.Fatalf("MoveTag() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("MoveTag() = %d results; want 2", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("MoveTag() result for %s error = %v", result.Item.Title, result.Err)
+		}
+	}
+
+	database, err := cli.GetItemByName(ctx, "Database")
+	if err != nil {
+		t.Fatalf("GetItemByName() error = %v", err)
+	}
+	if !slices.Equal(database.Tags, []string{"envs/prod/databases", "team"}) {
+		t.Errorf("tags = %q; want the moved tag and the untouched tag", database.Tags)
+	}
+	staging, err := cli.GetItemByName(ctx, "Staging")
+	if err != nil {
+		t.Fatalf("GetItemByName() error = %v", err)
+	}
+	if !slices.Equal(staging.Tags, []string{"production"}) {
+		t.Errorf("tags = %q; want tags outside the subtree unchanged", staging.Tags)
+	}
+
+	if _, err := cli.MoveTag(ctx, "envs", "envs/prod", onepassword.BatchOptions{}); err == nil {
+		t.Error("MoveTag() below itself error = nil; want an error")
+	}
+}
+
+func TestFakeCreateItemPreview(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	vault := fake.AddVault("Infrastructure")
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	cli.SetDryRun(true)
+
+	preview, err := cli.CreateItemPreview(ctx, &onepassword.Item{Title: "Database", Category: onepassword.CategoryLogin, Vault: vault}, true)
+	if err != nil {
+		t.Fatalf("CreateItemPreview() error = %v", err)
+	}
+	if password, err := preview.PasswordValue(); err != nil || password == "" {
+		t.Errorf("PasswordValue() = %q, %v; want a generated password", password, err)
+	}
+	if preview.ID != "" {
+		t.Errorf("preview ID = %q; want none", preview.ID)
+	}
+	if len(cli.DryRunCommands()) != 0 {
+		t.Errorf("DryRunCommands() = %v; want the preview to run in dry-run mode", cli.DryRunCommands())
+	}
+
+	items, err := cli.GetItems(ctx)
+	if err != nil || len(*items) != 0 {
+		t.Errorf("GetItems() = %v, %v; want no items to be created", items, err)
+	}
+
+	if _, err := cli.CreateItemPreview(ctx, &onepassword.Item{Title: "Database", Vault: onepassword.Vault{ID: "missing"}}, false); err == nil {
+		t.Error("CreateItemPreview() in a missing vault error = nil; want an error")
+	}
+}
+
+func TestFakeRotatePasswordGenerationFails(t *testing.T) {
+	ctx := context.Background()
+	errGenerate := errors.New("generation failed")
+
+	fake := New()
+	vault := fake.AddVault("Infrastructure")
+	created, err := fake.AddItem(onepassword.Item{
+		Title:    "Database",
+		Category: onepassword.CategoryLogin,
+		Vault:    vault,
+		Fields: []onepassword.Field{
+			{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Purpose: onepassword.FieldPurposePassword, Value: "old-password"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddItem() error = %v", err)
+	}
+
+	cli, err := fake.NewOpCLI()
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+	// Fail the edit that generates the password after the rotation fields were stored
+	cli.OnBeforeExec(func(ctx context.Context, info *onepassword.CommandInfo) error {
+		for _, arg := range info.Args {
+			if strings.HasPrefix(arg, "--generate-password") {
+				return errGenerate
+			}
+		}
+		return nil
+	})
+
+	item, err := cli.GetItemByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetItemByID() error = %v", err)
+	}
+	if err := item.RotatePassword(ctx, onepassword.PasswordRecipe{}, onepassword.RotationHooks{}); !errors.Is(err, errGenerate) {
+		t.Fatalf("RotatePassword() error = %v; want %v", err, errGenerate)
+	}
+
+	stored, err := cli.GetItemByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetItemByID() error = %v", err)
+	}
+	if password, err := stored.PasswordValue(); err != nil || password != "old-password" {
+		t.Errorf("stored password = %q, %v; want old-password", password, err)
+	}
+	for _, label := range []string{"previous password", "rotated at"} {
+		if fields, err := stored.GetFieldsByLabel(label); err == nil {
+			t.Errorf("field %q = %+v after the failed rotation; want none", label, fields)
+		}
+	}
+}
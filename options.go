@@ -0,0 +1,377 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Option configures an OpCLI instance created by NewOpCLI.
+type Option func(*OpCLI) error
+
+// WithPath sets the path to the 1Password CLI executable instead of
+// searching for it in PATH.
+//
+// Parameters:
+//   - path: The path to the op executable.
+func WithPath(path string) Option {
+	return func(cli *OpCLI) error {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("invalid op executable path: %w", err)
+		}
+		if stat.IsDir() {
+			return fmt.Errorf("invalid op executable path: %s is a directory", path)
+		}
+
+		cli.Path = path
+		return nil
+	}
+}
+
+// WithManagedInstall downloads and verifies the 1Password CLI with InstallOp
+// and uses the installed executable.
+//
+// Parameters:
+//   - opts: Options for the installation.
+func WithManagedInstall(opts InstallOptions) Option {
+	return func(cli *OpCLI) error {
+		path, err := InstallOp(context.Background(), opts)
+		if err != nil {
+			return err
+		}
+
+		cli.Path = path
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the OpCLI instance. See SetLogger.
+//
+// Parameters:
+//   - logger: The logger to use. If nil, log output is discarded.
+func WithLogger(logger *slog.Logger) Option {
+	return func(cli *OpCLI) error {
+		cli.SetLogger(logger)
+		return nil
+	}
+}
+
+// WithBackend sets the Backend that serves the vault and item operations.
+// See SetBackend. The 1Password CLI is not required when a Backend is set.
+//
+// Parameters:
+//   - backend: The Backend to use, e.g. a ConnectBackend.
+func WithBackend(backend Backend) Option {
+	return func(cli *OpCLI) error {
+		cli.SetBackend(backend)
+		return nil
+	}
+}
+
+// WithTimeout sets a default timeout for every command of the 1Password CLI.
+// The timeout applies in addition to the deadline of the context passed to
+// an operation.
+//
+// Parameters:
+//   - timeout: The maximum duration of a command. Zero disables the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cli *OpCLI) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout: %v", timeout)
+		}
+
+		cli.timeout = timeout
+		return nil
+	}
+}
+
+// WithCache sets how long the account list is cached. See SetAccountCacheTTL.
+//
+// Parameters:
+//   - ttl: The time the account list is cached. A negative duration disables the cache.
+func WithCache(ttl time.Duration) Option {
+	return func(cli *OpCLI) error {
+		cli.SetAccountCacheTTL(ttl)
+		return nil
+	}
+}
+
+// WithCacheTTL sets how long lookups of an entity are cached. See SetCacheTTL.
+//
+// Parameters:
+//   - entity: The kind of entity, e.g. CacheVaults.
+//   - ttl: The time entries are cached.
+func WithCacheTTL(entity CacheEntity, ttl time.Duration) Option {
+	return func(cli *OpCLI) error {
+		switch entity {
+		case CacheVaults, CacheUsers, CacheGroups, CacheAccounts, CacheItems:
+		default:
+			return fmt.Errorf("unknown cache entity: %s", entity)
+		}
+
+		cli.SetCacheTTL(entity, ttl)
+		return nil
+	}
+}
+
+// WithEnv adds environment variables to every command of the 1Password CLI,
+// e.g. "OP_CACHE=false". Each entry must have the form "KEY=value".
+//
+// Parameters:
+//   - env: The environment variables to add.
+func WithEnv(env ...string) Option {
+	return func(cli *OpCLI) error {
+		for _, entry := range env {
+			if key, _, ok := strings.Cut(entry, "="); !ok || key == "" {
+				return fmt.Errorf("invalid environment variable: %q", entry)
+			}
+		}
+
+		cli.env = append(cli.env, env...)
+		return nil
+	}
+}
+
+// WithAccount sets the active account of the OpCLI instance, e.g. an account
+// that was signed in with "op signin" before.
+//
+// Parameters:
+//   - account: The account to use.
+func WithAccount(account *Account) Option {
+	return func(cli *OpCLI) error {
+		if account == nil || account.UserUUID == "" {
+			return ErrMissingAccount
+		}
+
+		cli.Account = account
+		return nil
+	}
+}
+
+// WithRateLimit limits how many commands ExecuteOpCommand runs. See SetRateLimit.
+//
+// Parameters:
+//   - requests: The number of commands allowed per period.
+//   - per: The period, e.g. time.Second or time.Minute.
+//   - burst: The number of commands that may run at once before the limit applies.
+func WithRateLimit(requests int, per time.Duration, burst int) Option {
+	return func(cli *OpCLI) error {
+		return cli.SetRateLimit(requests, per, burst)
+	}
+}
+
+// WithCommandExecutor sets the CommandExecutor used to run commands of the
+// 1Password CLI. See SetCommandExecutor.
+//
+// Parameters:
+//   - executor: The CommandExecutor to use.
+func WithCommandExecutor(executor CommandExecutor) Option {
+	return func(cli *OpCLI) error {
+		cli.SetCommandExecutor(executor)
+		return nil
+	}
+}
+
+// WithDecodeMode sets how the output of commands is checked. See SetDecodeMode.
+//
+// Parameters:
+//   - mode: DecodeTolerant or DecodeStrict.
+func WithDecodeMode(mode DecodeMode) Option {
+	return func(cli *OpCLI) error {
+		cli.SetDecodeMode(mode)
+		return nil
+	}
+}
+
+// WithCredentialProvider sets the provider for sign-in secrets. See SetCredentialProvider.
+//
+// Parameters:
+//   - provider: The CredentialProvider to use.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(cli *OpCLI) error {
+		cli.SetCredentialProvider(provider)
+		return nil
+	}
+}
+
+// WithSessionStore sets the store for session tokens. See SetSessionStore.
+//
+// Parameters:
+//   - store: The SessionStore to use.
+func WithSessionStore(store SessionStore) Option {
+	return func(cli *OpCLI) error {
+		cli.SetSessionStore(store)
+		return nil
+	}
+}
+
+// WithBeforeExec registers a hook that is called before every command. See OnBeforeExec.
+//
+// Parameters:
+//   - hook: The hook to call.
+func WithBeforeExec(hook BeforeExecHook) Option {
+	return func(cli *OpCLI) error {
+		cli.OnBeforeExec(hook)
+		return nil
+	}
+}
+
+// WithAfterExec registers a hook that is called after every command. See OnAfterExec.
+//
+// Parameters:
+//   - hook: The hook to call.
+func WithAfterExec(hook AfterExecHook) Option {
+	return func(cli *OpCLI) error {
+		cli.OnAfterExec(hook)
+		return nil
+	}
+}
+
+// WithTracer sets the Tracer that starts a span for every command. See SetTracer.
+//
+// Parameters:
+//   - tracer: The Tracer to use.
+func WithTracer(tracer Tracer) Option {
+	return func(cli *OpCLI) error {
+		cli.SetTracer(tracer)
+		return nil
+	}
+}
+
+// WithDryRun enables the dry-run mode, in which commands that change data are
+// not run. See SetDryRun.
+func WithDryRun() Option {
+	return func(cli *OpCLI) error {
+		cli.SetDryRun(true)
+		return nil
+	}
+}
+
+// WithMaxConcurrency limits how many op processes run at the same time. See SetMaxConcurrency.
+//
+// Parameters:
+//   - n: The maximum number of concurrent commands.
+func WithMaxConcurrency(n int) Option {
+	return func(cli *OpCLI) error {
+		return cli.SetMaxConcurrency(n)
+	}
+}
+
+// WithConfigDir runs all commands with a dedicated configuration directory
+// of the 1Password CLI. See SetConfigDir.
+//
+// Parameters:
+//   - dir: The configuration directory. It is created if it does not exist.
+func WithConfigDir(dir string) Option {
+	return func(cli *OpCLI) error {
+		if dir == "" {
+			return errors.New("config directory is empty")
+		}
+		return cli.SetConfigDir(dir)
+	}
+}
+
+// WithTempConfigDir runs all commands with a new, empty configuration
+// directory in the temporary directory of the system, e.g. for tests. The
+// directory is returned by ConfigDir and removed by Close.
+func WithTempConfigDir() Option {
+	return func(cli *OpCLI) error {
+		dir, err := newTempConfigDir()
+		if err != nil {
+			return err
+		}
+		if err := cli.SetConfigDir(dir); err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+		cli.lifecycle.ownsConfigDir = true
+		return nil
+	}
+}
+
+// WithCommandLogging logs every command with its redacted command line,
+// duration and exit code at debug level. See SetCommandLogging.
+func WithCommandLogging() Option {
+	return func(cli *OpCLI) error {
+		cli.SetCommandLogging(true)
+		return nil
+	}
+}
+
+// WithKeepAlive keeps the session of the active account alive while the
+// instance is idle. See StartKeepAlive.
+//
+// Parameters:
+//   - interval: The idle time after which the session is renewed. Defaults to 20 minutes if zero.
+func WithKeepAlive(interval time.Duration) Option {
+	return func(cli *OpCLI) error {
+		return cli.StartKeepAlive(interval)
+	}
+}
+
+// WithISOTimestamps passes --iso-timestamps to every command. See
+// SetISOTimestamps.
+func WithISOTimestamps() Option {
+	return func(cli *OpCLI) error {
+		cli.SetISOTimestamps(true)
+		return nil
+	}
+}
+
+// WithNoColor passes --no-color to every command. See SetNoColor.
+func WithNoColor() Option {
+	return func(cli *OpCLI) error {
+		cli.SetNoColor(true)
+		return nil
+	}
+}
+
+// WithLocale runs every command with the given locale. See SetLocale.
+//
+// Parameters:
+//   - locale: The locale, e.g. "C" or "en_US.UTF-8".
+func WithLocale(locale string) Option {
+	return func(cli *OpCLI) error {
+		cli.SetLocale(locale)
+		return nil
+	}
+}
+
+// WithSessionCheck detects expired sessions before running commands. See
+// SetSessionCheck.
+func WithSessionCheck() Option {
+	return func(cli *OpCLI) error {
+		cli.SetSessionCheck(true)
+		return nil
+	}
+}
+
+// WithSignOutOnClose signs out of the active account when the instance is
+// closed. See SetSignOutOnClose.
+func WithSignOutOnClose() Option {
+	return func(cli *OpCLI) error {
+		cli.SetSignOutOnClose(true)
+		return nil
+	}
+}
+
+// WithPermissionDependencies registers additional permission dependencies for
+// the instance. See SetPermissionDependencies.
+//
+// Parameters:
+//   - dependencies: The dependencies to register, keyed by permission.
+func WithPermissionDependencies(dependencies PermissionDependenciesMap) Option {
+	return func(cli *OpCLI) error {
+		for permission, deps := range dependencies {
+			if err := cli.SetPermissionDependencies(permission, deps...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package onepassword
+
+// outputSettings holds the global flags and locale that make the output of
+// the 1Password CLI independent of the configuration of the user.
+type outputSettings struct {
+	isoTimestamps bool
+	noColor       bool
+	locale        string
+}
+
+// SetISOTimestamps enables or disables the --iso-timestamps flag on every
+// command, so that timestamps in the output are printed in ISO 8601 format
+// instead of relative times like "2 hours ago", regardless of the CLI
+// configuration of the user.
+//
+// Parameters:
+//   - enabled: Whether --iso-timestamps is passed.
+func (cli *OpCLI) SetISOTimestamps(enabled bool) {
+	cli.output.isoTimestamps = enabled
+}
+
+// SetNoColor enables or disables the --no-color flag on every command, so
+// that the output and error messages contain no ANSI escape sequences, even
+// if the CLI detects a terminal.
+//
+// Parameters:
+//   - enabled: Whether --no-color is passed.
+func (cli *OpCLI) SetNoColor(enabled bool) {
+	cli.output.noColor = enabled
+}
+
+// SetLocale overrides the locale of every command with the LANG and LC_ALL
+// environment variables, e.g. "C" or "en_US.UTF-8", so that messages and
+// formatting do not depend on the locale of the process. An empty locale
+// keeps the locale of the process.
+//
+// Parameters:
+//   - locale: The locale to use.
+func (cli *OpCLI) SetLocale(locale string) {
+	cli.output.locale = locale
+}
+
+// outputArgs returns the global flags appended to every command.
+func (cli *OpCLI) outputArgs() []string {
+	var args []string
+	if cli.output.isoTimestamps {
+		args = append(args, "--iso-timestamps")
+	}
+	if cli.output.noColor {
+		args = append(args, "--no-color")
+	}
+	return args
+}
+
+// outputEnv returns the environment variables of the locale override.
+func (cli *OpCLI) outputEnv() []string {
+	if cli.output.locale == "" {
+		return nil
+	}
+	return []string{"LANG=" + cli.output.locale, "LC_ALL=" + cli.output.locale}
+}
@@ -0,0 +1,50 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestOutputSettings(t *testing.T) {
+	var commands []*Command
+	cli, err := NewOpCLI(
+		WithAccount(&Account{UserUUID: "user-uuid"}),
+		WithCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+			commands = append(commands, cmd)
+			return []byte(`[]`), nil, nil
+		})),
+		WithISOTimestamps(),
+		WithNoColor(),
+		WithLocale("C"),
+	)
+	if err != nil {
+		t.Fatalf("NewOpCLI() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := cli.ExecuteOpCommand(ctx, "item", "list"); err != nil {
+		t.Fatalf("ExecuteOpCommand() error = %v", err)
+	}
+	if _, err := cli.ExecuteRaw(ctx, ExecOptions{NoDefaultArgs: true}, "--version"); err != nil {
+		t.Fatalf("ExecuteRaw() error = %v", err)
+	}
+
+	for _, cmd := range commands {
+		if !slices.Contains(cmd.Args, "--iso-timestamps") || !slices.Contains(cmd.Args, "--no-color") {
+			t.Errorf("args = %v; want --iso-timestamps and --no-color", cmd.Args)
+		}
+		if !slices.Contains(cmd.Env, "LANG=C") || !slices.Contains(cmd.Env, "LC_ALL=C") {
+			t.Errorf("env of %v lacks the locale override", cmd.Args)
+		}
+	}
+	if commands[0].Args[0] != "item" || commands[0].Args[1] != "list" {
+		t.Errorf("args = %v; want the flags appended after the subcommand", commands[0].Args)
+	}
+
+	cli.SetNoColor(false)
+	cli.SetISOTimestamps(false)
+	if cmd := cli.command("whoami"); !slices.Equal(cmd.Args, []string{"whoami"}) {
+		t.Errorf("args = %v; want no output flags after disabling them", cmd.Args)
+	}
+}
@@ -0,0 +1,101 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// itemPayload is the JSON template of an item passed to "item create" and
+// "item edit" on standard input. It only contains the fields the CLI accepts
+// in a template. Read-only data of an item, e.g. its version, timestamps,
+// last editor and the references and password details of its fields, is
+// left out, as the CLI computes it. Keys of the CLI output that the package
+// does not model are sent back unchanged, so saving an item does not drop them.
+type itemPayload struct {
+	Title    string         `json:"title"`
+	Category Category       `json:"category,omitempty"`
+	Vault    *vaultPayload  `json:"vault,omitempty"`
+	Favorite bool           `json:"favorite"`
+	Tags     []string       `json:"tags"`
+	URLs     []ItemURL      `json:"urls,omitempty"`
+	Sections []Section      `json:"sections,omitempty"`
+	Fields   []fieldPayload `json:"fields,omitempty"`
+
+	unknown unknownJSON
+}
+
+// vaultPayload identifies the vault of a created item by ID or name.
+type vaultPayload struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// fieldPayload is a field of an itemPayload.
+type fieldPayload struct {
+	ID      string       `json:"id,omitempty"`
+	Label   string       `json:"label"`
+	Type    FieldType    `json:"type"`
+	Purpose FieldPurpose `json:"purpose,omitempty"`
+	Value   string       `json:"value,omitempty"`
+	Section *Section     `json:"section,omitempty"`
+
+	unknown unknownJSON
+}
+
+// MarshalJSON adds the retained unknown keys of the item to the template.
+func (p itemPayload) MarshalJSON() ([]byte, error) {
+	type plain itemPayload
+	return marshalWithUnknown(plain(p), p.unknown)
+}
+
+// MarshalJSON adds the retained unknown keys of the field to the template.
+func (p fieldPayload) MarshalJSON() ([]byte, error) {
+	type plain fieldPayload
+	return marshalWithUnknown(plain(p), p.unknown)
+}
+
+// newItemPayload returns the template of an item. The vault is only
+// included for new items, as "item edit" cannot move an item to another
+// vault. The tags are always included, so removing the last tag of an item
+// clears its tags.
+func newItemPayload(item Item, create bool) itemPayload {
+	payload := itemPayload{
+		Title:    item.Title,
+		Category: item.Category,
+		Favorite: item.Favorite,
+		Tags:     item.Tags,
+		URLs:     item.URLs,
+		Sections: item.Sections,
+		unknown:  item.unknown,
+	}
+	if payload.Tags == nil {
+		payload.Tags = []string{}
+	}
+	if create && (item.Vault.ID != "" || item.Vault.Name != "") {
+		payload.Vault = &vaultPayload{ID: item.Vault.ID, Name: item.Vault.Name}
+	}
+
+	for _, field := range item.Fields {
+		payload.Fields = append(payload.Fields, fieldPayload{
+			ID:      field.ID,
+			Label:   field.Label,
+			Type:    field.Type,
+			Purpose: field.Purpose,
+			Value:   field.Value,
+			Section: field.Section,
+			unknown: field.unknown,
+		})
+	}
+
+	return payload
+}
+
+// marshalItemPayload serializes the template of an item for "item create"
+// or "item edit".
+func marshalItemPayload(item Item, create bool) ([]byte, error) {
+	data, err := json.Marshal(newItemPayload(item, create))
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize item to JSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,75 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMarshalItemPayload(t *testing.T) {
+	item := Item{
+		ID:             "item-id",
+		Title:          "Database",
+		Category:       CategoryLogin,
+		Vault:          Vault{ID: "vault-id", Name: "Infrastructure", ContentVersion: 3},
+		Version:        4,
+		LastEditedBy:   "user-id",
+		AdditionalInfo: "admin",
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		Fields: []Field{{
+			ID:              "password",
+			Label:           "password",
+			Type:            FieldTypeConcealed,
+			Purpose:         FieldPurposePassword,
+			Value:           "secret",
+			Reference:       "op://Infrastructure/Database/password",
+			Entropy:         120,
+			PasswordDetails: &PasswordDetails{Strength: StrengthFantastic},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		create        bool
+		expectedVault bool
+	}{
+		{name: "Create", create: true, expectedVault: true},
+		{name: "Edit", create: false, expectedVault: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := marshalItemPayload(item, tt.create)
+			if err != nil {
+				t.Fatalf("marshalItemPayload() error = %v", err)
+			}
+
+			var payload map[string]any
+			if err := json.Unmarshal(data, &payload); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			for _, key := range []string{"id", "version", "last_edited_by", "additional_information", "created_at", "updated_at", "state"} {
+				if _, ok := payload[key]; ok {
+					t.Errorf("payload contains read-only key %q: %s", key, data)
+				}
+			}
+			if _, ok := payload["vault"]; ok != tt.expectedVault {
+				t.Errorf("payload contains vault = %v; want %v", ok, tt.expectedVault)
+			}
+			if tags, ok := payload["tags"].([]any); !ok || len(tags) != 0 {
+				t.Errorf("payload tags = %v; want an empty list", payload["tags"])
+			}
+
+			field := payload["fields"].([]any)[0].(map[string]any)
+			for _, key := range []string{"reference", "entropy", "password_details"} {
+				if _, ok := field[key]; ok {
+					t.Errorf("field contains read-only key %q: %s", key, data)
+				}
+			}
+			if field["value"] != "secret" {
+				t.Errorf("field value = %v; want %q", field["value"], "secret")
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package onepassword
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
 
 // Permission represents a specific permission in 1Password.
 type Permission string
@@ -29,6 +35,32 @@ const (
 	PermissionMoveItems Permission = "move_items"
 )
 
+// knownPermissions contains all permissions supported by the 1Password CLI.
+var knownPermissions = map[Permission]struct{}{
+	PermissionViewItems:            {},
+	PermissionCreateItems:          {},
+	PermissionEditItems:            {},
+	PermissionArchiveItems:         {},
+	PermissionDeleteItems:          {},
+	PermissionViewAndCopyPasswords: {},
+	PermissionViewItemHistory:      {},
+	PermissionImportItems:          {},
+	PermissionExportItems:          {},
+	PermissionCopyAndShareItems:    {},
+	PermissionPrintItems:           {},
+	PermissionManageVault:          {},
+	PermissionAllowViewing:         {},
+	PermissionAllowEditing:         {},
+	PermissionAllowManaging:        {},
+}
+
+// IsValid reports whether the permission is supported by the 1Password CLI.
+// PermissionMoveItems is not valid on its own, as it is resolved to its dependencies.
+func (p Permission) IsValid() bool {
+	_, ok := knownPermissions[p]
+	return ok
+}
+
 // PermissionDependencies maps each permission to its required broader permissions.
 type PermissionDependenciesMap map[Permission][]Permission
 
@@ -48,22 +80,417 @@ var PermissionDependencies = PermissionDependenciesMap{
 
 // ResolvePermissions generates a string of permissions for a given permission key in the PermissionDependenciesMap.
 func ResolvePermissions(permission Permission) string {
-	dependencies, exists := PermissionDependencies[permission]
+	return FormatPermissions(PermissionDependencies.Resolve(permission))
+}
+
+// FormatPermissions joins a slice of permissions into the comma-separated
+// representation expected by the --permissions flag of the 1Password CLI.
+func FormatPermissions(permissions []Permission) string {
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, string(permission))
+	}
+
+	return strings.Join(result, ",")
+}
+
+// Resolve returns the given permissions together with their dependencies,
+// without duplicates and in order of first appearance. Dependencies are
+// resolved transitively, so an entry only needs to list the permissions it
+// directly builds on. Permissions without an entry resolve to themselves.
+//
+// Parameters:
+//   - permissions: The permissions to resolve.
+//
+// Returns:
+//   - []Permission: The resolved permissions.
+func (m PermissionDependenciesMap) Resolve(permissions ...Permission) []Permission {
+	seen := make(map[Permission]struct{})
+	var result []Permission
+
+	var resolve func(permission Permission)
+	resolve = func(permission Permission) {
+		dependencies, exists := m[permission]
+		if !exists {
+			dependencies = []Permission{permission}
+		}
+
+		for _, dep := range dependencies {
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+			seen[dep] = struct{}{}
+			result = append(result, dep)
+
+			if dep != permission {
+				resolve(dep)
+			}
+		}
+	}
+
+	for _, permission := range permissions {
+		resolve(permission)
+	}
+
+	return result
+}
+
+// Clone returns a copy of the map that can be modified independently.
+func (m PermissionDependenciesMap) Clone() PermissionDependenciesMap {
+	clone := make(PermissionDependenciesMap, len(m))
+	for permission, dependencies := range m {
+		clone[permission] = slices.Clone(dependencies)
+	}
+	return clone
+}
+
+// permissionRegistry holds the permission dependencies of an OpCLI instance.
+// Until dependencies are registered, the default PermissionDependencies are used.
+type permissionRegistry struct {
+	mu           sync.RWMutex
+	dependencies PermissionDependenciesMap
+}
+
+// SetPermissionDependencies registers the dependencies of a permission for
+// this instance, e.g. for permissions of newer CLI versions or to enforce
+// organization policies. The registered dependencies replace an existing
+// entry and are used by all grant and revoke methods of vaults of this
+// instance. The package-level PermissionDependencies are not modified.
+//
+// The dependencies should include the permission itself if it is passed to the
+// CLI; derived permissions like PermissionMoveItems omit it. Dependencies are
+// resolved transitively. If no dependencies are given, the entry is removed
+// and the permission resolves to itself.
+//
+// Parameters:
+//   - permission: The permission to register.
+//   - dependencies: The permissions granted for it.
+//
+// Returns:
+//   - error: An error if the permission is empty.
+//
+// Example usage:
+//
+//	err := cli.SetPermissionDependencies("manage_items",
+//	    "manage_items", onepassword.PermissionEditItems)
+func (cli *OpCLI) SetPermissionDependencies(permission Permission, dependencies ...Permission) error {
+	if permission == "" {
+		return errors.New("permission cannot be empty")
+	}
+
+	cli.permissions.mu.Lock()
+	defer cli.permissions.mu.Unlock()
+
+	if cli.permissions.dependencies == nil {
+		cli.permissions.dependencies = PermissionDependencies.Clone()
+	}
+
+	if len(dependencies) == 0 {
+		delete(cli.permissions.dependencies, permission)
+		return nil
+	}
+	cli.permissions.dependencies[permission] = slices.Clone(dependencies)
+
+	return nil
+}
+
+// PermissionDependencies returns a copy of the permission dependencies used
+// by this instance.
+func (cli *OpCLI) PermissionDependencies() PermissionDependenciesMap {
+	cli.permissions.mu.RLock()
+	defer cli.permissions.mu.RUnlock()
+
+	if cli.permissions.dependencies == nil {
+		return PermissionDependencies.Clone()
+	}
+	return cli.permissions.dependencies.Clone()
+}
+
+// ResolvePermissions resolves the given permissions with the permission
+// dependencies of this instance.
+//
+// Parameters:
+//   - permissions: The permissions to resolve.
+//
+// Returns:
+//   - []Permission: The permissions together with their dependencies.
+func (cli *OpCLI) ResolvePermissions(permissions ...Permission) []Permission {
+	cli.permissions.mu.RLock()
+	defer cli.permissions.mu.RUnlock()
+
+	if cli.permissions.dependencies == nil {
+		return PermissionDependencies.Resolve(permissions...)
+	}
+	return cli.permissions.dependencies.Resolve(permissions...)
+}
+
+// Role is a predefined set of vault permissions for common use cases. Roles
+// include all dependencies of their permissions, so they can be granted
+// without resolving dependency chains.
+type Role string
+
+const (
+	// RoleViewer can view items and reveal and copy their passwords.
+	RoleViewer Role = "viewer"
+
+	// RoleEditor can view, create, edit, archive and delete items.
+	RoleEditor Role = "editor"
+
+	// RoleManager has all item permissions and can manage the vault and its access.
+	RoleManager Role = "manager"
+
+	// RoleAuditor can view items and their history and export them for review,
+	// but cannot change them.
+	RoleAuditor Role = "auditor"
+)
+
+// rolePermissions maps each role to the permissions it grants. The
+// dependencies of these permissions are resolved by Role.Permissions.
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {
+		PermissionViewItems,
+		PermissionViewAndCopyPasswords,
+	},
+	RoleEditor: {
+		PermissionViewItems,
+		PermissionViewAndCopyPasswords,
+		PermissionViewItemHistory,
+		PermissionCreateItems,
+		PermissionEditItems,
+		PermissionArchiveItems,
+		PermissionDeleteItems,
+	},
+	RoleManager: {
+		PermissionViewItems,
+		PermissionViewAndCopyPasswords,
+		PermissionViewItemHistory,
+		PermissionCreateItems,
+		PermissionEditItems,
+		PermissionArchiveItems,
+		PermissionDeleteItems,
+		PermissionImportItems,
+		PermissionExportItems,
+		PermissionCopyAndShareItems,
+		PermissionPrintItems,
+		PermissionManageVault,
+	},
+	RoleAuditor: {
+		PermissionViewItems,
+		PermissionViewItemHistory,
+		PermissionExportItems,
+	},
+}
+
+// Roles returns all predefined roles.
+func Roles() []Role {
+	return []Role{RoleViewer, RoleEditor, RoleManager, RoleAuditor}
+}
+
+// Permissions returns the permissions granted by the role, including their
+// dependencies.
+//
+// Returns:
+//   - []Permission: The resolved permissions, or nil if the role is unknown.
+func (r Role) Permissions() []Permission {
+	permissions, exists := rolePermissions[r]
 	if !exists {
-		return string(permission)
+		return nil
+	}
+
+	return PermissionDependencies.Resolve(permissions...)
+}
+
+// Principal is a user or group that vault permissions can be granted to.
+// It is implemented by *User and *Group.
+type Principal interface {
+	principal() (kind, id string)
+}
+
+// principal implements Principal.
+func (user *User) principal() (string, string) {
+	return "user", user.ID
+}
+
+// principal implements Principal.
+func (group *Group) principal() (string, string) {
+	return "group", group.ID
+}
+
+// UnknownPermissionsError is returned by ParsePermissions if the input contains
+// values that are not valid permissions. It matches ErrUnknownPermission with
+// errors.Is.
+type UnknownPermissionsError struct {
+	Values []string
+}
+
+// Error implements the error interface.
+func (e *UnknownPermissionsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownPermission, strings.Join(e.Values, ", "))
+}
+
+// Is reports whether target is ErrUnknownPermission.
+func (e *UnknownPermissionsError) Is(target error) bool {
+	return target == ErrUnknownPermission
+}
+
+// ParsePermissions converts a comma-separated permission string, as returned by
+// "op vault user list" and "op vault group list", into a slice of permissions.
+//
+// Values are trimmed and compared case-insensitively, with spaces treated as
+// underscores, so both "view_items" and "View Items" are accepted. Duplicates
+// are removed and the order of first appearance is kept.
+//
+// Parameters:
+//   - value: The comma-separated permissions.
+//
+// Returns:
+//   - []Permission: The valid permissions, even if unknown values were found.
+//   - error: An *UnknownPermissionsError listing all values that are not valid permissions.
+//
+// Example usage:
+//
+//	permissions, err := onepassword.ParsePermissions("view_items, edit_items")
+//	if err != nil {
+//	    log.Fatalf("Invalid permissions: %v", err)
+//	}
+func ParsePermissions(value string) ([]Permission, error) {
+	var permissions []Permission
+	var unknown []string
+	seen := make(map[Permission]struct{})
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		permission := Permission(strings.ToLower(strings.Join(strings.Fields(part), "_")))
+		if !permission.IsValid() {
+			unknown = append(unknown, part)
+			continue
+		}
+
+		if _, ok := seen[permission]; ok {
+			continue
+		}
+		seen[permission] = struct{}{}
+		permissions = append(permissions, permission)
 	}
 
-	// Use a map to avoid duplicate permissions
-	resolved := make(map[Permission]struct{})
-	for _, dep := range dependencies {
-		resolved[dep] = struct{}{}
+	if len(unknown) > 0 {
+		return permissions, &UnknownPermissionsError{Values: unknown}
 	}
 
-	// Convert the map keys to a comma-separated string
-	var result []string
-	for perm := range resolved {
-		result = append(result, string(perm))
+	return permissions, nil
+}
+
+// broaderPermissions are the permissions of Teams and Families accounts.
+// Business accounts use the granular permissions instead.
+var broaderPermissions = map[Permission]struct{}{
+	PermissionAllowViewing:  {},
+	PermissionAllowEditing:  {},
+	PermissionAllowManaging: {},
+}
+
+// vaultTypePersonal is the type of the private vault of a user, which cannot be shared.
+const vaultTypePersonal = "PERSONAL"
+
+// UnsupportedPermissionError is returned when permissions are granted or
+// revoked that are not supported by the account plan or vault type. It
+// matches ErrUnsupportedPermission with errors.Is.
+//
+// Fields:
+//   - Permissions: The unsupported permissions.
+//   - AccountType: The type of the account, if known.
+//   - VaultType: The type of the vault, if known.
+//   - Reason: A description of why the permissions are not supported.
+type UnsupportedPermissionError struct {
+	Permissions []Permission
+	AccountType AccountType
+	VaultType   string
+	Reason      string
+}
+
+// Error implements the error interface.
+func (e *UnsupportedPermissionError) Error() string {
+	return fmt.Sprintf("%s %s: %s", ErrUnsupportedPermission, FormatPermissions(e.Permissions), e.Reason)
+}
+
+// Is reports whether target is ErrUnsupportedPermission.
+func (e *UnsupportedPermissionError) Is(target error) bool {
+	return target == ErrUnsupportedPermission
+}
+
+// ValidatePermissions checks whether permissions can be granted on a vault of
+// the given type in an account of the given type:
+//
+//   - Individual accounts cannot share vaults, so no permissions are supported.
+//   - Teams and Families accounts only support the broader allow_* permissions.
+//   - Business accounts only support the granular permissions.
+//   - Private vaults cannot be shared on any plan.
+//
+// Permissions that are not known to the package, e.g. custom permissions
+// registered with SetPermissionDependencies, are not rejected. An empty
+// account or vault type skips the respective checks.
+//
+// Parameters:
+//   - accountType: The type of the account, e.g. from OpCLI.AccountType.
+//   - vaultType: The Type of the vault.
+//   - permissions: The permissions to validate.
+//
+// Returns:
+//   - error: An *UnsupportedPermissionError if any permission is not supported.
+func ValidatePermissions(accountType AccountType, vaultType string, permissions []Permission) error {
+	if len(permissions) == 0 {
+		return nil
 	}
 
-	return strings.Join(result, ",")
+	if vaultType == vaultTypePersonal {
+		return &UnsupportedPermissionError{
+			Permissions: permissions,
+			AccountType: accountType,
+			VaultType:   vaultType,
+			Reason:      "private vaults cannot be shared",
+		}
+	}
+
+	var reason string
+	var supported func(Permission) bool
+	switch accountType {
+	case AccountTypeIndividual:
+		reason = "individual accounts cannot share vaults"
+		supported = func(Permission) bool { return false }
+	case AccountTypeTeams, AccountTypeFamily:
+		reason = "only allow_viewing, allow_editing and allow_managing are supported on " + string(accountType) + " accounts"
+		supported = func(p Permission) bool {
+			_, broader := broaderPermissions[p]
+			return broader || !p.IsValid()
+		}
+	case AccountTypeBusiness:
+		reason = "only granular permissions are supported on " + string(accountType) + " accounts"
+		supported = func(p Permission) bool {
+			_, broader := broaderPermissions[p]
+			return !broader
+		}
+	default:
+		return nil
+	}
+
+	var unsupported []Permission
+	for _, permission := range permissions {
+		if !supported(permission) {
+			unsupported = append(unsupported, permission)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return &UnsupportedPermissionError{
+			Permissions: unsupported,
+			AccountType: accountType,
+			VaultType:   vaultType,
+			Reason:      reason,
+		}
+	}
+
+	return nil
 }
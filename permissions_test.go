@@ -1,6 +1,9 @@
 package onepassword
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -59,3 +62,309 @@ func sortStrings(slice []string) {
 		}
 	}
 }
+
+func TestPermissionDependenciesResolve(t *testing.T) {
+	result := PermissionDependencies.Resolve(PermissionCreateItems, PermissionEditItems, PermissionManageVault)
+	expected := []Permission{
+		PermissionCreateItems,
+		PermissionViewItems,
+		PermissionEditItems,
+		PermissionViewAndCopyPasswords,
+		PermissionManageVault,
+	}
+
+	if FormatPermissions(result) != FormatPermissions(expected) {
+		t.Errorf("Resolve() = %q; want %q", FormatPermissions(result), FormatPermissions(expected))
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	tests := []struct {
+		role     Role
+		expected string
+	}{
+		{RoleViewer, "view_items,view_and_copy_passwords"},
+		{RoleEditor, "view_items,view_and_copy_passwords,view_item_history,create_items,edit_items,archive_items,delete_items"},
+		{RoleAuditor, "view_items,view_item_history,view_and_copy_passwords,export_items"},
+		{Role("owner"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if result := FormatPermissions(tt.role.Permissions()); result != tt.expected {
+				t.Errorf("%s.Permissions() = %q; want %q", tt.role, result, tt.expected)
+			}
+		})
+	}
+
+	// Every role must include the dependencies of its permissions
+	for _, role := range Roles() {
+		permissions := role.Permissions()
+		for _, permission := range permissions {
+			for _, dep := range PermissionDependencies[permission] {
+				if !slices.Contains(permissions, dep) {
+					t.Errorf("%s is missing %s required by %s", role, dep, permission)
+				}
+			}
+		}
+	}
+}
+
+func TestGrantRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		role      Role
+		expected  []string
+		wantErr   error
+	}{
+		{
+			name:      "User",
+			principal: &User{ID: "user-id"},
+			role:      RoleViewer,
+			expected:  []string{"vault", "user", "grant", "--vault", "vault-id", "--user", "user-id", "--permissions", "view_items,view_and_copy_passwords"},
+		},
+		{
+			name:      "Group",
+			principal: &Group{ID: "group-id"},
+			role:      RoleManager,
+			expected:  []string{"vault", "group", "grant", "--vault", "vault-id", "--group", "group-id", "--permissions", FormatPermissions(RoleManager.Permissions())},
+		},
+		{
+			name:      "Unknown role",
+			principal: &User{ID: "user-id"},
+			role:      Role("owner"),
+			wantErr:   ErrUnknownRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				args = cmd.Args
+				return nil, nil, nil
+			}))
+			vault := &Vault{ID: "vault-id", cli: cli}
+
+			err := vault.GrantRole(context.Background(), tt.principal, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GrantRole() error = %v; want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// The account flag is appended by ExecuteOpCommand
+			if len(args) < len(tt.expected) || !slices.Equal(args[:len(tt.expected)], tt.expected) {
+				t.Errorf("GrantRole() args = %v; want prefix %v", args, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGrantPermissions(t *testing.T) {
+	var commands [][]string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetLogger(nil)
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		if cmd.Args[0] != "vault" {
+			// The account type is not detected, so only the vault type is checked
+			return nil, []byte("[ERROR] unknown command"), errors.New("exit status 1")
+		}
+		// The account flag is appended by ExecuteOpCommand
+		commands = append(commands, cmd.Args[:9])
+		if slices.Contains(cmd.Args, "broken-group") {
+			return nil, []byte("[ERROR] group not found"), errors.New("exit status 1")
+		}
+		return nil, nil, nil
+	}))
+	vault := &Vault{ID: "vault-id", cli: cli}
+
+	user := &User{ID: "user-id"}
+	group := &Group{ID: "group-id"}
+	grants := []PermissionGrant{
+		{User: user, Permissions: []Permission{PermissionViewItems}},
+		{Group: group, Permissions: []Permission{PermissionEditItems}},
+		{User: user, Permissions: []Permission{PermissionViewAndCopyPasswords}},
+		{User: user, Group: group, Permissions: []Permission{PermissionViewItems}},
+		{Group: &Group{ID: "broken-group"}, Permissions: []Permission{PermissionViewItems}},
+		{User: &User{ID: "other-user"}},
+	}
+
+	results := vault.GrantPermissions(context.Background(), grants)
+
+	expected := [][]string{
+		// Both grants of the user are merged into a single command
+		{"vault", "user", "grant", "--vault", "vault-id", "--user", "user-id", "--permissions", "view_items,view_and_copy_passwords"},
+		{"vault", "group", "grant", "--vault", "vault-id", "--group", "group-id", "--permissions",
+			FormatPermissions(cli.ResolvePermissions(PermissionEditItems))},
+		{"vault", "group", "grant", "--vault", "vault-id", "--group", "broken-group", "--permissions",
+			FormatPermissions(cli.ResolvePermissions(PermissionViewItems))},
+	}
+	if !slices.EqualFunc(commands, expected, slices.Equal) {
+		t.Errorf("GrantPermissions() commands = %q; want %q", commands, expected)
+	}
+
+	if len(results) != len(grants) {
+		t.Fatalf("GrantPermissions() returned %d results; want %d", len(results), len(grants))
+	}
+	for i, wantErr := range []bool{false, false, false, true, true, true} {
+		if results[i].Grant.User != grants[i].User || results[i].Grant.Group != grants[i].Group {
+			t.Errorf("result %d has grant %+v; want %+v", i, results[i].Grant, grants[i])
+		}
+		if (results[i].Err != nil) != wantErr {
+			t.Errorf("result %d error = %v; want error %t", i, results[i].Err, wantErr)
+		}
+	}
+	if err := results[4].Err; err == nil || !strings.Contains(err.Error(), "broken-group") {
+		t.Errorf("result of the failed group error = %v; want the group in the error", err)
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []Permission
+		unknown  []string
+	}{
+		{
+			name:     "Empty",
+			value:    "",
+			expected: nil,
+		},
+		{
+			name:     "CLI output",
+			value:    "view_items,create_items,edit_items",
+			expected: []Permission{PermissionViewItems, PermissionCreateItems, PermissionEditItems},
+		},
+		{
+			name:     "Whitespace, case and duplicates",
+			value:    " View Items , allow_viewing,view_items,",
+			expected: []Permission{PermissionViewItems, PermissionAllowViewing},
+		},
+		{
+			name:     "Unknown values",
+			value:    "view_items,fly,move_items",
+			expected: []Permission{PermissionViewItems},
+			unknown:  []string{"fly", "move_items"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParsePermissions(tt.value)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("ParsePermissions(%q) = %v; want %v", tt.value, result, tt.expected)
+			}
+
+			if tt.unknown == nil {
+				if err != nil {
+					t.Errorf("ParsePermissions(%q) error = %v", tt.value, err)
+				}
+				return
+			}
+
+			var unknownErr *UnknownPermissionsError
+			if !errors.As(err, &unknownErr) || !errors.Is(err, ErrUnknownPermission) {
+				t.Fatalf("ParsePermissions(%q) error = %v; want UnknownPermissionsError", tt.value, err)
+			}
+			if !slices.Equal(unknownErr.Values, tt.unknown) {
+				t.Errorf("UnknownPermissionsError.Values = %v; want %v", unknownErr.Values, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestSetPermissionDependencies(t *testing.T) {
+	const manageItems Permission = "manage_items"
+
+	cli := &OpCLI{}
+	if err := cli.SetPermissionDependencies(manageItems, manageItems, PermissionEditItems); err != nil {
+		t.Fatalf("SetPermissionDependencies() error = %v", err)
+	}
+	if err := cli.SetPermissionDependencies(""); err == nil {
+		t.Error("SetPermissionDependencies() with empty permission succeeded")
+	}
+
+	tests := []struct {
+		name     string
+		cli      *OpCLI
+		expected string
+	}{
+		{
+			name:     "Registered dependencies are resolved transitively",
+			cli:      cli,
+			expected: "manage_items,edit_items,view_and_copy_passwords,view_items",
+		},
+		{
+			name:     "Other instances use the defaults",
+			cli:      &OpCLI{},
+			expected: "manage_items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FormatPermissions(tt.cli.ResolvePermissions(manageItems)); result != tt.expected {
+				t.Errorf("ResolvePermissions() = %q; want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, exists := PermissionDependencies[manageItems]; exists {
+		t.Error("SetPermissionDependencies() modified the package-level defaults")
+	}
+
+	// Removing the entry makes the permission resolve to itself
+	if err := cli.SetPermissionDependencies(PermissionEditItems); err != nil {
+		t.Fatalf("SetPermissionDependencies() error = %v", err)
+	}
+	if result := FormatPermissions(cli.ResolvePermissions(PermissionEditItems)); result != "edit_items" {
+		t.Errorf("ResolvePermissions() after removal = %q; want %q", result, "edit_items")
+	}
+}
+
+func TestValidatePermissions(t *testing.T) {
+	granular := []Permission{PermissionViewItems, PermissionEditItems}
+	broader := []Permission{PermissionAllowViewing}
+
+	tests := []struct {
+		name        string
+		accountType AccountType
+		vaultType   string
+		permissions []Permission
+		unsupported []Permission
+	}{
+		{name: "Granular on Business", accountType: AccountTypeBusiness, permissions: granular},
+		{name: "Broader on Business", accountType: AccountTypeBusiness, permissions: broader, unsupported: broader},
+		{name: "Broader on Teams", accountType: AccountTypeTeams, permissions: broader},
+		{name: "Granular on Families", accountType: AccountTypeFamily, permissions: granular, unsupported: granular},
+		{name: "Custom permission on Teams", accountType: AccountTypeTeams, permissions: []Permission{"custom"}},
+		{name: "Individual account", accountType: AccountTypeIndividual, permissions: broader, unsupported: broader},
+		{name: "Private vault", vaultType: "PERSONAL", permissions: granular, unsupported: granular},
+		{name: "Unknown account type", permissions: granular},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePermissions(tt.accountType, tt.vaultType, tt.permissions)
+			if tt.unsupported == nil {
+				if err != nil {
+					t.Errorf("ValidatePermissions() error = %v", err)
+				}
+				return
+			}
+
+			var unsupportedErr *UnsupportedPermissionError
+			if !errors.As(err, &unsupportedErr) || !errors.Is(err, ErrUnsupportedPermission) {
+				t.Fatalf("ValidatePermissions() error = %v; want UnsupportedPermissionError", err)
+			}
+			if !slices.Equal(unsupportedErr.Permissions, tt.unsupported) {
+				t.Errorf("UnsupportedPermissionError.Permissions = %v; want %v", unsupportedErr.Permissions, tt.unsupported)
+			}
+		})
+	}
+}
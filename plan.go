@@ -0,0 +1,246 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// AccountType is the plan of a 1Password account as reported by "op account get".
+type AccountType string
+
+const (
+	AccountTypeBusiness   AccountType = "BUSINESS"
+	AccountTypeTeams      AccountType = "TEAM"
+	AccountTypeFamily     AccountType = "FAMILY"
+	AccountTypeIndividual AccountType = "INDIVIDUAL"
+)
+
+// knownAccountTypes are the account types known to the package.
+var knownAccountTypes = []AccountType{AccountTypeIndividual, AccountTypeFamily, AccountTypeTeams, AccountTypeBusiness}
+
+// ErrUnsupportedPlan is returned before a command is executed that the plan
+// of the account does not support, e.g. managing users of an individual account.
+var ErrUnsupportedPlan = errors.New("operation not supported by the account plan")
+
+// Capability is a group of administrative operations that is only
+// available on some account plans.
+type Capability string
+
+const (
+	CapabilityUserManagement   Capability = "user management"   // Listing, provisioning and managing users.
+	CapabilityGroups           Capability = "groups"            // Managing groups and granting vault access to groups.
+	CapabilityVaultPermissions Capability = "vault permissions" // Granting vault access to users.
+)
+
+// capabilityPlans lists the account types that support each capability.
+var capabilityPlans = map[Capability][]AccountType{
+	CapabilityUserManagement:   {AccountTypeFamily, AccountTypeTeams, AccountTypeBusiness},
+	CapabilityGroups:           {AccountTypeTeams, AccountTypeBusiness},
+	CapabilityVaultPermissions: {AccountTypeFamily, AccountTypeTeams, AccountTypeBusiness},
+}
+
+// capabilityCommands maps the command prefixes to the capability they require.
+// Prefixes in capabilityAllowedCommands are exempt.
+var capabilityCommands = []struct {
+	prefix     []string
+	capability Capability
+}{
+	{[]string{"user"}, CapabilityUserManagement},
+	{[]string{"group"}, CapabilityGroups},
+	{[]string{"vault", "group"}, CapabilityGroups},
+	{[]string{"vault", "user"}, CapabilityVaultPermissions},
+}
+
+// capabilityAllowedCommands lists commands that are supported on all plans.
+var capabilityAllowedCommands = [][]string{
+	{"user", "get", "--me"},
+}
+
+// Supports reports whether the account type supports a capability. Unknown
+// account types are assumed to support everything, so new plans are not
+// rejected.
+//
+// Parameters:
+//   - capability: The capability to check.
+//
+// Returns:
+//   - bool: true if the capability is available on the plan.
+func (t AccountType) Supports(capability Capability) bool {
+	plans, ok := capabilityPlans[capability]
+	if !ok || !slices.Contains(knownAccountTypes, t) {
+		return true
+	}
+	return slices.Contains(plans, t)
+}
+
+// PlanError is returned for a command that requires a capability the plan
+// of the account does not support. Once the account type is known, such
+// commands are rejected before they are executed. It matches
+// ErrUnsupportedPlan with errors.Is.
+//
+// Fields:
+//   - Capability: The capability the command requires.
+//   - AccountType: The type of the account.
+//   - Command: The command that was rejected, e.g. "group create".
+//   - Err: The error of the CLI, if the command was run before the plan was known.
+type PlanError struct {
+	Capability  Capability
+	AccountType AccountType
+	Command     string
+	Err         error
+}
+
+// Error implements the error interface.
+func (e *PlanError) Error() string {
+	return fmt.Sprintf("%s: op %s requires %s, which %s accounts do not support",
+		ErrUnsupportedPlan, e.Command, e.Capability, strings.ToLower(string(e.AccountType)))
+}
+
+// Is reports whether target is ErrUnsupportedPlan.
+func (e *PlanError) Is(target error) bool {
+	return target == ErrUnsupportedPlan
+}
+
+// Unwrap returns the error of the CLI.
+func (e *PlanError) Unwrap() error {
+	return e.Err
+}
+
+// checkPlanSupport returns a *PlanError before a command is executed that
+// requires a capability the plan of the account does not support. Only an
+// account type that was already detected is used, so no command is added
+// to the first administrative command of an OpCLI instance.
+func (cli *OpCLI) checkPlanSupport(args []string) error {
+	capability, ok := cli.requiredCapability(args)
+	if !ok {
+		return nil
+	}
+
+	accountType := cli.cachedAccountType()
+	if accountType == "" || accountType.Supports(capability) {
+		return nil
+	}
+	return &PlanError{Capability: capability, AccountType: accountType, Command: commandName(args)}
+}
+
+// planError detects the account type after a command failed and returns a
+// *PlanError wrapping err if the plan does not support the command. Otherwise,
+// or if the account type cannot be detected, err is returned unchanged.
+func (cli *OpCLI) planError(ctx context.Context, args []string, err error) error {
+	capability, ok := cli.requiredCapability(args)
+	if !ok || ctx.Err() != nil {
+		return err
+	}
+
+	accountType, detectErr := cli.AccountType(ctx)
+	if detectErr != nil {
+		cli.log().Debug("skipping account plan check", "command", commandName(args), "error", detectErr)
+		return err
+	}
+	if accountType.Supports(capability) {
+		return err
+	}
+	return &PlanError{Capability: capability, AccountType: accountType, Command: commandName(args), Err: err}
+}
+
+// requiredCapability returns the capability a command requires, if any.
+// Service accounts are checked by checkServiceAccountSupport instead.
+func (cli *OpCLI) requiredCapability(args []string) (Capability, bool) {
+	if cli.isServiceAccount {
+		return "", false
+	}
+	for _, prefix := range capabilityAllowedCommands {
+		if hasCommandPrefix(args, prefix) {
+			return "", false
+		}
+	}
+	for _, command := range capabilityCommands {
+		if hasCommandPrefix(args, command.prefix) {
+			return command.capability, true
+		}
+	}
+	return "", false
+}
+
+// cachedAccountType returns the detected type of the active account, or an
+// empty string if it was not detected yet.
+func (cli *OpCLI) cachedAccountType() AccountType {
+	accountUUID := ""
+	if cli.Account != nil {
+		accountUUID = cli.Account.AccountUUID
+	}
+
+	cli.plan.mu.Lock()
+	defer cli.plan.mu.Unlock()
+
+	if cli.plan.accountUUID != accountUUID {
+		return ""
+	}
+	return cli.plan.accountType
+}
+
+// accountPlan caches the detected type of the active account.
+type accountPlan struct {
+	mu          sync.Mutex
+	accountUUID string
+	accountType AccountType
+}
+
+// AccountType retrieves the plan of the active account, e.g. AccountTypeBusiness.
+// The result is cached for the active account, so the account is only
+// queried once.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - AccountType: The type of the account.
+//   - error: An error if the account details cannot be retrieved.
+//
+// Example usage:
+//
+//	accountType, err := cli.AccountType(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to detect account type: %v", err)
+//	}
+//	if accountType == onepassword.AccountTypeBusiness {
+//	    // use granular permissions
+//	}
+func (cli *OpCLI) AccountType(ctx context.Context) (AccountType, error) {
+	accountUUID := ""
+	if cli.Account != nil {
+		accountUUID = cli.Account.AccountUUID
+	}
+
+	cli.plan.mu.Lock()
+	defer cli.plan.mu.Unlock()
+
+	if cli.plan.accountType != "" && cli.plan.accountUUID == accountUUID {
+		return cli.plan.accountType, nil
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, "account", "get")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve account details: %w", err)
+	}
+
+	var details struct {
+		Type AccountType `json:"type"`
+	}
+	if err := json.Unmarshal(output, &details); err != nil {
+		return "", fmt.Errorf("failed to parse account details: %w", err)
+	}
+	if details.Type == "" {
+		return "", fmt.Errorf("no account type reported")
+	}
+
+	cli.plan.accountUUID = accountUUID
+	cli.plan.accountType = details.Type
+
+	return details.Type, nil
+}
@@ -0,0 +1,148 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAccountType(t *testing.T) {
+	var calls int
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid", AccountUUID: "account-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		calls++
+		if !slices.Contains(cmd.Args, "get") {
+			t.Errorf("unexpected command %v", cmd.Args)
+		}
+		return []byte(`{"id":"account-uuid","name":"Example","type":"TEAM","state":"ACTIVE"}`), nil, nil
+	}))
+
+	ctx := context.Background()
+	for range 2 {
+		accountType, err := cli.AccountType(ctx)
+		if err != nil {
+			t.Fatalf("AccountType() error = %v", err)
+		}
+		if accountType != AccountTypeTeams {
+			t.Errorf("AccountType() = %s; want %s", accountType, AccountTypeTeams)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("executor calls = %d; want 1", calls)
+	}
+
+	// Switching the account detects the type again
+	cli.Account = &Account{UserUUID: "user-uuid", AccountUUID: "other-uuid"}
+	if _, err := cli.AccountType(ctx); err != nil {
+		t.Fatalf("AccountType() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("executor calls after switching account = %d; want 2", calls)
+	}
+}
+
+func TestAccountTypeSupports(t *testing.T) {
+	tests := []struct {
+		accountType AccountType
+		expected    map[Capability]bool
+	}{
+		{AccountTypeIndividual, map[Capability]bool{CapabilityUserManagement: false, CapabilityGroups: false, CapabilityVaultPermissions: false}},
+		{AccountTypeFamily, map[Capability]bool{CapabilityUserManagement: true, CapabilityGroups: false, CapabilityVaultPermissions: true}},
+		{AccountTypeTeams, map[Capability]bool{CapabilityUserManagement: true, CapabilityGroups: true, CapabilityVaultPermissions: true}},
+		{AccountTypeBusiness, map[Capability]bool{CapabilityUserManagement: true, CapabilityGroups: true, CapabilityVaultPermissions: true}},
+		{AccountType("ENTERPRISE"), map[Capability]bool{CapabilityUserManagement: true, CapabilityGroups: true, CapabilityVaultPermissions: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.accountType), func(t *testing.T) {
+			for capability, expected := range tt.expected {
+				if got := tt.accountType.Supports(capability); got != expected {
+					t.Errorf("Supports(%s) = %t; want %t", capability, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPlanSupport(t *testing.T) {
+	tests := []struct {
+		name               string
+		cached             AccountType
+		args               []string
+		serviceAccount     bool
+		expectedCapability Capability
+	}{
+		{name: "Item command", cached: AccountTypeIndividual, args: []string{"item", "list"}},
+		{name: "Own user", cached: AccountTypeIndividual, args: []string{"user", "get", "--me"}},
+		{name: "Users on individual", cached: AccountTypeIndividual, args: []string{"user", "list"}, expectedCapability: CapabilityUserManagement},
+		{name: "Vault groups on family", cached: AccountTypeFamily, args: []string{"vault", "group", "grant"}, expectedCapability: CapabilityGroups},
+		{name: "Vault users on family", cached: AccountTypeFamily, args: []string{"vault", "user", "grant"}},
+		{name: "Groups on business", cached: AccountTypeBusiness, args: []string{"group", "create"}},
+		{name: "Type not detected yet", args: []string{"group", "list"}},
+		{name: "Service account", cached: AccountTypeIndividual, args: []string{"group", "list"}, serviceAccount: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}, isServiceAccount: tt.serviceAccount}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				t.Errorf("unexpected command %v", cmd.Args)
+				return nil, nil, nil
+			}))
+			cli.plan.accountType = tt.cached
+
+			err := cli.checkPlanSupport(tt.args)
+			if tt.expectedCapability == "" {
+				if err != nil {
+					t.Errorf("checkPlanSupport() error = %v; want nil", err)
+				}
+				return
+			}
+
+			var planErr *PlanError
+			if !errors.As(err, &planErr) || !errors.Is(err, ErrUnsupportedPlan) || planErr.Capability != tt.expectedCapability {
+				t.Errorf("checkPlanSupport() error = %v; want a PlanError for %s", err, tt.expectedCapability)
+			}
+		})
+	}
+}
+
+func TestPlanError(t *testing.T) {
+	errCLI := errors.New("command failed")
+
+	tests := []struct {
+		name              string
+		accountOutput     string
+		args              []string
+		expectedPlanError bool
+		expectedDetection bool
+	}{
+		{name: "Item command", accountOutput: `{"type":"INDIVIDUAL"}`, args: []string{"item", "get", "x"}},
+		{name: "Groups on individual", accountOutput: `{"type":"INDIVIDUAL"}`, args: []string{"group", "list"}, expectedPlanError: true, expectedDetection: true},
+		{name: "Groups on business", accountOutput: `{"type":"BUSINESS"}`, args: []string{"group", "list"}, expectedDetection: true},
+		{name: "Detection fails", accountOutput: `not json`, args: []string{"group", "list"}, expectedDetection: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var detected bool
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				detected = true
+				return []byte(tt.accountOutput), nil, nil
+			}))
+
+			err := cli.planError(context.Background(), tt.args, errCLI)
+			if detected != tt.expectedDetection {
+				t.Errorf("planError() detected account type = %t; want %t", detected, tt.expectedDetection)
+			}
+			if !errors.Is(err, errCLI) {
+				t.Errorf("planError() = %v; want it to wrap the CLI error", err)
+			}
+			if errors.Is(err, ErrUnsupportedPlan) != tt.expectedPlanError {
+				t.Errorf("planError() = %v; want ErrUnsupportedPlan = %t", err, tt.expectedPlanError)
+			}
+		})
+	}
+}
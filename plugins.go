@@ -0,0 +1,212 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Plugin is a shell plugin of the 1Password CLI, which provides the
+// credentials of a third-party CLI like the AWS CLI from 1Password.
+//
+// Fields:
+//   - Name: The name of the plugin, e.g. "AWS CLI".
+//   - Executables: The executables the plugin authenticates, e.g. "aws". They
+//     identify the plugin in InitPlugin, InspectPlugin and ClearPlugin.
+type Plugin struct {
+	Name        string   `json:"name"`
+	Executables []string `json:"executables"`
+}
+
+// columnSeparator separates the columns of the table output of the CLI.
+var columnSeparator = regexp.MustCompile(`\s{2,}`)
+
+// ListPlugins retrieves the shell plugins supported by the 1Password CLI
+// with "op plugin list".
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - []Plugin: The available plugins.
+//   - error: An error if the command fails or its output cannot be parsed.
+func (cli *OpCLI) ListPlugins(ctx context.Context) ([]Plugin, error) {
+	output, err := cli.executeOpCommandWith(ctx, execSettings{noDefaultArgs: true}, "plugin", "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugins: %w", err)
+	}
+
+	return parsePluginList(output)
+}
+
+// parsePluginList parses the output of "op plugin list". The CLI prints a
+// table with a header row; JSON output is accepted as well.
+func parsePluginList(output []byte) ([]Plugin, error) {
+	trimmed := strings.TrimSpace(string(output))
+	if strings.HasPrefix(trimmed, "[") {
+		var plugins []Plugin
+		if err := json.Unmarshal([]byte(trimmed), &plugins); err != nil {
+			return nil, fmt.Errorf("failed to parse plugin list: %w", err)
+		}
+		return plugins, nil
+	}
+
+	lines := strings.Split(trimmed, "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		return nil, nil
+	}
+
+	nameColumn, executableColumn := -1, -1
+	for i, header := range columnSeparator.Split(strings.TrimSpace(lines[0]), -1) {
+		switch strings.ToUpper(header) {
+		case "NAME":
+			nameColumn = i
+		case "EXECUTABLE", "EXECUTABLES":
+			executableColumn = i
+		}
+	}
+	if nameColumn < 0 || executableColumn < 0 {
+		return nil, fmt.Errorf("failed to parse plugin list: unexpected header %q", lines[0])
+	}
+
+	var plugins []Plugin
+	for _, line := range lines[1:] {
+		columns := columnSeparator.Split(strings.TrimSpace(line), -1)
+		if len(columns) <= max(nameColumn, executableColumn) {
+			continue
+		}
+
+		plugin := Plugin{Name: columns[nameColumn]}
+		for _, executable := range strings.Split(columns[executableColumn], ",") {
+			if executable = strings.TrimSpace(executable); executable != "" {
+				plugin.Executables = append(plugin.Executables, executable)
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
+
+// PluginInitOptions configures the interactive setup of a shell plugin.
+//
+// Fields:
+//   - Stdin: The input for the prompts of the CLI. Defaults to os.Stdin.
+//   - Stdout: Receives the prompts of the CLI. Defaults to os.Stdout.
+//   - Stderr: Receives the standard error of the CLI. Defaults to os.Stderr.
+type PluginInitOptions struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// InitPlugin configures a shell plugin with "op plugin init". The CLI asks
+// interactively for the item with the credentials and the scope of the
+// configuration, so the command is connected to the terminal by default.
+// Onboarding tools can script the prompts by passing their own streams.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - plugin: The executable of the plugin, e.g. "aws".
+//   - opts: The streams connected to the CLI.
+//
+// Returns:
+//   - error: An error if the plugin cannot be configured.
+func (cli *OpCLI) InitPlugin(ctx context.Context, plugin string, opts PluginInitOptions) error {
+	if plugin == "" {
+		return errors.New("plugin cannot be empty")
+	}
+
+	args := []string{"plugin", "init", plugin}
+	if cli.Account != nil && cli.Account.UserUUID != "" {
+		args = append(args, "--account", cli.Account.UserUUID)
+	}
+
+	cmd := cli.command(args...)
+	cmd.Stdin = opts.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = opts.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = opts.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	_, stderr, err := cli.run(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize plugin %s: %w", plugin, &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		})
+	}
+
+	return nil
+}
+
+// InspectPlugin reports the configuration of a shell plugin with
+// "op plugin inspect", e.g. the configured credentials and where they apply.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - plugin: The executable of the plugin, e.g. "aws".
+//
+// Returns:
+//   - string: The report of the CLI.
+//   - error: An error if the plugin cannot be inspected.
+func (cli *OpCLI) InspectPlugin(ctx context.Context, plugin string) (string, error) {
+	if plugin == "" {
+		return "", errors.New("plugin cannot be empty")
+	}
+
+	output, err := cli.executeOpCommandWith(ctx, execSettings{noDefaultArgs: true}, "plugin", "inspect", plugin)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect plugin %s: %w", plugin, err)
+	}
+
+	return string(output), nil
+}
+
+// PluginClearOptions controls which configurations ClearPlugin removes.
+//
+// Fields:
+//   - All: Remove the configurations of all scopes (global, directory and shell session)
+//     instead of only the one that applies in the current directory.
+type PluginClearOptions struct {
+	All bool
+}
+
+// ClearPlugin removes the configuration of a shell plugin with
+// "op plugin clear". The confirmation prompt of the CLI is skipped.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - plugin: The executable of the plugin, e.g. "aws".
+//   - opts: Options for the removal.
+//
+// Returns:
+//   - error: An error if the configuration cannot be removed.
+func (cli *OpCLI) ClearPlugin(ctx context.Context, plugin string, opts PluginClearOptions) error {
+	if plugin == "" {
+		return errors.New("plugin cannot be empty")
+	}
+
+	args := []string{"plugin", "clear", plugin, "--force"}
+	if opts.All {
+		args = append(args, "--all")
+	}
+
+	if _, err := cli.executeOpCommandWith(ctx, execSettings{noDefaultArgs: true}, args...); err != nil {
+		return fmt.Errorf("failed to clear plugin %s: %w", plugin, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package onepassword
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePluginList(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []Plugin
+	}{
+		{
+			name: "Table",
+			output: "NAME                 EXECUTABLE\n" +
+				"AWS CLI              aws\n" +
+				"GitHub CLI           gh\n" +
+				"PostgreSQL           psql, pg_dump\n",
+			expected: []Plugin{
+				{Name: "AWS CLI", Executables: []string{"aws"}},
+				{Name: "GitHub CLI", Executables: []string{"gh"}},
+				{Name: "PostgreSQL", Executables: []string{"psql", "pg_dump"}},
+			},
+		},
+		{
+			name:     "JSON",
+			output:   `[{"name":"AWS CLI","executables":["aws"]}]`,
+			expected: []Plugin{{Name: "AWS CLI", Executables: []string{"aws"}}},
+		},
+		{
+			name:     "Empty",
+			output:   "",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugins, err := parsePluginList([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("parsePluginList() error = %v", err)
+			}
+			if !reflect.DeepEqual(plugins, tt.expected) {
+				t.Errorf("parsePluginList() = %+v; want %+v", plugins, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPluginCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      func(ctx context.Context, cli *OpCLI) error
+		expected []string
+	}{
+		{
+			name: "Init",
+			run: func(ctx context.Context, cli *OpCLI) error {
+				return cli.InitPlugin(ctx, "aws", PluginInitOptions{Stdin: strings.NewReader("\n"), Stdout: &strings.Builder{}, Stderr: &strings.Builder{}})
+			},
+			expected: []string{"plugin", "init", "aws", "--account", "user-uuid"},
+		},
+		{
+			name: "Inspect",
+			run: func(ctx context.Context, cli *OpCLI) error {
+				_, err := cli.InspectPlugin(ctx, "gh")
+				return err
+			},
+			expected: []string{"plugin", "inspect", "gh"},
+		},
+		{
+			name: "Clear all",
+			run: func(ctx context.Context, cli *OpCLI) error {
+				return cli.ClearPlugin(ctx, "aws", PluginClearOptions{All: true})
+			},
+			expected: []string{"plugin", "clear", "aws", "--force", "--all"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				args = cmd.Args
+				return nil, nil, nil
+			}))
+
+			if err := tt.run(context.Background(), cli); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !slices.Equal(args, tt.expected) {
+				t.Errorf("args = %v; want %v", args, tt.expected)
+			}
+		})
+	}
+}
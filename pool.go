@@ -0,0 +1,114 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// commandPool limits the number of concurrently running commands. Commands
+// that exceed the limit are queued and started in the order they arrive.
+type commandPool struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	waiting []chan struct{}
+}
+
+// SetMaxConcurrency limits how many op processes an OpCLI instance runs at
+// the same time. Bulk operations like ProvisionUsers, GrantPermissions and
+// streaming listings share this limit. Commands above the limit wait until a
+// running command finishes or their context is done. The limit is disabled
+// by default.
+//
+// A streaming listing like StreamItems or AllItems gives its slot up while
+// the callback or the loop body runs, so commands run from there, e.g.
+// Item.Save, do not wait for the listing to finish.
+//
+// Parameters:
+//   - n: The maximum number of concurrent commands. Zero disables the limit.
+//
+// Returns:
+//   - error: An error if n is negative.
+func (cli *OpCLI) SetMaxConcurrency(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid concurrency limit: %d", n)
+	}
+
+	cli.pool.mu.Lock()
+	defer cli.pool.mu.Unlock()
+
+	cli.pool.limit = n
+	cli.pool.dispatch()
+	return nil
+}
+
+// poolSlotKey is the context key of commands that already hold a slot of the
+// pool, see withPoolSlot.
+type poolSlotKey struct{}
+
+// withPoolSlot returns a context for commands whose slot was acquired by the
+// caller, so acquire does not wait for a second one.
+func withPoolSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, poolSlotKey{}, true)
+}
+
+// acquire waits for a free slot and returns a function that releases it.
+// Waiting commands get a slot in the order they called acquire.
+func (p *commandPool) acquire(ctx context.Context) (func(), error) {
+	if ctx.Value(poolSlotKey{}) != nil {
+		return func() {}, nil
+	}
+
+	p.mu.Lock()
+	if p.limit == 0 {
+		p.mu.Unlock()
+		return func() {}, nil
+	}
+	if len(p.waiting) == 0 && p.running < p.limit {
+		p.running++
+		p.mu.Unlock()
+		return p.release, nil
+	}
+
+	ready := make(chan struct{})
+	p.waiting = append(p.waiting, ready)
+	p.mu.Unlock()
+
+	select {
+	case <-ready:
+		return p.release, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		if i := slices.Index(p.waiting, ready); i >= 0 {
+			p.waiting = slices.Delete(p.waiting, i, i+1)
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		p.mu.Unlock()
+
+		// The slot was handed over while the context ended, pass it on
+		p.release()
+		return nil, ctx.Err()
+	}
+}
+
+// release frees a slot and hands it to the first waiting command.
+func (p *commandPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running--
+	p.dispatch()
+}
+
+// dispatch starts waiting commands while slots are free. The caller must
+// hold p.mu.
+func (p *commandPool) dispatch() {
+	for len(p.waiting) > 0 && (p.limit == 0 || p.running < p.limit) {
+		p.running++
+		close(p.waiting[0])
+		p.waiting = p.waiting[1:]
+	}
+}
@@ -0,0 +1,198 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		commands    int
+		expectedMax int32
+	}{
+		{name: "Limited", limit: 2, commands: 6, expectedMax: 2},
+		{name: "Single", limit: 1, commands: 3, expectedMax: 1},
+		{name: "Unlimited", limit: 0, commands: 4, expectedMax: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			var started sync.WaitGroup
+			started.Add(tt.commands)
+			release := make(chan struct{})
+
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			if err := cli.SetMaxConcurrency(tt.limit); err != nil {
+				t.Fatalf("SetMaxConcurrency() error = %v", err)
+			}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				n := running.Add(1)
+				for {
+					current := maxRunning.Load()
+					if n <= current || maxRunning.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				if tt.limit == 0 {
+					// Wait until all commands run at once to observe the maximum
+					started.Done()
+					started.Wait()
+				}
+				<-release
+				running.Add(-1)
+				return []byte(`[]`), nil, nil
+			}))
+
+			var wg sync.WaitGroup
+			for range tt.commands {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					cli.ExecuteOpCommand(context.Background(), "vault", "list")
+				}()
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if got := maxRunning.Load(); got != tt.expectedMax {
+				t.Errorf("max concurrent commands = %d; want %d", got, tt.expectedMax)
+			}
+		})
+	}
+}
+
+func TestCommandPoolCancel(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	if err := cli.SetMaxConcurrency(1); err != nil {
+		t.Fatalf("SetMaxConcurrency() error = %v", err)
+	}
+
+	release, err := cli.pool.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := cli.ExecuteOpCommand(ctx, "vault", "list"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ExecuteOpCommand() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if len(cli.pool.waiting) != 0 {
+		t.Errorf("waiting commands after cancel = %d; want 0", len(cli.pool.waiting))
+	}
+}
+
+func TestCommandPoolOrder(t *testing.T) {
+	cli := &OpCLI{}
+	if err := cli.SetMaxConcurrency(1); err != nil {
+		t.Fatalf("SetMaxConcurrency() error = %v", err)
+	}
+
+	release, err := cli.pool.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := cli.pool.acquire(context.Background())
+			if err != nil {
+				t.Errorf("acquire() error = %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			release()
+		}()
+
+		// Wait until the command is queued before the next one arrives
+		for {
+			cli.pool.mu.Lock()
+			queued := len(cli.pool.waiting)
+			cli.pool.mu.Unlock()
+			if queued == i+1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	release()
+	wg.Wait()
+
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(order, want) {
+		t.Errorf("commands started in order %v; want %v", order, want)
+	}
+	if cli.pool.running != 0 {
+		t.Errorf("running commands after release = %d; want 0", cli.pool.running)
+	}
+}
+
+func TestCommandPoolNestedInListing(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	if err := cli.SetMaxConcurrency(1); err != nil {
+		t.Fatalf("SetMaxConcurrency() error = %v", err)
+	}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		if cmd.Args[1] == "list" {
+			// Write the elements separately, so the listing is still running
+			// while the first one is yielded
+			for _, chunk := range []string{`[{"id":"a","title":"A"},`, `{"id":"b","title":"B"}]`} {
+				if _, err := cmd.Stdout.Write([]byte(chunk)); err != nil {
+					return nil, nil, err
+				}
+			}
+			return nil, nil, nil
+		}
+		return []byte(`{"id":"` + cmd.Args[2] + `","title":"Details"}`), nil, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var titles []string
+	for item, err := range cli.AllItems(ctx) {
+		if err != nil {
+			t.Fatalf("AllItems() error = %v", err)
+		}
+		// The listing holds no slot while the loop body runs
+		details, err := cli.GetItemByID(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("GetItemByID() in the loop body error = %v", err)
+		}
+		titles = append(titles, details.Title)
+	}
+	if want := []string{"Details", "Details"}; !slices.Equal(titles, want) {
+		t.Errorf("titles = %q; want %q", titles, want)
+	}
+
+	err := cli.StreamItems(ctx, func(item Item) error {
+		_, err := cli.GetItemByID(ctx, item.ID)
+		return err
+	})
+	if err != nil {
+		t.Errorf("StreamItems() with a nested command error = %v", err)
+	}
+
+	if cli.pool.running != 0 {
+		t.Errorf("running commands after the listings = %d; want 0", cli.pool.running)
+	}
+}
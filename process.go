@@ -0,0 +1,64 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned for commands of an OpCLI instance after Close.
+var ErrClosed = errors.New("op cli is closed")
+
+// defaultGracePeriod is the time a cancelled op process has to exit after it
+// received SIGTERM before it is killed.
+const defaultGracePeriod = 5 * time.Second
+
+// runningCommands tracks the commands of an OpCLI instance that are running,
+// so Close can cancel them and wait until their processes have exited.
+type runningCommands struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed bool
+}
+
+// start registers a command. The returned context is cancelled with the
+// cause ErrClosed when the OpCLI instance is closed, and the returned
+// function must be called when the command has finished.
+func (r *runningCommands) start(ctx context.Context) (context.Context, func(), error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, nil, ErrClosed
+	}
+	if r.ctx == nil {
+		r.ctx, r.cancel = context.WithCancel(context.Background())
+	}
+	closing := r.ctx
+	r.wg.Add(1)
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(closing, func() { cancel(ErrClosed) })
+
+	return ctx, func() {
+		stop()
+		cancel(nil)
+		r.wg.Done()
+	}, nil
+}
+
+// close cancels all running commands, rejects new ones and waits until the
+// running commands have finished.
+func (r *runningCommands) close() {
+	r.mu.Lock()
+	r.closed = true
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
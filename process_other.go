@@ -0,0 +1,19 @@
+//go:build !unix
+
+package onepassword
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup does nothing on platforms without process groups.
+func setProcessGroup(cmd *exec.Cmd, interactive bool) {}
+
+// terminateProcess kills the process, as platforms other than Unix have no
+// signal to ask a process to exit.
+func terminateProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killProcessGroup does nothing on platforms without process groups.
+func killProcessGroup(cmd *exec.Cmd) {}
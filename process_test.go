@@ -0,0 +1,104 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecCommandExecutorCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM is not supported on Windows")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "Exits on SIGTERM", script: `trap 'exit 143' TERM; sleep 30 & wait`},
+		{name: "Ignores SIGTERM", script: `trap '' TERM; sleep 30`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			executor := ExecCommandExecutor{GracePeriod: 200 * time.Millisecond}
+			_, _, err := executor.Execute(ctx, &Command{Path: sh, Args: []string{"-c", tt.script}})
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Execute() error = %v; want %v", err, context.DeadlineExceeded)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Execute() returned after %v; want the process to be stopped", elapsed)
+			}
+		})
+	}
+}
+
+func TestExecCommandExecutorInteractive(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM is not supported on Windows")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	if !isInteractive(&Command{Stdin: os.Stdin}) || isInteractive(&Command{Stdin: strings.NewReader("input")}) {
+		t.Error("isInteractive() does not detect commands reading the standard input of the process")
+	}
+
+	// Interactive commands keep the process group of the terminal, but are
+	// still stopped when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	executor := ExecCommandExecutor{GracePeriod: 200 * time.Millisecond}
+	_, _, err = executor.Execute(ctx, &Command{Path: sh, Args: []string{"-c", "exec sleep 30"}, Stdin: os.Stdin})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Execute() returned after %v; want the process to be stopped", elapsed)
+	}
+}
+
+func TestClose(t *testing.T) {
+	started := make(chan struct{})
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, nil, context.Cause(ctx)
+	}))
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := cli.ExecuteOpCommand(context.Background(), "vault", "list")
+		errc <- err
+	}()
+
+	<-started
+	if err := cli.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := <-errc; !errors.Is(err, ErrClosed) {
+		t.Errorf("running command error = %v; want %v", err, ErrClosed)
+	}
+	if _, err := cli.ExecuteOpCommand(context.Background(), "vault", "list"); !errors.Is(err, ErrClosed) {
+		t.Errorf("command after Close error = %v; want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package onepassword
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group, so it can be
+// signalled together with the child processes it spawns. Interactive
+// commands stay in the process group of the terminal, as a background
+// process group that reads from the terminal is stopped with SIGTTIN.
+func setProcessGroup(cmd *exec.Cmd, interactive bool) {
+	if interactive {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// hasProcessGroup reports whether the command runs in its own process group.
+func hasProcessGroup(cmd *exec.Cmd) bool {
+	return cmd.SysProcAttr != nil && cmd.SysProcAttr.Setpgid
+}
+
+// terminateProcess asks the process group of the command to exit, or only
+// the process if it has no process group of its own.
+func terminateProcess(cmd *exec.Cmd) error {
+	if !hasProcessGroup(cmd) {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// killProcessGroup kills the remaining processes of the process group of the
+// command, e.g. children that outlived the op process.
+func killProcessGroup(cmd *exec.Cmd) {
+	if hasProcessGroup(cmd) {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,114 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that limits how often commands are executed.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time to refill a single token
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// newRateLimiter creates a rateLimiter that allows requests per period with
+// bursts of up to burst requests.
+func newRateLimiter(requests int, per time.Duration, burst int) *rateLimiter {
+	return &rateLimiter{
+		interval: per / time.Duration(requests),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller has
+// to wait before the token is available.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// available returns the number of tokens in the bucket at the given time
+// without taking one.
+func (l *rateLimiter) available(now time.Time) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+}
+
+// cancel returns a token that was reserved but not used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// Wait blocks until a request is allowed or the context is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetRateLimit limits how many commands ExecuteOpCommand runs, independent of
+// the rate limits of service accounts. Commands that exceed the limit wait
+// until they are allowed or their context is done.
+//
+// Parameters:
+//   - requests: The number of commands allowed per period. Zero disables the limit.
+//   - per: The period, e.g. time.Second or time.Minute.
+//   - burst: The number of commands that may run at once before the limit applies.
+//     If less than 1, a burst of 1 is used.
+//
+// Returns:
+//   - error: An error if the limit is invalid.
+func (cli *OpCLI) SetRateLimit(requests int, per time.Duration, burst int) error {
+	if requests == 0 {
+		cli.rateLimiter = nil
+		return nil
+	}
+	if requests < 0 || per <= 0 {
+		return fmt.Errorf("invalid rate limit: %d per %v", requests, per)
+	}
+
+	cli.rateLimiter = newRateLimiter(requests, per, max(burst, 1))
+	return nil
+}
+
+// waitForRateLimit blocks until the rate limit of the OpCLI instance allows
+// another command.
+func (cli *OpCLI) waitForRateLimit(ctx context.Context) error {
+	if cli.rateLimiter == nil {
+		return nil
+	}
+	return cli.rateLimiter.Wait(ctx)
+}
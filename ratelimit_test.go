@@ -0,0 +1,29 @@
+package onepassword
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := newRateLimiter(10, time.Second, 2)
+	now := limiter.last
+
+	tests := []struct {
+		name     string
+		at       time.Duration
+		expected time.Duration
+	}{
+		{name: "First request of burst", at: 0, expected: 0},
+		{name: "Second request of burst", at: 0, expected: 0},
+		{name: "Exceeds burst", at: 0, expected: 100 * time.Millisecond},
+		{name: "Queued behind previous request", at: 0, expected: 200 * time.Millisecond},
+		{name: "After refill", at: time.Second, expected: 0},
+	}
+
+	for _, tt := range tests {
+		if got := limiter.reserve(now.Add(tt.at)); got != tt.expected {
+			t.Errorf("%s: reserve() = %v; want %v", tt.name, got, tt.expected)
+		}
+	}
+}
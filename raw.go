@@ -0,0 +1,81 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExecOptions configures a command run with ExecuteRaw.
+//
+// Fields:
+//   - Stdin: The standard input of the command, e.g. an item template. It is
+//     read completely before the command starts.
+//   - Stdout: An optional writer that receives the standard output as it is written.
+//     If set, ExecuteRaw returns no output.
+//   - Stderr: An optional writer that receives the standard error as it is written.
+//   - Env: Additional environment variables of the form "KEY=value".
+//   - Dir: The working directory. If empty, the working directory of the current process is used.
+//   - Format: The output format passed with --format, e.g. "human". Defaults to "json".
+//   - NoDefaultArgs: Do not append --account and --format to the arguments. The
+//     command then also runs without an active account.
+type ExecOptions struct {
+	Stdin         io.Reader
+	Stdout        io.Writer
+	Stderr        io.Writer
+	Env           []string
+	Dir           string
+	Format        string
+	NoDefaultArgs bool
+}
+
+// ExecuteRaw runs an arbitrary command of the 1Password CLI, e.g. a
+// subcommand that has no typed wrapper in this package yet. Like
+// ExecuteOpCommand, the command runs with the session, hooks, rate limit and
+// timeout of the OpCLI instance, but the options control its input, output,
+// environment and default arguments.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: The options of the command.
+//   - args: The arguments passed to the 1Password CLI.
+//
+// Returns:
+//   - []byte: The standard output of the command, unless opts.Stdout is set.
+//   - error: An error if an option is invalid, account information is missing,
+//     or the command fails.
+//
+// Example usage:
+//
+//	output, err := cli.ExecuteRaw(ctx, onepassword.ExecOptions{Format: "human"}, "vault", "list")
+//	if err != nil {
+//	    log.Fatalf("Command failed: %v", err)
+//	}
+//	fmt.Println(string(output))
+func (cli *OpCLI) ExecuteRaw(ctx context.Context, opts ExecOptions, args ...string) ([]byte, error) {
+	for _, entry := range opts.Env {
+		if key, _, ok := strings.Cut(entry, "="); !ok || key == "" {
+			return nil, fmt.Errorf("invalid environment variable: %q", entry)
+		}
+	}
+
+	settings := execSettings{
+		dir:           opts.Dir,
+		stdout:        opts.Stdout,
+		stderr:        opts.Stderr,
+		env:           opts.Env,
+		format:        opts.Format,
+		noDefaultArgs: opts.NoDefaultArgs,
+	}
+
+	if opts.Stdin != nil {
+		stdin, err := io.ReadAll(opts.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		settings.stdin = stdin
+	}
+
+	return cli.executeOpCommandWith(ctx, settings, args...)
+}
@@ -0,0 +1,96 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExecuteRaw(t *testing.T) {
+	tests := []struct {
+		name         string
+		account      *Account
+		opts         ExecOptions
+		expectedArgs []string
+		expectedErr  error
+	}{
+		{
+			name:         "Default arguments",
+			account:      &Account{UserUUID: "user-uuid"},
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=json"},
+		},
+		{
+			name:         "Custom format",
+			account:      &Account{UserUUID: "user-uuid"},
+			opts:         ExecOptions{Format: "human"},
+			expectedArgs: []string{"vault", "list", "--account", "user-uuid", "--format=human"},
+		},
+		{
+			name:         "Without default arguments or account",
+			opts:         ExecOptions{NoDefaultArgs: true},
+			expectedArgs: []string{"vault", "list"},
+		},
+		{
+			name:        "Missing account",
+			expectedErr: ErrMissingAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			cli := &OpCLI{Path: "op", Account: tt.account}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				gotArgs = cmd.Args
+				return []byte("ok"), nil, nil
+			}))
+
+			output, err := cli.ExecuteRaw(context.Background(), tt.opts, "vault", "list")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ExecuteRaw() error = %v; want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			if string(output) != "ok" || !slices.Equal(gotArgs, tt.expectedArgs) {
+				t.Errorf("ExecuteRaw() = %q with args %q; want %q with args %q", output, gotArgs, "ok", tt.expectedArgs)
+			}
+		})
+	}
+}
+
+func TestExecuteRawOptions(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		stdin, _ := io.ReadAll(cmd.Stdin)
+		if !slices.Contains(cmd.Env, "OP_CACHE=false") || cmd.Dir != "/tmp" {
+			t.Errorf("command env = %q, dir = %q; want OP_CACHE=false in /tmp", cmd.Env, cmd.Dir)
+		}
+		cmd.Stdout.Write(stdin)
+		cmd.Stderr.Write([]byte("warning"))
+		return nil, []byte("warning"), nil
+	}))
+
+	var stdout, stderr bytes.Buffer
+	_, err := cli.ExecuteRaw(context.Background(), ExecOptions{
+		Stdin:  strings.NewReader(`{"title":"Raw"}`),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Env:    []string{"OP_CACHE=false"},
+		Dir:    "/tmp",
+	}, "item", "create")
+	if err != nil {
+		t.Fatalf("ExecuteRaw() error = %v", err)
+	}
+	if stdout.String() != `{"title":"Raw"}` || stderr.String() != "warning" {
+		t.Errorf("stdout = %q, stderr = %q; want the input and a warning", stdout.String(), stderr.String())
+	}
+
+	if _, err := cli.ExecuteRaw(context.Background(), ExecOptions{Env: []string{"INVALID"}}, "vault", "list"); err == nil {
+		t.Errorf("ExecuteRaw() with invalid environment succeeded; want an error")
+	}
+}
@@ -0,0 +1,302 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNoRecording is returned by a Replayer for commands that are not part of the recording.
+var ErrNoRecording = errors.New("no recorded response for command")
+
+// Interaction is a single command and its response stored in a recording.
+//
+// Fields:
+//   - Args: The arguments of the command, without the account flag.
+//   - Stdout: The standard output with secret values redacted.
+//   - Stderr: The standard error.
+//   - ExitCode: The exit code of the command, or -1 if it did not exit.
+type Interaction struct {
+	Args     []string `json:"args"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr,omitempty"`
+	ExitCode int      `json:"exitCode"`
+}
+
+// recording is the content of a fixture file.
+type recording struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is a CommandExecutor that runs commands with another executor and
+// records them with their responses, so they can be served by a Replayer in
+// tests without the 1Password CLI or credentials. Secret values in item
+// fields and whole outputs of secrets like "read" or "signin --raw" are
+// redacted, and environment variables including session tokens are never
+// recorded.
+type Recorder struct {
+	path     string
+	executor CommandExecutor
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder creates a Recorder that writes its recording to path when Save is called.
+//
+// Parameters:
+//   - path: The path of the fixture file.
+//   - executor: The executor that runs the commands. If nil, commands are run as subprocesses.
+//
+// Returns:
+//   - *Recorder: The recording executor.
+//
+// Example usage:
+//
+//	recorder := onepassword.NewRecorder("testdata/vaults.json", nil)
+//	cli, err := onepassword.NewOpCLI(onepassword.WithCommandExecutor(recorder))
+//	// ... run commands ...
+//	err = recorder.Save()
+func NewRecorder(path string, executor CommandExecutor) *Recorder {
+	if executor == nil {
+		executor = ExecCommandExecutor{}
+	}
+	return &Recorder{path: path, executor: executor}
+}
+
+// Execute runs the command and records it.
+func (r *Recorder) Execute(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+	// Capture streamed output in addition to passing it on
+	var streamed bytes.Buffer
+	if cmd.Stdout != nil {
+		teeCmd := *cmd
+		teeCmd.Stdout = io.MultiWriter(cmd.Stdout, &streamed)
+		cmd = &teeCmd
+	}
+
+	stdout, stderr, err := r.executor.Execute(ctx, cmd)
+	recordedStdout := stdout
+	if streamed.Len() > 0 {
+		recordedStdout = streamed.Bytes()
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitCode = (&OpCliError{Err: err}).ExitCode()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Args:     recordedArgs(cmd.Args),
+		Stdout:   string(recordedOutput(cmd.Args, recordedStdout)),
+		Stderr:   string(stderr),
+		ExitCode: exitCode,
+	})
+
+	return stdout, stderr, err
+}
+
+// Save writes the recorded interactions to the fixture file.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(recording{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// Replayer is a CommandExecutor that serves responses from a recording
+// instead of running the 1Password CLI. Commands are matched by their
+// arguments. If a command was recorded multiple times, the responses are
+// served in the recorded order and the last one is repeated.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	served       []bool
+}
+
+// NewReplayer creates a Replayer from a fixture file written by a Recorder.
+//
+// Parameters:
+//   - path: The path of the fixture file.
+//
+// Returns:
+//   - *Replayer: The replaying executor.
+//   - error: An error if the file cannot be read or parsed.
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse recording: %w", err)
+	}
+
+	return &Replayer{
+		interactions: rec.Interactions,
+		served:       make([]bool, len(rec.Interactions)),
+	}, nil
+}
+
+// Execute returns the recorded response of the command.
+func (r *Replayer) Execute(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	args := recordedArgs(cmd.Args)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.interactions {
+		if !slices.Equal(interaction.Args, args) {
+			continue
+		}
+		match = i
+		if !r.served[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoRecording, strings.Join(args, " "))
+	}
+	r.served[match] = true
+
+	interaction := r.interactions[match]
+	var err error
+	if interaction.ExitCode != 0 {
+		err = &replayedExitError{code: interaction.ExitCode}
+	}
+
+	return []byte(interaction.Stdout), []byte(interaction.Stderr), err
+}
+
+// replayedExitError is the error of a recorded command that failed.
+type replayedExitError struct {
+	code int
+}
+
+func (e *replayedExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// ExitCode returns the recorded exit code.
+func (e *replayedExitError) ExitCode() int {
+	return e.code
+}
+
+// recordedArgs returns the arguments used to match recorded commands. The
+// account flag is removed so recordings can be replayed for any account, and
+// values of field assignments are redacted.
+func recordedArgs(args []string) []string {
+	recorded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--account":
+			i++
+			continue
+		case strings.HasPrefix(arg, "--account="):
+			continue
+		case !strings.HasPrefix(arg, "-") && strings.Contains(arg, "="):
+			// Field assignments like "password=secret" of item create and edit
+			name, _, _ := strings.Cut(arg, "=")
+			arg = name + "=" + redactedArg
+		}
+		recorded = append(recorded, arg)
+	}
+	return recorded
+}
+
+// rawSecretCommands are the commands whose whole output is a secret: raw
+// field values and documents, session tokens and bearer tokens.
+var rawSecretCommands = []string{"document get", "events-api create", "read", "signin"}
+
+// recordedOutput returns the output of a command as it is recorded. The
+// outputs of commands in rawSecretCommands and of commands run with --raw,
+// e.g. "account add --signin --raw", are replaced entirely.
+func recordedOutput(args []string, output []byte) []byte {
+	if len(output) > 0 && (slices.Contains(rawSecretCommands, commandName(args)) || slices.Contains(args, "--raw")) {
+		return []byte(redactedArg)
+	}
+	return redactOutput(output)
+}
+
+// redactOutput replaces the values of concealed, one-time password and SSH
+// key fields in JSON output. Output that is not JSON is returned unchanged.
+func redactOutput(output []byte) []byte {
+	var value any
+	if err := json.Unmarshal(output, &value); err != nil {
+		return output
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return output
+	}
+	return redacted
+}
+
+// redactValue redacts secret field values in a decoded JSON value.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if isSecretField(v) {
+			if _, ok := v["value"]; ok {
+				v["value"] = redactedArg
+			}
+			delete(v, "totp")
+		}
+		if v["type"] == string(FieldTypeSSHKey) {
+			// The private key in other formats, e.g. "openssh"
+			if formats, ok := v["ssh_formats"].(map[string]any); ok {
+				for _, format := range formats {
+					if format, ok := format.(map[string]any); ok {
+						if _, ok := format["value"]; ok {
+							format["value"] = redactedArg
+						}
+					}
+				}
+			}
+		}
+		for key, element := range v {
+			v[key] = redactValue(element)
+		}
+	case []any:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	}
+	return value
+}
+
+// isSecretField reports whether a decoded JSON object is a field with a secret value.
+func isSecretField(field map[string]any) bool {
+	switch field["type"] {
+	case string(FieldTypeConcealed), string(FieldTypeOTP), string(FieldTypeSSHKey):
+		return true
+	}
+	return field["purpose"] == string(FieldPurposePassword)
+}
@@ -0,0 +1,272 @@
+// Package reference parses, builds and resolves 1Password secret
+// references of the form
+//
+//	op://<vault>/<item>[/<section>]/<field>[?attribute=<attribute>]
+//
+// Names are case-insensitive and may contain spaces. Because "/" separates
+// the parts of a reference and "?" starts the query, a name containing "/",
+// "?" or "%" is percent-encoded by String and decoded by Parse:
+//
+//	ref := reference.New("Private", "AC/DC", "password")
+//	ref.String() // "op://Private/AC%2FDC/password"
+//
+// The 1Password CLI itself does not decode escaped names, so Resolve looks
+// such references up by name instead of passing them to "op read".
+package reference
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+// Scheme is the prefix of secret references.
+const Scheme = "op://"
+
+// AttributeQuery is the query parameter that selects an attribute of a field.
+const AttributeQuery = "attribute"
+
+// escaped lists the characters that are percent-encoded in names.
+const escaped = "%/?"
+
+// Reference is a parsed secret reference.
+//
+// Fields:
+//   - Vault: The name or ID of the vault.
+//   - Item: The title or ID of the item.
+//   - Section: The label or ID of the section. Empty for fields outside of sections.
+//   - Field: The label or ID of the field.
+//   - Query: The query parameters, e.g. attribute=otp or ssh-format=openssh.
+type Reference struct {
+	Vault   string
+	Item    string
+	Section string
+	Field   string
+	Query   url.Values
+}
+
+// New builds a reference to a field that is not in a section.
+//
+// Parameters:
+//   - vault: The name or ID of the vault.
+//   - item: The title or ID of the item.
+//   - field: The label or ID of the field.
+//
+// Returns:
+//   - Reference: The reference.
+func New(vault, item, field string) Reference {
+	return Reference{Vault: vault, Item: item, Field: field}
+}
+
+// NewInSection builds a reference to a field in a section.
+//
+// Parameters:
+//   - vault: The name or ID of the vault.
+//   - item: The title or ID of the item.
+//   - section: The label or ID of the section.
+//   - field: The label or ID of the field.
+//
+// Returns:
+//   - Reference: The reference.
+func NewInSection(vault, item, section, field string) Reference {
+	return Reference{Vault: vault, Item: item, Section: section, Field: field}
+}
+
+// FromField builds a reference to a field of an item. Vault, item and
+// section are referenced by ID, the field by ID or label, so the reference
+// stays valid when they are renamed.
+//
+// Parameters:
+//   - item: The item. Its vault must have an ID.
+//   - field: The field of the item.
+//
+// Returns:
+//   - Reference: The reference.
+func FromField(item onepassword.Item, field onepassword.Field) Reference {
+	ref := Reference{Vault: item.Vault.ID, Item: item.ID, Field: field.ID}
+	if ref.Field == "" {
+		ref.Field = field.Label
+	}
+	if field.Section != nil {
+		ref.Section = field.Section.ID
+	}
+	return ref
+}
+
+// Parse parses a secret reference.
+//
+// Parameters:
+//   - ref: The reference, e.g. "op://Private/Database/password".
+//
+// Returns:
+//   - Reference: The parsed reference.
+//   - error: An error matching onepassword.ErrInvalidReference if the reference is malformed.
+//
+// Example usage:
+//
+//	ref, err := reference.Parse("op://Private/Database/password?attribute=otp")
+//	if err != nil {
+//	    log.Fatalf("Failed to parse reference: %v", err)
+//	}
+//	fmt.Println(ref.Item, ref.Attribute())
+func Parse(ref string) (Reference, error) {
+	rest, ok := strings.CutPrefix(ref, Scheme)
+	if !ok {
+		return Reference{}, fmt.Errorf("%w: %q must start with %s", onepassword.ErrInvalidReference, ref, Scheme)
+	}
+
+	var parsed Reference
+	path, query, hasQuery := strings.Cut(rest, "?")
+	if hasQuery {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return Reference{}, fmt.Errorf("%w: %q has an invalid query: %v", onepassword.ErrInvalidReference, ref, err)
+		}
+		parsed.Query = values
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Reference{}, fmt.Errorf("%w: %q must have the form %svault/item/[section/]field", onepassword.ErrInvalidReference, ref, Scheme)
+	}
+	for i, part := range parts {
+		if part == "" {
+			return Reference{}, fmt.Errorf("%w: %q has an empty part", onepassword.ErrInvalidReference, ref)
+		}
+		parts[i] = unescape(part)
+	}
+
+	parsed.Vault, parsed.Item, parsed.Field = parts[0], parts[1], parts[len(parts)-1]
+	if len(parts) == 4 {
+		parsed.Section = parts[2]
+	}
+	return parsed, nil
+}
+
+// Validate checks that the vault, item and field of the reference are set.
+//
+// Returns:
+//   - error: An error matching onepassword.ErrInvalidReference if a part is missing.
+func (r Reference) Validate() error {
+	switch {
+	case r.Vault == "":
+		return fmt.Errorf("%w: vault is missing", onepassword.ErrInvalidReference)
+	case r.Item == "":
+		return fmt.Errorf("%w: item is missing", onepassword.ErrInvalidReference)
+	case r.Field == "":
+		return fmt.Errorf("%w: field is missing", onepassword.ErrInvalidReference)
+	}
+	return nil
+}
+
+// String returns the reference in the op:// format, with names containing
+// "/", "?" or "%" percent-encoded.
+func (r Reference) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme)
+	b.WriteString(escape(r.Vault))
+	b.WriteString("/")
+	b.WriteString(escape(r.Item))
+	if r.Section != "" {
+		b.WriteString("/")
+		b.WriteString(escape(r.Section))
+	}
+	b.WriteString("/")
+	b.WriteString(escape(r.Field))
+	if len(r.Query) > 0 {
+		b.WriteString("?")
+		b.WriteString(r.Query.Encode())
+	}
+	return b.String()
+}
+
+// Attribute returns the attribute selected by the reference, e.g. "otp", or
+// an empty string for the value of the field.
+func (r Reference) Attribute() string {
+	return r.Query.Get(AttributeQuery)
+}
+
+// WithAttribute returns a copy of the reference that selects an attribute
+// of the field.
+//
+// Parameters:
+//   - attribute: The attribute, e.g. "otp" or "type".
+//
+// Returns:
+//   - Reference: The reference with the attribute.
+func (r Reference) WithAttribute(attribute string) Reference {
+	query := make(url.Values, len(r.Query)+1)
+	for key, values := range r.Query {
+		query[key] = append([]string(nil), values...)
+	}
+	query.Set(AttributeQuery, attribute)
+	r.Query = query
+	return r
+}
+
+// IsEscaped reports whether a name of the reference contains characters
+// that String percent-encodes. Such references cannot be read with "op read".
+func (r Reference) IsEscaped() bool {
+	for _, name := range []string{r.Vault, r.Item, r.Section, r.Field} {
+		if strings.ContainsAny(name, escaped) {
+			return true
+		}
+	}
+	return false
+}
+
+// escape percent-encodes the characters of a name that have a meaning in references.
+func escape(name string) string {
+	if !strings.ContainsAny(name, escaped) {
+		return name
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if strings.IndexByte(escaped, name[i]) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", name[i])
+			continue
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// unescape decodes percent-encoded characters of a name. A "%" that is not
+// followed by two hex digits is kept, so hand-written names like "100% Off"
+// are parsed as written.
+func unescape(name string) string {
+	if !strings.Contains(name, "%") {
+		return name
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]) {
+			b.WriteByte(unhex(name[i+1])<<4 | unhex(name[i+2]))
+			i += 2
+			continue
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// isHex reports whether c is a hex digit.
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unhex returns the value of a hex digit.
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
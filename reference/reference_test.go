@@ -0,0 +1,182 @@
+package reference
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+	"github.com/sthayduk/onepassword-cli-go/onepasswordtest"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected Reference
+		wantErr  bool
+	}{
+		{
+			name:     "Field",
+			ref:      "op://Private/Database/password",
+			expected: New("Private", "Database", "password"),
+		},
+		{
+			name:     "Field in section",
+			ref:      "op://Private/Database/admin/password",
+			expected: NewInSection("Private", "Database", "admin", "password"),
+		},
+		{
+			name:     "Spaces",
+			ref:      "op://Shared Vault/My Database/one-time password",
+			expected: New("Shared Vault", "My Database", "one-time password"),
+		},
+		{
+			name:     "Escaped slash",
+			ref:      "op://Private/AC%2FDC/password",
+			expected: New("Private", "AC/DC", "password"),
+		},
+		{
+			name:     "Escaped question mark and percent",
+			ref:      "op://Private/Why%3F/100%25",
+			expected: New("Private", "Why?", "100%"),
+		},
+		{
+			name:     "Literal percent",
+			ref:      "op://Private/100% Off/code",
+			expected: New("Private", "100% Off", "code"),
+		},
+		{
+			name: "Attribute",
+			ref:  "op://Private/Database/one-time password?attribute=otp",
+			expected: Reference{Vault: "Private", Item: "Database", Field: "one-time password",
+				Query: url.Values{"attribute": {"otp"}}},
+		},
+		{name: "Missing scheme", ref: "Private/Database/password", wantErr: true},
+		{name: "Too few parts", ref: "op://Private/Database", wantErr: true},
+		{name: "Too many parts", ref: "op://Private/AC/DC/admin/password", wantErr: true},
+		{name: "Empty part", ref: "op://Private//password", wantErr: true},
+		{name: "Invalid query", ref: "op://Private/Database/password?attribute=%zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v; wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, onepassword.ErrInvalidReference) {
+					t.Errorf("Parse(%q) error = %v; want ErrInvalidReference", tt.ref, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Parse(%q) = %+v; want %+v", tt.ref, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      Reference
+		expected string
+	}{
+		{name: "Field", ref: New("Private", "Database", "password"), expected: "op://Private/Database/password"},
+		{name: "Section", ref: NewInSection("Private", "Database", "admin", "password"), expected: "op://Private/Database/admin/password"},
+		{name: "Spaces", ref: New("Shared Vault", "My Database", "password"), expected: "op://Shared Vault/My Database/password"},
+		{name: "Slash", ref: New("Private", "AC/DC", "password"), expected: "op://Private/AC%2FDC/password"},
+		{name: "Question mark and percent", ref: New("Private", "Why?", "100%"), expected: "op://Private/Why%3F/100%25"},
+		{name: "Attribute", ref: New("Private", "Database", "otp").WithAttribute("otp"), expected: "op://Private/Database/otp?attribute=otp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.ref.String()
+			if got != tt.expected {
+				t.Errorf("String() = %q; want %q", got, tt.expected)
+			}
+
+			parsed, err := Parse(got)
+			if err != nil || !reflect.DeepEqual(parsed, tt.ref) {
+				t.Errorf("Parse(String()) = %+v, %v; want %+v", parsed, err, tt.ref)
+			}
+		})
+	}
+}
+
+func TestFromField(t *testing.T) {
+	section := onepassword.Section{ID: "admin", Label: "Admin"}
+	item := onepassword.Item{ID: "item-id", Title: "Database", Vault: onepassword.Vault{ID: "vault-id", Name: "Private"}}
+
+	tests := []struct {
+		name     string
+		field    onepassword.Field
+		expected string
+	}{
+		{name: "Field ID", field: onepassword.Field{ID: "password", Label: "Password"}, expected: "op://vault-id/item-id/password"},
+		{name: "Label without ID", field: onepassword.Field{Label: "Password"}, expected: "op://vault-id/item-id/Password"},
+		{name: "Section", field: onepassword.Field{ID: "pw", Section: &section}, expected: "op://vault-id/item-id/admin/pw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromField(item, tt.field).String(); got != tt.expected {
+				t.Errorf("FromField() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     Reference
+		wantErr bool
+	}{
+		{name: "Complete", ref: New("Private", "Database", "password")},
+		{name: "Missing vault", ref: New("", "Database", "password"), wantErr: true},
+		{name: "Missing item", ref: New("Private", "", "password"), wantErr: true},
+		{name: "Missing field", ref: New("Private", "Database", ""), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ref.Validate()
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, onepassword.ErrInvalidReference)) {
+				t.Errorf("Validate() error = %v; wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newTestFake returns a fake with a vault of items whose titles need escaping.
+func newTestFake(t *testing.T) *onepasswordtest.Fake {
+	t.Helper()
+
+	fake := onepasswordtest.New()
+	vault := fake.AddVault("Private")
+	admin := onepassword.Section{ID: "admin", Label: "Admin"}
+	for _, item := range []onepassword.Item{
+		{
+			Title:    "AC/DC",
+			Category: onepassword.CategoryLogin,
+			Vault:    vault,
+			Sections: []onepassword.Section{admin},
+			Fields: []onepassword.Field{
+				{ID: "password", Label: "password", Type: onepassword.FieldTypeConcealed, Value: "highway"},
+				{ID: "admin-password", Label: "password", Type: onepassword.FieldTypeConcealed, Section: &admin, Value: "thunder"},
+				{ID: "pin", Label: "PIN", Type: onepassword.FieldTypeConcealed, Section: &admin, Value: "1975"},
+			},
+		},
+		{Title: "Database", Category: onepassword.CategoryLogin, Vault: vault},
+	} {
+		if _, err := fake.AddItem(item); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+	return fake
+}
@@ -0,0 +1,86 @@
+package reference
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+// Resolve reads the value of the field the reference points to. References
+// whose names can be passed to the 1Password CLI are read with "op read";
+// references with escaped names are resolved by looking up the item and
+// its field, which supports the value of the field only.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - cli: The signed-in OpCLI instance.
+//
+// Returns:
+//   - string: The value of the field, or of the selected attribute.
+//   - error: An error matching onepassword.ErrInvalidReference if the reference is incomplete,
+//     onepassword.ErrFieldNotFound or onepassword.ErrMoreThanOneMatch if the field cannot be
+//     identified, or an error if the item cannot be read.
+//
+// Example usage:
+//
+//	ref, err := reference.Parse(os.Getenv("DB_PASSWORD_REF"))
+//	if err != nil {
+//	    log.Fatalf("Failed to parse reference: %v", err)
+//	}
+//	password, err := ref.Resolve(ctx, cli)
+func (r Reference) Resolve(ctx context.Context, cli *onepassword.OpCLI) (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	if !r.IsEscaped() {
+		return cli.ReadSecret(ctx, r.String())
+	}
+
+	if attribute := r.Attribute(); attribute != "" && attribute != "value" {
+		return "", fmt.Errorf("%w: attribute %q is not supported for names containing %q",
+			onepassword.ErrInvalidReference, attribute, escaped)
+	}
+
+	item, err := onepassword.Get[onepassword.Item](ctx, cli, "item", "get", r.Item, "--vault", r.Vault)
+	if err != nil {
+		return "", fmt.Errorf("failed to get item of %s: %w", r, err)
+	}
+
+	field, err := r.findField(item)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", r, err)
+	}
+	return field.Value, nil
+}
+
+// findField returns the field of the item the reference points to. Like the
+// 1Password CLI, sections and fields match by ID or case-insensitively by
+// label, and a reference without a section matches fields of all sections.
+func (r Reference) findField(item onepassword.Item) (onepassword.Field, error) {
+	var matches []onepassword.Field
+	for _, field := range item.Fields {
+		if !matchesName(r.Field, field.ID, field.Label) {
+			continue
+		}
+		if r.Section != "" && (field.Section == nil || !matchesName(r.Section, field.Section.ID, field.Section.Label)) {
+			continue
+		}
+		matches = append(matches, field)
+	}
+
+	switch len(matches) {
+	case 0:
+		return onepassword.Field{}, onepassword.ErrFieldNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return onepassword.Field{}, fmt.Errorf("%w: %d fields match %q", onepassword.ErrMoreThanOneMatch, len(matches), r.Field)
+	}
+}
+
+// matchesName reports whether a name of a reference matches an ID or label.
+func matchesName(name, id, label string) bool {
+	return name == id || strings.EqualFold(name, label)
+}
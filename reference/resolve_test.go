@@ -0,0 +1,94 @@
+package reference
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	onepassword "github.com/sthayduk/onepassword-cli-go"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		ref          Reference
+		expected     string
+		expectedRead bool
+		expectedErr  error
+	}{
+		{
+			name:         "Read with op",
+			ref:          New("Private", "Database", "password"),
+			expected:     "read:op://Private/Database/password",
+			expectedRead: true,
+		},
+		{
+			name:     "Escaped item",
+			ref:      NewInSection("Private", "AC/DC", "admin", "password"),
+			expected: "thunder",
+		},
+		{
+			name:     "Field label is case-insensitive",
+			ref:      NewInSection("Private", "AC/DC", "Admin", "pin"),
+			expected: "1975",
+		},
+		{
+			name:     "Field ID",
+			ref:      New("Private", "AC/DC", "admin-password"),
+			expected: "thunder",
+		},
+		{
+			name:        "Ambiguous field",
+			ref:         New("Private", "AC/DC", "password"),
+			expectedErr: onepassword.ErrMoreThanOneMatch,
+		},
+		{
+			name:        "Missing field",
+			ref:         New("Private", "AC/DC", "username"),
+			expectedErr: onepassword.ErrFieldNotFound,
+		},
+		{
+			name:        "Unsupported attribute",
+			ref:         New("Private", "AC/DC", "admin-password").WithAttribute("otp"),
+			expectedErr: onepassword.ErrInvalidReference,
+		},
+		{
+			name:        "Incomplete",
+			ref:         New("Private", "AC/DC", ""),
+			expectedErr: onepassword.ErrInvalidReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newTestFake(t)
+
+			// The fake does not implement op read, so it is answered here
+			var read bool
+			executor := onepassword.CommandExecutorFunc(func(ctx context.Context, cmd *onepassword.Command) ([]byte, []byte, error) {
+				if len(cmd.Args) > 0 && cmd.Args[0] == "read" {
+					read = true
+					i := slices.Index(cmd.Args, "--no-newline")
+					return []byte("read:" + cmd.Args[i+1]), nil, nil
+				}
+				return fake.Execute(ctx, cmd)
+			})
+			cli, err := fake.NewOpCLI(onepassword.WithCommandExecutor(executor))
+			if err != nil {
+				t.Fatalf("NewOpCLI() error = %v", err)
+			}
+
+			got, err := tt.ref.Resolve(context.Background(), cli)
+			if !errors.Is(err, tt.expectedErr) || (err != nil) != (tt.expectedErr != nil) {
+				t.Fatalf("Resolve() error = %v; want %v", err, tt.expectedErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Resolve() = %q; want %q", got, tt.expected)
+			}
+			if read != tt.expectedRead {
+				t.Errorf("Resolve() used op read = %t; want %t", read, tt.expectedRead)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minPasswordLength and maxPasswordLength are the lengths supported by password recipes.
+	minPasswordLength = 1
+	maxPasswordLength = 64
+
+	// defaultPasswordLength is the length of generated passwords if a recipe has none.
+	defaultPasswordLength = 32
+
+	// rotationSectionID is the section of the fields written by RotatePassword.
+	rotationSectionID = "rotation"
+
+	// previousPasswordLabel is the label of the rollback field written by RotatePassword.
+	previousPasswordLabel = "previous password"
+
+	// rotatedAtLabel is the label of the field with the time of the last rotation.
+	rotatedAtLabel = "rotated at"
+)
+
+// Character sets of generated passwords.
+const (
+	passwordLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordDigits  = "0123456789"
+	passwordSymbols = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+)
+
+// ErrNoPasswordField is returned when a password is rotated on an item without a password field.
+var ErrNoPasswordField = fmt.Errorf("password %w", ErrFieldNotFound)
+
+// PasswordRecipe describes the passwords generated by the 1Password CLI or
+// by Generate. The zero value uses the default recipe of the CLI, which is a
+// 32 character password of letters, digits and symbols.
+//
+// Fields:
+//   - Length: The number of characters, between 1 and 64. Defaults to 32.
+//   - Letters: Include upper and lower case letters.
+//   - Digits: Include digits.
+//   - Symbols: Include symbols.
+type PasswordRecipe struct {
+	Length  int
+	Letters bool
+	Digits  bool
+	Symbols bool
+}
+
+// DefaultPasswordRecipe is the recipe used by the 1Password CLI if none is given.
+var DefaultPasswordRecipe = PasswordRecipe{Length: defaultPasswordLength, Letters: true, Digits: true, Symbols: true}
+
+// IsZero reports whether the recipe is the zero value.
+func (r PasswordRecipe) IsZero() bool {
+	return r == PasswordRecipe{}
+}
+
+// Validate checks that the recipe can be used to generate passwords.
+//
+// Returns:
+//   - error: An error if the length is out of range or no character set is included.
+func (r PasswordRecipe) Validate() error {
+	if r.IsZero() {
+		return nil
+	}
+	if r.Length != 0 && (r.Length < minPasswordLength || r.Length > maxPasswordLength) {
+		return fmt.Errorf("password length must be between %d and %d, got %d", minPasswordLength, maxPasswordLength, r.Length)
+	}
+	if !r.Letters && !r.Digits && !r.Symbols {
+		return errors.New("password recipe must include letters, digits or symbols")
+	}
+	return nil
+}
+
+// String returns the recipe in the format of the --generate-password flag
+// of the 1Password CLI, e.g. "letters,digits,20". The zero value is
+// returned as an empty string.
+func (r PasswordRecipe) String() string {
+	if r.IsZero() {
+		return ""
+	}
+
+	var parts []string
+	if r.Letters {
+		parts = append(parts, "letters")
+	}
+	if r.Digits {
+		parts = append(parts, "digits")
+	}
+	if r.Symbols {
+		parts = append(parts, "symbols")
+	}
+	return strings.Join(append(parts, strconv.Itoa(r.length())), ",")
+}
+
+// length returns the length of the recipe with the default applied.
+func (r PasswordRecipe) length() int {
+	if r.Length == 0 {
+		return defaultPasswordLength
+	}
+	return r.Length
+}
+
+// Generate generates a password from the recipe locally using crypto/rand.
+// The password contains at least one character of each included set, as
+// long as the length allows it.
+//
+// Returns:
+//   - string: The generated password.
+//   - error: An error if the recipe is invalid or no random numbers are available.
+func (r PasswordRecipe) Generate() (string, error) {
+	if r.IsZero() {
+		r = DefaultPasswordRecipe
+	}
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+
+	var sets []string
+	if r.Letters {
+		sets = append(sets, passwordLetters)
+	}
+	if r.Digits {
+		sets = append(sets, passwordDigits)
+	}
+	if r.Symbols {
+		sets = append(sets, passwordSymbols)
+	}
+	alphabet := strings.Join(sets, "")
+
+	password := make([]byte, r.length())
+	for i := range password {
+		// Start with one character of each set, then fill from all of them
+		set := alphabet
+		if i < len(sets) {
+			set = sets[i]
+		}
+		c, err := randomByte(set)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// Shuffle, so the guaranteed characters are not at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomByte returns a random character of set.
+func randomByte(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return set[n.Int64()], nil
+}
+
+// Rotation describes a password rotation in progress and is passed to the
+// hooks of RotatePassword.
+//
+// Fields:
+//   - Item: The item whose password is rotated.
+//   - OldPassword: The password before the rotation.
+//   - NewPassword: The new password. Empty in the Before hook if the password is generated by the 1Password CLI.
+type Rotation struct {
+	Item        *Item
+	OldPassword string
+	NewPassword string
+}
+
+// RotationHooks customizes RotatePassword.
+//
+// Fields:
+//   - Generate: Generates the new password locally, e.g. PasswordRecipe.Generate. If nil,
+//     the 1Password CLI generates it when the item is updated.
+//   - Before: Called before the item is updated. Returning an error aborts the rotation
+//     without changes. With local generation, this is the place to set the new password on
+//     the target system before it is stored.
+//   - After: Called after the new password is stored, e.g. to set it on the target system.
+//     Returning an error restores the old password of the item.
+type RotationHooks struct {
+	Generate func(recipe PasswordRecipe) (string, error)
+	Before   func(ctx context.Context, rotation Rotation) error
+	After    func(ctx context.Context, rotation Rotation) error
+}
+
+// RotatePassword replaces the password of the item with a newly generated
+// one. The old password is kept in the concealed "previous password" field of
+// the "rotation" section, next to the time of the rotation, so it can be
+// restored manually; 1Password additionally keeps it in the password
+// history. The item is updated with the rotated state.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - recipe: The recipe of the new password. The zero value uses the default recipe.
+//   - hooks: Optional local generation and hooks to update the target system.
+//
+// Returns:
+//   - error: ErrNoPasswordField if the item has no password field, an error returned by a
+//     hook, or an error if the item cannot be updated. If After fails, the error also
+//     reports whether the old password was restored. If the 1Password CLI fails to
+//     generate the password, the rotation fields are restored, so the item does not
+//     record a rotation that did not happen.
+//
+// Example usage:
+//
+//	err := item.RotatePassword(ctx, onepassword.PasswordRecipe{Length: 40, Letters: true, Digits: true},
+//	    onepassword.RotationHooks{
+//	        After: func(ctx context.Context, rotation onepassword.Rotation) error {
+//	            return db.SetPassword(ctx, "app", rotation.NewPassword)
+//	        },
+//	    })
+func (item *Item) RotatePassword(ctx context.Context, recipe PasswordRecipe, hooks RotationHooks) error {
+	if item.cli == nil {
+		return fmt.Errorf("cannot rotate password: %w", ErrNoClient)
+	}
+	if err := recipe.Validate(); err != nil {
+		return err
+	}
+
+	// Listed items do not include fields
+	current, err := item.cli.getItem(ctx, item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get item: %w", err)
+	}
+	password := current.passwordFieldIndex()
+	if password < 0 {
+		return ErrNoPasswordField
+	}
+
+	rotation := Rotation{Item: current, OldPassword: current.Fields[password].Value}
+	if hooks.Generate != nil {
+		if rotation.NewPassword, err = hooks.Generate(recipe); err != nil {
+			return fmt.Errorf("failed to generate password: %w", err)
+		}
+	}
+
+	if hooks.Before != nil {
+		if err := hooks.Before(ctx, rotation); err != nil {
+			return fmt.Errorf("rotation aborted: %w", err)
+		}
+	}
+
+	// Keep the item without the rotation fields, so they can be restored if
+	// the CLI fails to generate the password after they were stored
+	previous := *current
+	previous.Fields = slices.Clone(current.Fields)
+	previous.Sections = slices.Clone(current.Sections)
+
+	current.setRotationFields(rotation.OldPassword)
+	if hooks.Generate != nil {
+		current.Fields[password].Value = rotation.NewPassword
+	}
+	updated, err := item.cli.updateItemWithStruct(ctx, *current)
+	if err != nil {
+		return fmt.Errorf("failed to store password: %w", err)
+	}
+
+	if hooks.Generate == nil {
+		generated, err := item.cli.regeneratePassword(ctx, *updated, recipe)
+		if err != nil {
+			return item.rollbackRotationFields(ctx, &previous, err)
+		}
+		updated = generated
+		if i := updated.passwordFieldIndex(); i >= 0 {
+			rotation.NewPassword = updated.Fields[i].Value
+		}
+	}
+	updated.cli = item.cli
+	rotation.Item = updated
+
+	if hooks.After != nil {
+		if err := hooks.After(ctx, rotation); err != nil {
+			return item.rollbackPassword(ctx, updated, rotation.OldPassword, err)
+		}
+	}
+
+	*item = *updated
+	return nil
+}
+
+// passwordFieldIndex returns the index of the first password field of the
+// item, or -1 if it has none. The purpose is compared case-insensitively,
+// because the CLI prints it in upper case.
+func (item *Item) passwordFieldIndex() int {
+	return slices.IndexFunc(item.Fields, func(field Field) bool {
+		return strings.EqualFold(string(field.Purpose), string(FieldPurposePassword))
+	})
+}
+
+// setRotationFields records the previous password and the time of the
+// rotation in the rotation section of the item.
+func (item *Item) setRotationFields(previousPassword string) {
+	section := Section{ID: rotationSectionID, Label: "Rotation"}
+	if item.isSectionIDUnique(rotationSectionID) {
+		item.Sections = append(item.Sections, section)
+	}
+
+	for _, field := range []Field{
+		{Label: previousPasswordLabel, Value: previousPassword, Type: FieldTypeConcealed},
+		{Label: rotatedAtLabel, Value: time.Now().UTC().Format(time.RFC3339), Type: FieldTypeString},
+	} {
+		field.Section = &section
+		for i := range item.Fields {
+			existing := &item.Fields[i]
+			if existing.Section != nil && existing.Section.ID == rotationSectionID && existing.Label == field.Label {
+				existing.Value = field.Value
+				field.Label = ""
+				break
+			}
+		}
+		if field.Label != "" {
+			item.Fields = append(item.Fields, field)
+		}
+	}
+}
+
+// rollbackPassword restores the old password after the After hook of a
+// rotation failed and returns an error describing both.
+func (item *Item) rollbackPassword(ctx context.Context, rotated *Item, oldPassword string, hookErr error) error {
+	if i := rotated.passwordFieldIndex(); i >= 0 {
+		rotated.Fields[i].Value = oldPassword
+	}
+
+	restored, err := item.cli.updateItemWithStruct(ctx, *rotated)
+	if err != nil {
+		*item = *rotated
+		return fmt.Errorf("rotation hook failed and the old password could not be restored: %w", errors.Join(hookErr, err))
+	}
+
+	restored.cli = item.cli
+	*item = *restored
+	return fmt.Errorf("rotation hook failed, old password restored: %w", hookErr)
+}
+
+// rollbackRotationFields restores the item as it was before the rotation
+// fields were stored, after the 1Password CLI failed to generate the new
+// password, and returns an error describing both.
+func (item *Item) rollbackRotationFields(ctx context.Context, previous *Item, generateErr error) error {
+	if _, err := item.cli.updateItemWithStruct(ctx, *previous); err != nil {
+		return fmt.Errorf("failed to generate password and the rotation fields could not be restored: %w", errors.Join(generateErr, err))
+	}
+	return fmt.Errorf("failed to generate password, rotation fields restored: %w", generateErr)
+}
+
+// RegeneratePassword replaces the password of the item with one generated by
+// the 1Password CLI from the recipe, without generating it client-side and
+// without keeping the old password in the item like RotatePassword. The
+// item is updated in place and returned; the new password is the value of
+// its password field.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - recipe: The recipe of the new password. The zero value uses the default recipe of the CLI.
+//
+// Returns:
+//   - *Item: The updated item.
+//   - error: An error if the recipe is invalid or the item cannot be updated, or
+//     ErrNoPasswordField if the updated item has no password field.
+//
+// Example usage:
+//
+//	updated, err := item.RegeneratePassword(ctx, onepassword.PasswordRecipe{Length: 40, Letters: true, Digits: true})
+//	if err != nil {
+//	    log.Fatalf("Failed to regenerate password: %v", err)
+//	}
+//	password, _ := updated.PasswordValue()
+func (item *Item) RegeneratePassword(ctx context.Context, recipe PasswordRecipe) (*Item, error) {
+	if item.cli == nil {
+		return nil, fmt.Errorf("cannot regenerate password: %w", ErrNoClient)
+	}
+	if item.ID == "" {
+		return nil, fmt.Errorf("item ID is empty, cannot regenerate password")
+	}
+	if err := recipe.Validate(); err != nil {
+		return nil, err
+	}
+
+	updated, err := item.cli.regeneratePassword(ctx, *item, recipe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate password: %w", err)
+	}
+	if updated.passwordFieldIndex() < 0 {
+		return nil, ErrNoPasswordField
+	}
+
+	updated.cli = item.cli
+	*item = *updated
+	return item, nil
+}
+
+// PasswordValue returns the value of the first password field of the item.
+// Items from listings have no fields; fetch them with GetItemByID first.
+//
+// Returns:
+//   - string: The password.
+//   - error: ErrNoPasswordField if the item has no password field.
+func (item *Item) PasswordValue() (string, error) {
+	i := item.passwordFieldIndex()
+	if i < 0 {
+		return "", ErrNoPasswordField
+	}
+	return item.Fields[i].Value, nil
+}
+
+// regeneratePassword replaces the password of an item with one generated by
+// the 1Password CLI from the recipe.
+func (cli *OpCLI) regeneratePassword(ctx context.Context, item Item, recipe PasswordRecipe) (*Item, error) {
+	flag := "--generate-password"
+	if !recipe.IsZero() {
+		flag += "=" + recipe.String()
+	}
+
+	// --reveal makes sure the concealed value of the new password is printed
+	output, err := cli.ExecuteOpCommand(ctx, "item", "edit", item.ID, flag, "--reveal")
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Item
+	if err := cli.unmarshalItem(output, &updated); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updated item: %w", err)
+	}
+	return &updated, nil
+}
@@ -0,0 +1,124 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPasswordRecipe(t *testing.T) {
+	tests := []struct {
+		name           string
+		recipe         PasswordRecipe
+		expectedString string
+		wantErr        bool
+	}{
+		{name: "Zero value", recipe: PasswordRecipe{}, expectedString: ""},
+		{name: "Default", recipe: DefaultPasswordRecipe, expectedString: "letters,digits,symbols,32"},
+		{name: "Default length", recipe: PasswordRecipe{Digits: true}, expectedString: "digits,32"},
+		{name: "Letters and digits", recipe: PasswordRecipe{Length: 20, Letters: true, Digits: true}, expectedString: "letters,digits,20"},
+		{name: "Too long", recipe: PasswordRecipe{Length: 65, Letters: true}, expectedString: "letters,65", wantErr: true},
+		{name: "No character set", recipe: PasswordRecipe{Length: 20}, expectedString: "20", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recipe.String(); got != tt.expectedString {
+				t.Errorf("String() = %q; want %q", got, tt.expectedString)
+			}
+			if err := tt.recipe.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v; wantErr %v", err, tt.wantErr)
+			}
+
+			password, err := tt.recipe.Generate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			recipe := tt.recipe
+			if recipe.IsZero() {
+				recipe = DefaultPasswordRecipe
+			}
+			if len(password) != recipe.length() {
+				t.Errorf("Generate() length = %d; want %d", len(password), recipe.length())
+			}
+			for set, included := range map[string]bool{passwordLetters: recipe.Letters, passwordDigits: recipe.Digits, passwordSymbols: recipe.Symbols} {
+				if strings.ContainsAny(password, set) != included {
+					t.Errorf("Generate() = %q; characters of %q included = %v, want %v", password, set, !included, included)
+				}
+			}
+		})
+	}
+}
+
+func TestRegeneratePassword(t *testing.T) {
+	tests := []struct {
+		name             string
+		recipe           PasswordRecipe
+		output           string
+		expectedFlag     string
+		expectedPassword string
+		expectedErr      error
+		wantErr          bool
+	}{
+		{
+			name:             "Default recipe",
+			output:           `{"id":"item-id","title":"DB","fields":[{"id":"password","purpose":"PASSWORD","type":"CONCEALED","value":"new"}]}`,
+			expectedFlag:     "--generate-password",
+			expectedPassword: "new",
+		},
+		{
+			name:             "Recipe",
+			recipe:           PasswordRecipe{Length: 20, Letters: true, Digits: true},
+			output:           `{"id":"item-id","title":"DB","fields":[{"id":"password","purpose":"PASSWORD","type":"CONCEALED","value":"new"}]}`,
+			expectedFlag:     "--generate-password=letters,digits,20",
+			expectedPassword: "new",
+		},
+		{
+			name:        "No password field",
+			output:      `{"id":"item-id","title":"DB"}`,
+			expectedErr: ErrNoPasswordField,
+			wantErr:     true,
+		},
+		{
+			name:    "Invalid recipe",
+			recipe:  PasswordRecipe{Length: 100, Letters: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				args = cmd.Args
+				return []byte(tt.output), nil, nil
+			}))
+			item := &Item{cli: cli, ID: "item-id", Title: "DB"}
+
+			updated, err := item.RegeneratePassword(context.Background(), tt.recipe)
+			if (err != nil) != tt.wantErr || (tt.expectedErr != nil && !errors.Is(err, tt.expectedErr)) {
+				t.Fatalf("RegeneratePassword() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !slices.Contains(args, tt.expectedFlag) || !slices.Contains(args, "--reveal") {
+				t.Errorf("RegeneratePassword() args = %v; want %s and --reveal", args, tt.expectedFlag)
+			}
+			if updated != item || updated.cli != cli {
+				t.Errorf("RegeneratePassword() did not update the item in place")
+			}
+			if password, err := updated.PasswordValue(); err != nil || password != tt.expectedPassword {
+				t.Errorf("PasswordValue() = %q, %v; want %q", password, err, tt.expectedPassword)
+			}
+		})
+	}
+}
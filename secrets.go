@@ -0,0 +1,90 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ReadSecret reads the value of a secret reference, e.g.
+// "op://Private/Database/password", with "op read".
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - reference: The secret reference. It must start with "op://".
+//
+// Returns:
+//   - string: The value of the referenced field.
+//   - error: ErrInvalidReference if the reference is malformed, or an error if it cannot be read.
+//
+// Example usage:
+//
+//	password, err := cli.ReadSecret(ctx, "op://Private/Database/password")
+//	if err != nil {
+//	    log.Fatalf("Failed to read secret: %v", err)
+//	}
+func (cli *OpCLI) ReadSecret(ctx context.Context, reference string) (string, error) {
+	if !strings.HasPrefix(reference, "op://") {
+		return "", fmt.Errorf("%w: %q must start with op://", ErrInvalidReference, reference)
+	}
+
+	// op read prints the raw value, so --format is not passed
+	args := []string{"read", "--no-newline", reference}
+	if cli.Account != nil && cli.Account.UserUUID != "" {
+		args = append(args, "--account", cli.Account.UserUUID)
+	}
+
+	output, err := cli.executeOpCommandWith(ctx, execSettings{noDefaultArgs: true}, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret reference: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// Resolve reads the live value of the field through its secret reference
+// with ReadSecret. Fields of item lists and of items fetched without their
+// values carry only the reference, so secrets can be fetched on demand.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - cli: The client used to run "op read".
+//
+// Returns:
+//   - string: The value of the field.
+//   - error: ErrInvalidReference if the field has no reference, or an error if it cannot be read.
+//
+// Example usage:
+//
+//	password, err := field.Resolve(ctx, cli)
+//	if err != nil {
+//	    log.Fatalf("Failed to resolve field: %v", err)
+//	}
+func (f Field) Resolve(ctx context.Context, cli *OpCLI) (string, error) {
+	if f.Reference == "" {
+		return "", fmt.Errorf("%w: field %s has no reference", ErrInvalidReference, f.Label)
+	}
+	return cli.ReadSecret(ctx, f.Reference)
+}
+
+// ResolveSecrets reads the values of multiple secret references.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - references: The secret references, keyed by an arbitrary name such as an environment variable.
+//
+// Returns:
+//   - map[string]string: The values of the references, keyed by the same names.
+//   - error: An error naming the first reference that cannot be read.
+func (cli *OpCLI) ResolveSecrets(ctx context.Context, references map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(references))
+	for _, name := range sortedKeys(references) {
+		value, err := cli.ReadSecret(ctx, references[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
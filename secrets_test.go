@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		expected  string
+		wantErr   error
+	}{
+		{
+			name:      "Reference",
+			reference: "op://Private/Database/password",
+			expected:  "secret",
+		},
+		{
+			name:      "Missing scheme",
+			reference: "Private/Database/password",
+			wantErr:   ErrInvalidReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				args = cmd.Args
+				return []byte("secret"), nil, nil
+			}))
+
+			value, err := cli.ReadSecret(context.Background(), tt.reference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadSecret() error = %v; want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if value != tt.expected {
+				t.Errorf("ReadSecret() = %q; want %q", value, tt.expected)
+			}
+
+			expectedArgs := []string{"read", "--no-newline", tt.reference, "--account", "user-uuid"}
+			if !slices.Equal(args, expectedArgs) {
+				t.Errorf("ReadSecret() args = %v; want %v", args, expectedArgs)
+			}
+		})
+	}
+}
+
+func TestFieldResolve(t *testing.T) {
+	var args []string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		args = cmd.Args
+		return []byte("secret"), nil, nil
+	}))
+
+	field := Field{Label: "password", Reference: "op://Private/Database/password"}
+	value, err := field.Resolve(context.Background(), cli)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if value != "secret" {
+		t.Errorf("Resolve() = %q; want %q", value, "secret")
+	}
+	if !slices.Contains(args, field.Reference) {
+		t.Errorf("Resolve() args = %v; want the reference %s", args, field.Reference)
+	}
+
+	if _, err := (Field{Label: "notes"}).Resolve(context.Background(), cli); !errors.Is(err, ErrInvalidReference) {
+		t.Errorf("Resolve() without reference error = %v; want %v", err, ErrInvalidReference)
+	}
+}
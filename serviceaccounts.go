@@ -1,15 +1,19 @@
 package onepassword
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 // ServiceAccountRateLimit represents the rate limit information for a service account action.
 // It includes the type of rate limit, the action being limited, the maximum allowed requests (Limit),
 // the number of requests used (Used), the number of requests remaining (Remaining),
-// and the time when the rate limit resets (Reset, as a Unix timestamp).
+// and the time until the rate limit resets (Reset, in seconds relative to when the limits were retrieved).
 type ServiceAccountRateLimit struct {
 	Type      string `json:"type"`
 	Action    string `json:"action"`
@@ -17,6 +21,136 @@ type ServiceAccountRateLimit struct {
 	Used      int    `json:"used"`
 	Remaining int    `json:"remaining"`
 	Reset     int64  `json:"reset"` // Time in seconds until the rate limit resets
+
+	retrievedAt time.Time
+}
+
+// ResetAt returns the point in time at which the rate limit window resets.
+func (r ServiceAccountRateLimit) ResetAt() time.Time {
+	retrievedAt := r.retrievedAt
+	if retrievedAt.IsZero() {
+		retrievedAt = time.Now()
+	}
+	return retrievedAt.Add(time.Duration(r.Reset) * time.Second)
+}
+
+// ResetIn returns the time remaining until the rate limit window resets.
+// It returns zero if the window has already reset.
+func (r ServiceAccountRateLimit) ResetIn() time.Duration {
+	return max(time.Until(r.ResetAt()), 0)
+}
+
+// WaitForReset blocks until requests for the given action are allowed again.
+//
+// It retrieves the current rate limits and returns immediately if all limits
+// matching the action have remaining requests. Otherwise it sleeps until the
+// latest reset time of the exhausted limits, or until the context is done.
+//
+// Parameters:
+//   - ctx: The context to cancel the wait.
+//   - action: The rate limited action, e.g. "read" or "write". An empty string matches all actions.
+//
+// Returns:
+//   - error: Non-nil if the limits cannot be retrieved or the context is done before the reset.
+func (cli *OpCLI) WaitForReset(ctx context.Context, action string) error {
+	rateLimits, err := cli.GetServiceAccountRateLimits(ctx)
+	if err != nil {
+		return err
+	}
+
+	var resetAt time.Time
+	for _, rateLimit := range rateLimits {
+		if action != "" && !strings.Contains(rateLimit.Action, action) {
+			continue
+		}
+		if rateLimit.Remaining > 0 {
+			continue
+		}
+		if rateLimit.ResetAt().After(resetAt) {
+			resetAt = rateLimit.ResetAt()
+		}
+	}
+
+	wait := time.Until(resetAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	cli.log().Debug("waiting for service account rate limit reset", "action", action, "wait", wait)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ErrUnsupportedForServiceAccount is returned before a command is executed
+// that the 1Password CLI does not support when authenticated as a service account.
+var ErrUnsupportedForServiceAccount = errors.New("operation not supported for service accounts")
+
+// serviceAccountUnsupportedCommands lists the command prefixes that fail
+// when authenticated as a service account.
+var serviceAccountUnsupportedCommands = [][]string{
+	{"signin"},
+	{"signout"},
+	{"account", "add"},
+	{"account", "forget"},
+	{"user"},
+	{"group"},
+	{"vault", "user"},
+	{"vault", "group"},
+	{"connect"},
+	{"events-api"},
+}
+
+// serviceAccountAllowedCommands lists exceptions to serviceAccountUnsupportedCommands.
+var serviceAccountAllowedCommands = [][]string{
+	{"user", "get", "--me"},
+}
+
+// checkServiceAccountSupport returns ErrUnsupportedForServiceAccount if the
+// OpCLI instance is authenticated as a service account and the command is not
+// supported in that mode.
+func (cli *OpCLI) checkServiceAccountSupport(args []string) error {
+	if !cli.isServiceAccount || !isUnsupportedForServiceAccount(args) {
+		return nil
+	}
+	return fmt.Errorf("%w: op %s", ErrUnsupportedForServiceAccount, strings.Join(args, " "))
+}
+
+// isUnsupportedForServiceAccount reports whether the command is not supported
+// when authenticated as a service account.
+func isUnsupportedForServiceAccount(args []string) bool {
+	for _, prefix := range serviceAccountAllowedCommands {
+		if hasCommandPrefix(args, prefix) {
+			return false
+		}
+	}
+
+	for _, prefix := range serviceAccountUnsupportedCommands {
+		if hasCommandPrefix(args, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommandPrefix reports whether args starts with all elements of prefix.
+func hasCommandPrefix(args, prefix []string) bool {
+	if len(args) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if args[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // GetServiceAccountRateLimits retrieves the current rate limit information for the authenticated service account.
@@ -32,18 +166,18 @@ type ServiceAccountRateLimit struct {
 //
 // Example usage:
 //
-//	rateLimits, err := cli.GetServiceAccountRateLimits()
+//	rateLimits, err := cli.GetServiceAccountRateLimits(ctx)
 //	if err != nil {
 //	    log.Fatalf("Failed to get rate limit: %v", err)
 //	}
 //	fmt.Printf("Remaining requests: %d\n", rateLimits[0].Remaining)
-func (cli *OpCLI) GetServiceAccountRateLimits() ([]ServiceAccountRateLimit, error) {
+func (cli *OpCLI) GetServiceAccountRateLimits(ctx context.Context) ([]ServiceAccountRateLimit, error) {
 
 	if !cli.isServiceAccount {
 		return []ServiceAccountRateLimit{}, errors.New("not authenticated as a service account")
 	}
 
-	output, err := cli.ExecuteOpCommand("service-account", "rate-limit")
+	output, err := cli.ExecuteOpCommand(ctx, "service-account", "rate-limit")
 	if err != nil {
 		return []ServiceAccountRateLimit{}, err
 	}
@@ -54,41 +188,49 @@ func (cli *OpCLI) GetServiceAccountRateLimits() ([]ServiceAccountRateLimit, erro
 		return []ServiceAccountRateLimit{}, err
 	}
 
+	retrievedAt := time.Now()
+	for i := range rateLimits {
+		rateLimits[i].retrievedAt = retrievedAt
+	}
+
 	return rateLimits, nil
 }
 
 // SignInWithServiceAccount authenticates the OpCLI instance using a 1Password service account access token.
 //
-// This method sets the provided access token as the current authentication token for the CLI instance,
-// marks the instance as authenticated via a service account, and sets the "OP_SERVICE_ACCOUNT_TOKEN"
-// environment variable for downstream processes. It then retrieves the current user's details using the
+// This method sets the provided access token as the current authentication token for the CLI instance and
+// marks the instance as authenticated via a service account. The token is passed as "OP_SERVICE_ACCOUNT_TOKEN"
+// in the environment of every command this instance runs; the environment of the current process is not
+// modified, so the token is not inherited by other child processes of the application. It then retrieves the current user's details using the
 // GetMe method and updates the OpCLI's Account field with the user's UUID and email.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - accesstoken: A string representing the 1Password service account access token.
 //
 // Returns:
 //   - error: Returns an error if retrieving the user details fails; otherwise, returns nil.
 //
 // Side Effects:
-//   - Modifies the OpCLI instance's accesstoken and isServiceAccount fields.
-//   - Sets the "OP_SERVICE_ACCOUNT_TOKEN" environment variable.
+//   - Modifies the OpCLI instance's accesstoken and isServiceAccount fields. Both are reset if the
+//     sign-in fails.
 //   - Updates the OpCLI's Account field with the authenticated user's details.
 //
 // Example usage:
 //
-//	err := cli.SignInWithServiceAccount("your-access-token")
+//	err := cli.SignInWithServiceAccount(ctx, "your-access-token")
 //	if err != nil {
 //	    log.Fatalf("Failed to sign in: %v", err)
 //	}
-func (cli *OpCLI) SignInWithServiceAccount(accesstoken string) error {
+func (cli *OpCLI) SignInWithServiceAccount(ctx context.Context, accesstoken string) error {
 	cli.accesstoken = accesstoken
 	cli.isServiceAccount = true
 
-	os.Setenv("OP_SERVICE_ACCOUNT_TOKEN", cli.accesstoken)
-
-	user, err := cli.GetMe()
+	user, err := cli.GetMe(ctx)
 	if err != nil {
+		// Do not keep a token that could not be used to sign in
+		cli.accesstoken = ""
+		cli.isServiceAccount = false
 		return err
 	}
 
@@ -96,10 +238,102 @@ func (cli *OpCLI) SignInWithServiceAccount(accesstoken string) error {
 	// This is necessary for the CLI to function properly with the service account
 	// and the other signin methods return a account object with the required information
 	// but this method does return a Userobject, so we need to set it manually
-	cli.Account = &Account{
+	cli.setAccount(&Account{
 		UserUUID: user.ID,
 		Email:    user.Email,
-	}
+	})
 
 	return nil
 }
+
+// ErrServiceAccountTokenExpired is returned by ValidateServiceAccountToken
+// if the 1Password CLI reports that the token has expired.
+var ErrServiceAccountTokenExpired = errors.New("service account token has expired")
+
+// ServiceAccountTokenInfo contains the identity associated with a service
+// account token as reported by "op whoami".
+//
+// The 1Password CLI does not report the expiry date of a service account
+// token. An expired token is reported by ValidateServiceAccountToken as
+// ErrServiceAccountTokenExpired.
+type ServiceAccountTokenInfo struct {
+	URL         string    `json:"url"`
+	Email       string    `json:"email"`
+	UserUUID    string    `json:"user_uuid"`
+	AccountUUID string    `json:"account_uuid"`
+	UserType    UserType  `json:"user_type"`
+	ValidatedAt time.Time `json:"-"`
+}
+
+// ValidateServiceAccountToken checks a service account token by running
+// "op whoami" with the token in an isolated environment.
+//
+// The command does not inherit any session or service account token from
+// the environment of the current process or of this OpCLI instance, and the
+// state of the OpCLI instance is not modified. This makes it suitable for
+// health checks and token rotation tooling.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - token: The service account token to validate.
+//
+// Returns:
+//   - *ServiceAccountTokenInfo: The identity associated with the token.
+//   - error: ErrServiceAccountTokenExpired if the token has expired, or a
+//     non-nil error if the token is invalid or the command fails.
+//
+// Example usage:
+//
+//	info, err := cli.ValidateServiceAccountToken(ctx, os.Getenv("NEW_TOKEN"))
+//	if err != nil {
+//	    log.Fatalf("Token is not usable: %v", err)
+//	}
+//	fmt.Printf("Token belongs to %s\n", info.UserUUID)
+func (cli *OpCLI) ValidateServiceAccountToken(ctx context.Context, token string) (*ServiceAccountTokenInfo, error) {
+	if token == "" {
+		return nil, errors.New("service account token is empty")
+	}
+
+	cmd := &Command{
+		Path: cli.Path,
+		Args: []string{"whoami", "--format=json"},
+		Env:  append(isolatedEnviron(), "OP_SERVICE_ACCOUNT_TOKEN="+token),
+	}
+	if cli.configDir != "" {
+		cmd.Env = append(cmd.Env, "OP_CONFIG_DIR="+cli.configDir)
+	}
+
+	output, stderr, err := cli.run(ctx, cmd)
+	if err != nil {
+		cliErr := &OpCliError{Err: err, StderrOutput: string(stderr)}
+		if strings.Contains(strings.ToLower(cliErr.StderrOutput), "expired") {
+			return nil, fmt.Errorf("%w: %w", ErrServiceAccountTokenExpired, cliErr)
+		}
+		return nil, fmt.Errorf("failed to validate service account token: %w", cliErr)
+	}
+
+	var info ServiceAccountTokenInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse whoami output: %w", err)
+	}
+	info.ValidatedAt = time.Now()
+
+	if info.UserType != "" && info.UserType != UserTypeServiceAccount {
+		return nil, fmt.Errorf("token does not belong to a service account: user type %s", info.UserType)
+	}
+
+	return &info, nil
+}
+
+// isolatedEnviron returns the environment of the current process without
+// any 1Password session or service account tokens.
+func isolatedEnviron() []string {
+	var env []string
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, "OP_SESSION_") || strings.HasPrefix(entry, "OP_SERVICE_ACCOUNT_TOKEN=") {
+			continue
+		}
+		env = append(env, entry)
+	}
+	return env
+}
@@ -0,0 +1,104 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCheckServiceAccountSupport(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		unsupported bool
+	}{
+		{
+			name:        "Sign in",
+			args:        []string{"signin"},
+			unsupported: true,
+		},
+		{
+			name:        "Vault permission grant",
+			args:        []string{"vault", "user", "grant", "--vault", "abc"},
+			unsupported: true,
+		},
+		{
+			name:        "Current user",
+			args:        []string{"user", "get", "--me"},
+			unsupported: false,
+		},
+		{
+			name:        "Other user",
+			args:        []string{"user", "get", "someone@example.com"},
+			unsupported: true,
+		},
+		{
+			name:        "Item list",
+			args:        []string{"item", "list"},
+			unsupported: false,
+		},
+		{
+			name:        "Vault list",
+			args:        []string{"vault", "list"},
+			unsupported: false,
+		},
+	}
+
+	serviceAccount := &OpCLI{isServiceAccount: true}
+	user := &OpCLI{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := serviceAccount.checkServiceAccountSupport(tt.args)
+			if got := errors.Is(err, ErrUnsupportedForServiceAccount); got != tt.unsupported {
+				t.Errorf("checkServiceAccountSupport(%q) = %v; want unsupported %t", tt.args, err, tt.unsupported)
+			}
+
+			if err := user.checkServiceAccountSupport(tt.args); err != nil {
+				t.Errorf("checkServiceAccountSupport(%q) without service account = %v; want nil", tt.args, err)
+			}
+		})
+	}
+}
+
+func TestSignInWithServiceAccountKeepsTokenInClient(t *testing.T) {
+	const token = "ops_test-token"
+
+	tests := []struct {
+		name    string
+		stdout  string
+		err     error
+		wantErr bool
+	}{
+		{name: "Success", stdout: `{"id":"service-account-uuid","email":"sa@example.com"}`},
+		{name: "Failure", err: errors.New("exit status 1"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commandEnv []string
+			cli := &OpCLI{Path: "op"}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				commandEnv = cmd.Env
+				return []byte(tt.stdout), nil, tt.err
+			}))
+
+			err := cli.SignInWithServiceAccount(context.Background(), token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignInWithServiceAccount() error = %v; want error %t", err, tt.wantErr)
+			}
+
+			if !slices.Contains(commandEnv, "OP_SERVICE_ACCOUNT_TOKEN="+token) {
+				t.Errorf("op command environment does not contain the token")
+			}
+			if os.Getenv("OP_SERVICE_ACCOUNT_TOKEN") == token {
+				t.Errorf("token was set in the process environment")
+			}
+			if tt.wantErr && slices.Contains(cli.environ(), "OP_SERVICE_ACCOUNT_TOKEN="+token) {
+				t.Errorf("token is kept after a failed sign-in")
+			}
+		})
+	}
+}
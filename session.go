@@ -0,0 +1,103 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SessionExpiredError is returned when the session of the active account has
+// expired and cannot be refreshed, either because the CLI reported it or,
+// with SetSessionCheck, because the account was idle for longer than the
+// session lifetime. It matches ErrSessionExpired with errors.Is.
+//
+// Fields:
+//   - Account: The user UUID of the account.
+//   - LastActivity: The time of the sign-in or the last successful command. Zero if unknown.
+//   - IdleTimeout: The idle time after which the session expires. Zero if unknown.
+//   - Err: The error reported by the CLI, or nil if the command was not run.
+type SessionExpiredError struct {
+	Account      string
+	LastActivity time.Time
+	IdleTimeout  time.Duration
+	Err          error
+}
+
+func (e *SessionExpiredError) Error() string {
+	msg := fmt.Sprintf("%v for account %s", ErrSessionExpired, e.Account)
+	if !e.LastActivity.IsZero() {
+		msg += fmt.Sprintf(" after %s idle", time.Since(e.LastActivity).Round(time.Second))
+	}
+	if e.IdleTimeout > 0 {
+		msg += fmt.Sprintf(" (timeout %s)", e.IdleTimeout)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg + "; sign in again"
+}
+
+// Is reports whether target is ErrSessionExpired.
+func (e *SessionExpiredError) Is(target error) bool {
+	return target == ErrSessionExpired
+}
+
+// Unwrap returns the error reported by the CLI.
+func (e *SessionExpiredError) Unwrap() error {
+	return e.Err
+}
+
+// SessionExpiresAt returns the time at which the session of the account
+// expires unless another command is run, or the zero time if the account has
+// no session from SignIn.
+func (a *Account) SessionExpiresAt() time.Time {
+	if !a.hasSession() {
+		return time.Time{}
+	}
+	return a.lastActivity().Add(a.signInExpireDuration)
+}
+
+// SetSessionCheck enables or disables the session check in ExecuteOpCommand.
+// If enabled, commands fail with a SessionExpiredError without running the
+// CLI when the session of the active account is known to be expired and
+// cannot be refreshed, e.g. because SetSessionRefresh disabled the refresh.
+// Sessions that can be refreshed are refreshed as before. It is disabled by
+// default, so the CLI decides whether a session is still valid.
+//
+// Parameters:
+//   - enabled: Whether expired sessions are detected before running a command.
+func (cli *OpCLI) SetSessionCheck(enabled bool) {
+	cli.sessionCheck = enabled
+}
+
+// checkSession returns a SessionExpiredError if the session check is enabled
+// and the session of the active account is expired.
+func (cli *OpCLI) checkSession() error {
+	if !cli.sessionCheck || cli.Account == nil || !cli.Account.hasSession() || !cli.Account.IsSessionExpired() {
+		return nil
+	}
+	return cli.sessionExpiredError(nil)
+}
+
+// sessionExpiredError returns a SessionExpiredError for the active account.
+func (cli *OpCLI) sessionExpiredError(err error) error {
+	expired := &SessionExpiredError{Err: err}
+	if cli.Account != nil {
+		expired.Account = cli.Account.UserUUID
+		if cli.Account.hasSession() {
+			expired.LastActivity = cli.Account.lastActivity()
+			expired.IdleTimeout = cli.Account.signInExpireDuration
+		}
+	}
+	return expired
+}
+
+// asSessionExpiredError converts an error that indicates an expired session
+// into a SessionExpiredError. Other errors are returned unchanged.
+func (cli *OpCLI) asSessionExpiredError(err error) error {
+	var expired *SessionExpiredError
+	if !errors.Is(err, ErrSessionExpired) || errors.As(err, &expired) {
+		return err
+	}
+	return cli.sessionExpiredError(err)
+}
@@ -0,0 +1,66 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		sessionCheck  bool
+		stderr        string
+		expectedCalls int
+		expectedCLI   bool
+	}{
+		{name: "Check enabled", sessionCheck: true, expectedCalls: 0},
+		{name: "Check disabled", sessionCheck: false, stderr: "[ERROR] You are not currently signed in.", expectedCalls: 1, expectedCLI: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &Account{UserUUID: "user-uuid"}
+			account.SetSignInInfo("session-token")
+			account.signInTime = time.Now().Add(-time.Hour)
+
+			var calls int
+			cli := &OpCLI{Path: "op", Account: account}
+			cli.SetSessionRefresh(false)
+			cli.SetSessionCheck(tt.sessionCheck)
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				calls++
+				return nil, []byte(tt.stderr), errors.New("exit status 1")
+			}))
+
+			_, err := cli.ExecuteOpCommand(context.Background(), "vault", "list")
+			if !errors.Is(err, ErrSessionExpired) {
+				t.Fatalf("ExecuteOpCommand() error = %v; want %v", err, ErrSessionExpired)
+			}
+			var expired *SessionExpiredError
+			if !errors.As(err, &expired) || expired.Account != "user-uuid" || expired.IdleTimeout == 0 {
+				t.Errorf("ExecuteOpCommand() error = %#v; want a SessionExpiredError for the account", err)
+			}
+			var cliErr *OpCliError
+			if errors.As(err, &cliErr) != tt.expectedCLI {
+				t.Errorf("ExecuteOpCommand() error wraps OpCliError = %v; want %v", !tt.expectedCLI, tt.expectedCLI)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("executor calls = %d; want %d", calls, tt.expectedCalls)
+			}
+		})
+	}
+}
+
+func TestSessionExpiresAt(t *testing.T) {
+	account := &Account{UserUUID: "user-uuid"}
+	if !account.SessionExpiresAt().IsZero() {
+		t.Errorf("SessionExpiresAt() = %v; want zero without a session", account.SessionExpiresAt())
+	}
+
+	account.SetSignInInfo("session-token")
+	if expiresIn := time.Until(account.SessionExpiresAt()); expiresIn < 28*time.Minute || expiresIn > 29*time.Minute {
+		t.Errorf("SessionExpiresAt() in %v; want about 29 minutes", expiresIn)
+	}
+}
@@ -0,0 +1,226 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ErrSessionNotFound is returned by a SessionStore if no session token is
+// stored for an account.
+var ErrSessionNotFound = errors.New("session token not found")
+
+// SessionStore persists session tokens between program runs, so short-lived
+// programs can reuse an existing session instead of prompting for the
+// password on every run.
+type SessionStore interface {
+	// Load returns the stored session token of the account, or ErrSessionNotFound.
+	Load(account *Account) (string, error)
+	// Save stores the session token of the account.
+	Save(account *Account, token string) error
+	// Delete removes the stored session token of the account.
+	Delete(account *Account) error
+}
+
+// SetSessionStore sets the SessionStore used by SignIn to reuse and persist
+// session tokens. Passing nil disables persistence.
+//
+// Parameters:
+//   - store: The SessionStore to use.
+func (cli *OpCLI) SetSessionStore(store SessionStore) {
+	cli.sessionStore = store
+}
+
+// existingSession returns a session token for the account that the CLI
+// still accepts, checking the token held by the account, the process
+// environment and the configured SessionStore, in this order.
+func (cli *OpCLI) existingSession(ctx context.Context, account *Account) (string, bool) {
+	if account.sessionToken != "" && cli.isSessionTokenValid(ctx, account, account.sessionToken) {
+		cli.log().Debug("reusing session of account", "account", account.UserUUID)
+		return account.sessionToken, true
+	}
+
+	if token := os.Getenv("OP_SESSION_" + account.UserUUID); token != "" {
+		if cli.isSessionTokenValid(ctx, account, token) {
+			cli.log().Debug("reusing session from environment", "account", account.UserUUID)
+			return token, true
+		}
+		cli.log().Debug("session from environment is no longer valid", "account", account.UserUUID)
+	}
+
+	if token, ok := cli.loadStoredSession(ctx, account); ok {
+		cli.log().Debug("reusing stored session", "account", account.UserUUID)
+		return token, true
+	}
+
+	return "", false
+}
+
+// loadStoredSession returns the stored session token of the account if one
+// exists and the CLI still accepts it.
+func (cli *OpCLI) loadStoredSession(ctx context.Context, account *Account) (string, bool) {
+	if cli.sessionStore == nil {
+		return "", false
+	}
+
+	token, err := cli.sessionStore.Load(account)
+	if err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			cli.log().Warn("failed to load stored session token", "account", account.UserUUID, "error", err)
+		}
+		return "", false
+	}
+
+	if !cli.isSessionTokenValid(ctx, account, token) {
+		cli.log().Debug("stored session token is no longer valid", "account", account.UserUUID)
+		if err := cli.sessionStore.Delete(account); err != nil {
+			cli.log().Warn("failed to delete stored session token", "account", account.UserUUID, "error", err)
+		}
+		return "", false
+	}
+
+	return token, true
+}
+
+// isSessionTokenValid checks a session token with the cheap "op whoami" command.
+// The token is passed in the OP_SESSION_<uuid> environment variable like the
+// session of the active account, so it does not appear in the process list,
+// the execution hooks or traces.
+func (cli *OpCLI) isSessionTokenValid(ctx context.Context, account *Account, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	name := "OP_SESSION_" + account.UserUUID
+	cmd := cli.command("whoami", "--account", account.UserUUID, "--format=json")
+	cmd.Env = append(slices.DeleteFunc(cmd.Env, func(v string) bool {
+		return strings.HasPrefix(v, name+"=")
+	}), name+"="+token)
+
+	_, _, err := cli.run(ctx, cmd)
+	return err == nil
+}
+
+// keyringService is the service name used for entries in the OS keyring.
+const keyringService = "onepassword-cli-go"
+
+// KeyringSessionStore is a SessionStore backed by the keyring of the
+// operating system: the Keychain on macOS (via the security tool), the
+// Credential Manager on Windows, and the Secret Service on Linux (via
+// secret-tool from libsecret).
+type KeyringSessionStore struct {
+	Service string
+}
+
+// NewKeyringSessionStore creates a KeyringSessionStore that stores its
+// entries under the given service name. If service is empty, a default
+// service name is used.
+//
+// Returns:
+//   - *KeyringSessionStore: The keyring backed session store.
+func NewKeyringSessionStore(service string) *KeyringSessionStore {
+	if service == "" {
+		service = keyringService
+	}
+	return &KeyringSessionStore{Service: service}
+}
+
+// Load returns the session token stored for the account in the OS keyring.
+func (s *KeyringSessionStore) Load(account *Account) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		output, err := runKeyringCommand(nil, "security", "find-generic-password", "-s", s.Service, "-a", account.UserUUID, "-w")
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSessionNotFound, err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	case "windows":
+		return credentialRead(s.target(account))
+	case "linux":
+		output, err := runKeyringCommand(nil, "secret-tool", "lookup", "service", s.Service, "account", account.UserUUID)
+		token := strings.TrimSpace(string(output))
+		if err != nil || token == "" {
+			return "", ErrSessionNotFound
+		}
+		return token, nil
+	default:
+		return "", fmt.Errorf("keyring not supported on %s", runtime.GOOS)
+	}
+}
+
+// Save stores the session token of the account in the OS keyring,
+// replacing any existing entry.
+func (s *KeyringSessionStore) Save(account *Account, token string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		// security reads the command from stdin in interactive mode, so the
+		// token does not appear in the process list
+		_, err := runKeyringCommand(strings.NewReader(securityCommand("add-generic-password", "-U", "-s", s.Service, "-a", account.UserUUID, "-w", token)), "security", "-i")
+		return err
+	case "windows":
+		return credentialWrite(s.target(account), account.UserUUID, token)
+	case "linux":
+		// secret-tool reads the secret from stdin
+		_, err := runKeyringCommand(strings.NewReader(token), "secret-tool", "store",
+			"--label", "1Password session "+account.UserUUID,
+			"service", s.Service, "account", account.UserUUID)
+		return err
+	default:
+		return fmt.Errorf("keyring not supported on %s", runtime.GOOS)
+	}
+}
+
+// Delete removes the session token of the account from the OS keyring.
+func (s *KeyringSessionStore) Delete(account *Account) error {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := runKeyringCommand(nil, "security", "delete-generic-password", "-s", s.Service, "-a", account.UserUUID); err != nil {
+			return fmt.Errorf("%w: %v", ErrSessionNotFound, err)
+		}
+		return nil
+	case "windows":
+		return credentialDelete(s.target(account))
+	case "linux":
+		_, err := runKeyringCommand(nil, "secret-tool", "clear", "service", s.Service, "account", account.UserUUID)
+		return err
+	default:
+		return fmt.Errorf("keyring not supported on %s", runtime.GOOS)
+	}
+}
+
+// target returns the Windows Credential Manager target name of the account.
+func (s *KeyringSessionStore) target(account *Account) string {
+	return s.Service + ":" + account.UserUUID
+}
+
+// securityCommand returns a command line for the interactive mode of the
+// macOS security tool, with every argument quoted.
+func securityCommand(args ...string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = `"` + escape.Replace(arg) + `"`
+	}
+	return strings.Join(quoted, " ") + "\n"
+}
+
+// runKeyringCommand runs a keyring helper tool and returns its stdout.
+func runKeyringCommand(stdin *strings.Reader, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
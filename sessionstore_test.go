@@ -0,0 +1,67 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// memorySessionStore is a SessionStore that keeps tokens in memory.
+type memorySessionStore map[string]string
+
+func (s memorySessionStore) Load(account *Account) (string, error) {
+	token, ok := s[account.UserUUID]
+	if !ok {
+		return "", ErrSessionNotFound
+	}
+	return token, nil
+}
+
+func (s memorySessionStore) Save(account *Account, token string) error {
+	s[account.UserUUID] = token
+	return nil
+}
+
+func (s memorySessionStore) Delete(account *Account) error {
+	delete(s, account.UserUUID)
+	return nil
+}
+
+func TestSignInStoredSession(t *testing.T) {
+	var whoami *Command
+	cli := &OpCLI{Path: "op"}
+	cli.SetSessionStore(memorySessionStore{"user-uuid": "stored-token"})
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		if cmd.Args[0] == "whoami" {
+			whoami = cmd
+		}
+		return []byte(`{}`), nil, nil
+	}))
+
+	account := &Account{UserUUID: "user-uuid"}
+	if err := cli.SignIn(context.Background(), account); err != nil {
+		t.Fatalf("SignIn() error = %v", err)
+	}
+	if account.sessionToken != "stored-token" {
+		t.Errorf("session token = %q; want the stored token", account.sessionToken)
+	}
+
+	if whoami == nil {
+		t.Fatal("stored session was not checked with whoami")
+	}
+	if slices.ContainsFunc(whoami.Args, func(arg string) bool { return strings.Contains(arg, "stored-token") }) {
+		t.Errorf("whoami args %v contain the session token", whoami.Args)
+	}
+	if !slices.Contains(whoami.Env, "OP_SESSION_user-uuid=stored-token") {
+		t.Errorf("whoami environment does not contain the session token")
+	}
+}
+
+func TestSecurityCommand(t *testing.T) {
+	got := securityCommand("add-generic-password", "-s", `my "service"`, "-w", `to\ken`)
+	expected := `"add-generic-password" "-s" "my \"service\"" "-w" "to\\ken"` + "\n"
+	if got != expected {
+		t.Errorf("securityCommand() = %q; want %q", got, expected)
+	}
+}
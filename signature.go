@@ -0,0 +1,378 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned if the signature of the op executable is
+// invalid or the executable is not signed by AgileBits.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrSignatureUnverified is returned by InstallOp if the signature of the
+// downloaded executable could not be checked, e.g. because gpg is not
+// installed or the operating system is not supported.
+var ErrSignatureUnverified = errors.New("signature not verified")
+
+// agileBitsOrganization is the organization AgileBits signs the 1Password CLI as.
+const agileBitsOrganization = "AgileBits Inc."
+
+// SignatureInfo describes the verified signer of the op executable.
+//
+// Fields:
+//   - Signer: The organization that signed the executable, e.g. "AgileBits Inc.".
+//   - Subject: The subject of the signing certificate or the first signing authority.
+//   - Issuer: The issuer of the signing certificate, if known.
+//   - Fingerprint: The thumbprint of the certificate or the fingerprint of the signing key, if known.
+//   - NotAfter: The expiry of the signing certificate, or the zero time if unknown.
+type SignatureInfo struct {
+	Signer      string    `json:"signer"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	NotAfter    time.Time `json:"not_after,omitzero"`
+}
+
+// VerifyOpExecutable verifies the digital signature of the specified executable file
+// to ensure it is signed by AgileBits Inc. (the developers of 1Password).
+//
+// The verification process varies depending on the operating system:
+//   - On macOS, it uses the `codesign` command to check the signature authority.
+//   - On Windows, it uses PowerShell's `Get-AuthenticodeSignature` to validate the signature
+//     and ensure it is signed by AgileBits.
+//   - On Linux, it uses GPG to verify the detached signature "op.sig" against the pinned
+//     AgileBits GPG key. A missing signature is downloaded with the release archive.
+//
+// If the verification fails or the executable is not signed by AgileBits, an error is returned.
+// On Linux, if GPG is not available, the function logs a warning and skips the verification.
+// The executable is never run before it is verified, so on Linux a missing signature can
+// only be downloaded with VerifyOpSignatureWith, which takes the version of the executable.
+//
+// Parameters:
+//   - path: The file path to the executable to be verified.
+//
+// Returns:
+//   - An error if the verification fails or the executable is not signed by AgileBits.
+//     Returns nil if the verification succeeds or is skipped (e.g., on Linux without GPG).
+func VerifyOpExecutable(path string) error {
+	_, err := VerifyOpSignature(path)
+	return err
+}
+
+// VerifyOpSignature verifies the signature of the op executable like
+// VerifyOpExecutable and returns details about the signer.
+//
+// Parameters:
+//   - path: The file path to the executable to be verified.
+//
+// Returns:
+//   - *SignatureInfo: The signer of the executable, or nil if the verification was skipped.
+//   - error: ErrInvalidSignature if the executable is not signed by AgileBits, or
+//     another error if the verification fails.
+func VerifyOpSignature(path string) (*SignatureInfo, error) {
+	return VerifyOpSignatureWith(path, VerifyOptions{})
+}
+
+// VerifyOptions configures VerifyOpSignatureWith.
+//
+// Fields:
+//   - Version: The version of the executable, e.g. "2.30.0". On Linux, the detached
+//     signature of this version is downloaded if none is found next to the executable.
+//   - HTTPClient: The client used to download the signature. If nil, http.DefaultClient is used.
+//   - Logger: The logger for progress and warnings. If nil, the default logger of the
+//     slog package is used.
+type VerifyOptions struct {
+	Version    string
+	HTTPClient *http.Client
+	Logger     *slog.Logger
+}
+
+// VerifyOpSignatureWith verifies the signature of the op executable like
+// VerifyOpSignature with the given options.
+//
+// Parameters:
+//   - path: The file path to the executable to be verified.
+//   - opts: The version of the executable and the logger.
+//
+// Returns:
+//   - *SignatureInfo: The signer of the executable, or nil if the verification was skipped.
+//   - error: ErrInvalidSignature if the executable is not signed by AgileBits, or
+//     another error if the verification fails.
+func VerifyOpSignatureWith(path string, opts VerifyOptions) (*SignatureInfo, error) {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return verifyCodesign(path)
+	case "windows":
+		return verifyAuthenticode(path)
+	case "linux":
+		return verifyGPG(path, opts)
+	}
+	return nil, nil
+}
+
+// verifyCodesign verifies the signature of the executable with codesign on macOS.
+func verifyCodesign(path string) (*SignatureInfo, error) {
+	cmd := exec.Command("codesign", "-d", "-vvv", path)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return parseCodesignOutput(output)
+}
+
+// parseCodesignOutput returns the signer from the output of "codesign -d -vvv".
+func parseCodesignOutput(output []byte) (*SignatureInfo, error) {
+	var authorities []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if authority, ok := strings.CutPrefix(strings.TrimSpace(line), "Authority="); ok {
+			authorities = append(authorities, authority)
+		}
+	}
+
+	const developerID = "Developer ID Application: " + agileBitsOrganization
+	if len(authorities) == 0 || !strings.HasPrefix(authorities[0], developerID) {
+		return nil, fmt.Errorf("%w: not signed by AgileBits", ErrInvalidSignature)
+	}
+
+	info := &SignatureInfo{Signer: agileBitsOrganization, Subject: authorities[0]}
+	if len(authorities) > 1 {
+		info.Issuer = authorities[1]
+	}
+	return info, nil
+}
+
+// authenticodeScript reads the Authenticode signature of the file in the
+// OP_SIGNATURE_PATH environment variable and prints it as JSON. The path is
+// passed through the environment, so it is never interpreted as PowerShell
+// code regardless of quotes or other special characters.
+const authenticodeScript = `$ErrorActionPreference = 'Stop'
+$sig = Get-AuthenticodeSignature -LiteralPath $env:OP_SIGNATURE_PATH
+$cert = $sig.SignerCertificate
+[pscustomobject]@{
+	Status        = [string]$sig.Status
+	StatusMessage = $sig.StatusMessage
+	Subject       = if ($cert) { $cert.Subject } else { '' }
+	Issuer        = if ($cert) { $cert.Issuer } else { '' }
+	Thumbprint    = if ($cert) { $cert.Thumbprint } else { '' }
+	NotAfter      = if ($cert) { $cert.NotAfter.ToUniversalTime().ToString('o') } else { '' }
+} | ConvertTo-Json -Compress`
+
+// verifyAuthenticode verifies the Authenticode signature of the executable on Windows.
+func verifyAuthenticode(path string) (*SignatureInfo, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", authenticodeScript)
+	cmd.Env = append(os.Environ(), "OP_SIGNATURE_PATH="+path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return parseAuthenticodeOutput(output)
+}
+
+// parseAuthenticodeOutput returns the signer from the output of authenticodeScript.
+func parseAuthenticodeOutput(output []byte) (*SignatureInfo, error) {
+	var sig struct {
+		Status        string `json:"Status"`
+		StatusMessage string `json:"StatusMessage"`
+		Subject       string `json:"Subject"`
+		Issuer        string `json:"Issuer"`
+		Thumbprint    string `json:"Thumbprint"`
+		NotAfter      string `json:"NotAfter"`
+	}
+	if err := json.Unmarshal(output, &sig); err != nil {
+		return nil, fmt.Errorf("failed to parse signature details: %w", err)
+	}
+
+	if sig.Status != "Valid" {
+		return nil, fmt.Errorf("%w: status %s: %s", ErrInvalidSignature, sig.Status, sig.StatusMessage)
+	}
+	if distinguishedNameValue(sig.Subject, "O") != agileBitsOrganization {
+		return nil, fmt.Errorf("%w: signed by %q, not AgileBits", ErrInvalidSignature, sig.Subject)
+	}
+
+	info := &SignatureInfo{
+		Signer:      agileBitsOrganization,
+		Subject:     sig.Subject,
+		Issuer:      sig.Issuer,
+		Fingerprint: sig.Thumbprint,
+	}
+	if sig.NotAfter != "" {
+		if notAfter, err := time.Parse(time.RFC3339Nano, sig.NotAfter); err == nil {
+			info.NotAfter = notAfter
+		}
+	}
+	return info, nil
+}
+
+// distinguishedNameValue returns the value of an attribute of a distinguished
+// name like `CN=AgileBits Inc., O="AgileBits Inc.", C=CA`.
+func distinguishedNameValue(dn, attribute string) string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range dn {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	parts = append(parts, current.String())
+
+	for _, part := range parts {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && strings.EqualFold(key, attribute) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// agileBitsKeyFingerprint is the fingerprint of the GPG key AgileBits signs
+// the 1Password CLI for Linux with.
+const agileBitsKeyFingerprint = "3FEF9748469ADBE15DA7CA80AC2D62742012EA22"
+
+// signatureDownloadTimeout limits the download of a missing detached signature.
+const signatureDownloadTimeout = 2 * time.Minute
+
+// verifyGPG verifies the executable against its detached signature "op.sig"
+// with GPG on Linux. The signature is looked up next to the executable and
+// downloaded with the release archive of the given version if it is
+// missing. Only signatures by the pinned AgileBits key are accepted.
+func verifyGPG(path string, opts VerifyOptions) (*SignatureInfo, error) {
+	// Check if gpg is available
+	if _, err := exec.LookPath("gpg"); err != nil {
+		opts.Logger.Warn("gpg not found, skipping signature verification on Linux")
+		return nil, nil
+	}
+
+	sigPath, cleanup, err := findOpSignature(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Verify if key is in keyring, if not fetch it
+	checkKey := exec.Command("gpg", "--batch", "--list-keys", agileBitsKeyFingerprint)
+	if err := checkKey.Run(); err != nil {
+		opts.Logger.Info("fetching AgileBits GPG key")
+		fetchKey := exec.Command("gpg", "--batch", "--keyserver", "hkps://keyserver.ubuntu.com", "--recv-keys", agileBitsKeyFingerprint)
+		if err := fetchKey.Run(); err != nil {
+			return nil, fmt.Errorf("failed to fetch AgileBits GPG key: %w", err)
+		}
+	}
+
+	// The machine readable status output reports the fingerprint of the key
+	// that made a valid signature
+	cmd := exec.Command("gpg", "--batch", "--status-fd", "1", "--verify", sigPath, path)
+	status, err := cmd.Output()
+	info, parseErr := parseGPGStatus(status, agileBitsKeyFingerprint)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GPG signature verification failed: %w", err)
+	}
+	return info, nil
+}
+
+// findOpSignature returns the path of the detached signature of the
+// executable. If no signature is found next to the executable, the signature
+// of the given version is downloaded to a temporary directory that is removed
+// by cleanup. The executable is not run to determine its version, as it is not
+// verified yet.
+func findOpSignature(path string, opts VerifyOptions) (sigPath string, cleanup func(), err error) {
+	for _, candidate := range []string{path + ".sig", filepath.Join(filepath.Dir(path), "op.sig")} {
+		if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() {
+			return candidate, func() {}, nil
+		}
+	}
+
+	version := strings.TrimPrefix(opts.Version, "v")
+	if version == "" {
+		return "", nil, fmt.Errorf("no signature found next to %s and no version given to download it", path)
+	}
+
+	dir, err := os.MkdirTemp("", "op-signature-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	opts.Logger.Info("downloading signature of 1Password CLI", "version", version)
+	ctx, cancel := context.WithTimeout(context.Background(), signatureDownloadTimeout)
+	defer cancel()
+	if err := downloadOp(ctx, opts.HTTPClient, version, dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to download signature: %w", err)
+	}
+
+	sigPath = filepath.Join(dir, "op.sig")
+	if _, err := os.Stat(sigPath); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("release %s contains no signature: %w", version, err)
+	}
+	return sigPath, cleanup, nil
+}
+
+// parseGPGStatus returns the signer from the status output of
+// "gpg --status-fd 1 --verify". The signature must be valid and made by the
+// key with the given fingerprint or one of its subkeys.
+func parseGPGStatus(status []byte, fingerprint string) (*SignatureInfo, error) {
+	var signer string
+	for _, line := range strings.Split(string(status), "\n") {
+		fields := strings.Fields(strings.TrimPrefix(line, "[GNUPG:] "))
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "BADSIG", "EXPSIG", "EXPKEYSIG", "REVKEYSIG":
+			return nil, fmt.Errorf("%w: gpg reported %s", ErrInvalidSignature, fields[0])
+		case "GOODSIG":
+			if len(fields) > 2 {
+				signer = strings.Join(fields[2:], " ")
+			}
+		case "VALIDSIG":
+			// VALIDSIG <fingerprint> <date> <timestamp> <expiry> <version>
+			// <reserved> <algorithm> <hash> <class> [<primary key fingerprint>]
+			if len(fields) < 2 {
+				continue
+			}
+			primary := fields[1]
+			if len(fields) > 10 {
+				primary = fields[10]
+			}
+			if !strings.EqualFold(fields[1], fingerprint) && !strings.EqualFold(primary, fingerprint) {
+				return nil, fmt.Errorf("%w: signed by key %s, not AgileBits", ErrInvalidSignature, primary)
+			}
+			return &SignatureInfo{
+				Signer:      agileBitsOrganization,
+				Subject:     signer,
+				Fingerprint: strings.ToUpper(primary),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no valid signature found", ErrInvalidSignature)
+}
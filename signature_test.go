@@ -0,0 +1,144 @@
+package onepassword
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseAuthenticodeOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		expectedErr error
+	}{
+		{
+			name:   "Signed by AgileBits",
+			output: `{"Status":"Valid","StatusMessage":"Signature verified.","Subject":"CN=AgileBits Inc., O=AgileBits Inc., L=Toronto, S=Ontario, C=CA","Issuer":"CN=DigiCert","Thumbprint":"ABC123","NotAfter":"2027-01-02T00:00:00.0000000Z"}`,
+		},
+		{
+			name:        "Invalid signature",
+			output:      `{"Status":"HashMismatch","StatusMessage":"The file has been modified.","Subject":"CN=AgileBits Inc., O=AgileBits Inc., C=CA"}`,
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Other organization containing AgileBits",
+			output:      `{"Status":"Valid","Subject":"CN=NotAgileBits, O=\"NotAgileBits, Ltd.\", C=CA"}`,
+			expectedErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseAuthenticodeOutput([]byte(tt.output))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("parseAuthenticodeOutput() error = %v; want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			if info.Signer != "AgileBits Inc." || info.Fingerprint != "ABC123" || info.NotAfter.Year() != 2027 {
+				t.Errorf("parseAuthenticodeOutput() = %+v; want AgileBits with thumbprint and expiry", info)
+			}
+		})
+	}
+}
+
+func TestParseCodesignOutput(t *testing.T) {
+	output := "Executable=/usr/local/bin/op\n" +
+		"Authority=Developer ID Application: AgileBits Inc. (2BUA8C4S2C)\n" +
+		"Authority=Developer ID Certification Authority\n" +
+		"Authority=Apple Root CA\n"
+
+	info, err := parseCodesignOutput([]byte(output))
+	if err != nil {
+		t.Fatalf("parseCodesignOutput() error = %v", err)
+	}
+	if info.Signer != "AgileBits Inc." || info.Issuer != "Developer ID Certification Authority" {
+		t.Errorf("parseCodesignOutput() = %+v; want AgileBits issued by Developer ID", info)
+	}
+
+	if _, err := parseCodesignOutput([]byte("Authority=Developer ID Application: Someone Else\n")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("parseCodesignOutput() error = %v; want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestParseGPGStatus(t *testing.T) {
+	const primary = "3FEF9748469ADBE15DA7CA80AC2D62742012EA22"
+
+	tests := []struct {
+		name        string
+		status      string
+		expectedErr error
+	}{
+		{
+			name: "Signed by subkey of AgileBits",
+			status: "[GNUPG:] NEWSIG\n" +
+				"[GNUPG:] GOODSIG AC2D62742012EA22 Code signing for 1Password <codesign@1password.com>\n" +
+				"[GNUPG:] VALIDSIG 1111222233334444555566667777888899990000 2024-01-02 1704153600 0 4 0 1 10 00 " + primary + "\n",
+		},
+		{
+			name: "Signed by another key",
+			status: "[GNUPG:] GOODSIG 0123456789ABCDEF Someone Else\n" +
+				"[GNUPG:] VALIDSIG 0000000000000000000000000123456789ABCDEF 2024-01-02 1704153600 0 4 0 1 10 00 0000000000000000000000000123456789ABCDEF\n",
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Bad signature",
+			status:      "[GNUPG:] BADSIG AC2D62742012EA22 Code signing for 1Password\n",
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Missing key",
+			status:      "[GNUPG:] ERRSIG AC2D62742012EA22 1 10 00 1704153600 9 -\n[GNUPG:] NO_PUBKEY AC2D62742012EA22\n",
+			expectedErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseGPGStatus([]byte(tt.status), primary)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("parseGPGStatus() error = %v; want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr == nil && (info.Fingerprint != primary || info.Subject == "") {
+				t.Errorf("parseGPGStatus() = %+v; want fingerprint %s and a subject", info, primary)
+			}
+		})
+	}
+}
+
+func TestFindOpSignature(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on Windows")
+	}
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "executed")
+	path := filepath.Join(dir, "op")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\ntouch "+marker+"\necho 2.30.0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := findOpSignature(path, VerifyOptions{}); err == nil {
+		t.Error("findOpSignature() without signature and version succeeded; want an error")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("findOpSignature() ran the unverified executable")
+	}
+
+	sigPath := filepath.Join(dir, "op.sig")
+	if err := os.WriteFile(sigPath, []byte("signature"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, cleanup, err := findOpSignature(path, VerifyOptions{Version: "2.30.0"})
+	if err != nil {
+		t.Fatalf("findOpSignature() error = %v", err)
+	}
+	defer cleanup()
+	if got != sigPath {
+		t.Errorf("findOpSignature() = %s; want the sibling signature %s", got, sigPath)
+	}
+}
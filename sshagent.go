@@ -0,0 +1,346 @@
+package onepassword
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// sshKeysTable is the array of tables in agent.toml that lists the keys of the agent.
+const sshKeysTable = "[[ssh-keys]]"
+
+// SSHAgentKey is an entry of the 1Password SSH agent configuration. Each
+// entry makes the SSH keys it matches available to the agent. Empty fields
+// match any value, so an entry with only a vault makes all SSH keys of the
+// vault available.
+//
+// Fields:
+//   - Item: The title or ID of an SSH key item.
+//   - Vault: The name or ID of a vault.
+//   - Account: The sign-in address or ID of an account.
+type SSHAgentKey struct {
+	Item    string
+	Vault   string
+	Account string
+}
+
+// matches reports whether the entry matches an SSH key item.
+func (key SSHAgentKey) matches(item Item) bool {
+	if key.Item != "" && key.Item != item.ID && key.Item != item.Title {
+		return false
+	}
+	if key.Vault != "" && key.Vault != item.Vault.ID && key.Vault != item.Vault.Name {
+		return false
+	}
+	return key.Item != "" || key.Vault != ""
+}
+
+// SSHAgentConfig is the configuration file of the 1Password SSH agent
+// (agent.toml), which selects the SSH keys the agent offers. If the file does
+// not exist, the agent offers the keys of the Private vault of all accounts.
+//
+// Fields:
+//   - Path: The path of the configuration file.
+//   - Keys: The entries of the file in order.
+type SSHAgentConfig struct {
+	Path string
+	Keys []SSHAgentKey
+}
+
+// SSHAgentConfigPath returns the default path of the agent.toml file of the
+// 1Password SSH agent for the current operating system.
+//
+// Returns:
+//   - string: The path of the configuration file.
+//   - error: An error if the home or configuration directory cannot be determined.
+func SSHAgentConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return sshAgentConfigPath(runtime.GOOS, home, os.Getenv), nil
+}
+
+// sshAgentConfigPath returns the path of agent.toml for an operating system.
+func sshAgentConfigPath(goos, home string, getenv func(string) string) string {
+	switch goos {
+	case "windows":
+		localAppData := getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			localAppData = filepath.Join(home, "AppData", "Local")
+		}
+		return filepath.Join(localAppData, "1Password", "config", "ssh", "agent.toml")
+	case "linux":
+		if configHome := getenv("XDG_CONFIG_HOME"); configHome != "" {
+			return filepath.Join(configHome, "1Password", "ssh", "agent.toml")
+		}
+	}
+	return filepath.Join(home, ".config", "1Password", "ssh", "agent.toml")
+}
+
+// SSHAgentSocketPath returns the address of the 1Password SSH agent for the
+// current operating system, which is used as SSH_AUTH_SOCK or IdentityAgent.
+// On Windows, the agent listens on the OpenSSH named pipe.
+//
+// Returns:
+//   - string: The path of the agent socket or named pipe.
+//   - error: An error if the home directory cannot be determined.
+func SSHAgentSocketPath() (string, error) {
+	if runtime.GOOS == "windows" {
+		return sshAgentSocketPath(runtime.GOOS, ""), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return sshAgentSocketPath(runtime.GOOS, home), nil
+}
+
+// sshAgentSocketPath returns the agent socket for an operating system.
+func sshAgentSocketPath(goos, home string) string {
+	switch goos {
+	case "windows":
+		return `\\.\pipe\openssh-ssh-agent`
+	case "darwin":
+		return filepath.Join(home, "Library", "Group Containers", "2BUA8C4S2C.com.1password", "t", "agent.sock")
+	default:
+		return filepath.Join(home, ".1password", "agent.sock")
+	}
+}
+
+// LoadSSHAgentConfig reads the agent.toml file of the 1Password SSH agent.
+// A missing file results in a configuration without entries.
+//
+// Parameters:
+//   - path: The path of the file. If empty, SSHAgentConfigPath is used.
+//
+// Returns:
+//   - *SSHAgentConfig: The configuration.
+//   - error: An error if the file cannot be read or contains unsupported content.
+func LoadSSHAgentConfig(path string) (*SSHAgentConfig, error) {
+	if path == "" {
+		var err error
+		if path, err = SSHAgentConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	config := &SSHAgentConfig{Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH agent configuration: %w", err)
+	}
+
+	if config.Keys, err = parseSSHAgentConfig(data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// parseSSHAgentConfig parses the [[ssh-keys]] entries of agent.toml. Only the
+// subset of TOML used by the file is supported.
+func parseSSHAgentConfig(data []byte) ([]SSHAgentKey, error) {
+	var keys []SSHAgentKey
+	var current *SSHAgentKey
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if text == sshKeysTable {
+			keys = append(keys, SSHAgentKey{})
+			current = &keys[len(keys)-1]
+			continue
+		}
+
+		name, rawValue, ok := strings.Cut(text, "=")
+		if !ok || current == nil {
+			return nil, fmt.Errorf("line %d: unsupported content %q", line, text)
+		}
+
+		value, err := parseTOMLString(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch strings.TrimSpace(name) {
+		case "item":
+			current.Item = value
+		case "vault":
+			current.Vault = value
+		case "account":
+			current.Account = value
+		default:
+			return nil, fmt.Errorf("line %d: unsupported key %q", line, strings.TrimSpace(name))
+		}
+	}
+
+	return keys, scanner.Err()
+}
+
+// parseTOMLString parses a basic or literal TOML string, followed by an
+// optional comment.
+func parseTOMLString(value string) (string, error) {
+	if strings.HasPrefix(value, "'") {
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : end+1], checkTrailingComment(value[end+2:])
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		for end := 1; end < len(value); end++ {
+			switch value[end] {
+			case '\\':
+				end++
+			case '"':
+				unquoted, err := strconv.Unquote(value[:end+1])
+				if err != nil {
+					return "", fmt.Errorf("invalid string %s", value[:end+1])
+				}
+				return unquoted, checkTrailingComment(value[end+1:])
+			}
+		}
+		return "", fmt.Errorf("unterminated string %s", value)
+	}
+
+	return "", fmt.Errorf("unsupported value %s", value)
+}
+
+// checkTrailingComment ensures that only a comment follows a value.
+func checkTrailingComment(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected content %q", rest)
+	}
+	return nil
+}
+
+// quoteTOMLString quotes a value as a basic TOML string.
+func quoteTOMLString(value string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&builder, `\u%04X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// Add appends an entry to the configuration unless an equal entry exists.
+//
+// Parameters:
+//   - key: The entry to add. Item or Vault must be set.
+//
+// Returns:
+//   - bool: true if the entry was added.
+//   - error: An error if neither Item nor Vault is set.
+func (c *SSHAgentConfig) Add(key SSHAgentKey) (bool, error) {
+	if key.Item == "" && key.Vault == "" {
+		return false, errors.New("SSH agent entry requires an item or a vault")
+	}
+	if slices.Contains(c.Keys, key) {
+		return false, nil
+	}
+
+	c.Keys = append(c.Keys, key)
+	return true, nil
+}
+
+// Remove removes all entries equal to key from the configuration.
+//
+// Parameters:
+//   - key: The entry to remove.
+//
+// Returns:
+//   - bool: true if an entry was removed.
+func (c *SSHAgentConfig) Remove(key SSHAgentKey) bool {
+	count := len(c.Keys)
+	c.Keys = slices.DeleteFunc(c.Keys, func(k SSHAgentKey) bool { return k == key })
+	return len(c.Keys) != count
+}
+
+// Includes reports whether an entry of the configuration matches the SSH key
+// item, i.e. whether the agent offers it. Entries are matched by the IDs and
+// names of the item and its vault; the account of entries is not checked.
+//
+// Parameters:
+//   - item: The SSH key item.
+//
+// Returns:
+//   - bool: true if the item is offered by the agent.
+func (c *SSHAgentConfig) Includes(item Item) bool {
+	return slices.ContainsFunc(c.Keys, func(key SSHAgentKey) bool { return key.matches(item) })
+}
+
+// Save writes the configuration to its path. The 1Password app reloads the
+// file automatically. Comments of the original file are not preserved.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func (c *SSHAgentConfig) Save() error {
+	var builder strings.Builder
+	builder.WriteString("# 1Password SSH agent configuration\n")
+	builder.WriteString("# https://developer.1password.com/docs/ssh/agent/config\n")
+
+	for _, key := range c.Keys {
+		builder.WriteString("\n" + sshKeysTable + "\n")
+		for _, field := range []struct{ name, value string }{
+			{"item", key.Item},
+			{"vault", key.Vault},
+			{"account", key.Account},
+		} {
+			if field.value != "" {
+				fmt.Fprintf(&builder, "%s = %s\n", field.name, quoteTOMLString(field.value))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create SSH agent configuration directory: %w", err)
+	}
+	return writeSecretFile(c.Path, []byte(builder.String()))
+}
+
+// ListSSHKeys retrieves all SSH key items that can be offered by the
+// 1Password SSH agent. Use SSHAgentConfig.Includes to check which of them
+// are currently configured.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - []Item: The SSH key items.
+//   - error: An error if the items cannot be listed.
+func (cli *OpCLI) ListSSHKeys(ctx context.Context) ([]Item, error) {
+	items, err := cli.GetItemsByCategory(ctx, []Category{CategorySSHKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+
+	return *items, nil
+}
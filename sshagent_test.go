@@ -0,0 +1,150 @@
+package onepassword
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSSHAgentConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []SSHAgentKey
+		wantErr  bool
+	}{
+		{
+			name: "Entries with comments",
+			content: `# Keys of the agent
+[[ssh-keys]]
+vault = "Private"
+
+[[ssh-keys]]
+item = 'GitHub "signing" key' # inline comment
+vault = "Work"
+account = "example.1password.com"
+`,
+			expected: []SSHAgentKey{
+				{Vault: "Private"},
+				{Item: `GitHub "signing" key`, Vault: "Work", Account: "example.1password.com"},
+			},
+		},
+		{
+			name:    "Key outside of table",
+			content: `vault = "Private"`,
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported key",
+			content: "[[ssh-keys]]\nkey = \"value\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated string",
+			content: "[[ssh-keys]]\nvault = \"Private\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseSSHAgentConfig([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSSHAgentConfig() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(keys, tt.expected) {
+				t.Errorf("parseSSHAgentConfig() = %+v; want %+v", keys, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSHAgentConfigSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1Password", "ssh", "agent.toml")
+
+	config, err := LoadSSHAgentConfig(path)
+	if err != nil || len(config.Keys) != 0 {
+		t.Fatalf("LoadSSHAgentConfig() of missing file = %+v, %v; want no entries", config, err)
+	}
+
+	for _, key := range []SSHAgentKey{{Vault: "Private"}, {Item: "Deploy\\key", Vault: "Work"}, {Vault: "Private"}} {
+		if _, err := config.Add(key); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if _, err := config.Add(SSHAgentKey{Account: "example.1password.com"}); err == nil {
+		t.Error("Add() without item and vault succeeded")
+	}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadSSHAgentConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSSHAgentConfig() error = %v", err)
+	}
+	expected := []SSHAgentKey{{Vault: "Private"}, {Item: "Deploy\\key", Vault: "Work"}}
+	if !reflect.DeepEqual(loaded.Keys, expected) {
+		t.Errorf("LoadSSHAgentConfig() = %+v; want %+v", loaded.Keys, expected)
+	}
+
+	item := Item{ID: "item-id", Title: "Deploy\\key", Vault: Vault{ID: "vault-id", Name: "Work"}}
+	if !loaded.Includes(item) {
+		t.Error("Includes() = false; want true")
+	}
+	if !loaded.Remove(SSHAgentKey{Item: "Deploy\\key", Vault: "Work"}) || loaded.Includes(item) {
+		t.Error("Remove() did not remove the entry")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Stat() error = %v", err)
+	}
+}
+
+func TestSSHAgentPaths(t *testing.T) {
+	getenv := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	tests := []struct {
+		goos           string
+		env            map[string]string
+		expectedConfig string
+		expectedSocket string
+	}{
+		{
+			goos:           "linux",
+			expectedConfig: filepath.Join("/home/jane", ".config", "1Password", "ssh", "agent.toml"),
+			expectedSocket: filepath.Join("/home/jane", ".1password", "agent.sock"),
+		},
+		{
+			goos:           "linux",
+			env:            map[string]string{"XDG_CONFIG_HOME": "/xdg"},
+			expectedConfig: filepath.Join("/xdg", "1Password", "ssh", "agent.toml"),
+			expectedSocket: filepath.Join("/home/jane", ".1password", "agent.sock"),
+		},
+		{
+			goos:           "darwin",
+			expectedConfig: filepath.Join("/home/jane", ".config", "1Password", "ssh", "agent.toml"),
+			expectedSocket: filepath.Join("/home/jane", "Library", "Group Containers", "2BUA8C4S2C.com.1password", "t", "agent.sock"),
+		},
+		{
+			goos:           "windows",
+			env:            map[string]string{"LOCALAPPDATA": "/appdata"},
+			expectedConfig: filepath.Join("/appdata", "1Password", "config", "ssh", "agent.toml"),
+			expectedSocket: `\\.\pipe\openssh-ssh-agent`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			if path := sshAgentConfigPath(tt.goos, "/home/jane", getenv(tt.env)); path != tt.expectedConfig {
+				t.Errorf("sshAgentConfigPath() = %q; want %q", path, tt.expectedConfig)
+			}
+			if path := sshAgentSocketPath(tt.goos, "/home/jane"); path != tt.expectedSocket {
+				t.Errorf("sshAgentSocketPath() = %q; want %q", path, tt.expectedSocket)
+			}
+		})
+	}
+}
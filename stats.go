@@ -0,0 +1,232 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// categories are the categories known to the package, used to normalize
+// the category names printed by the CLI.
+var categories = []Category{
+	CategoryAPICredential, CategoryBankAccount, CategoryCreditCard, CategoryCryptoWallet,
+	CategoryDatabase, CategoryDocument, CategoryDriverLicense, CategoryEmailAccount,
+	CategoryIdentity, CategoryLogin, CategoryMedicalRecord, CategoryMembership,
+	CategoryOutdoorLicense, CategoryPassport, CategoryPassword, CategoryRewardProgram,
+	CategorySecureNote, CategoryServer, CategorySocialSecurity, CategorySoftwareLicense,
+	CategorySSHKey, CategoryWirelessRouter,
+}
+
+// VaultStats are the item counts of a vault.
+//
+// Fields:
+//   - Total: The number of items, including archived items.
+//   - Archived: The number of archived items.
+//   - Favorites: The number of active items marked as favorite.
+//   - Categories: The number of active items per category. The categories printed by the CLI, e.g.
+//     "SECURE_NOTE", are normalized to the Category constants, e.g. CategorySecureNote.
+//   - Tags: The number of active items per tag. Items with several tags are counted for each of them.
+//   - Untagged: The number of active items without tags.
+type VaultStats struct {
+	Total      int              `json:"total"`
+	Archived   int              `json:"archived"`
+	Favorites  int              `json:"favorites"`
+	Categories map[Category]int `json:"categories"`
+	Tags       map[string]int   `json:"tags"`
+	Untagged   int              `json:"untagged"`
+}
+
+// Active returns the number of items that are not archived.
+func (s *VaultStats) Active() int {
+	return s.Total - s.Archived
+}
+
+// Stats counts the items of the vault by category, tag, favorite and
+// archived state. The items are listed with a single "item list" command
+// scoped to the vault, without fetching their fields.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - *VaultStats: The item counts.
+//   - error: An error if the items cannot be listed.
+//
+// Example usage:
+//
+//	stats, err := vault.Stats(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to get vault statistics: %v", err)
+//	}
+//	fmt.Printf("%d active logins\n", stats.Categories[onepassword.CategoryLogin])
+func (vault *Vault) Stats(ctx context.Context) (*VaultStats, error) {
+	stats := &VaultStats{Categories: make(map[Category]int), Tags: make(map[string]int)}
+	err := vault.cli.StreamItems(ctx, func(item Item) error {
+		stats.add(item)
+		return nil
+	}, ListItemsOptions{Vault: vault.ID, IncludeArchive: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items of vault %s: %w", vault.Name, err)
+	}
+
+	return stats, nil
+}
+
+// add counts an item.
+func (s *VaultStats) add(item Item) {
+	s.Total++
+	if item.State == ItemStateArchived {
+		s.Archived++
+		return
+	}
+
+	if item.Favorite {
+		s.Favorites++
+	}
+	s.Categories[normalizeCategory(item.Category)]++
+	if len(item.Tags) == 0 {
+		s.Untagged++
+	}
+	for _, tag := range item.Tags {
+		s.Tags[tag]++
+	}
+}
+
+// normalizeCategory returns the Category constant of a category printed by
+// the CLI, e.g. CategorySecureNote for "SECURE_NOTE". Unknown categories are
+// returned unchanged.
+func normalizeCategory(category Category) Category {
+	name := strings.ReplaceAll(string(category), "_", " ")
+	for _, known := range categories {
+		if strings.EqualFold(string(known), name) {
+			return known
+		}
+	}
+	return category
+}
+
+// DefaultAgeBuckets are the upper bounds of the age buckets of ItemStats if
+// StatsScope has none: 30 days, 90 days and one year.
+var DefaultAgeBuckets = []time.Duration{30 * 24 * time.Hour, 90 * 24 * time.Hour, 365 * 24 * time.Hour}
+
+// StatsScope selects the items counted by ItemStats.
+//
+// Fields:
+//   - Vault: Only count items in this vault (name or ID). All vaults if empty.
+//   - Categories: Only count items of these categories.
+//   - IncludeArchive: Also count archived items.
+//   - AgeBuckets: The ascending upper bounds of the age buckets. Defaults to DefaultAgeBuckets.
+type StatsScope struct {
+	Vault          string
+	Categories     []Category
+	IncludeArchive bool
+	AgeBuckets     []time.Duration
+}
+
+// AgeBucket is the number of items whose time since the last update lies
+// between MinAge (inclusive) and MaxAge (exclusive). MaxAge is zero for the
+// last bucket, which has no upper bound.
+type AgeBucket struct {
+	MinAge time.Duration `json:"min_age"`
+	MaxAge time.Duration `json:"max_age"`
+	Count  int           `json:"count"`
+}
+
+// ItemStats are the item counts of an account or vault.
+//
+// Fields:
+//   - Total: The number of counted items.
+//   - Categories: The number of items per category, normalized like VaultStats.Categories.
+//   - Vaults: The number of items per vault name.
+//   - Tags: The number of items per tag. Items with several tags are counted for each of them.
+//   - Untagged: The number of items without tags.
+//   - Ages: The number of items per age bucket, in ascending order of age.
+type ItemStats struct {
+	Total      int              `json:"total"`
+	Categories map[Category]int `json:"categories"`
+	Vaults     map[string]int   `json:"vaults"`
+	Tags       map[string]int   `json:"tags"`
+	Untagged   int              `json:"untagged"`
+	Ages       []AgeBucket      `json:"ages"`
+}
+
+// ItemStats counts items by category, vault, tag and age in a single pass
+// over the output of "item list". The age of an item is the time since its
+// last update, which helps to find stale items.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - scope: The items to count.
+//
+// Returns:
+//   - *ItemStats: The item counts.
+//   - error: An error if the age buckets are not ascending or the items cannot be listed.
+//
+// Example usage:
+//
+//	stats, err := cli.ItemStats(ctx, onepassword.StatsScope{Categories: []onepassword.Category{onepassword.CategoryLogin}})
+//	if err != nil {
+//	    log.Fatalf("Failed to get item statistics: %v", err)
+//	}
+//	stale := stats.Ages[len(stats.Ages)-1].Count
+//	fmt.Printf("%d logins were not updated for over a year\n", stale)
+func (cli *OpCLI) ItemStats(ctx context.Context, scope StatsScope) (*ItemStats, error) {
+	bounds := scope.AgeBuckets
+	if len(bounds) == 0 {
+		bounds = DefaultAgeBuckets
+	}
+
+	stats := &ItemStats{
+		Categories: make(map[Category]int),
+		Vaults:     make(map[string]int),
+		Tags:       make(map[string]int),
+	}
+	var minAge time.Duration
+	for _, bound := range bounds {
+		if bound <= minAge {
+			return nil, fmt.Errorf("age buckets must be positive and ascending, got %v", bounds)
+		}
+		stats.Ages = append(stats.Ages, AgeBucket{MinAge: minAge, MaxAge: bound})
+		minAge = bound
+	}
+	stats.Ages = append(stats.Ages, AgeBucket{MinAge: minAge})
+
+	now := time.Now()
+	err := cli.StreamItems(ctx, func(item Item) error {
+		stats.add(item, now)
+		return nil
+	}, ListItemsOptions{Vault: scope.Vault, Categories: scope.Categories, IncludeArchive: scope.IncludeArchive})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	return stats, nil
+}
+
+// add counts an item.
+func (s *ItemStats) add(item Item, now time.Time) {
+	s.Total++
+	s.Categories[normalizeCategory(item.Category)]++
+
+	vault := item.Vault.Name
+	if vault == "" {
+		vault = item.Vault.ID
+	}
+	s.Vaults[vault]++
+
+	if len(item.Tags) == 0 {
+		s.Untagged++
+	}
+	for _, tag := range item.Tags {
+		s.Tags[tag]++
+	}
+
+	age := now.Sub(item.UpdatedAt)
+	for i := range s.Ages {
+		if s.Ages[i].MaxAge == 0 || age < s.Ages[i].MaxAge {
+			s.Ages[i].Count++
+			return
+		}
+	}
+}
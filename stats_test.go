@@ -0,0 +1,125 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestVaultStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		output         string
+		expected       VaultStats
+		expectedActive int
+	}{
+		{
+			name:     "Empty vault",
+			output:   `[]`,
+			expected: VaultStats{Categories: map[Category]int{}, Tags: map[string]int{}},
+		},
+		{
+			name: "Mixed items",
+			output: `[
+				{"id":"1","title":"A","category":"LOGIN","favorite":true,"tags":["prod","db"]},
+				{"id":"2","title":"B","category":"LOGIN","tags":["prod"]},
+				{"id":"3","title":"C","category":"SECURE_NOTE"},
+				{"id":"4","title":"D","category":"LOGIN","favorite":true,"state":"ARCHIVED","tags":["prod"]}
+			]`,
+			expected: VaultStats{
+				Total:      4,
+				Archived:   1,
+				Favorites:  1,
+				Categories: map[Category]int{CategoryLogin: 2, CategorySecureNote: 1},
+				Tags:       map[string]int{"prod": 2, "db": 1},
+				Untagged:   1,
+			},
+			expectedActive: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				args = cmd.Args
+				return []byte(tt.output), nil, nil
+			}))
+			vault := Vault{cli: cli, ID: "vault-id", Name: "Private"}
+
+			stats, err := vault.Stats(context.Background())
+			if err != nil {
+				t.Fatalf("Stats() error = %v", err)
+			}
+			if !slices.Contains(args, "--include-archive") || !slices.Contains(args, "vault-id") {
+				t.Errorf("Stats() args = %v; want a vault-scoped listing including archived items", args)
+			}
+
+			if stats.Total != tt.expected.Total || stats.Archived != tt.expected.Archived ||
+				stats.Favorites != tt.expected.Favorites || stats.Untagged != tt.expected.Untagged {
+				t.Errorf("Stats() = %+v; want %+v", *stats, tt.expected)
+			}
+			if !maps.Equal(stats.Categories, tt.expected.Categories) || !maps.Equal(stats.Tags, tt.expected.Tags) {
+				t.Errorf("Stats() categories = %v, tags = %v; want %v, %v",
+					stats.Categories, stats.Tags, tt.expected.Categories, tt.expected.Tags)
+			}
+			if stats.Active() != tt.expectedActive {
+				t.Errorf("Active() = %d; want %d", stats.Active(), tt.expectedActive)
+			}
+		})
+	}
+}
+
+func TestItemStats(t *testing.T) {
+	day := 24 * time.Hour
+	updated := func(age time.Duration) string {
+		return time.Now().Add(-age).UTC().Format(time.RFC3339)
+	}
+	output := fmt.Sprintf(`[
+		{"id":"1","title":"A","category":"LOGIN","vault":{"id":"v1","name":"Private"},"tags":["prod"],"updated_at":%q},
+		{"id":"2","title":"B","category":"LOGIN","vault":{"id":"v2","name":"Shared"},"updated_at":%q},
+		{"id":"3","title":"C","category":"SECURE_NOTE","vault":{"id":"v2","name":"Shared"},"tags":["prod","db"],"updated_at":%q},
+		{"id":"4","title":"D","category":"PASSWORD","vault":{"id":"v1","name":"Private"},"updated_at":%q}
+	]`, updated(day), updated(10*day), updated(100*day), updated(800*day))
+
+	var args []string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		args = cmd.Args
+		return []byte(output), nil, nil
+	}))
+
+	stats, err := cli.ItemStats(context.Background(), StatsScope{IncludeArchive: true})
+	if err != nil {
+		t.Fatalf("ItemStats() error = %v", err)
+	}
+	if !slices.Contains(args, "--include-archive") || slices.Contains(args, "--vault") {
+		t.Errorf("ItemStats() args = %v; want an account-wide listing including archived items", args)
+	}
+
+	if stats.Total != 4 || stats.Untagged != 2 {
+		t.Errorf("ItemStats() = %+v; want 4 items, 2 untagged", *stats)
+	}
+	if !maps.Equal(stats.Categories, map[Category]int{CategoryLogin: 2, CategorySecureNote: 1, CategoryPassword: 1}) {
+		t.Errorf("ItemStats() categories = %v", stats.Categories)
+	}
+	if !maps.Equal(stats.Vaults, map[string]int{"Private": 2, "Shared": 2}) || !maps.Equal(stats.Tags, map[string]int{"prod": 2, "db": 1}) {
+		t.Errorf("ItemStats() vaults = %v, tags = %v", stats.Vaults, stats.Tags)
+	}
+
+	var counts []int
+	for _, bucket := range stats.Ages {
+		counts = append(counts, bucket.Count)
+	}
+	if !slices.Equal(counts, []int{2, 0, 1, 1}) {
+		t.Errorf("ItemStats() age buckets = %v; want [2 0 1 1]", counts)
+	}
+
+	if _, err := cli.ItemStats(context.Background(), StatsScope{AgeBuckets: []time.Duration{90 * day, 30 * day}}); err == nil {
+		t.Error("ItemStats() with descending age buckets error = nil; want an error")
+	}
+}
@@ -0,0 +1,198 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ListItemsOptions filters the items returned by StreamItems.
+//
+// Fields:
+//   - Vault: Only list items in this vault (name or ID).
+//   - Categories: Only list items of these categories.
+//   - Tags: Only list items with any of these tags.
+//   - IncludeArchive: Also list archived items. Their State is ItemStateArchived.
+type ListItemsOptions struct {
+	Vault          string
+	Categories     []Category
+	Tags           []string
+	IncludeArchive bool
+}
+
+// StreamItems lists items and calls fn for every item as soon as it is
+// decoded from the output of the 1Password CLI, without buffering the whole
+// listing in memory. Listing stops when fn returns an error, which is then
+// returned by StreamItems.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - fn: The function called for every item.
+//   - opts: Optional ListItemsOptions to filter the items.
+//
+// Returns:
+//   - error: An error if the command fails, the output cannot be decoded or fn returns an error.
+//
+// Example usage:
+//
+//	err := cli.StreamItems(ctx, func(item onepassword.Item) error {
+//	    fmt.Println(item.Title)
+//	    return nil
+//	}, onepassword.ListItemsOptions{Vault: "Private"})
+func (cli *OpCLI) StreamItems(ctx context.Context, fn func(Item) error, opts ...ListItemsOptions) error {
+	return streamList(ctx, cli, func(item Item) error {
+		item.cli = cli
+		return fn(item)
+	}, itemListArgs(opts...)...)
+}
+
+// itemListArgs returns the arguments of "item list" for the options.
+func itemListArgs(opts ...ListItemsOptions) []string {
+	args := []string{"item", "list"}
+	if len(opts) > 0 {
+		if opts[0].Vault != "" {
+			args = append(args, "--vault", opts[0].Vault)
+		}
+		if len(opts[0].Categories) > 0 {
+			args = append(args, "--categories", FormatCategories(opts[0].Categories))
+		}
+		if len(opts[0].Tags) > 0 {
+			args = append(args, "--tags", strings.Join(opts[0].Tags, ","))
+		}
+		if opts[0].IncludeArchive {
+			args = append(args, "--include-archive")
+		}
+	}
+	return args
+}
+
+// listItems collects the items of a streamed listing into a slice.
+func (cli *OpCLI) listItems(ctx context.Context, opts ListItemsOptions) (*[]Item, error) {
+	items := []Item{}
+	err := cli.StreamItems(ctx, func(item Item) error {
+		items = append(items, item)
+		return nil
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+// collectList runs a list command and decodes its output element by element
+// into a slice.
+func collectList[T any](ctx context.Context, cli *OpCLI, args ...string) ([]T, error) {
+	list := []T{}
+	err := streamList(ctx, cli, func(element T) error {
+		list = append(list, element)
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// streamList runs a list command and calls fn for every element of the JSON
+// array written to its standard output while the command is still running.
+//
+// The slot of the command in the pool is released while fn runs, so commands
+// run by fn do not wait for the listing, which is blocked writing its output
+// in the meantime anyway.
+func streamList[T any](ctx context.Context, cli *OpCLI, fn func(T) error, args ...string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	release, err := cli.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if release != nil {
+			release()
+		}
+	}()
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := cli.executeOpCommandWith(withPoolSlot(ctx), execSettings{stdout: writer}, args...)
+		writer.CloseWithError(err)
+		done <- err
+	}()
+
+	decode := func(raw json.RawMessage) error {
+		var element T
+		if err := cli.unmarshal(raw, &element); err != nil {
+			return err
+		}
+
+		release()
+		release = nil
+		if err := fn(element); err != nil {
+			return err
+		}
+		release, err = cli.pool.acquire(ctx)
+		return err
+	}
+
+	if err := decodeJSONArray(reader, decode); err != nil {
+		// Stop the command and unblock its writes if decoding ended early
+		cancel()
+		reader.CloseWithError(err)
+		<-done
+		return err
+	}
+
+	return <-done
+}
+
+// decodeJSONArray decodes the elements of a JSON array one at a time and
+// calls fn for each of them. Empty input is treated as an empty array. The
+// input is read to the end, and only whitespace may follow the array.
+func decodeJSONArray[T any](r io.Reader, fn func(T) error) error {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", token)
+	}
+
+	for decoder.More() {
+		var element T
+		if err := decoder.Decode(&element); err != nil {
+			return err
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	// Read the rest of the output, so a writer of a pipe is not blocked by
+	// a trailing newline, and reject anything else after the array
+	rest, err := io.ReadAll(io.MultiReader(decoder.Buffered(), r))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return fmt.Errorf("unexpected output after JSON array")
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONArray(t *testing.T) {
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name        string
+		input       string
+		stopAt      string
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "Elements",
+			input:    `[{"id":"a"},{"id":"b"},{"id":"c"}]`,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "Empty array",
+			input:    `[]`,
+			expected: nil,
+		},
+		{
+			name:     "Empty output",
+			input:    ``,
+			expected: nil,
+		},
+		{
+			name:        "Stop early",
+			input:       `[{"id":"a"},{"id":"b"},{"id":"c"}]`,
+			stopAt:      "b",
+			expected:    []string{"a", "b"},
+			expectedErr: true,
+		},
+		{
+			name:     "Trailing newline",
+			input:    "[{\"id\":\"a\"}]\n",
+			expected: []string{"a"},
+		},
+		{
+			name:        "Trailing output",
+			input:       `[{"id":"a"}] {"id":"b"}`,
+			expected:    []string{"a"},
+			expectedErr: true,
+		},
+		{
+			name:        "Not an array",
+			input:       `{"id":"a"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Truncated",
+			input:       `[{"id":"a"},{"id":`,
+			expected:    []string{"a"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := decodeJSONArray(strings.NewReader(tt.input), func(v Vault) error {
+				got = append(got, v.ID)
+				if v.ID == tt.stopAt {
+					return errStop
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("decodeJSONArray() error = %v; want error %v", err, tt.expectedErr)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("decodeJSONArray() elements = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStreamItems(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ListItemsOptions
+		stdout       string
+		trailing     string
+		err          error
+		expectedArgs []string
+		expected     []string
+		expectedErr  bool
+	}{
+		{
+			name:         "All items",
+			stdout:       `[{"id":"a","title":"A"},{"id":"b","title":"B"}]`,
+			expectedArgs: []string{"item", "list", "--account", "user-uuid", "--format=json"},
+			expected:     []string{"A", "B"},
+		},
+		{
+			name:         "Filtered",
+			opts:         []ListItemsOptions{{Vault: "Private", Categories: []Category{CategoryLogin}, Tags: []string{"a", "b"}}},
+			stdout:       `[{"id":"a","title":"A"}]`,
+			expectedArgs: []string{"item", "list", "--vault", "Private", "--categories", "Login", "--tags", "a,b", "--account", "user-uuid", "--format=json"},
+			expected:     []string{"A"},
+		},
+		{
+			name:         "Trailing newline in a later write",
+			stdout:       `[{"id":"a","title":"A"}]`,
+			trailing:     "\n",
+			expectedArgs: []string{"item", "list", "--account", "user-uuid", "--format=json"},
+			expected:     []string{"A"},
+		},
+		{
+			name:         "Trailing output",
+			stdout:       `[{"id":"a","title":"A"}]`,
+			trailing:     "warning: update available\n",
+			expectedArgs: []string{"item", "list", "--account", "user-uuid", "--format=json"},
+			expected:     []string{"A"},
+			expectedErr:  true,
+		},
+		{
+			name:         "Command fails",
+			err:          errors.New("exit status 1"),
+			expectedArgs: []string{"item", "list", "--account", "user-uuid", "--format=json"},
+			expectedErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				gotArgs = cmd.Args
+				if _, err := cmd.Stdout.Write([]byte(tt.stdout)); err != nil {
+					return nil, nil, err
+				}
+				if tt.trailing != "" {
+					if _, err := cmd.Stdout.Write([]byte(tt.trailing)); err != nil {
+						return nil, nil, err
+					}
+				}
+				return nil, nil, tt.err
+			}))
+
+			var got []string
+			err := cli.StreamItems(context.Background(), func(item Item) error {
+				if item.cli != cli {
+					t.Errorf("item %q has no cli reference", item.ID)
+				}
+				got = append(got, item.Title)
+				return nil
+			}, tt.opts...)
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("StreamItems() error = %v; want error %v", err, tt.expectedErr)
+			}
+			if !slices.Equal(gotArgs, tt.expectedArgs) {
+				t.Errorf("executor args = %q; want %q", gotArgs, tt.expectedArgs)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("StreamItems() titles = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
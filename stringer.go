@@ -0,0 +1,67 @@
+package onepassword
+
+import (
+	"fmt"
+	"strings"
+)
+
+// describe formats an object as its kind, quoted name and the non-empty
+// details in parentheses, e.g. `vault "Private" (abcdef)`.
+func describe(kind, name string, details ...string) string {
+	var parts []string
+	for _, detail := range details {
+		if detail != "" {
+			parts = append(parts, detail)
+		}
+	}
+
+	if len(parts) == 0 {
+		return fmt.Sprintf("%s %q", kind, name)
+	}
+	return fmt.Sprintf("%s %q (%s)", kind, name, strings.Join(parts, ", "))
+}
+
+// String returns the title, ID, category and vault of the item, e.g.
+// `item "Database" (abcdef, Login, vault "Infrastructure")`. Field values
+// are never included.
+func (item Item) String() string {
+	vault := item.Vault.Name
+	if vault == "" {
+		vault = item.Vault.ID
+	}
+	if vault != "" {
+		vault = fmt.Sprintf("vault %q", vault)
+	}
+	return describe("item", item.Title, item.ID, string(item.Category), vault)
+}
+
+// String returns the name and ID of the vault, e.g. `vault "Private" (abcdef)`.
+func (vault Vault) String() string {
+	return describe("vault", vault.Name, vault.ID)
+}
+
+// String returns the name, email, ID and state of the user, e.g.
+// `user "Jane Doe" (jane@example.com, abcdef, ACTIVE)`.
+func (user User) String() string {
+	return describe("user", user.Name, user.Email, user.ID, string(user.State))
+}
+
+// String returns the name and ID of the group, e.g. `group "Engineering" (abcdef)`.
+func (group Group) String() string {
+	return describe("group", group.Name, group.ID)
+}
+
+// String returns the name of the permission as used by the CLI, e.g. "view_items".
+func (p Permission) String() string {
+	return string(p)
+}
+
+// String returns the email, sign-in address and user ID of the account, e.g.
+// `account "jane@example.com" (my.1password.com, abcdef)`. The session token
+// is never included.
+func (a *Account) String() string {
+	if a == nil {
+		return "account <nil>"
+	}
+	return describe("account", a.Email, a.URL, a.UserUUID)
+}
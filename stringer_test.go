@@ -0,0 +1,41 @@
+package onepassword
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStringers(t *testing.T) {
+	account := &Account{URL: "my.1password.com", Email: "jane@example.com", UserUUID: "user-uuid"}
+	account.SetSignInInfo("session-token")
+
+	tests := []struct {
+		name     string
+		value    fmt.Stringer
+		expected string
+	}{
+		{
+			name:     "Item",
+			value:    Item{ID: "item-id", Title: "Database", Category: CategoryLogin, Vault: Vault{ID: "vault-id", Name: "Infrastructure"}, Fields: []Field{{Label: "password", Value: "hunter2"}}},
+			expected: `item "Database" (item-id, Login, vault "Infrastructure")`,
+		},
+		{
+			name:     "Item without vault name",
+			value:    Item{ID: "item-id", Title: "Database", Vault: Vault{ID: "vault-id"}},
+			expected: `item "Database" (item-id, vault "vault-id")`,
+		},
+		{name: "Vault", value: Vault{ID: "vault-id", Name: "Private"}, expected: `vault "Private" (vault-id)`},
+		{name: "User", value: User{ID: "user-id", Name: "Jane Doe", Email: "jane@example.com", State: UserStateActive}, expected: `user "Jane Doe" (jane@example.com, user-id, ACTIVE)`},
+		{name: "Group", value: Group{ID: "group-id", Name: "Engineering"}, expected: `group "Engineering" (group-id)`},
+		{name: "Permission", value: PermissionViewItems, expected: "view_items"},
+		{name: "Account", value: account, expected: `account "jane@example.com" (my.1password.com, user-uuid)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(tt.value); got != tt.expected {
+				t.Errorf("String() = %s; want %s", got, tt.expected)
+			}
+		})
+	}
+}
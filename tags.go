@@ -0,0 +1,255 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// tagSeparator separates the segments of nested tags, e.g. "prod/databases".
+const tagSeparator = "/"
+
+// TagPath is a nested tag split into its segments, e.g. ["prod", "databases"]
+// for the tag "prod/databases". 1Password shows tags with a slash as a tree.
+type TagPath []string
+
+// ParseTagPath splits a tag into its segments. Surrounding whitespace of the
+// segments and empty segments, e.g. of a leading or doubled slash, are removed.
+//
+// Parameters:
+//   - tag: The tag to parse, e.g. "prod/databases".
+//
+// Returns:
+//   - TagPath: The segments of the tag. Empty if the tag has no segments.
+func ParseTagPath(tag string) TagPath {
+	var path TagPath
+	for _, segment := range strings.Split(tag, tagSeparator) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return path
+}
+
+// String returns the tag of the path, e.g. "prod/databases".
+func (p TagPath) String() string {
+	return strings.Join(p, tagSeparator)
+}
+
+// Name returns the last segment of the path, e.g. "databases" for
+// "prod/databases", or an empty string if the path is empty.
+func (p TagPath) Name() string {
+	if len(p) == 0 {
+		return ""
+	}
+	return p[len(p)-1]
+}
+
+// Parent returns the path without its last segment, e.g. "prod" for
+// "prod/databases". The parent of a top-level tag is empty.
+func (p TagPath) Parent() TagPath {
+	if len(p) <= 1 {
+		return nil
+	}
+	return p[:len(p)-1]
+}
+
+// IsUnder reports whether the path is ancestor itself or nested below it,
+// e.g. "prod/databases" is under "prod", but "production" is not.
+//
+// Parameters:
+//   - ancestor: The root of the subtree.
+func (p TagPath) IsUnder(ancestor TagPath) bool {
+	return len(ancestor) > 0 && len(p) >= len(ancestor) && slices.Equal(p[:len(ancestor)], ancestor)
+}
+
+// HasTagUnder reports whether the item carries the tag or a tag nested below it.
+//
+// Parameters:
+//   - tag: The root of the tag subtree, e.g. "prod".
+func (item *Item) HasTagUnder(tag string) bool {
+	root := ParseTagPath(tag)
+	return slices.ContainsFunc(item.Tags, func(t string) bool {
+		return ParseTagPath(t).IsUnder(root)
+	})
+}
+
+// GetItemsByTagTree retrieves the items that carry the tag or a tag nested
+// below it, e.g. "prod" and "prod/databases" for the tag "prod". The items
+// are listed with a single "item list" command and filtered locally, because
+// the --tags flag of the CLI only matches whole tags.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - tag: The root of the tag subtree.
+//   - opts: Optional ListItemsOptions to filter the items. Their Tags are ignored.
+//
+// Returns:
+//   - *[]Item: The items in the tag subtree, without their fields.
+//   - error: An error if the tag is empty or the items cannot be listed.
+func (cli *OpCLI) GetItemsByTagTree(ctx context.Context, tag string, opts ...ListItemsOptions) (*[]Item, error) {
+	if len(ParseTagPath(tag)) == 0 {
+		return nil, errors.New("tag cannot be empty")
+	}
+
+	var options ListItemsOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	options.Tags = nil
+
+	items := []Item{}
+	err := cli.StreamItems(ctx, func(item Item) error {
+		if item.HasTagUnder(tag) {
+			items = append(items, item)
+		}
+		return nil
+	}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items with tag %s: %w", tag, err)
+	}
+
+	return &items, nil
+}
+
+// TagEditResult holds the outcome of editing the tags of a single item with
+// RenameTag or MoveTag.
+//
+// Fields:
+//   - Item: The updated item, or the unchanged item if the edit failed.
+//   - Err: The error of the edit, if any.
+type TagEditResult struct {
+	Item *Item
+	Err  error
+}
+
+// RenameTag renames a tag and all tags nested below it on every item that
+// carries them, e.g. renaming "prod" to "production" changes "prod/databases"
+// to "production/databases". The tags of each item are replaced with one
+// "item edit --tags" command, with bounded concurrency. Tags that become
+// duplicates are merged.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - oldTag: The tag to rename.
+//   - newTag: The new tag.
+//   - opts: BatchOptions controlling the concurrency.
+//
+// Returns:
+//   - []TagEditResult: One result per edited item.
+//   - error: An error if a tag is empty or the items cannot be listed.
+//
+// Example usage:
+//
+//	results, err := cli.RenameTag(ctx, "prod", "production", onepassword.BatchOptions{})
+//	if err != nil {
+//	    log.Fatalf("Failed to rename tag: %v", err)
+//	}
+//	for _, result := range results {
+//	    if result.Err != nil {
+//	        log.Printf("Failed to retag %s: %v", result.Item.Title, result.Err)
+//	    }
+//	}
+func (cli *OpCLI) RenameTag(ctx context.Context, oldTag, newTag string, opts BatchOptions) ([]TagEditResult, error) {
+	oldPath, newPath := ParseTagPath(oldTag), ParseTagPath(newTag)
+	if len(oldPath) == 0 || len(newPath) == 0 {
+		return nil, errors.New("tag cannot be empty")
+	}
+
+	items, err := cli.GetItemsByTagTree(ctx, oldTag)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]TagEditResult, len(*items))
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for i := range *items {
+		item := &(*items)[i]
+		results[i].Item = item
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			updated, err := cli.setItemTags(ctx, *item, renameTags(item.Tags, oldPath, newPath))
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Item = updated
+		}(i)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// MoveTag moves a tag and all tags nested below it under a new parent, e.g.
+// moving "databases" to "prod" changes "databases/postgres" to
+// "prod/databases/postgres". An empty parent moves the tag to the top level.
+// See RenameTag for how the items are edited.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - tag: The tag to move.
+//   - newParent: The tag to move it under.
+//   - opts: BatchOptions controlling the concurrency.
+//
+// Returns:
+//   - []TagEditResult: One result per edited item.
+//   - error: An error if the tag is empty, would be moved below itself, or the items cannot be listed.
+func (cli *OpCLI) MoveTag(ctx context.Context, tag, newParent string, opts BatchOptions) ([]TagEditResult, error) {
+	path, parent := ParseTagPath(tag), ParseTagPath(newParent)
+	if len(path) == 0 {
+		return nil, errors.New("tag cannot be empty")
+	}
+	if parent.IsUnder(path) {
+		return nil, fmt.Errorf("cannot move tag %s below itself", path)
+	}
+
+	return cli.RenameTag(ctx, tag, append(slices.Clone(parent), path.Name()).String(), opts)
+}
+
+// renameTags returns the tags with the prefix oldPath replaced by newPath,
+// without duplicates.
+func renameTags(tags []string, oldPath, newPath TagPath) []string {
+	var renamed []string
+	for _, tag := range tags {
+		if path := ParseTagPath(tag); path.IsUnder(oldPath) {
+			tag = append(slices.Clone(newPath), path[len(oldPath):]...).String()
+		}
+		if !slices.Contains(renamed, tag) {
+			renamed = append(renamed, tag)
+		}
+	}
+	return renamed
+}
+
+// setItemTags replaces the tags of an item with the "item edit --tags" command.
+func (cli *OpCLI) setItemTags(ctx context.Context, item Item, tags []string) (*Item, error) {
+	output, err := cli.ExecuteOpCommand(ctx, "item", "edit", item.ID, "--vault", item.Vault.ID, "--tags", strings.Join(tags, ","))
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit tags of item %s: %w", item.Title, err)
+	}
+
+	var updated Item
+	if err := cli.unmarshalItem(output, &updated); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updated item: %w", err)
+	}
+	updated.cli = cli
+
+	return &updated, nil
+}
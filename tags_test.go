@@ -0,0 +1,64 @@
+package onepassword
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTagPath(t *testing.T) {
+	tests := []struct {
+		tag            string
+		expectedPath   TagPath
+		expectedParent string
+		expectedName   string
+	}{
+		{tag: "prod", expectedPath: TagPath{"prod"}, expectedParent: "", expectedName: "prod"},
+		{tag: "example-tag-2/credit-card", expectedPath: TagPath{"example-tag-2", "credit-card"}, expectedParent: "example-tag-2", expectedName: "credit-card"},
+		{tag: "/a//b / c/", expectedPath: TagPath{"a", "b", "c"}, expectedParent: "a/b", expectedName: "c"},
+		{tag: "", expectedPath: nil, expectedParent: "", expectedName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			path := ParseTagPath(tt.tag)
+			if !slices.Equal(path, tt.expectedPath) {
+				t.Errorf("ParseTagPath(%q) = %q; want %q", tt.tag, path, tt.expectedPath)
+			}
+			if got := path.Parent().String(); got != tt.expectedParent {
+				t.Errorf("Parent() = %q; want %q", got, tt.expectedParent)
+			}
+			if got := path.Name(); got != tt.expectedName {
+				t.Errorf("Name() = %q; want %q", got, tt.expectedName)
+			}
+		})
+	}
+}
+
+func TestTagPathIsUnder(t *testing.T) {
+	tests := []struct {
+		tag      string
+		ancestor string
+		expected bool
+	}{
+		{tag: "prod", ancestor: "prod", expected: true},
+		{tag: "prod/databases", ancestor: "prod", expected: true},
+		{tag: "production", ancestor: "prod", expected: false},
+		{tag: "prod", ancestor: "prod/databases", expected: false},
+		{tag: "prod", ancestor: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTagPath(tt.tag).IsUnder(ParseTagPath(tt.ancestor)); got != tt.expected {
+			t.Errorf("ParseTagPath(%q).IsUnder(%q) = %v; want %v", tt.tag, tt.ancestor, got, tt.expected)
+		}
+	}
+}
+
+func TestRenameTags(t *testing.T) {
+	tags := []string{"prod", "prod/databases", "production", "team/prod", "production/databases"}
+	got := renameTags(tags, ParseTagPath("prod"), ParseTagPath("production"))
+	expected := []string{"production", "production/databases", "team/prod"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("renameTags() = %q; want %q", got, expected)
+	}
+}
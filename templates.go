@@ -0,0 +1,73 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// templateCache caches the output of "item template list" and "item template
+// get". The templates are built into the CLI and only change with its
+// version, so the entries do not expire.
+type templateCache struct {
+	mu    sync.Mutex
+	list  []byte
+	items map[string][]byte
+}
+
+// cachedTemplateList returns the output of "item template list", running the
+// command only if it is not cached yet.
+func (cli *OpCLI) cachedTemplateList(ctx context.Context) ([]byte, error) {
+	cli.templates.mu.Lock()
+	output := cli.templates.list
+	cli.templates.mu.Unlock()
+	if output != nil {
+		return output, nil
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, "item", "template", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	cli.templates.mu.Lock()
+	cli.templates.list = output
+	cli.templates.mu.Unlock()
+	return output, nil
+}
+
+// cachedTemplate returns the output of "item template get" for a template,
+// running the command only if it is not cached yet. Template names are
+// matched case-insensitively, like the CLI does.
+func (cli *OpCLI) cachedTemplate(ctx context.Context, name string) ([]byte, error) {
+	key := strings.ToLower(name)
+
+	cli.templates.mu.Lock()
+	output, ok := cli.templates.items[key]
+	cli.templates.mu.Unlock()
+	if ok {
+		return output, nil
+	}
+
+	output, err := cli.ExecuteOpCommand(ctx, "item", "template", "get", name)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.templates.mu.Lock()
+	if cli.templates.items == nil {
+		cli.templates.items = make(map[string][]byte)
+	}
+	cli.templates.items[key] = output
+	cli.templates.mu.Unlock()
+	return output, nil
+}
+
+// clearTemplates discards the cached templates.
+func (cli *OpCLI) clearTemplates() {
+	cli.templates.mu.Lock()
+	defer cli.templates.mu.Unlock()
+
+	cli.templates.list = nil
+	cli.templates.items = nil
+}
@@ -0,0 +1,61 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestItemTemplatesCached(t *testing.T) {
+	var commands [][]string
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		commands = append(commands, cmd.Args[:3])
+		if slices.Contains(cmd.Args, "list") {
+			return []byte(`[{"uuid":"001","name":"Login"},{"uuid":"003","name":"Secure Note"}]`), nil, nil
+		}
+		return []byte(`{"title":"","category":"LOGIN","fields":[{"id":"username","type":"STRING","purpose":"USERNAME","label":"username"}]}`), nil, nil
+	}))
+
+	ctx := context.Background()
+	for range 2 {
+		templates, err := cli.GetItemTemplates(ctx)
+		if err != nil {
+			t.Fatalf("GetItemTemplates() error = %v", err)
+		}
+		if len(*templates) != 2 || (*templates)[1].Name != string(CategorySecureNote) {
+			t.Errorf("GetItemTemplates() = %v", *templates)
+		}
+	}
+
+	first, err := cli.GetItemTemplate(ctx, CategoryLogin)
+	if err != nil {
+		t.Fatalf("GetItemTemplate() error = %v", err)
+	}
+	first.Fields[0].Value = "changed"
+
+	second, err := cli.GetItemTemplateByName(ctx, "login")
+	if err != nil {
+		t.Fatalf("GetItemTemplateByName() error = %v", err)
+	}
+	if second.Fields[0].Value != "" {
+		t.Errorf("cached template was modified through an earlier result: %q", second.Fields[0].Value)
+	}
+	if second.cli != cli {
+		t.Error("GetItemTemplateByName() did not set the client of the template")
+	}
+
+	want := [][]string{{"item", "template", "list"}, {"item", "template", "get"}}
+	if !slices.EqualFunc(commands, want, slices.Equal) {
+		t.Errorf("commands = %v; want %v", commands, want)
+	}
+
+	// Close clears the cache
+	cli.clearCaches()
+	if _, err := cli.GetItemTemplates(ctx); err != nil {
+		t.Fatalf("GetItemTemplates() error = %v", err)
+	}
+	if len(commands) != 3 {
+		t.Errorf("commands after clearing the caches = %d; want 3", len(commands))
+	}
+}
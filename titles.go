@@ -0,0 +1,104 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// itemListCacheKey is the identifier of the cached item list of title lookups.
+const itemListCacheKey = "list"
+
+// GetItemsByTitlePrefix retrieves the items whose title starts with the
+// prefix, e.g. all items of the family "prod/db/". The comparison is case
+// sensitive. The items are listed with a single "item list" command, which
+// is served from the cache if a TTL is set for CacheItems.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - prefix: The prefix of the titles.
+//
+// Returns:
+//   - *[]Item: The matching items, without their fields.
+//   - error: An error if the items cannot be listed.
+func (cli *OpCLI) GetItemsByTitlePrefix(ctx context.Context, prefix string) (*[]Item, error) {
+	return cli.getItemsByTitle(ctx, func(title string) bool {
+		return strings.HasPrefix(title, prefix)
+	})
+}
+
+// GetItemsByTitlePattern retrieves the items whose title matches a glob
+// pattern with the syntax of path.Match, e.g. "prod/db/*". As in file paths,
+// "*" and "?" do not match a slash, so "prod/*" matches "prod/api" but not
+// "prod/db/primary". The items are listed as by GetItemsByTitlePrefix.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - pattern: The glob pattern of the titles.
+//
+// Returns:
+//   - *[]Item: The matching items, without their fields.
+//   - error: path.ErrBadPattern if the pattern is malformed, or an error if the items cannot be listed.
+//
+// Example usage:
+//
+//	items, err := cli.GetItemsByTitlePattern(ctx, "prod/db/*")
+//	if err != nil {
+//	    log.Fatalf("Failed to find items: %v", err)
+//	}
+//	for _, item := range *items {
+//	    fmt.Println(item.Title)
+//	}
+func (cli *OpCLI) GetItemsByTitlePattern(ctx context.Context, pattern string) (*[]Item, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid title pattern %q: %w", pattern, err)
+	}
+
+	return cli.getItemsByTitle(ctx, func(title string) bool {
+		matched, _ := path.Match(pattern, title)
+		return matched
+	})
+}
+
+// getItemsByTitle returns the listed items whose title matches.
+func (cli *OpCLI) getItemsByTitle(ctx context.Context, matches func(title string) bool) (*[]Item, error) {
+	all, err := cli.cachedItemList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []Item{}
+	for _, item := range all {
+		if matches(item.Title) {
+			items = append(items, item)
+		}
+	}
+
+	return &items, nil
+}
+
+// cachedItemList runs "item list" through the read-through cache of CacheItems.
+func (cli *OpCLI) cachedItemList(ctx context.Context) ([]Item, error) {
+	output, ok := cli.entityCache.lookup(CacheItems, itemListCacheKey)
+	if ok {
+		cli.log().Debug("using cached item list")
+	} else {
+		var err error
+		output, err = cli.ExecuteOpCommand(ctx, itemListArgs()...)
+		if err != nil {
+			return nil, err
+		}
+		cli.entityCache.store(CacheItems, itemListCacheKey, output)
+	}
+
+	var items []Item
+	if err := cli.unmarshal(output, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item list: %w", err)
+	}
+	for i := range items {
+		items[i].cli = cli
+	}
+
+	return items, nil
+}
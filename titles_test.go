@@ -0,0 +1,86 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestGetItemsByTitle(t *testing.T) {
+	var calls int
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		calls++
+		return []byte(`[{"id":"1","title":"prod/db/primary"},{"id":"2","title":"prod/db/replica"},{"id":"3","title":"prod/api"},{"id":"4","title":"staging/db/primary"}]`), nil, nil
+	}))
+	cli.SetCacheTTL(CacheItems, time.Minute)
+
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		run            func() (*[]Item, error)
+		expectedTitles []string
+	}{
+		{
+			name:           "Prefix",
+			run:            func() (*[]Item, error) { return cli.GetItemsByTitlePrefix(ctx, "prod/") },
+			expectedTitles: []string{"prod/db/primary", "prod/db/replica", "prod/api"},
+		},
+		{
+			name:           "Pattern",
+			run:            func() (*[]Item, error) { return cli.GetItemsByTitlePattern(ctx, "prod/db/*") },
+			expectedTitles: []string{"prod/db/primary", "prod/db/replica"},
+		},
+		{
+			name:           "Pattern does not cross slashes",
+			run:            func() (*[]Item, error) { return cli.GetItemsByTitlePattern(ctx, "prod/*") },
+			expectedTitles: []string{"prod/api"},
+		},
+		{
+			name:           "Pattern with wildcard segment",
+			run:            func() (*[]Item, error) { return cli.GetItemsByTitlePattern(ctx, "*/db/primary") },
+			expectedTitles: []string{"prod/db/primary", "staging/db/primary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := tt.run()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			var titles []string
+			for _, item := range *items {
+				titles = append(titles, item.Title)
+			}
+			if len(titles) != len(tt.expectedTitles) {
+				t.Fatalf("titles = %q; want %q", titles, tt.expectedTitles)
+			}
+			for i := range titles {
+				if titles[i] != tt.expectedTitles[i] {
+					t.Errorf("titles = %q; want %q", titles, tt.expectedTitles)
+				}
+			}
+		})
+	}
+
+	if calls != 1 {
+		t.Errorf("executor calls = %d; want the item list to be cached", calls)
+	}
+
+	if _, err := cli.GetItemsByTitlePattern(ctx, "prod/[db"); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("GetItemsByTitlePattern() error = %v; want %v", err, path.ErrBadPattern)
+	}
+
+	if _, err := cli.ExecuteOpCommand(ctx, "item", "delete", "3"); err != nil {
+		t.Fatalf("ExecuteOpCommand() error = %v", err)
+	}
+	if _, err := cli.GetItemsByTitlePrefix(ctx, "prod/"); err != nil {
+		t.Fatalf("GetItemsByTitlePrefix() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("executor calls = %d; want the cache to be invalidated by item delete", calls)
+	}
+}
@@ -0,0 +1,115 @@
+package onepassword
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Tracer starts spans for commands of the 1Password CLI. It is the subset of
+// a tracing API that OpCLI needs, so tracers like the one of OpenTelemetry
+// can be adapted without this package depending on them.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx and returns a context
+	// containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttributes sets attributes of the span.
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError records an error and marks the span as failed.
+	RecordError(err error)
+	// End ends the span.
+	End()
+}
+
+// redactedArg replaces argument values that are not included in traces.
+const redactedArg = "[redacted]"
+
+// SetTracer sets the Tracer that starts a span for every command of the
+// 1Password CLI. The span is started from the context passed to the
+// operation and has the following attributes:
+//   - op.command: The subcommand, e.g. "item get".
+//   - op.args: The arguments with names, IDs and other values redacted.
+//   - op.account: The user UUID of the active account.
+//   - op.duration: The duration of the command.
+//   - op.exit_status: The exit status of the op process.
+//
+// Parameters:
+//   - tracer: The Tracer to use. If nil, commands are not traced.
+func (cli *OpCLI) SetTracer(tracer Tracer) {
+	cli.tracer = tracer
+}
+
+// startSpan starts a span for the command if a Tracer is set.
+func (cli *OpCLI) startSpan(ctx context.Context, info CommandInfo) (context.Context, Span) {
+	if cli.tracer == nil {
+		return ctx, nil
+	}
+
+	ctx, span := cli.tracer.Start(ctx, "op "+info.Name)
+	span.SetAttributes(
+		slog.String("op.command", info.Name),
+		slog.String("op.args", strings.Join(redactArgs(info.Args), " ")),
+		slog.String("op.account", info.Account),
+	)
+	return ctx, span
+}
+
+// endSpan records the outcome of the command and ends the span.
+func endSpan(span Span, result CommandResult, err error) {
+	if span == nil {
+		return
+	}
+
+	exitStatus := 0
+	if err != nil {
+		exitStatus = (&OpCliError{Err: err}).ExitCode()
+		span.RecordError(err)
+	}
+
+	span.SetAttributes(
+		slog.Duration("op.duration", result.Duration),
+		slog.Int("op.exit_status", exitStatus),
+	)
+	span.End()
+}
+
+// redactArgs returns the arguments with all values except the subcommand and
+// flag names replaced, e.g. "vault user grant --vault [redacted]". The
+// argument following a flag is its value unless the flag is a boolean flag,
+// even if the value starts with a dash.
+func redactArgs(args []string) []string {
+	words := commandWords(args)
+	redacted := make([]string, len(args))
+	copy(redacted, args[:words])
+
+	for i := words; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			// Everything after the separator is a positional value
+			redacted[i] = arg
+			for i++; i < len(args); i++ {
+				redacted[i] = redactedArg
+			}
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(arg, "=")
+			if hasValue {
+				redacted[i] = name + "=" + redactedArg
+				continue
+			}
+			redacted[i] = name
+			if !slices.Contains(booleanFlags, name) && i+1 < len(args) {
+				i++
+				redacted[i] = redactedArg
+			}
+		default:
+			redacted[i] = redactedArg
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,151 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]slog.Value
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.err = err }
+
+func (s *testSpan) End() { s.ended = true }
+
+type spanKey struct{}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: make(map[string]slog.Value)}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracer(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedArgs string
+	}{
+		{
+			name:         "Success",
+			expectedArgs: "item get [redacted] --vault [redacted] --account [redacted] --format=[redacted]",
+		},
+		{
+			name:         "Failure",
+			err:          errors.New("exit status 1"),
+			expectedArgs: "item get [redacted] --vault [redacted] --account [redacted] --format=[redacted]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &testTracer{}
+			cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+			cli.SetTracer(tracer)
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				if ctx.Value(spanKey{}) == nil {
+					t.Error("executor context does not contain the span")
+				}
+				return []byte(`{}`), nil, tt.err
+			}))
+
+			cli.ExecuteOpCommand(context.Background(), "item", "get", "Secret Login", "--vault", "Private")
+
+			if len(tracer.spans) != 1 {
+				t.Fatalf("started %d spans; want 1", len(tracer.spans))
+			}
+			span := tracer.spans[0]
+			if span.name != "op item get" || !span.ended {
+				t.Errorf("span = %q, ended %v; want \"op item get\", ended", span.name, span.ended)
+			}
+			if got := span.attrs["op.args"].String(); got != tt.expectedArgs {
+				t.Errorf("op.args = %q; want %q", got, tt.expectedArgs)
+			}
+			if got := span.attrs["op.account"].String(); got != "user-uuid" {
+				t.Errorf("op.account = %q; want %q", got, "user-uuid")
+			}
+			if !errors.Is(span.err, tt.err) {
+				t.Errorf("recorded error = %v; want %v", span.err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Subcommand only",
+			args:     []string{"vault", "list"},
+			expected: []string{"vault", "list"},
+		},
+		{
+			name:     "Positional and flag values",
+			args:     []string{"user", "provision", "--email", "jane@example.com", "--name=Jane"},
+			expected: []string{"user", "provision", "--email", redactedArg, "--name=" + redactedArg},
+		},
+		{
+			name:     "Single word command",
+			args:     []string{"whoami", "--format=json"},
+			expected: []string{"whoami", "--format=" + redactedArg},
+		},
+		{
+			name:     "Single word command with positional value",
+			args:     []string{"read", "op://Private/Database/password"},
+			expected: []string{"read", redactedArg},
+		},
+		{
+			name:     "Nested subcommand",
+			args:     []string{"vault", "user", "grant", "--vault", "Private", "--user", "jane@example.com", "--permissions", "view_items"},
+			expected: []string{"vault", "user", "grant", "--vault", redactedArg, "--user", redactedArg, "--permissions", redactedArg},
+		},
+		{
+			name:     "Nested subcommand with positional value",
+			args:     []string{"item", "template", "get", "Login"},
+			expected: []string{"item", "template", "get", redactedArg},
+		},
+		{
+			name:     "Flag value starting with a dash",
+			args:     []string{"item", "edit", "Database", "--title", "-secret-"},
+			expected: []string{"item", "edit", redactedArg, "--title", redactedArg},
+		},
+		{
+			name:     "Boolean flag before positional value",
+			args:     []string{"item", "get", "--reveal", "Database"},
+			expected: []string{"item", "get", "--reveal", redactedArg},
+		},
+		{
+			name:     "Arguments after separator",
+			args:     []string{"run", "--", "deploy", "--token", "secret"},
+			expected: []string{"run", "--", redactedArg, redactedArg, redactedArg},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactArgs(tt.args); !slices.Equal(got, tt.expected) {
+				t.Errorf("redactArgs() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// unknownJSON holds the raw JSON of the keys of an object that the package
+// does not model, e.g. passkey data or file metadata of an item, by key.
+type unknownJSON map[string]json.RawMessage
+
+// unmarshalItem decodes the JSON of a single item like unmarshal and retains
+// the keys of the item and its fields that the package does not model, so
+// they are sent back when the item is saved.
+func (cli *OpCLI) unmarshalItem(data []byte, item *Item) error {
+	if err := cli.unmarshal(data, item); err != nil {
+		return err
+	}
+	item.retainUnknownJSON(data)
+	return nil
+}
+
+// retainUnknownJSON stores the keys of the item JSON that are not fields of
+// Item, and the unknown keys of each of its fields. Invalid JSON is ignored,
+// as it has already been reported by the decoder.
+func (item *Item) retainUnknownJSON(data []byte) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	item.unknown = unknownKeys(raw, reflect.TypeFor[Item]())
+
+	var rawFields []map[string]json.RawMessage
+	if err := json.Unmarshal(raw["fields"], &rawFields); err != nil || len(rawFields) != len(item.Fields) {
+		return
+	}
+	for i := range item.Fields {
+		item.Fields[i].unknown = unknownKeys(rawFields[i], reflect.TypeFor[Field]())
+	}
+}
+
+// unknownKeys returns the keys of a JSON object that are not fields of the
+// struct type t, or nil if there are none.
+func unknownKeys(object map[string]json.RawMessage, t reflect.Type) unknownJSON {
+	fields := jsonFields(t)
+
+	var unknown unknownJSON
+	for key, value := range object {
+		if _, ok := lookupJSONField(fields, key); ok {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(unknownJSON)
+		}
+		unknown[key] = value
+	}
+	return unknown
+}
+
+// marshalWithUnknown marshals v and adds the retained unknown keys that v
+// does not set itself.
+func marshalWithUnknown(v any, unknown unknownJSON) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for key, value := range unknown {
+		if _, ok := object[key]; !ok {
+			object[key] = value
+		}
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,56 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnknownJSONRoundTrip(t *testing.T) {
+	output := []byte(`{
+		"id": "item-id",
+		"title": "GitHub",
+		"category": "LOGIN",
+		"vault": {"id": "vault-id", "name": "Private"},
+		"passkey": {"rp_id": "github.com"},
+		"files": [{"id": "file-id", "name": "key.pem", "size": 1024}],
+		"fields": [
+			{"id": "password", "type": "CONCEALED", "purpose": "PASSWORD", "label": "password", "value": "secret", "totp": "123456"},
+			{"id": "username", "type": "STRING", "purpose": "USERNAME", "label": "username", "value": "octocat"}
+		]
+	}`)
+
+	cli := &OpCLI{}
+	var item Item
+	if err := cli.unmarshalItem(output, &item); err != nil {
+		t.Fatalf("unmarshalItem() error = %v", err)
+	}
+
+	item.Title = "GitHub Enterprise"
+	data, err := marshalItemPayload(item, false)
+	if err != nil {
+		t.Fatalf("marshalItemPayload() error = %v", err)
+	}
+
+	var payload struct {
+		Title   string            `json:"title"`
+		Passkey map[string]string `json:"passkey"`
+		Files   []map[string]any  `json:"files"`
+		Fields  []map[string]any  `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if payload.Title != "GitHub Enterprise" {
+		t.Errorf("title = %q; want the modified title", payload.Title)
+	}
+	if payload.Passkey["rp_id"] != "github.com" || len(payload.Files) != 1 {
+		t.Errorf("payload = %s; want passkey and files to be preserved", data)
+	}
+	if len(payload.Fields) != 2 || payload.Fields[0]["totp"] != "123456" {
+		t.Errorf("fields = %v; want the unknown field key to be preserved", payload.Fields)
+	}
+	if _, ok := payload.Fields[1]["totp"]; ok {
+		t.Errorf("fields = %v; want unknown keys only on the field that had them", payload.Fields)
+	}
+}
@@ -0,0 +1,124 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UpdateInfo describes the installed and the latest version of the 1Password CLI.
+//
+// Fields:
+//   - CurrentVersion: The version of the op executable used by the OpCLI instance.
+//   - LatestVersion: The latest released version of the 1Password CLI.
+//   - UpdateAvailable: Whether the latest version is newer than the current version.
+type UpdateInfo struct {
+	CurrentVersion  string `json:"current_version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+}
+
+// Version returns the version of the op executable, e.g. "2.30.0".
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//
+// Returns:
+//   - string: The version of the 1Password CLI.
+//   - error: An error if the command fails.
+func (cli *OpCLI) Version(ctx context.Context) (string, error) {
+	output, stderr, err := cli.run(ctx, cli.command("--version"))
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve 1Password CLI version: %w", &OpCliError{
+			Err:          err,
+			StderrOutput: string(stderr),
+		})
+	}
+
+	version := strings.TrimSpace(string(output))
+	if version == "" {
+		return "", fmt.Errorf("no 1Password CLI version reported")
+	}
+	return version, nil
+}
+
+// CheckForUpdate compares the version of the op executable with the latest
+// release of the 1Password CLI, like "op update" does, without downloading
+// anything. This allows tooling that manages many machines to report
+// outdated installations.
+//
+// Parameters:
+//   - ctx: The context for the command execution and the version lookup.
+//
+// Returns:
+//   - *UpdateInfo: The current and the latest version.
+//   - error: An error if either version cannot be determined.
+//
+// Example usage:
+//
+//	info, err := cli.CheckForUpdate(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to check for updates: %v", err)
+//	}
+//	if info.UpdateAvailable {
+//	    fmt.Printf("op %s is outdated, latest is %s\n", info.CurrentVersion, info.LatestVersion)
+//	}
+func (cli *OpCLI) CheckForUpdate(ctx context.Context) (*UpdateInfo, error) {
+	return cli.checkForUpdate(ctx, http.DefaultClient, opUpdateURL)
+}
+
+// checkForUpdate implements CheckForUpdate with the given update endpoint.
+func (cli *OpCLI) checkForUpdate(ctx context.Context, client *http.Client, updateURL string) (*UpdateInfo, error) {
+	current, err := cli.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := latestOpVersion(ctx, client, updateURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateInfo{
+		CurrentVersion:  current,
+		LatestVersion:   latest,
+		UpdateAvailable: compareVersions(current, latest) < 0,
+	}, nil
+}
+
+// compareVersions compares two versions of the 1Password CLI, e.g. "2.30.0"
+// and "2.30.3-beta.01". It returns -1 if a is older than b, 1 if a is newer,
+// and 0 if both are equal. Pre-releases are older than their release.
+func compareVersions(a, b string) int {
+	a, preA, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, preB, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var numA, numB int
+		if i < len(partsA) {
+			numA, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			numB, _ = strconv.Atoi(partsB[i])
+		}
+		if numA != numB {
+			if numA < numB {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return strings.Compare(preA, preB)
+}
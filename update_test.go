@@ -0,0 +1,63 @@
+package onepassword
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "2.30.0", b: "2.30.0", expected: 0},
+		{a: "2.30.0", b: "2.30.3", expected: -1},
+		{a: "2.9.1", b: "2.30.0", expected: -1},
+		{a: "v3.0.0", b: "2.30.0", expected: 1},
+		{a: "2.30.0-beta.01", b: "2.30.0", expected: -1},
+		{a: "2.30", b: "2.30.0", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.expected {
+				t.Errorf("compareVersions(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"2.31.0"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		current         string
+		updateAvailable bool
+	}{
+		{name: "Outdated", current: "2.30.0\n", updateAvailable: true},
+		{name: "Up to date", current: "2.31.0\n", updateAvailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &OpCLI{Path: "op"}
+			cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+				return []byte(tt.current), nil, nil
+			}))
+
+			info, err := cli.checkForUpdate(context.Background(), server.Client(), server.URL)
+			if err != nil {
+				t.Fatalf("checkForUpdate() error = %v", err)
+			}
+			if info.LatestVersion != "2.31.0" || info.UpdateAvailable != tt.updateAvailable {
+				t.Errorf("checkForUpdate() = %+v; want latest 2.31.0 and update available %t", info, tt.updateAvailable)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package onepassword
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
+	"net/mail"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,49 +15,139 @@ type UserType string
 
 const (
 	UserTypeMember         UserType = "MEMBER"
+	UserTypeGuest          UserType = "GUEST"
 	UserTypeServiceAccount UserType = "SERVICE_ACCOUNT"
+	UserTypeUnknown        UserType = "UNKNOWN"
 )
 
+var knownUserTypes = map[UserType]bool{
+	UserTypeMember:         true,
+	UserTypeGuest:          true,
+	UserTypeServiceAccount: true,
+	UserTypeUnknown:        true,
+}
+
+// UnmarshalJSON decodes a UserType leniently. Values are normalized to upper
+// case, and values that are not strings or not known to this package are
+// decoded as UserTypeUnknown instead of failing the whole listing.
+func (t *UserType) UnmarshalJSON(data []byte) error {
+	*t = UserType(decodeEnum(data, knownUserTypes, UserTypeUnknown))
+	return nil
+}
+
+// knownValue reports whether raw is a UserType known to this package.
+func (UserType) knownValue(raw string) bool {
+	return knownUserTypes[normalizeEnum[UserType](raw)]
+}
+
 // UserState represents the state of a user.
 type UserState string
 
 const (
-	UserStateActive            UserState = "ACTIVE"
-	UserStateTransferStarted   UserState = "TRANSFER_STARTED"
-	UserStateSuspended         UserState = "SUSPENDED"
-	UserStateTransferSuspended UserState = "TRANSFER_SUSPENDED"
+	UserStateActive                    UserState = "ACTIVE"
+	UserStatePending                   UserState = "PENDING"
+	UserStateDeleted                   UserState = "DELETED"
+	UserStateSuspended                 UserState = "SUSPENDED"
+	UserStateRecoveryStarted           UserState = "RECOVERY_STARTED"
+	UserStateRecoveryAccepted          UserState = "RECOVERY_ACCEPTED"
+	UserStateTransferPending           UserState = "TRANSFER_PENDING"
+	UserStateTransferStarted           UserState = "TRANSFER_STARTED"
+	UserStateTransferAccepted          UserState = "TRANSFER_ACCEPTED"
+	UserStateTransferSuspended         UserState = "TRANSFER_SUSPENDED"
+	UserStateRegistrationIncomplete    UserState = "EMAIL_VERIFIED_BUT_REGISTRATION_INCOMPLETE"
+	UserStateTeamRegistrationInitiated UserState = "TEAM_REGISTRATION_INITIATED"
+	UserStateUnknown                   UserState = "UNKNOWN"
 )
 
+var knownUserStates = map[UserState]bool{
+	UserStateActive:                    true,
+	UserStatePending:                   true,
+	UserStateDeleted:                   true,
+	UserStateSuspended:                 true,
+	UserStateRecoveryStarted:           true,
+	UserStateRecoveryAccepted:          true,
+	UserStateTransferPending:           true,
+	UserStateTransferStarted:           true,
+	UserStateTransferAccepted:          true,
+	UserStateTransferSuspended:         true,
+	UserStateRegistrationIncomplete:    true,
+	UserStateTeamRegistrationInitiated: true,
+	UserStateUnknown:                   true,
+}
+
+// UnmarshalJSON decodes a UserState leniently. Values are normalized to upper
+// case, and values that are not strings or not known to this package are
+// decoded as UserStateUnknown instead of failing the whole listing.
+func (s *UserState) UnmarshalJSON(data []byte) error {
+	*s = UserState(decodeEnum(data, knownUserStates, UserStateUnknown))
+	return nil
+}
+
+// knownValue reports whether raw is a UserState known to this package.
+func (UserState) knownValue(raw string) bool {
+	return knownUserStates[normalizeEnum[UserState](raw)]
+}
+
+// decodeEnum decodes a JSON string into one of the known values, falling
+// back to the given unknown value for anything unrepresentable. Unknown
+// values are logged by OpCLI.unmarshal, which knows the logger to use.
+func decodeEnum[T ~string](data []byte, known map[T]bool, unknown T) T {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return unknown
+	}
+
+	value := normalizeEnum[T](raw)
+	if !known[value] {
+		return unknown
+	}
+
+	return value
+}
+
+// normalizeEnum returns the enum value of a raw string of the CLI output.
+func normalizeEnum[T ~string](raw string) T {
+	return T(strings.ToUpper(strings.TrimSpace(raw)))
+}
+
 // User represents a user in the 1Password system.
 type User struct {
 	cli *OpCLI `json:"-"` // Reference to the OpCLI instance for update operations
 
-	ID         string    `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	Type       UserType  `json:"type"`
-	State      UserState `json:"state"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	LastAuthAt time.Time `json:"last_auth_at"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Email       string       `json:"email"`
+	Type        UserType     `json:"type"`
+	State       UserState    `json:"state"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	LastAuthAt  time.Time    `json:"last_auth_at"`
+	Permissions []Permission `json:"permissions,omitempty"` // Only populated when listed through a vault
+}
+
+// ListUsersOptions filters the users returned by ListUsers.
+//
+// Fields:
+// - Group: Only list users that belong to this group (name or ID).
+// - Vault: Only list users with direct access to this vault (name or ID).
+type ListUsersOptions struct {
+	Group string
+	Vault string
 }
 
 // ListUsers retrieves a list of all users in the 1Password system.
 // It executes the "op user list" command using the OpCLI instance.
 //
+// Parameters:
+// - ctx: The context for the command execution.
+// - opts: Optional ListUsersOptions to filter the users by group or vault.
+//
 // Returns:
 // - A slice of User objects representing the users in the system.
 // - An error if the command execution or JSON unmarshalling fails.
-func (cli *OpCLI) ListUsers() ([]User, error) {
-
+func (cli *OpCLI) ListUsers(ctx context.Context, opts ...ListUsersOptions) ([]User, error) {
 	// Execute the command to list users
-	output, err := cli.ExecuteOpCommand("user", "list")
-	if err != nil {
-		return nil, err
-	}
-
-	var users []User
-	err = json.Unmarshal([]byte(output), &users)
+	users, err := collectList[User](ctx, cli, userListArgs(opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +159,29 @@ func (cli *OpCLI) ListUsers() ([]User, error) {
 	return users, nil
 }
 
-func (cli *OpCLI) getUser(userID string) (*User, error) {
+// userListArgs returns the arguments of "user list" for the options.
+func userListArgs(opts ...ListUsersOptions) []string {
+	args := []string{"user", "list"}
+	if len(opts) > 0 {
+		if opts[0].Group != "" {
+			args = append(args, "--group", opts[0].Group)
+		}
+		if opts[0].Vault != "" {
+			args = append(args, "--vault", opts[0].Vault)
+		}
+	}
+	return args
+}
+
+func (cli *OpCLI) getUser(ctx context.Context, userID string) (*User, error) {
 	// Execute the command to get a user by ID
-	output, err := cli.ExecuteOpCommand("user", "get", userID)
+	output, err := cli.getEntity(ctx, CacheUsers, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	var user User
-	err = json.Unmarshal([]byte(output), &user)
+	err = cli.unmarshal(output, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -88,41 +195,44 @@ func (cli *OpCLI) getUser(userID string) (*User, error) {
 // It uses the "op user get" command to fetch the user details.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - userName: The name of the user to retrieve.
 //
 // Returns:
 // - A pointer to the User object if found.
 // - An error if the user is not found or the command fails.
-func (cli *OpCLI) GetUserByName(userName string) (*User, error) {
-	return cli.getUser(userName)
+func (cli *OpCLI) GetUserByName(ctx context.Context, userName string) (*User, error) {
+	return cli.getUser(ctx, userName)
 }
 
 // GetUserByEmail retrieves a user by their email address.
 // It validates the email format before attempting to fetch the user.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - userEmail: The email address of the user to retrieve.
 //
 // Returns:
 //   - A pointer to the User object if found.
 //   - An error if the email format is invalid or if the user cannot be retrieved.
-func (cli *OpCLI) GetUserByEmail(userEmail string) (*User, error) {
+func (cli *OpCLI) GetUserByEmail(ctx context.Context, userEmail string) (*User, error) {
 	// Validate the email format
-	if !isValidEmail(userEmail) {
+	if !cli.isValidEmail(userEmail) {
 		return nil, fmt.Errorf("invalid email format: %s", userEmail)
 	}
 
-	return cli.getUser(userEmail)
+	return cli.getUser(ctx, userEmail)
 }
 
-func (cli *OpCLI) GetUserByID(userID string) (*User, error) {
-	return cli.getUser(userID)
+func (cli *OpCLI) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	return cli.getUser(ctx, userID)
 }
 
 // ProvisionUser creates a new user in the 1Password system.
 // It uses the "op user provision" command to create the user.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - name: The name of the user to create.
 // - email: The email address of the user.
 // - language: The preferred language of the user (default is "en").
@@ -130,9 +240,9 @@ func (cli *OpCLI) GetUserByID(userID string) (*User, error) {
 // Returns:
 // - A pointer to the newly created User object.
 // - An error if the command fails or the email format is invalid.
-func (cli *OpCLI) ProvisionUser(name, email, language string) (*User, error) {
+func (cli *OpCLI) ProvisionUser(ctx context.Context, name, email, language string) (*User, error) {
 	// Validate the email format
-	if !isValidEmail(email) {
+	if !cli.isValidEmail(email) {
 		return nil, fmt.Errorf("invalid email format: %s", email)
 	}
 
@@ -145,13 +255,13 @@ func (cli *OpCLI) ProvisionUser(name, email, language string) (*User, error) {
 	}
 
 	// Execute the command to provision a new user
-	output, err := cli.ExecuteOpCommand("user", "provision", "--name", name, "--email", email, "--language", language)
+	output, err := cli.ExecuteOpCommand(ctx, "user", "provision", "--name", name, "--email", email, "--language", language)
 	if err != nil {
 		return nil, err
 	}
 
 	var user User
-	err = json.Unmarshal([]byte(output), &user)
+	err = cli.unmarshal(output, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -161,37 +271,209 @@ func (cli *OpCLI) ProvisionUser(name, email, language string) (*User, error) {
 	return &user, nil
 }
 
-// isValidEmail validates if a given string is a valid email address.
-func isValidEmail(email string) bool {
-	// Define a regular expression for validating email addresses.
-	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	regex := regexp.MustCompile(emailRegex)
-	return regex.MatchString(email)
+// UserProvisionRequest describes a single user to provision with ProvisionUsers.
+type UserProvisionRequest struct {
+	Name     string
+	Email    string
+	Language string
+}
+
+// UserProvisionResult holds the outcome of a single UserProvisionRequest.
+type UserProvisionResult struct {
+	Request UserProvisionRequest
+	User    *User
+	Err     error
+}
+
+// BatchOptions controls the execution of batch operations.
+//
+// Fields:
+//   - Concurrency: The maximum number of CLI commands running at the same time.
+//     Defaults to 4 if zero or negative. The limit of SetMaxConcurrency applies in addition.
+type BatchOptions struct {
+	Concurrency int
+}
+
+// defaultBatchConcurrency is used when BatchOptions.Concurrency is not set.
+const defaultBatchConcurrency = 4
+
+// ProvisionUsers provisions multiple users with bounded concurrency.
+// All requests are validated before any user is provisioned; invalid requests
+// are reported in their result and skipped, valid requests are provisioned.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - requests: The users to provision.
+//   - opts: BatchOptions controlling the concurrency.
+//
+// Returns:
+//   - One UserProvisionResult per request, in the order of the input slice.
+func (cli *OpCLI) ProvisionUsers(ctx context.Context, requests []UserProvisionRequest, opts BatchOptions) []UserProvisionResult {
+	results := make([]UserProvisionResult, len(requests))
+
+	// Validate all requests up front
+	var valid []int
+	for i, request := range requests {
+		results[i].Request = request
+		switch {
+		case !cli.isValidEmail(request.Email):
+			results[i].Err = fmt.Errorf("invalid email format: %s", request.Email)
+		case request.Name == "":
+			results[i].Err = fmt.Errorf("name cannot be empty")
+		default:
+			valid = append(valid, i)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for _, i := range valid {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			request := requests[i]
+			results[i].User, results[i].Err = cli.ProvisionUser(ctx, request.Name, request.Email, request.Language)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// EmailValidator reports whether an email address is acceptable for user
+// lookups and provisioning.
+type EmailValidator func(email string) bool
+
+// SetEmailValidator replaces the email validation used by GetUserByEmail,
+// ProvisionUser and ProvisionUsers. Passing nil restores ValidateEmail.
+//
+// Parameters:
+//   - validator: The EmailValidator to use.
+func (cli *OpCLI) SetEmailValidator(validator EmailValidator) {
+	cli.emailValidator = validator
+}
+
+// ValidateEmail is the default EmailValidator. It parses the address
+// according to RFC 5322 using net/mail, which accepts quoted local parts and
+// any top-level domain, and rejects display names and domains without a dot.
+func ValidateEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || strings.ContainsAny(email, "<>") {
+		return false
+	}
+
+	at := strings.LastIndex(address.Address, "@")
+	if at <= 0 {
+		return false
+	}
+
+	domain := address.Address[at+1:]
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// isValidEmail validates if a given string is a valid email address using
+// the configured EmailValidator.
+func (cli *OpCLI) isValidEmail(email string) bool {
+	if cli.emailValidator != nil {
+		return cli.emailValidator(email)
+	}
+	return ValidateEmail(email)
+}
+
+// Reload re-fetches the user using the "op user get" command and updates
+// the receiver with the current state.
+//
+// Returns:
+//   - An error if the user cannot be retrieved.
+func (user *User) Reload(ctx context.Context) error {
+	updatedUser, err := user.cli.getUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload user: %w", err)
+	}
+
+	*user = *updatedUser
+
+	return nil
 }
 
 // Confirm confirms a user by their ID using the 1Password CLI.
-// It executes the "user confirm" command with the user's ID and parses
-// the resulting output into an updated User object.
+// It executes the "user confirm" command with the user's ID and reloads
+// the user afterwards, so the receiver reflects the new state.
 //
 // Returns:
 //   - A pointer to the updated User object if the confirmation is successful.
-//   - An error if the command execution or JSON unmarshalling fails.
-func (user *User) Confirm() (*User, error) {
+//   - An error if the command execution or reloading the user fails.
+func (user *User) Confirm(ctx context.Context) (*User, error) {
 	// Execute the command to confirm a user by ID
-	output, err := user.cli.ExecuteOpCommand("user", "confirm", user.ID)
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "confirm", user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	var updatedUser User
-	err = json.Unmarshal([]byte(output), &updatedUser)
+	if err := user.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListPendingUsers retrieves all users whose invitation is waiting to be
+// confirmed by an administrator.
+//
+// Returns:
+//   - A slice of User objects in the PENDING state.
+//   - An error if the users cannot be listed.
+func (cli *OpCLI) ListPendingUsers(ctx context.Context) ([]User, error) {
+	users, err := cli.ListUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedUser.cli = user.cli
+	var pending []User
+	for _, user := range users {
+		if user.State == UserStatePending {
+			pending = append(pending, user)
+		}
+	}
+
+	return pending, nil
+}
+
+// ConfirmAllUsers confirms every user that is waiting for confirmation.
+// It executes the "op user confirm --all" command.
+//
+// Returns:
+//   - An error if the command fails.
+func (cli *OpCLI) ConfirmAllUsers(ctx context.Context) error {
+	// Execute the command to confirm all pending users
+	_, err := cli.ExecuteOpCommand(ctx, "user", "confirm", "--all")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CancelInvitation revokes the invitation of a user that has not been
+// confirmed yet by deleting the pending user.
+//
+// Returns:
+//   - An error if the user is not pending or the command fails.
+func (user *User) CancelInvitation(ctx context.Context) error {
+	if user.State != UserStatePending {
+		return fmt.Errorf("user %s has no pending invitation (state %s)", user.ID, user.State)
+	}
 
-	return &updatedUser, nil
+	return user.Delete(ctx)
 }
 
 // Delete removes a user from the 1Password system.
@@ -199,9 +481,9 @@ func (user *User) Confirm() (*User, error) {
 //
 // Returns:
 // - An error if the command fails.
-func (user *User) Delete() error {
+func (user *User) Delete(ctx context.Context) error {
 	// Execute the command to delete a user by ID
-	_, err := user.cli.ExecuteOpCommand("user", "delete", user.ID)
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "delete", user.ID)
 	if err != nil {
 		return err
 	}
@@ -210,43 +492,40 @@ func (user *User) Delete() error {
 }
 
 // Suspend suspends the current user by executing the appropriate CLI command.
-// It sends a request to suspend the user identified by their ID and returns
-// the updated user object if successful.
+// It sends a request to suspend the user identified by their ID and reloads
+// the user afterwards, so the receiver reflects the new state.
 //
 // Returns:
 //   - A pointer to the updated User object with the suspension applied.
-//   - An error if the suspension process fails or if the response cannot be unmarshaled.
-func (user *User) Suspend() (*User, error) {
+//   - An error if the suspension process or reloading the user fails.
+func (user *User) Suspend(ctx context.Context) (*User, error) {
 	// Execute the command to suspend a user by ID
-	output, err := user.cli.ExecuteOpCommand("user", "suspend", user.ID)
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "suspend", user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	var updatedUser User
-	err = json.Unmarshal([]byte(output), &updatedUser)
-	if err != nil {
+	if err := user.Reload(ctx); err != nil {
 		return nil, err
 	}
 
-	updatedUser.cli = user.cli
-
-	return &updatedUser, nil
+	return user, nil
 }
 
 // Reactivate reactivates a deactivated user in the system.
 //
 // This method sends a command to the 1Password CLI to reactivate the user
 // associated with the current User instance. The reactivation is performed
-// using the user's unique ID.
+// using the user's unique ID. Afterwards the user is reloaded, so the
+// receiver reflects the new state.
 //
 // Returns:
 //   - nil if the reactivation is successful.
-//   - An error if the reactivation command fails or encounters an issue.
+//   - An error if the reactivation command or reloading the user fails.
 //
 // Usage:
 //
-//	err := user.Reactivate()
+//	err := user.Reactivate(ctx)
 //	if err != nil {
 //	    log.Fatalf("Failed to reactivate user: %v", err)
 //	}
@@ -255,27 +534,28 @@ func (user *User) Suspend() (*User, error) {
 //
 //	Ensure that the 1Password CLI is properly configured and authenticated
 //	before calling this method, as it relies on the CLI to execute the command.
-func (user *User) Reactivate() error {
+func (user *User) Reactivate(ctx context.Context) error {
 	// Execute the command to reactivate a user by ID
-	_, err := user.cli.ExecuteOpCommand("user", "reactivate", user.ID)
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "reactivate", user.ID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return user.Reload(ctx)
 }
 
 // SetTravelMode enables or disables travel mode for a user.
 // It uses the "op user edit" command to update the travel mode setting.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - enabled: A boolean indicating whether to enable or disable travel mode.
 //
 // Returns:
 // - An error if the command fails.
-func (user *User) SetTravelMode(enabled bool) error {
+func (user *User) SetTravelMode(ctx context.Context, enabled bool) error {
 	// Execute the command to set travel mode for a user by ID
-	_, err := user.cli.ExecuteOpCommand("user", "edit", user.ID, fmt.Sprintf("--travel-mode=%t", enabled))
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "edit", user.ID, fmt.Sprintf("--travel-mode=%t", enabled))
 	if err != nil {
 		return err
 	}
@@ -284,34 +564,223 @@ func (user *User) SetTravelMode(enabled bool) error {
 }
 
 // SetName updates the name of the user by executing a command with the user's ID.
-// It uses the 1Password CLI to perform the operation.
+// It uses the 1Password CLI to perform the operation and reloads the user afterwards.
 //
 // Parameters:
+//   - ctx: The context for the command execution.
 //   - name: The new name to set for the user.
 //
 // Returns:
 //   - error: An error if the command execution fails, otherwise nil.
-func (user *User) SetName(name string) error {
+func (user *User) SetName(ctx context.Context, name string) error {
 	// Execute the command to set the name for a user by ID
-	_, err := user.cli.ExecuteOpCommand("user", "edit", user.ID, fmt.Sprintf("--name=%s", name))
+	_, err := user.cli.ExecuteOpCommand(ctx, "user", "edit", user.ID, fmt.Sprintf("--name=%s", name))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return user.Reload(ctx)
+}
+
+// ListVaults retrieves all vaults the user has direct access to.
+// It uses the "op vault list --user" command. Each returned Vault has its
+// Permissions field populated with the permissions granted to the user.
+//
+// Returns:
+//   - A slice of Vault objects the user can access.
+//   - An error if the command execution or JSON unmarshalling fails.
+func (user *User) ListVaults(ctx context.Context) ([]Vault, error) {
+	// Execute the command to list the vaults of a user by ID
+	output, err := user.cli.ExecuteOpCommand(ctx, "vault", "list", "--user", user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var vaults []Vault
+	err = user.cli.unmarshal(output, &vaults)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the cli reference for each vault
+	for i := range vaults {
+		vaults[i].cli = user.cli
+	}
+
+	return vaults, nil
+}
+
+// ListGroups retrieves all groups the user is a member of.
+// It uses the "op group list --user" command.
+//
+// Returns:
+//   - A slice of Group objects the user belongs to.
+//   - An error if the command execution or JSON unmarshalling fails.
+func (user *User) ListGroups(ctx context.Context) ([]Group, error) {
+	return user.cli.ListGroups(ctx, ListGroupsOptions{User: user.ID})
+}
+
+// InactiveUsersReport lists users that have not authenticated within a threshold.
+type InactiveUsersReport struct {
+	Threshold   time.Duration
+	GeneratedAt time.Time
+	ByState     map[UserState][]User
+	Total       int
+}
+
+// ReportInactiveUsers lists all users whose last authentication is older than
+// the given threshold, grouped by their state. Users that never authenticated
+// are included as well.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - threshold: The maximum time since the last authentication.
+//
+// Returns:
+//   - An InactiveUsersReport with the inactive users grouped by state.
+//   - An error if the users cannot be listed.
+func (cli *OpCLI) ReportInactiveUsers(ctx context.Context, threshold time.Duration) (*InactiveUsersReport, error) {
+	users, err := cli.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &InactiveUsersReport{
+		Threshold:   threshold,
+		GeneratedAt: time.Now(),
+		ByState:     make(map[UserState][]User),
+	}
+
+	for _, user := range users {
+		if report.GeneratedAt.Sub(user.LastAuthAt) <= threshold {
+			continue
+		}
+		report.ByState[user.State] = append(report.ByState[user.State], user)
+		report.Total++
+	}
+
+	return report, nil
+}
+
+// DeprovisionOptions controls the steps performed by User.Deprovision.
+//
+// Fields:
+//   - DeauthorizeDevicesAfter: Delay before the user's devices are deauthorized
+//     after suspension. Zero uses the CLI default.
+//   - KeepUser: Stop after revoking access instead of deleting the user.
+type DeprovisionOptions struct {
+	DeauthorizeDevicesAfter time.Duration
+	KeepUser                bool
 }
 
-func (cli *OpCLI) GetMe() (*User, error) {
+// DeprovisionReport records which offboarding steps were completed, so a
+// partially failed deprovisioning can be inspected and rolled back.
+type DeprovisionReport struct {
+	Suspended     bool
+	RemovedGroups []Group
+	RevokedVaults []Vault
+	Deleted       bool
+}
 
-	output, err := cli.Execute("user", "get", "--me")
+// Deprovision offboards the user. It suspends the user, removes them from all
+// groups, revokes all direct vault grants and finally deletes the user.
+// The steps run in this order so access is blocked before anything else is
+// changed. If a step fails, the process stops and the returned report lists
+// the steps that were already completed.
+//
+// Parameters:
+//   - ctx: The context for the command execution.
+//   - opts: DeprovisionOptions controlling device deauthorization and deletion.
+//
+// Returns:
+//   - A DeprovisionReport describing the completed steps.
+//   - An error if any step fails.
+func (user *User) Deprovision(ctx context.Context, opts DeprovisionOptions) (*DeprovisionReport, error) {
+	report := &DeprovisionReport{}
+
+	// Suspend the user first to block access immediately
+	args := []string{"user", "suspend", user.ID}
+	if opts.DeauthorizeDevicesAfter > 0 {
+		args = append(args, "--deauthorize-devices-after", opts.DeauthorizeDevicesAfter.String())
+	}
+	if _, err := user.cli.ExecuteOpCommand(ctx, args...); err != nil {
+		return report, fmt.Errorf("failed to suspend user: %w", err)
+	}
+	report.Suspended = true
+
+	groups, err := user.ListGroups(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list groups of user: %w", err)
+	}
+	for _, group := range groups {
+		if err := group.RemoveMember(ctx, *user); err != nil {
+			return report, fmt.Errorf("failed to remove user from group %s: %w", group.ID, err)
+		}
+		report.RemovedGroups = append(report.RemovedGroups, group)
+	}
+
+	vaults, err := user.ListVaults(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list vaults of user: %w", err)
+	}
+	for _, vault := range vaults {
+		if len(vault.Permissions) == 0 {
+			continue
+		}
+
+		_, err := user.cli.ExecuteOpCommand(ctx,
+			"vault", "user", "revoke",
+			"--vault", vault.ID,
+			"--user", user.ID,
+			"--permissions", FormatPermissions(vault.Permissions),
+		)
+		if err != nil {
+			return report, fmt.Errorf("failed to revoke access to vault %s: %w", vault.ID, err)
+		}
+		report.RevokedVaults = append(report.RevokedVaults, vault)
+	}
+
+	if opts.KeepUser {
+		return report, nil
+	}
+
+	if err := user.Delete(ctx); err != nil {
+		return report, fmt.Errorf("failed to delete user: %w", err)
+	}
+	report.Deleted = true
+
+	return report, nil
+}
+
+// GetMe retrieves the user that is currently signed in, which is the acting
+// identity for all commands executed by this OpCLI instance. For service
+// accounts this is the service account user.
+// It uses the "op user get --me" command.
+//
+// Returns:
+//   - A pointer to the User object of the signed-in user.
+//   - An error if the command fails or the output cannot be parsed.
+func (cli *OpCLI) GetMe(ctx context.Context) (*User, error) {
+	var output []byte
+	var err error
+
+	// Service account sign-in resolves the current user before the account
+	// information is populated, so fall back to running without --account
+	if cli.Account != nil && cli.Account.UserUUID != "" {
+		output, err = cli.ExecuteOpCommand(ctx, "user", "get", "--me")
+	} else {
+		output, err = cli.Execute(ctx, "user", "get", "--me")
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	var user User
-	if err := json.Unmarshal(output, &user); err != nil {
+	if err := cli.unmarshal(output, &user); err != nil {
 		return nil, fmt.Errorf("failed to parse user details: %v", err)
 	}
 
+	user.cli = cli
+
 	return &user, nil
 }
@@ -0,0 +1,80 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUserLenientDecoding(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedType  UserType
+		expectedState UserState
+	}{
+		{
+			name:          "Known values",
+			input:         `{"type":"MEMBER","state":"ACTIVE"}`,
+			expectedType:  UserTypeMember,
+			expectedState: UserStateActive,
+		},
+		{
+			name:          "Lower case values",
+			input:         `{"type":"guest","state":"recovery_started"}`,
+			expectedType:  UserTypeGuest,
+			expectedState: UserStateRecoveryStarted,
+		},
+		{
+			name:          "Unknown values",
+			input:         `{"type":"ROBOT","state":"HIBERNATING"}`,
+			expectedType:  UserTypeUnknown,
+			expectedState: UserStateUnknown,
+		},
+		{
+			name:          "Non-string values",
+			input:         `{"type":null,"state":3}`,
+			expectedType:  UserTypeUnknown,
+			expectedState: UserStateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user User
+			if err := json.Unmarshal([]byte(tt.input), &user); err != nil {
+				t.Fatalf("json.Unmarshal(%s) returned error: %v", tt.input, err)
+			}
+
+			if user.Type != tt.expectedType {
+				t.Errorf("Type = %q; want %q", user.Type, tt.expectedType)
+			}
+			if user.State != tt.expectedState {
+				t.Errorf("State = %q; want %q", user.State, tt.expectedState)
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected bool
+	}{
+		{"user@example.com", true},
+		{"first.last+tag@sub.example.co.uk", true},
+		{"user@example.technology", true},
+		{`"john doe"@example.com`, true},
+		{"user@localhost", false},
+		{"John Doe <user@example.com>", false},
+		{"user.example.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if result := ValidateEmail(tt.email); result != tt.expected {
+				t.Errorf("ValidateEmail(%q) = %t; want %t", tt.email, result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package onepassword
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,7 @@ import (
 // - Description: A brief description of the vault's purpose or contents.
 // - AttributeVersion: The version of the vault's attributes.
 // - Type: The type of the vault, e.g., USER_CREATED or SYSTEM_GENERATED.
+// - Permissions: The permissions granted on the vault, only populated when listed for a user or group.
 type Vault struct {
 	cli *OpCLI `json:"-"` // Reference to the OpCLI instance for update operations
 
@@ -32,6 +34,8 @@ type Vault struct {
 	Description      string `json:"description"`
 	AttributeVersion int    `json:"attribute_version"`
 	Type             string `json:"type"`
+
+	Permissions []Permission `json:"permissions,omitempty"`
 }
 
 // VaultIcon represents the valid icon names for a vault.
@@ -92,14 +96,12 @@ const (
 // Returns:
 // - *[]Vault: A pointer to a slice of Vault structs containing details of each vault.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) GetVaultDetails() (*[]Vault, error) {
-	output, err := cli.ExecuteOpCommand("vault", "list")
-	if err != nil {
-		return nil, err
+func (cli *OpCLI) GetVaultDetails(ctx context.Context) (*[]Vault, error) {
+	if cli.backend != nil {
+		return cli.backendVaults(ctx)
 	}
 
-	var vaults []Vault
-	err = json.Unmarshal(output, &vaults)
+	vaults, err := collectList[Vault](ctx, cli, "vault", "list")
 	if err != nil {
 		return nil, err
 	}
@@ -118,19 +120,20 @@ func (cli *OpCLI) GetVaultDetails() (*[]Vault, error) {
 // It unmarshals the JSON output into a Vault struct and sets the CLI reference for the vault.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - identifier: The unique identifier or name of the vault.
 //
 // Returns:
 // - *Vault: A pointer to a Vault struct containing the vault's details.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) getVaultDetails(identifier string) (*Vault, error) {
-	output, err := cli.ExecuteOpCommand("vault", "get", identifier)
+func (cli *OpCLI) getVaultDetails(ctx context.Context, identifier string) (*Vault, error) {
+	output, err := cli.getEntity(ctx, CacheVaults, identifier)
 	if err != nil {
 		return nil, err
 	}
 
 	var vault Vault
-	err = json.Unmarshal(output, &vault)
+	err = cli.unmarshal(output, &vault)
 	if err != nil {
 		return nil, err
 	}
@@ -146,13 +149,17 @@ func (cli *OpCLI) getVaultDetails(identifier string) (*Vault, error) {
 // This method is a wrapper around getVaultDetails, allowing retrieval of vault details using the vault's name.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - vaultName: The name of the vault.
 //
 // Returns:
 // - *Vault: A pointer to a Vault struct containing the vault's details.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) GetVaultDetailsByName(vaultName string) (*Vault, error) {
-	return cli.getVaultDetails(vaultName)
+func (cli *OpCLI) GetVaultDetailsByName(ctx context.Context, vaultName string) (*Vault, error) {
+	if cli.backend != nil {
+		return cli.backendVaultByName(ctx, vaultName)
+	}
+	return cli.getVaultDetails(ctx, vaultName)
 }
 
 // GetVaultDetailsByID retrieves the details of a vault by its ID.
@@ -160,17 +167,21 @@ func (cli *OpCLI) GetVaultDetailsByName(vaultName string) (*Vault, error) {
 // This method validates the vault ID format and then calls getVaultDetails to fetch the vault details.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - vaultID: The unique identifier of the vault.
 //
 // Returns:
 // - *Vault: A pointer to a Vault struct containing the vault's details.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) GetVaultDetailsByID(vaultID string) (*Vault, error) {
+func (cli *OpCLI) GetVaultDetailsByID(ctx context.Context, vaultID string) (*Vault, error) {
 	if err := ValidateVaultID(vaultID); err != nil {
 		return nil, err
 	}
+	if cli.backend != nil {
+		return cli.backendVault(ctx, vaultID)
+	}
 
-	return cli.getVaultDetails(vaultID)
+	return cli.getVaultDetails(ctx, vaultID)
 }
 
 // CreateVault creates a new vault in 1Password.
@@ -178,6 +189,7 @@ func (cli *OpCLI) GetVaultDetailsByID(vaultID string) (*Vault, error) {
 // This method executes the "vault create" command using the 1Password CLI to create a new vault with the specified parameters.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - name: The name of the new vault.
 // - description: A brief description of the vault's purpose or contents.
 // - icon: The icon to associate with the vault. Must be a valid VaultIcon.
@@ -186,20 +198,20 @@ func (cli *OpCLI) GetVaultDetailsByID(vaultID string) (*Vault, error) {
 // Returns:
 // - *Vault: A pointer to a Vault struct containing the details of the newly created vault.
 // - error: An error object if the operation fails.
-func (cli *OpCLI) CreateVault(name, description string, icon VaultIcon, adminAccess bool) (*Vault, error) {
+func (cli *OpCLI) CreateVault(ctx context.Context, name, description string, icon VaultIcon, adminAccess bool) (*Vault, error) {
 	// Validate the vault name
 	if name == "" {
 		return nil, errors.New("vault name cannot be empty")
 	}
 
 	// Execute the command to create a new vault
-	output, err := cli.ExecuteOpCommand("vault", "create", name, "--description", description, "--icon", string(icon), "--allow-admins-to-manage", fmt.Sprintf("%t", adminAccess))
+	output, err := cli.ExecuteOpCommand(ctx, "vault", "create", name, "--description", description, "--icon", string(icon), "--allow-admins-to-manage", fmt.Sprintf("%t", adminAccess))
 	if err != nil {
 		return nil, err
 	}
 
 	var vault Vault
-	err = json.Unmarshal(output, &vault)
+	err = cli.unmarshal(output, &vault)
 	if err != nil {
 		return nil, err
 	}
@@ -289,12 +301,13 @@ func ValidateVault(vault Vault) error {
 // to update the icon of the specified vault.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - vaultID: The unique identifier of the vault.
 // - icon: The new icon to set for the vault. Must be a valid VaultIcon.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (cli *OpCLI) UpdateVaultIcon(vaultID string, icon VaultIcon) error {
+func (cli *OpCLI) UpdateVaultIcon(ctx context.Context, vaultID string, icon VaultIcon) error {
 	if err := ValidateVaultID(vaultID); err != nil {
 		return err
 	}
@@ -350,7 +363,7 @@ func (cli *OpCLI) UpdateVaultIcon(vaultID string, icon VaultIcon) error {
 		return errors.New("invalid icon name")
 	}
 
-	_, err := cli.ExecuteOpCommand("vault", "edit", vaultID, "--icon", string(icon))
+	_, err := cli.ExecuteOpCommand(ctx, "vault", "edit", vaultID, "--icon", string(icon))
 	if err != nil {
 		return fmt.Errorf("failed to update vault icon: %w", err)
 	}
@@ -364,22 +377,27 @@ func (cli *OpCLI) UpdateVaultIcon(vaultID string, icon VaultIcon) error {
 // using the 1Password CLI to grant the specified permission to the user.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - user: The User struct representing the user to grant permission to.
 // - permission: The Permission struct representing the permission to grant.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) GrantUserPermission(user User, permission Permission) error {
+func (vault *Vault) GrantUserPermission(ctx context.Context, user User, permission Permission) error {
 	// Check if the user is valid
 	if user.ID == "" {
 		return errors.New("invalid user: user ID cannot be empty")
 	}
 
-	// Resolve the permission string using ResolvePermissions
-	resolvedPermissions := ResolvePermissions(permission)
+	// Resolve the permission with the dependencies of the client
+	permissions := vault.cli.ResolvePermissions(permission)
+	if err := vault.validatePermissions(ctx, permissions); err != nil {
+		return err
+	}
+	resolvedPermissions := FormatPermissions(permissions)
 
 	// Execute the command to grant permissions
-	_, err := vault.cli.ExecuteOpCommand(
+	_, err := vault.cli.ExecuteOpCommand(ctx,
 		"vault", "user", "grant",
 		"--vault", vault.ID,
 		"--user", user.ID,
@@ -398,22 +416,27 @@ func (vault *Vault) GrantUserPermission(user User, permission Permission) error
 // using the 1Password CLI to revoke the specified permission from the user.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - user: The User struct representing the user to revoke permission from.
 // - permission: The Permission struct representing the permission to revoke.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) RevokeUserPermission(user User, permission Permission) error {
+func (vault *Vault) RevokeUserPermission(ctx context.Context, user User, permission Permission) error {
 	// Check if the user is valid
 	if user.ID == "" {
 		return errors.New("invalid user: user ID cannot be empty")
 	}
 
-	// Resolve the permission string using ResolvePermissions
-	resolvedPermissions := ResolvePermissions(permission)
+	// Resolve the permission with the dependencies of the client
+	permissions := vault.cli.ResolvePermissions(permission)
+	if err := vault.validatePermissions(ctx, permissions); err != nil {
+		return err
+	}
+	resolvedPermissions := FormatPermissions(permissions)
 
 	// Execute the command to revoke permissions
-	_, err := vault.cli.ExecuteOpCommand(
+	_, err := vault.cli.ExecuteOpCommand(ctx,
 		"vault", "user", "revoke",
 		"--vault", vault.ID,
 		"--user", user.ID,
@@ -432,22 +455,27 @@ func (vault *Vault) RevokeUserPermission(user User, permission Permission) error
 // using the 1Password CLI to grant the specified permission to the group.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - group: The Group struct representing the group to grant permission to.
 // - permission: The Permission struct representing the permission to grant.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) GrantGroupPermission(group Group, permission Permission) error {
+func (vault *Vault) GrantGroupPermission(ctx context.Context, group Group, permission Permission) error {
 	// Check if the group is valid
 	if group.ID == "" {
 		return errors.New("invalid group: group ID cannot be empty")
 	}
 
-	// Resolve the permission string using ResolvePermissions
-	resolvedPermissions := ResolvePermissions(permission)
+	// Resolve the permission with the dependencies of the client
+	permissions := vault.cli.ResolvePermissions(permission)
+	if err := vault.validatePermissions(ctx, permissions); err != nil {
+		return err
+	}
+	resolvedPermissions := FormatPermissions(permissions)
 
 	// Execute the command to grant permissions
-	_, err := vault.cli.ExecuteOpCommand(
+	_, err := vault.cli.ExecuteOpCommand(ctx,
 		"vault", "group", "grant",
 		"--vault", vault.ID,
 		"--group", group.ID,
@@ -466,22 +494,27 @@ func (vault *Vault) GrantGroupPermission(group Group, permission Permission) err
 // using the 1Password CLI to revoke the specified permission from the group.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - group: The Group struct representing the group to revoke permission from.
 // - permission: The Permission struct representing the permission to revoke.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) RevokeGroupPermission(group Group, permission Permission) error {
+func (vault *Vault) RevokeGroupPermission(ctx context.Context, group Group, permission Permission) error {
 	// Check if the group is valid
 	if group.ID == "" {
 		return errors.New("invalid group: group ID cannot be empty")
 	}
 
-	// Resolve the permission string using ResolvePermissions
-	resolvedPermissions := ResolvePermissions(permission)
+	// Resolve the permission with the dependencies of the client
+	permissions := vault.cli.ResolvePermissions(permission)
+	if err := vault.validatePermissions(ctx, permissions); err != nil {
+		return err
+	}
+	resolvedPermissions := FormatPermissions(permissions)
 
 	// Execute the command to revoke permissions
-	_, err := vault.cli.ExecuteOpCommand(
+	_, err := vault.cli.ExecuteOpCommand(ctx,
 		"vault", "group", "revoke",
 		"--vault", vault.ID,
 		"--group", group.ID,
@@ -494,15 +527,86 @@ func (vault *Vault) RevokeGroupPermission(group Group, permission Permission) er
 	return nil
 }
 
+// validatePermissions rejects permissions that are not supported by the plan of
+// the account or the type of the vault before a command is executed. If the
+// account type cannot be detected, only the vault type is checked.
+func (vault *Vault) validatePermissions(ctx context.Context, permissions []Permission) error {
+	accountType, err := vault.cli.AccountType(ctx)
+	if err != nil {
+		vault.cli.log().Debug("skipping account type validation of permissions", "error", err)
+	}
+
+	return ValidatePermissions(accountType, vault.Type, permissions)
+}
+
+// revokePermissions revokes exactly the given permissions from a user or group,
+// without resolving their dependencies.
+func (vault *Vault) revokePermissions(ctx context.Context, kind, id string, permissions []Permission) error {
+	_, err := vault.cli.ExecuteOpCommand(ctx,
+		"vault", kind, "revoke",
+		"--vault", vault.ID,
+		"--"+kind, id,
+		"--permissions", FormatPermissions(permissions),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke permissions from %s %s: %w", kind, id, err)
+	}
+
+	return nil
+}
+
+// ErrVaultNotEmpty is returned by Vault.Delete when RequireEmpty is set and the vault still contains items.
+var ErrVaultNotEmpty = errors.New("vault is not empty")
+
+// VaultDeleteOptions controls the safeguards applied by Vault.Delete.
+//
+// Fields:
+// - RequireEmpty: Reload the vault and refuse to delete it if it still contains items.
+// - DryRun: Report what would be deleted without deleting anything. The skipped command is
+// recorded like in the dry-run mode of the client, see OpCLI.SetDryRun.
+type VaultDeleteOptions struct {
+	RequireEmpty bool
+	DryRun       bool
+}
+
+// VaultDeleteReport describes the vault deleted by Vault.DeleteWithReport.
+//
+// Fields:
+// - VaultID: The ID of the vault.
+// - Name: The name of the vault.
+// - Items: The number of items in the vault when it was deleted.
+// - Deleted: Whether the vault was deleted, or only reported in dry-run mode.
+type VaultDeleteReport struct {
+	VaultID string `json:"vault_id"`
+	Name    string `json:"name"`
+	Items   int    `json:"items"`
+	Deleted bool   `json:"deleted"`
+}
+
 // Delete deletes the current vault.
 //
 // This method executes the "vault delete" command using the 1Password CLI to delete the current vault.
+// If RequireEmpty or DryRun is set in the options, the vault is deleted with DeleteWithReport.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - opts: Optional VaultDeleteOptions.
 //
 // Returns:
-// - error: An error object if the operation fails.
-func (vault *Vault) Delete() error {
+// - error: An error object if the operation fails, or ErrVaultNotEmpty if RequireEmpty is set and the vault contains items.
+func (vault *Vault) Delete(ctx context.Context, opts ...VaultDeleteOptions) error {
+	var options VaultDeleteOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.RequireEmpty || options.DryRun {
+		_, err := vault.DeleteWithReport(ctx, options)
+		return err
+	}
+
 	// Execute the command to delete the vault
-	_, err := vault.cli.ExecuteOpCommand("vault", "delete", vault.ID)
+	_, err := vault.cli.ExecuteOpCommand(ctx, "vault", "delete", vault.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete vault: %w", err)
 	}
@@ -510,22 +614,112 @@ func (vault *Vault) Delete() error {
 	return nil
 }
 
+// DeleteWithReport deletes the current vault like Delete and reports what was deleted.
+// The vault details are reloaded first, so the report and RequireEmpty are based on the
+// current item count rather than the possibly stale struct. With DryRun, or if the
+// dry-run mode of the client is enabled, the vault is not deleted.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - opts: The safeguards to apply.
+//
+// Returns:
+// - *VaultDeleteReport: The vault that was deleted or would be deleted.
+// - error: An error object if the operation fails, or ErrVaultNotEmpty if RequireEmpty is set and the vault contains items.
+//
+// Example usage:
+//
+//	report, err := vault.DeleteWithReport(ctx, onepassword.VaultDeleteOptions{DryRun: true})
+//	if err != nil {
+//	    log.Fatalf("Failed to delete vault: %v", err)
+//	}
+//	fmt.Printf("Would delete %s with %d items\n", report.Name, report.Items)
+func (vault *Vault) DeleteWithReport(ctx context.Context, opts VaultDeleteOptions) (*VaultDeleteReport, error) {
+	current, err := vault.cli.getVaultDetails(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload vault: %w", err)
+	}
+
+	if opts.RequireEmpty && current.Items > 0 {
+		return nil, fmt.Errorf("%w: vault %s contains %d items", ErrVaultNotEmpty, current.ID, current.Items)
+	}
+
+	if opts.DryRun {
+		ctx = withDryRun(ctx)
+	}
+	report := &VaultDeleteReport{
+		VaultID: current.ID,
+		Name:    current.Name,
+		Items:   current.Items,
+		Deleted: !vault.cli.isDryRun(ctx),
+	}
+
+	// Execute the command to delete the vault. In dry-run mode, it is only recorded.
+	_, err = vault.cli.ExecuteOpCommand(ctx, "vault", "delete", vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete vault: %w", err)
+	}
+
+	return report, nil
+}
+
+// VaultNameConflictError is returned when a vault is renamed to a name that is already used by another vault.
+type VaultNameConflictError struct {
+	Name       string
+	ExistingID string
+}
+
+// Error returns the string representation of the name conflict
+func (e *VaultNameConflictError) Error() string {
+	return fmt.Sprintf("vault name %q is already used by vault %s", e.Name, e.ExistingID)
+}
+
+// SetNameOptions controls the behavior of Vault.SetName.
+//
+// Fields:
+// - RequireUnique: Check the names of all existing vaults (case-insensitively) before renaming.
+type SetNameOptions struct {
+	RequireUnique bool
+}
+
 // SetName updates the name of the current vault.
 //
 // This method validates the new name and executes the "vault edit" command using the 1Password CLI to update the vault's name.
+// If RequireUnique is set in the options, the names of all existing vaults are compared case-insensitively first and a
+// *VaultNameConflictError is returned if another vault already uses the name.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - name: The new name to set for the vault.
+// - opts: Optional SetNameOptions.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) SetName(name string) error {
+func (vault *Vault) SetName(ctx context.Context, name string, opts ...SetNameOptions) error {
 	if name == "" {
 		return errors.New("name cannot be empty")
 	}
 
+	var options SetNameOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.RequireUnique {
+		vaults, err := vault.cli.GetVaultDetails(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check existing vault names: %w", err)
+		}
+
+		for _, existing := range *vaults {
+			if existing.ID != vault.ID && strings.EqualFold(existing.Name, name) {
+				return &VaultNameConflictError{Name: name, ExistingID: existing.ID}
+			}
+		}
+	}
+
 	args := []string{"vault", "edit", vault.ID, "--name", name}
-	_, err := vault.cli.ExecuteOpCommand(args...)
+	_, err := vault.cli.ExecuteOpCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to edit vault name: %w", err)
 	}
@@ -538,13 +732,14 @@ func (vault *Vault) SetName(name string) error {
 // This method executes the "vault edit" command using the 1Password CLI to update the vault's description.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - description: The new description to set for the vault.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) SetDescription(description string) error {
+func (vault *Vault) SetDescription(ctx context.Context, description string) error {
 	args := []string{"vault", "edit", vault.ID, "--description", description}
-	_, err := vault.cli.ExecuteOpCommand(args...)
+	_, err := vault.cli.ExecuteOpCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to edit vault description: %w", err)
 	}
@@ -557,17 +752,18 @@ func (vault *Vault) SetDescription(description string) error {
 // This method validates the new icon and executes the "vault edit" command using the 1Password CLI to update the vault's icon.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - icon: The new icon to set for the vault. Must be a valid VaultIcon.
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) SetIcon(icon VaultIcon) error {
+func (vault *Vault) SetIcon(ctx context.Context, icon VaultIcon) error {
 	if icon == "" {
 		return errors.New("icon cannot be empty")
 	}
 
 	args := []string{"vault", "edit", vault.ID, "--icon", string(icon)}
-	_, err := vault.cli.ExecuteOpCommand(args...)
+	_, err := vault.cli.ExecuteOpCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to edit vault icon: %w", err)
 	}
@@ -580,21 +776,273 @@ func (vault *Vault) SetIcon(icon VaultIcon) error {
 // This method executes the "vault edit" command using the 1Password CLI to update the Travel Mode status of the vault.
 //
 // Parameters:
+// - ctx: The context for the command execution.
 // - travelModeOn: A boolean value indicating whether to turn Travel Mode on (true) or off (false).
 //
 // Returns:
 // - error: An error object if the operation fails.
-func (vault *Vault) SetTravelMode(travelModeOn bool) error {
+func (vault *Vault) SetTravelMode(ctx context.Context, travelModeOn bool) error {
 	mode := "off"
 	if travelModeOn {
 		mode = "on"
 	}
 
 	args := []string{"vault", "edit", vault.ID, "--travel-mode", mode}
-	_, err := vault.cli.ExecuteOpCommand(args...)
+	_, err := vault.cli.ExecuteOpCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to edit vault travel mode: %w", err)
 	}
 
 	return nil
 }
+
+// ListUsers retrieves all users with direct access to the current vault.
+//
+// This method executes the "vault user list" command using the 1Password CLI. Each returned User has its
+// Permissions field populated with the permissions granted on this vault.
+//
+// Returns:
+// - []User: A slice of User structs with their vault permissions.
+// - error: An error object if the operation fails.
+func (vault *Vault) ListUsers(ctx context.Context) ([]User, error) {
+	output, err := vault.cli.ExecuteOpCommand(ctx, "vault", "user", "list", vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vault users: %w", err)
+	}
+
+	var users []User
+	err = vault.cli.unmarshal(output, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the cli reference for each user
+	for i := range users {
+		users[i].cli = vault.cli
+	}
+
+	return users, nil
+}
+
+// ListGroups retrieves all groups with access to the current vault.
+//
+// This method executes the "vault group list" command using the 1Password CLI. Each returned Group has its
+// Permissions field populated with the permissions granted on this vault.
+//
+// Returns:
+// - []Group: A slice of Group structs with their vault permissions.
+// - error: An error object if the operation fails.
+func (vault *Vault) ListGroups(ctx context.Context) ([]Group, error) {
+	output, err := vault.cli.ExecuteOpCommand(ctx, "vault", "group", "list", vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vault groups: %w", err)
+	}
+
+	var groups []Group
+	err = vault.cli.unmarshal(output, &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the cli reference for each group
+	for i := range groups {
+		groups[i].cli = vault.cli
+	}
+
+	return groups, nil
+}
+
+// CopyPermissionsFrom applies the user and group grants of the source vault to the current vault.
+//
+// This method reads the grants of the source vault using ListUsers and ListGroups and executes the
+// "vault user grant" and "vault group grant" commands for each of them on the current vault. Existing
+// grants on the current vault are kept; permissions are only added.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - source: The Vault struct whose permissions should be copied.
+//
+// Returns:
+// - error: An error object if reading the source grants or applying any of them fails.
+func (vault *Vault) CopyPermissionsFrom(ctx context.Context, source Vault) error {
+	if source.ID == vault.ID {
+		return errors.New("source and target vault must be different")
+	}
+
+	// Use the cli of the target vault if the source was constructed manually
+	if source.cli == nil {
+		source.cli = vault.cli
+	}
+
+	users, err := source.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups, err := source.ListGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if len(user.Permissions) == 0 {
+			continue
+		}
+
+		_, err := vault.cli.ExecuteOpCommand(ctx,
+			"vault", "user", "grant",
+			"--vault", vault.ID,
+			"--user", user.ID,
+			"--permissions", FormatPermissions(user.Permissions),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy permissions of user %s: %w", user.ID, err)
+		}
+	}
+
+	for _, group := range groups {
+		if len(group.Permissions) == 0 {
+			continue
+		}
+
+		_, err := vault.cli.ExecuteOpCommand(ctx,
+			"vault", "group", "grant",
+			"--vault", vault.ID,
+			"--group", group.ID,
+			"--permissions", FormatPermissions(group.Permissions),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy permissions of group %s: %w", group.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// PermissionGrant describes a set of permissions to grant to either a user or a group.
+//
+// Fields:
+// - User: The user to grant the permissions to. Mutually exclusive with Group.
+// - Group: The group to grant the permissions to. Mutually exclusive with User.
+// - Permissions: The permissions to grant. Dependencies are resolved automatically.
+type PermissionGrant struct {
+	User        *User
+	Group       *Group
+	Permissions []Permission
+}
+
+// PermissionGrantResult holds the outcome of a single PermissionGrant.
+type PermissionGrantResult struct {
+	Grant PermissionGrant
+	Err   error
+}
+
+// GrantPermissions grants multiple permissions to multiple users and groups on the current vault.
+//
+// Grants targeting the same user or group are merged, so the 1Password CLI is invoked only once per principal.
+// A failure for one principal does not stop the remaining grants from being applied.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - grants: The PermissionGrant entries to apply.
+//
+// Returns:
+// - []PermissionGrantResult: One result per grant, in the order of the input slice.
+func (vault *Vault) GrantPermissions(ctx context.Context, grants []PermissionGrant) []PermissionGrantResult {
+	type principal struct {
+		kind string
+		id   string
+	}
+
+	results := make([]PermissionGrantResult, len(grants))
+	permissionsByPrincipal := make(map[principal][]Permission)
+	grantsByPrincipal := make(map[principal][]int)
+	var order []principal
+
+	for i, grant := range grants {
+		results[i].Grant = grant
+
+		var p principal
+		switch {
+		case grant.User != nil && grant.Group != nil:
+			results[i].Err = errors.New("invalid grant: user and group cannot both be set")
+			continue
+		case grant.User != nil && grant.User.ID != "":
+			p = principal{kind: "user", id: grant.User.ID}
+		case grant.Group != nil && grant.Group.ID != "":
+			p = principal{kind: "group", id: grant.Group.ID}
+		default:
+			results[i].Err = errors.New("invalid grant: user or group ID must be set")
+			continue
+		}
+
+		if len(grant.Permissions) == 0 {
+			results[i].Err = errors.New("invalid grant: no permissions specified")
+			continue
+		}
+
+		if _, exists := grantsByPrincipal[p]; !exists {
+			order = append(order, p)
+		}
+		grantsByPrincipal[p] = append(grantsByPrincipal[p], i)
+		permissionsByPrincipal[p] = append(permissionsByPrincipal[p], grant.Permissions...)
+	}
+
+	for _, p := range order {
+		permissions := vault.cli.ResolvePermissions(permissionsByPrincipal[p]...)
+
+		// Execute a single grant command per principal
+		err := vault.validatePermissions(ctx, permissions)
+		if err == nil {
+			_, err = vault.cli.ExecuteOpCommand(ctx,
+				"vault", p.kind, "grant",
+				"--vault", vault.ID,
+				"--"+p.kind, p.id,
+				"--permissions", FormatPermissions(permissions),
+			)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to grant permissions to %s %s: %w", p.kind, p.id, err)
+		}
+
+		for _, i := range grantsByPrincipal[p] {
+			results[i].Err = err
+		}
+	}
+
+	return results
+}
+
+// GrantRole grants the permissions of a predefined role to a user or group on the current vault.
+//
+// The permissions of the role already include their dependencies, so a single grant command is executed.
+//
+// Parameters:
+// - ctx: The context for the command execution.
+// - principal: The *User or *Group to grant the role to.
+// - role: The Role to grant, e.g. RoleEditor.
+//
+// Returns:
+// - error: ErrUnknownRole if the role is not predefined, or an error if the grant fails.
+//
+// Example usage:
+//
+//	err := vault.GrantRole(ctx, &user, onepassword.RoleViewer)
+func (vault *Vault) GrantRole(ctx context.Context, principal Principal, role Role) error {
+	permissions := role.Permissions()
+	if permissions == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownRole, role)
+	}
+
+	grant := PermissionGrant{Permissions: permissions}
+	switch p := principal.(type) {
+	case *User:
+		grant.User = p
+	case *Group:
+		grant.Group = p
+	default:
+		return errors.New("invalid principal: must be a user or group")
+	}
+
+	return vault.GrantPermissions(ctx, []PermissionGrant{grant})[0].Err
+}
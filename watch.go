@@ -0,0 +1,200 @@
+package onepassword
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is the polling interval of WatchVaults if none is given.
+const defaultWatchInterval = time.Minute
+
+// ChangeType is the kind of change reported by WatchVaults.
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created" // The item was created or moved into a watched vault.
+	ChangeUpdated ChangeType = "updated" // The item was edited.
+	ChangeDeleted ChangeType = "deleted" // The item was deleted, archived or moved out of the watched vaults.
+)
+
+// ChangeEvent is a change of an item in a watched vault.
+//
+// Fields:
+//   - Type: The kind of change.
+//   - Item: The item as listed by the CLI, without fields. For ChangeDeleted, the last known state.
+//   - DetectedAt: The time the change was detected.
+type ChangeEvent struct {
+	Type       ChangeType
+	Item       Item
+	DetectedAt time.Time
+}
+
+// ChangeHandler is called by a Watcher for every detected change.
+type ChangeHandler func(ctx context.Context, event ChangeEvent) error
+
+// WatchOptions configures WatchVaults.
+//
+// Fields:
+//   - Vaults: The names or IDs of the watched vaults. If empty, all vaults are watched.
+//   - Interval: The time between two polls. Defaults to one minute.
+type WatchOptions struct {
+	Vaults   []string
+	Interval time.Duration
+}
+
+// Watcher polls vaults for changed items. It is stopped with Stop or when
+// the OpCLI instance is closed.
+type Watcher struct {
+	cli     *OpCLI
+	opts    WatchOptions
+	handler ChangeHandler
+
+	known    map[string]Item
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// WatchVaults starts polling vaults and calls handler for every item that
+// was created, updated or deleted since the previous poll. The items of the
+// first poll are the baseline and are not reported. Failed polls and handler
+// errors are logged, and polling continues.
+//
+// Parameters:
+//   - ctx: The context of the watcher. Cancelling it stops the watcher.
+//   - opts: The watched vaults and the polling interval.
+//   - handler: The function called for every change.
+//
+// Returns:
+//   - *Watcher: The running watcher.
+//   - error: An error if handler is nil or the baseline cannot be listed.
+//
+// Example usage:
+//
+//	watcher, err := cli.WatchVaults(ctx, onepassword.WatchOptions{Vaults: []string{"Production"}},
+//	    func(ctx context.Context, event onepassword.ChangeEvent) error {
+//	        log.Printf("%s %s", event.Item.Title, event.Type)
+//	        return nil
+//	    })
+//	if err != nil {
+//	    log.Fatalf("Failed to watch vaults: %v", err)
+//	}
+//	defer watcher.Stop()
+func (cli *OpCLI) WatchVaults(ctx context.Context, opts WatchOptions, handler ChangeHandler) (*Watcher, error) {
+	if handler == nil {
+		return nil, errors.New("change handler cannot be nil")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+
+	watcher := &Watcher{cli: cli, opts: opts, handler: handler, done: make(chan struct{})}
+	known, err := watcher.snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watched items: %w", err)
+	}
+	watcher.known = known
+
+	ctx, watcher.cancel = context.WithCancel(ctx)
+	go watcher.run(ctx)
+	cli.onClose(watcher.Stop)
+
+	return watcher, nil
+}
+
+// Stop stops polling and waits until a running handler returns. Calling
+// Stop more than once has no effect.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		w.cancel()
+		<-w.done
+	})
+}
+
+// run polls until the context is done.
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll lists the watched items and reports the differences to the previous poll.
+func (w *Watcher) poll(ctx context.Context) {
+	current, err := w.snapshot(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.cli.log().Warn("failed to poll watched vaults", "error", err)
+		}
+		return
+	}
+
+	for _, event := range diffItems(w.known, current, time.Now()) {
+		if err := w.handler(ctx, event); err != nil {
+			w.cli.log().Warn("change handler failed", "item", event.Item.ID, "change", event.Type, "error", err)
+		}
+	}
+	w.known = current
+}
+
+// snapshot lists the items of the watched vaults by ID.
+func (w *Watcher) snapshot(ctx context.Context) (map[string]Item, error) {
+	vaults := w.opts.Vaults
+	if len(vaults) == 0 {
+		vaults = []string{""}
+	}
+
+	items := make(map[string]Item)
+	for _, vault := range vaults {
+		listed, err := w.cli.listItems(ctx, ListItemsOptions{Vault: vault})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range *listed {
+			items[item.ID] = item
+		}
+	}
+	return items, nil
+}
+
+// diffItems returns the changes between two snapshots, sorted by vault and title.
+func diffItems(previous, current map[string]Item, detectedAt time.Time) []ChangeEvent {
+	var events []ChangeEvent
+	for id, item := range current {
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			events = append(events, ChangeEvent{Type: ChangeCreated, Item: item, DetectedAt: detectedAt})
+		case old.Version != item.Version || !old.UpdatedAt.Equal(item.UpdatedAt):
+			events = append(events, ChangeEvent{Type: ChangeUpdated, Item: item, DetectedAt: detectedAt})
+		}
+	}
+	for id, item := range previous {
+		if _, ok := current[id]; !ok {
+			events = append(events, ChangeEvent{Type: ChangeDeleted, Item: item, DetectedAt: detectedAt})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b ChangeEvent) int {
+		return cmp.Or(
+			cmp.Compare(a.Item.Vault.Name, b.Item.Vault.Name),
+			cmp.Compare(a.Item.Title, b.Item.Title),
+			cmp.Compare(a.Item.ID, b.Item.ID),
+		)
+	})
+	return events
+}
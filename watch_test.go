@@ -0,0 +1,90 @@
+package onepassword
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiffItems(t *testing.T) {
+	edited := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	previous := map[string]Item{
+		"a": {ID: "a", Title: "Database", Version: 1},
+		"b": {ID: "b", Title: "Deploy key", Version: 1},
+		"c": {ID: "c", Title: "Legacy", Version: 3},
+	}
+	current := map[string]Item{
+		"a": {ID: "a", Title: "Database", Version: 1},
+		"b": {ID: "b", Title: "Deploy key", Version: 2, UpdatedAt: edited},
+		"d": {ID: "d", Title: "API token", Version: 1},
+	}
+
+	var got []string
+	for _, event := range diffItems(previous, current, time.Now()) {
+		got = append(got, string(event.Type)+" "+event.Item.Title)
+	}
+
+	expected := []string{"created API token", "updated Deploy key", "deleted Legacy"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("diffItems() = %q; want %q", got, expected)
+	}
+}
+
+func TestWatchVaults(t *testing.T) {
+	var polls atomic.Int32
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	cli.SetCommandExecutor(CommandExecutorFunc(func(ctx context.Context, cmd *Command) ([]byte, []byte, error) {
+		output := `[{"id":"a","title":"Database","version":1}]`
+		if polls.Add(1) > 1 {
+			output = `[{"id":"a","title":"Database","version":2}]`
+		}
+		_, err := cmd.Stdout.Write([]byte(output))
+		return nil, nil, err
+	}))
+
+	var mu sync.Mutex
+	var events []ChangeEvent
+	received := make(chan struct{}, 1)
+	watcher, err := cli.WatchVaults(context.Background(), WatchOptions{Vaults: []string{"Production"}, Interval: 5 * time.Millisecond},
+		func(ctx context.Context, event ChangeEvent) error {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("WatchVaults() error = %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchVaults() reported no change")
+	}
+
+	// Closing the client stops the watcher
+	if err := cli.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	watcher.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0].Type != ChangeUpdated || events[0].Item.Version != 2 {
+		t.Errorf("WatchVaults() events = %+v; want one update to version 2", events)
+	}
+}
+
+func TestWatchVaultsRequiresHandler(t *testing.T) {
+	cli := &OpCLI{Path: "op", Account: &Account{UserUUID: "user-uuid"}}
+	if _, err := cli.WatchVaults(context.Background(), WatchOptions{}, nil); err == nil {
+		t.Error("WatchVaults() without handler succeeded")
+	}
+}
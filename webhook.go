@@ -0,0 +1,188 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of a webhook
+	// payload in the format "sha256=<hex>".
+	WebhookSignatureHeader = "X-Onepassword-Signature"
+
+	// WebhookTimestampHeader carries the Unix time at which a webhook payload
+	// was signed. It is part of the signed content.
+	WebhookTimestampHeader = "X-Onepassword-Timestamp"
+)
+
+// ErrInvalidWebhookSignature is returned by VerifyWebhookSignature for payloads
+// that were not signed with the secret or were modified.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookPayload is the JSON body posted by a Notifier. It describes the
+// changed item by its metadata only; field values, URLs and notes are never
+// included.
+//
+// Fields:
+//   - Event: The kind of change.
+//   - ItemID: The ID of the item.
+//   - Title: The title of the item.
+//   - Category: The category of the item.
+//   - VaultID: The ID of the vault of the item.
+//   - VaultName: The name of the vault of the item.
+//   - Version: The version of the item.
+//   - UpdatedAt: The time the item was last edited.
+//   - DetectedAt: The time the change was detected.
+type WebhookPayload struct {
+	Event      ChangeType `json:"event"`
+	ItemID     string     `json:"item_id"`
+	Title      string     `json:"title"`
+	Category   Category   `json:"category"`
+	VaultID    string     `json:"vault_id"`
+	VaultName  string     `json:"vault_name"`
+	Version    int        `json:"version"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DetectedAt time.Time  `json:"detected_at"`
+}
+
+// NewWebhookPayload returns the redacted payload of a change event.
+//
+// Parameters:
+//   - event: The change event.
+//
+// Returns:
+//   - WebhookPayload: The metadata of the changed item.
+func NewWebhookPayload(event ChangeEvent) WebhookPayload {
+	return WebhookPayload{
+		Event:      event.Type,
+		ItemID:     event.Item.ID,
+		Title:      event.Item.Title,
+		Category:   event.Item.Category,
+		VaultID:    event.Item.Vault.ID,
+		VaultName:  event.Item.Vault.Name,
+		Version:    event.Item.Version,
+		UpdatedAt:  event.Item.UpdatedAt,
+		DetectedAt: event.DetectedAt,
+	}
+}
+
+// Notifier forwards change events of a Watcher to a webhook or a handler.
+// Its Notify method is a ChangeHandler.
+//
+// Fields:
+//   - URL: The URL the payloads are posted to. Ignored if Handler is set.
+//   - Secret: The key payloads are signed with using HMAC-SHA256. If empty, payloads are not signed.
+//   - Handler: Receives the payloads instead of the webhook.
+//   - HTTPClient: The client used for requests. If nil, http.DefaultClient is used.
+type Notifier struct {
+	URL        string
+	Secret     []byte
+	Handler    func(ctx context.Context, payload WebhookPayload) error
+	HTTPClient *http.Client
+}
+
+// Notify delivers the redacted payload of a change event.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - event: The change event.
+//
+// Returns:
+//   - error: An error if the handler fails, the request fails or the webhook does not respond with 2xx.
+//
+// Example usage:
+//
+//	notifier := &onepassword.Notifier{URL: "https://monitoring.example.com/hooks/1password", Secret: secret}
+//	watcher, err := cli.WatchVaults(ctx, onepassword.WatchOptions{Vaults: []string{"Production"}}, notifier.Notify)
+func (n *Notifier) Notify(ctx context.Context, event ChangeEvent) error {
+	payload := NewWebhookPayload(event)
+	if n.Handler != nil {
+		return n.Handler(ctx, payload)
+	}
+	if n.URL == "" {
+		return errors.New("notifier has neither a URL nor a handler")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, signWebhook(n.Secret, timestamp, body))
+	}
+
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhook returns the signature header value of a payload.
+func signWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature of a payload received from a
+// Notifier, e.g. in the HTTP handler of a monitoring system.
+//
+// Parameters:
+//   - secret: The secret of the Notifier.
+//   - timestamp: The value of the WebhookTimestampHeader header.
+//   - body: The request body.
+//   - signature: The value of the WebhookSignatureHeader header.
+//   - maxAge: The maximum age of the payload to reject replays. Zero disables the check.
+//
+// Returns:
+//   - error: ErrInvalidWebhookSignature if the signature does not match or the payload is too old.
+func VerifyWebhookSignature(secret []byte, timestamp string, body []byte, signature string, maxAge time.Duration) error {
+	if !strings.HasPrefix(signature, "sha256=") ||
+		!hmac.Equal([]byte(signWebhook(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	if maxAge > 0 {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidWebhookSignature, timestamp)
+		}
+		if time.Since(time.Unix(seconds, 0)) > maxAge {
+			return fmt.Errorf("%w: payload is older than %s", ErrInvalidWebhookSignature, maxAge)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifierWebhook(t *testing.T) {
+	secret := []byte("webhook-secret")
+	event := ChangeEvent{
+		Type: ChangeUpdated,
+		Item: Item{
+			ID:     "item-id",
+			Title:  "Database",
+			Vault:  Vault{ID: "vault-id", Name: "Production"},
+			Fields: []Field{{Label: "password", Value: "hunter2"}},
+		},
+		DetectedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Delivered", status: http.StatusNoContent},
+		{name: "Rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verifyErr error
+			var payload WebhookPayload
+			var rawBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				rawBody = string(body)
+				verifyErr = VerifyWebhookSignature(secret, r.Header.Get(WebhookTimestampHeader), body, r.Header.Get(WebhookSignatureHeader), time.Minute)
+				_ = json.Unmarshal(body, &payload)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier := &Notifier{URL: server.URL, Secret: secret}
+			err := notifier.Notify(context.Background(), event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if verifyErr != nil {
+				t.Errorf("VerifyWebhookSignature() error = %v", verifyErr)
+			}
+			if payload.Event != ChangeUpdated || payload.ItemID != "item-id" || payload.VaultName != "Production" {
+				t.Errorf("payload = %+v; want the change of item-id", payload)
+			}
+			if strings.Contains(rawBody, "hunter2") {
+				t.Errorf("payload contains a field value: %s", rawBody)
+			}
+		})
+	}
+}
+
+func TestNotifierHandler(t *testing.T) {
+	var got WebhookPayload
+	notifier := &Notifier{Handler: func(ctx context.Context, payload WebhookPayload) error {
+		got = payload
+		return nil
+	}}
+
+	if err := notifier.Notify(context.Background(), ChangeEvent{Type: ChangeDeleted, Item: Item{ID: "item-id"}}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got.Event != ChangeDeleted || got.ItemID != "item-id" {
+		t.Errorf("handler payload = %+v; want deletion of item-id", got)
+	}
+
+	if err := (&Notifier{}).Notify(context.Background(), ChangeEvent{}); err == nil {
+		t.Error("Notify() without URL and handler succeeded")
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"event":"created"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		body      []byte
+		signature string
+		wantErr   bool
+	}{
+		{name: "Valid", timestamp: now, body: body, signature: signWebhook(secret, now, body)},
+		{name: "Modified body", timestamp: now, body: []byte(`{"event":"deleted"}`), signature: signWebhook(secret, now, body), wantErr: true},
+		{name: "Wrong secret", timestamp: now, body: body, signature: signWebhook([]byte("other"), now, body), wantErr: true},
+		{name: "Replayed", timestamp: old, body: body, signature: signWebhook(secret, old, body), wantErr: true},
+		{name: "Missing signature", timestamp: now, body: body, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(secret, tt.timestamp, tt.body, tt.signature, time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyWebhookSignature() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidWebhookSignature) {
+				t.Errorf("VerifyWebhookSignature() error = %v; want ErrInvalidWebhookSignature", err)
+			}
+		})
+	}
+}